@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var importAccountID string
+
+var importCmd = &cobra.Command{
+	Use:   "import <archive>",
+	Short: "Import a chat archive into the chat storage",
+	Long: `Load a JSON Lines chat archive created by the chat export endpoints back into the chat storage.
+A zip export is accepted as well, its archive is read and the bundled media is left out.
+Messages that are already stored are skipped, so an archive can be imported more than once.`,
+	Args: cobra.ExactArgs(1),
+	Run:  importArchive,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVar(&importAccountID, "account-id", "", "Account to import into, defaults to the account recorded in the archive")
+}
+
+func importArchive(_ *cobra.Command, args []string) {
+	archives, closeArchives, err := openArchives(args[0])
+	if err != nil {
+		logrus.Fatalf("failed to open archive: %v", err)
+	}
+	defer closeArchives()
+
+	for _, archive := range archives {
+		response, err := chatUsecase.ImportChats(context.Background(), domainChat.ImportChatsRequest{
+			AccountID: importAccountID,
+			Archive:   archive,
+		})
+		if err != nil {
+			logrus.Fatalf("failed to import archive: %v (%d chats and %d messages were imported before)",
+				err, response.Chats, response.Messages)
+		}
+
+		fmt.Printf("Imported %d chats and %d messages into account %q, %d messages were skipped\n",
+			response.Chats, response.Messages, response.AccountID, response.SkippedMessages)
+	}
+}
+
+// openArchives opens a JSON Lines archive, or the JSON Lines archives inside a zip export
+func openArchives(name string) (archives []io.Reader, closeAll func(), err error) {
+	if !strings.EqualFold(path.Ext(name), ".zip") {
+		file, err := os.Open(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []io.Reader{file}, func() { file.Close() }, nil
+	}
+
+	zipFile, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entries []io.ReadCloser
+	closeAll = func() {
+		for _, entry := range entries {
+			entry.Close()
+		}
+		zipFile.Close()
+	}
+	for _, file := range zipFile.File {
+		if !strings.EqualFold(path.Ext(file.Name), ".jsonl") {
+			continue
+		}
+		entry, err := file.Open()
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		entries = append(entries, entry)
+		archives = append(archives, entry)
+	}
+	if len(archives) == 0 {
+		closeAll()
+		return nil, nil, fmt.Errorf("%s does not contain a .jsonl archive", name)
+	}
+
+	return archives, closeAll, nil
+}
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/mcp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/mark3labs/mcp-go/server"
@@ -29,8 +31,15 @@ func init() {
 func mcpServer(_ *cobra.Command, _ []string) {
 	// Set auto reconnect to whatsapp server after booting
 	go helpers.SetAutoConnectAfterBooting(appUsecase)
+	// Restore registered accounts and reconnect the logged in ones
+	go helpers.SetAutoRestoreAccountsAfterBooting(accountUsecase)
+	// Deliver queued webhooks, including the ones left over from the previous run
+	go whatsapp.StartWebhookWorkers(context.Background())
+	go sendQueueWorker.Run(context.Background())
+	// Prune messages and media by the retention rules
+	go storageJanitor.Run(context.Background())
 	// Set auto reconnect checking
-	go helpers.SetAutoReconnectChecking(whatsappCli)
+	go helpers.SetAutoReconnectChecking()
 
 	// Create MCP server with capabilities
 	mcpServer := server.NewMCPServer(
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
@@ -62,7 +64,7 @@ func restServer(_ *cobra.Command, _ []string) {
 	}
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept",
+		AllowHeaders: "Origin, Content-Type, Accept, Authorization, X-Api-Key, X-Backup-Passphrase",
 	}))
 
 	if len(config.AppBasicAuthCredential) > 0 {
@@ -77,8 +79,11 @@ func restServer(_ *cobra.Command, _ []string) {
 
 		app.Use(basicauth.New(basicauth.Config{
 			Users: account,
+			// Requests with an API key are authenticated by the API key middleware below
+			Next: middleware.HasAPIKey,
 		}))
 	}
+	app.Use(middleware.APIKey(apiKeyUsecase))
 
 	// Create base path group or use app directly
 	var apiGroup fiber.Router = app
@@ -95,6 +100,13 @@ func restServer(_ *cobra.Command, _ []string) {
 	rest.InitRestMessage(apiGroup, messageUsecase)
 	rest.InitRestGroup(apiGroup, groupUsecase)
 	rest.InitRestNewsletter(apiGroup, newsletterUsecase)
+	rest.InitRestWebhook(apiGroup, webhookUsecase)
+	rest.InitRestTemplate(apiGroup, templateUsecase)
+	rest.InitRestAPIKey(apiGroup, apiKeyUsecase)
+	rest.InitRestStorage(apiGroup, storageUsecase)
+	rest.InitRestAutoReply(apiGroup, autoReplyUsecase)
+	rest.InitRestEvent(apiGroup, eventUsecase)
+	rest.InitRestMetrics(apiGroup)
 
 	apiGroup.Get("/", func(c *fiber.Ctx) error {
 		return c.Render("views/index", fiber.Map{
@@ -112,8 +124,15 @@ func restServer(_ *cobra.Command, _ []string) {
 
 	// Set auto reconnect to whatsapp server after booting
 	go helpers.SetAutoConnectAfterBooting(appUsecase)
+	// Restore registered accounts and reconnect the logged in ones
+	go helpers.SetAutoRestoreAccountsAfterBooting(accountUsecase)
+	// Deliver queued webhooks, including the ones left over from the previous run
+	go whatsapp.StartWebhookWorkers(context.Background())
+	go sendQueueWorker.Run(context.Background())
+	// Prune messages and media by the retention rules
+	go storageJanitor.Run(context.Background())
 	// Set auto reconnect checking
-	go helpers.SetAutoReconnectChecking(whatsappCli)
+	go helpers.SetAutoReconnectChecking()
 
 	if err := app.Listen(":" + config.AppPort); err != nil {
 		logrus.Fatalln("Failed to start: ", err.Error())
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"go.mau.fi/whatsmeow/proto/waCompanionReg"
 )
 
@@ -36,8 +38,35 @@ var (
 	WhatsappTypeUser                     = "@s.whatsapp.net"
 	WhatsappTypeGroup                    = "@g.us"
 	WhatsappAccountValidation            = true
+	WhatsappWebhookWorkers               = 4              // Number of workers draining the webhook outbox
+	WhatsappWebhookMaxAge                = 24 * time.Hour // Failed deliveries older than this are dead-lettered
+	WhatsappWebhookEnvelope              = false          // Wrap webhook payloads in the versioned envelope
 
-	ChatStorageURI               = "file:storages/chatstorage.db"
+	ChatStorageURI               = "file:storages/chatstorage.db" // file: for SQLite, postgres:// for PostgreSQL
 	ChatStorageEnableForeignKeys = true
 	ChatStorageEnableWAL         = true
+
+	AccountDBURI = "" // Accounts, webhooks, templates and API keys; empty uses ChatStorageURI
+
+	AccountRestoreConcurrency = 5 // Number of accounts reconnected in parallel on boot
+
+	SendQueueRatePerMinute = 20              // Queued messages sent per minute for each account
+	SendQueueJitter        = 5 * time.Second // Random extra delay added between queued messages
+	SendQueueQuietHours    = ""              // Daily window (HH:MM-HH:MM) in which queued messages are held back
+	SendQueueMaxAttempts   = 3               // Attempts per queued message before it fails
+	SendQueueMaxAge        = 24 * time.Hour  // Queued messages this long past their send time expire
+
+	SendBulkDelay = 2 * time.Second // Default delay between recipients of a bulk send
+
+	RetentionMaxMessageDays       = 0         // Messages older than this many days are pruned, 0 keeps them forever
+	RetentionMaxMediaSize   int64 = 0         // Media folders are pruned down to this many bytes, 0 means no limit
+	RetentionKeepStarred          = true      // Starred messages are left out of the global retention rule
+	RetentionInterval             = time.Hour // How often the storage janitor runs
+
+	EventStreamBuffer       = 1000            // Events kept for subscribers resuming with a cursor, 0 disables the stream
+	EventStreamResumeWindow = 5 * time.Minute // How long events of an account are still buffered after its last subscriber left
+
+	ReconnectMinBackoff = 2 * time.Second // First delay before reconnecting a dropped account connection
+	ReconnectMaxBackoff = 5 * time.Minute // Upper bound of the exponential reconnect delay
+	ConnectionHistory   = 1000            // Connection events kept per account, older ones are pruned, 0 keeps them all
 )
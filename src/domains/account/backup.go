@@ -0,0 +1,64 @@
+package account
+
+import (
+	"io"
+	"mime/multipart"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+)
+
+// BackupVersion is the version of the account backup format
+const BackupVersion = 1
+
+// BackupRequest exports the session of an account. Release disconnects the session before it is
+// copied and keeps this instance from reconnecting it, so it can be restored elsewhere.
+type BackupRequest struct {
+	AccountID    string `json:"account_id" uri:"accountId"`
+	Passphrase   string `json:"passphrase"`
+	IncludeChats bool   `json:"include_chats" query:"include_chats"`
+	Release      bool   `json:"release" query:"release"`
+}
+
+// AccountBackup is a prepared backup, Write streams the encrypted archive once the response headers are sent
+type AccountBackup struct {
+	Filename string
+	Write    func(w io.Writer) error
+}
+
+// BackupManifest describes the account and the session of a backup, it is stored in the archive
+// next to the copies of the device and keys stores
+type BackupManifest struct {
+	Version     int                      `json:"version"`
+	AccountID   string                   `json:"account_id"`
+	CreatedAt   time.Time                `json:"created_at"`
+	DeviceID    string                   `json:"device_id,omitempty"`
+	PhoneNumber string                   `json:"phone_number,omitempty"`
+	Connected   bool                     `json:"connected"` // the session was connected when the backup was taken
+	Released    bool                     `json:"released"`  // the session was disconnected on the source for the backup
+	KeysStore   bool                     `json:"keys_store"`
+	ChatStorage bool                     `json:"chat_storage"`
+	Account     Account                  `json:"account"`
+	Webhook     WebhookInfo              `json:"webhook"`
+	Endpoints   []domainWebhook.Endpoint `json:"endpoints"`
+	Settings    Settings                 `json:"settings"`
+}
+
+// RestoreRequest rehydrates an account from a backup. Force restores a session that wasn't released
+// when the backup was taken, once the source instance is known to be shut down.
+type RestoreRequest struct {
+	AccountID  string                `json:"account_id" uri:"accountId"`
+	Backup     *multipart.FileHeader `json:"backup" form:"backup"`
+	Passphrase string                `json:"passphrase" form:"passphrase"`
+	Force      bool                  `json:"force" form:"force"`
+}
+
+type RestoreResponse struct {
+	AccountID        string `json:"account_id"`
+	DeviceID         string `json:"device_id,omitempty"`
+	PhoneNumber      string `json:"phone_number,omitempty"`
+	IsConnected      bool   `json:"is_connected"`
+	WebhookEndpoints int    `json:"webhook_endpoints"`
+	Chats            int    `json:"chats"`
+	Messages         int    `json:"messages"`
+}
@@ -14,11 +14,18 @@ type IAccountUsecase interface {
 	ListAccounts(ctx context.Context) (response []AccountInfo, err error)
 	GetAccount(ctx context.Context, accountID string) (response AccountInfo, err error)
 	LoginAccount(ctx context.Context, accountID string) (response LoginResponse, err error)
+	LoginAccountStream(ctx context.Context, accountID string) (events <-chan LoginEvent, err error)
 	LoginAccountWithCode(ctx context.Context, accountID string, phoneNumber string) (loginCode string, err error)
 	LogoutAccount(ctx context.Context, accountID string) (err error)
 	ReconnectAccount(ctx context.Context, accountID string) (err error)
 	SetAccountWebhook(ctx context.Context, accountID string, webhookURL string, secret string) (err error)
 	GetAccountWebhook(ctx context.Context, accountID string) (webhook WebhookInfo, err error)
+	RestoreAccounts(ctx context.Context) (err error)
+	GetConnectionHistory(ctx context.Context, request ConnectionHistoryRequest) (response ConnectionHistoryResponse, err error)
+	GetSettings(ctx context.Context, accountID string) (response SettingsResponse, err error)
+	UpdateSettings(ctx context.Context, request UpdateSettingsRequest) (response SettingsResponse, err error)
+	BackupAccount(ctx context.Context, request BackupRequest) (backup AccountBackup, err error)
+	RestoreAccount(ctx context.Context, request RestoreRequest) (response RestoreResponse, err error)
 }
 
 type IAccountRepository interface {
@@ -29,6 +36,11 @@ type IAccountRepository interface {
 	ListAccounts() ([]*Account, error)
 	SetWebhook(accountID string, webhookURL string, secret string) error
 	GetWebhook(accountID string) (*WebhookInfo, error)
+	AddConnectionEvent(event *ConnectionEvent) error
+	ListConnectionEvents(accountID string, limit, offset int) ([]*ConnectionEvent, error)
+	PruneConnectionEvents(accountID string, keep int) error
+	GetSettings(accountID string) (*Settings, error)
+	SaveSettings(accountID string, settings *Settings) error
 }
 
 type IAccountManager interface {
@@ -63,6 +75,37 @@ type LoginResponse struct {
 	Code      string        `json:"code"`
 }
 
+// LoginEvent is pushed on the QR login stream, a code event for every new QR code followed by
+// a single success, timeout or error event
+type LoginEvent struct {
+	Event   string `json:"event"`
+	Code    string `json:"code,omitempty"`
+	Image   string `json:"image,omitempty"`   // QR code as a data URI PNG
+	Timeout int    `json:"timeout,omitempty"` // seconds until the next code is pushed
+	Message string `json:"message,omitempty"`
+}
+
+// Events of the QR login stream
+const (
+	LoginEventCode    = "code"
+	LoginEventSuccess = "success"
+	LoginEventTimeout = "timeout"
+	LoginEventError   = "error"
+)
+
+// ConnectionHistoryResponse lists the connection state transitions of an account, newest first
+type ConnectionHistoryResponse struct {
+	Events []ConnectionEvent `json:"events"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+type ConnectionHistoryRequest struct {
+	AccountID string `json:"account_id" uri:"accountId"`
+	Limit     int    `json:"limit" query:"limit"`
+	Offset    int    `json:"offset" query:"offset"`
+}
+
 type WebhookInfo struct {
 	URL    string `json:"url"`
 	Secret string `json:"secret"`
@@ -78,8 +121,30 @@ type Account struct {
 	LastConnected time.Time `json:"last_connected" db:"last_connected"`
 }
 
+// ConnectionEvent is a single state transition recorded by the connection supervisor
+type ConnectionEvent struct {
+	ID        int64     `json:"id" db:"id"`
+	AccountID string    `json:"account_id" db:"account_id"`
+	State     string    `json:"state" db:"state"`
+	Reason    string    `json:"reason,omitempty" db:"reason"`
+	Attempt   int       `json:"attempt,omitempty" db:"attempt"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Connection states recorded in the connection history
+const (
+	ConnectionConnected       = "connected"
+	ConnectionDisconnected    = "disconnected"
+	ConnectionLoggedOut       = "logged_out"
+	ConnectionStreamReplaced  = "stream_replaced"
+	ConnectionReconnecting    = "reconnecting"
+	ConnectionReconnectFailed = "reconnect_failed"
+	ConnectionReleased        = "released"
+)
+
 const (
 	StatusDisconnected = "disconnected"
 	StatusConnected    = "connected"
 	StatusLoggedIn     = "logged_in"
+	StatusReleased     = "released" // the session was handed to another instance by a backup
 )
\ No newline at end of file
@@ -0,0 +1,61 @@
+package account
+
+// Settings overrides the global configuration for a single account. A nil field uses the global value.
+type Settings struct {
+	AutoMarkRead         *bool   `json:"auto_mark_read"`
+	AutoReplyMessage     *string `json:"auto_reply_message"`
+	DeviceName           *string `json:"device_name"`
+	MaxImageSize         *int64  `json:"max_image_size"`
+	MaxFileSize          *int64  `json:"max_file_size"`
+	MaxVideoSize         *int64  `json:"max_video_size"`
+	DisappearingDuration *int    `json:"disappearing_duration"` // seconds, applied when a send request sets no duration
+	WebhookSecret        *string `json:"webhook_secret,omitempty"`
+}
+
+// EffectiveSettings are the values in use for an account, its overrides merged over the global configuration
+type EffectiveSettings struct {
+	AutoMarkRead         bool   `json:"auto_mark_read"`
+	AutoReplyMessage     string `json:"auto_reply_message"`
+	DeviceName           string `json:"device_name"`
+	MaxImageSize         int64  `json:"max_image_size"`
+	MaxFileSize          int64  `json:"max_file_size"`
+	MaxVideoSize         int64  `json:"max_video_size"`
+	DisappearingDuration int    `json:"disappearing_duration"`
+	WebhookSecret        string `json:"webhook_secret,omitempty"`
+}
+
+// Names of the settings, as used in the JSON fields and in UpdateSettingsRequest.Reset
+const (
+	SettingAutoMarkRead         = "auto_mark_read"
+	SettingAutoReplyMessage     = "auto_reply_message"
+	SettingDeviceName           = "device_name"
+	SettingMaxImageSize         = "max_image_size"
+	SettingMaxFileSize          = "max_file_size"
+	SettingMaxVideoSize         = "max_video_size"
+	SettingDisappearingDuration = "disappearing_duration"
+	SettingWebhookSecret        = "webhook_secret"
+)
+
+// SettingNames lists every setting an account can override
+var SettingNames = []string{
+	SettingAutoMarkRead, SettingAutoReplyMessage, SettingDeviceName, SettingMaxImageSize,
+	SettingMaxFileSize, SettingMaxVideoSize, SettingDisappearingDuration, SettingWebhookSecret,
+}
+
+// SettingsResponse shows the overrides of an account next to the values in use.
+// The webhook secret is left out, only whether the account overrides it and whether one is in use is reported.
+type SettingsResponse struct {
+	AccountID               string            `json:"account_id"`
+	Overrides               Settings          `json:"overrides"`
+	Effective               EffectiveSettings `json:"effective"`
+	WebhookSecretOverridden bool              `json:"webhook_secret_overridden"`
+	WebhookSecretSet        bool              `json:"webhook_secret_set"`
+}
+
+// UpdateSettingsRequest changes the overrides of an account. Fields left out are kept as they are,
+// and the settings named in Reset go back to the global value.
+type UpdateSettingsRequest struct {
+	AccountID string `json:"account_id" uri:"accountId"`
+	Settings
+	Reset []string `json:"reset"`
+}
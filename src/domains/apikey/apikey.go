@@ -0,0 +1,89 @@
+package apikey
+
+import (
+	"context"
+	"time"
+)
+
+// Scopes an API key can be granted. Admin implies send and read.
+const (
+	ScopeSend  = "send"
+	ScopeRead  = "read"
+	ScopeAdmin = "admin"
+)
+
+// Scopes lists every scope accepted when creating a key
+var Scopes = []string{ScopeSend, ScopeRead, ScopeAdmin}
+
+// AllAccounts in AccountIDs lets a key reach every account
+const AllAccounts = "*"
+
+// APIKey grants access to a set of accounts. Only the SHA-256 hash of the key is stored.
+type APIKey struct {
+	ID         string     `json:"id" db:"id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	AccountIDs []string   `json:"account_ids" db:"account_ids"`
+	Scopes     []string   `json:"scopes" db:"scopes"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// AllowsAllAccounts reports whether the key may reach every account
+func (k APIKey) AllowsAllAccounts() bool {
+	return k.AllowsAccount(AllAccounts)
+}
+
+// AllowsAccount reports whether the key may act on the given account
+func (k APIKey) AllowsAccount(accountID string) bool {
+	for _, allowed := range k.AccountIDs {
+		if allowed == AllAccounts || allowed == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the key was granted the scope, admin keys have every scope
+func (k APIKey) HasScope(scope string) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+// WithAPIKey returns a copy of ctx carrying the API key the request authenticated with
+func WithAPIKey(ctx context.Context, key *APIKey) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// FromContext returns the API key the request authenticated with, nil when it used basic auth
+// or doesn't come from the REST API
+func FromContext(ctx context.Context) *APIKey {
+	key, _ := ctx.Value(contextKey{}).(*APIKey)
+	return key
+}
+
+// Request and Response structures for API key operations
+
+type CreateAPIKeyRequest struct {
+	Name       string   `json:"name"`
+	AccountIDs []string `json:"account_ids"`
+	Scopes     []string `json:"scopes"`
+}
+
+// CreateAPIKeyResponse carries the plain key, which is only shown once
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+type RevokeAPIKeyRequest struct {
+	KeyID string `json:"key_id" uri:"keyId"`
+}
@@ -0,0 +1,25 @@
+package apikey
+
+import (
+	"context"
+	"time"
+)
+
+// IAPIKeyUsecase defines the interface for API key management and authentication
+type IAPIKeyUsecase interface {
+	CreateAPIKey(ctx context.Context, request CreateAPIKeyRequest) (response CreateAPIKeyResponse, err error)
+	ListAPIKeys(ctx context.Context) (response []APIKey, err error)
+	RevokeAPIKey(ctx context.Context, request RevokeAPIKeyRequest) (err error)
+	// Authenticate returns the active key matching the plain key
+	Authenticate(ctx context.Context, key string) (response *APIKey, err error)
+}
+
+// IAPIKeyRepository persists API keys
+type IAPIKeyRepository interface {
+	CreateAPIKey(key *APIKey) error
+	GetAPIKey(keyID string) (*APIKey, error)
+	GetAPIKeyByHash(keyHash string) (*APIKey, error)
+	ListAPIKeys() ([]*APIKey, error)
+	RevokeAPIKey(keyID string, revokedAt time.Time) error
+	TouchAPIKey(keyID string, usedAt time.Time) error
+}
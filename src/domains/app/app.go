@@ -6,12 +6,13 @@ import (
 )
 
 type IAppUsecase interface {
-	Login(ctx context.Context) (response LoginResponse, err error)
-	LoginWithCode(ctx context.Context, phoneNumber string) (loginCode string, err error)
-	Logout(ctx context.Context) (err error)
-	Reconnect(ctx context.Context) (err error)
-	FirstDevice(ctx context.Context) (response DevicesResponse, err error)
-	FetchDevices(ctx context.Context) (response []DevicesResponse, err error)
+	// An empty accountID targets the legacy device, any other the client of that account
+	Login(ctx context.Context, accountID string) (response LoginResponse, err error)
+	LoginWithCode(ctx context.Context, accountID string, phoneNumber string) (loginCode string, err error)
+	Logout(ctx context.Context, accountID string) (err error)
+	Reconnect(ctx context.Context, accountID string) (err error)
+	FirstDevice(ctx context.Context, accountID string) (response DevicesResponse, err error)
+	FetchDevices(ctx context.Context, accountID string) (response []DevicesResponse, err error)
 }
 
 type DevicesResponse struct {
@@ -0,0 +1,116 @@
+package autoreply
+
+import "time"
+
+// Matchers decide which incoming texts a rule replies to
+const (
+	MatchKeyword = "keyword" // Pattern is a comma separated list of words or phrases
+	MatchRegex   = "regex"   // Pattern is a regular expression
+	MatchAny     = "any"     // Every incoming text, e.g. for out-of-hours replies
+)
+
+// Reply types of a rule
+const (
+	ReplyText     = "text"
+	ReplyTemplate = "template" // Sends the message template with TemplateID
+	ReplyMedia    = "media"    // Sends the image or video at MediaURL with ReplyText as caption
+)
+
+// Media types a media reply can carry
+const (
+	MediaTypeImage = "image"
+	MediaTypeVideo = "video"
+)
+
+// Rule is an auto-reply of an account. Rules are evaluated by ascending priority and only the
+// first matching rule replies. ReplyText may contain the {{name}} and {{phone}} placeholders.
+type Rule struct {
+	ID            string `json:"id" db:"id"`
+	AccountID     string `json:"account_id" db:"account_id"`
+	Name          string `json:"name" db:"name"`
+	Enabled       bool   `json:"enabled" db:"enabled"`
+	Priority      int    `json:"priority" db:"priority"`
+	MatchType     string `json:"match_type" db:"match_type"`
+	Pattern       string `json:"pattern" db:"pattern"`
+	CaseSensitive bool   `json:"case_sensitive" db:"case_sensitive"`
+	IncludeGroups bool   `json:"include_groups" db:"include_groups"`
+
+	// ActiveHours (HH:MM-HH:MM) and ActiveDays (mon..sun) limit the rule to a time window
+	// in Timezone. Empty values mean always.
+	ActiveHours string   `json:"active_hours" db:"active_hours"`
+	ActiveDays  []string `json:"active_days" db:"active_days"`
+	Timezone    string   `json:"timezone" db:"timezone"`
+
+	// CooldownMinutes keeps the rule from replying to the same contact again within this period
+	CooldownMinutes int `json:"cooldown_minutes" db:"cooldown_minutes"`
+
+	ReplyType  string `json:"reply_type" db:"reply_type"`
+	ReplyText  string `json:"reply_text" db:"reply_text"`
+	TemplateID string `json:"template_id,omitempty" db:"template_id"`
+	MediaURL   string `json:"media_url,omitempty" db:"media_url"`
+	MediaType  string `json:"media_type,omitempty" db:"media_type"`
+
+	HitCount  int64      `json:"hit_count" db:"hit_count"`
+	LastHitAt *time.Time `json:"last_hit_at" db:"last_hit_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IncomingMessage is the part of a received message the rules are evaluated against
+type IncomingMessage struct {
+	AccountID string
+	ChatJID   string
+	SenderJID string
+	PushName  string
+	Text      string
+	IsGroup   bool
+	Timestamp time.Time
+}
+
+// Request structures for auto-reply operations
+
+type RuleRequest struct {
+	AccountID       string   `json:"account_id" uri:"accountId"`
+	RuleID          string   `json:"rule_id" uri:"ruleId"`
+	Name            string   `json:"name"`
+	Enabled         *bool    `json:"enabled"`
+	Priority        int      `json:"priority"`
+	MatchType       string   `json:"match_type"`
+	Pattern         string   `json:"pattern"`
+	CaseSensitive   bool     `json:"case_sensitive"`
+	IncludeGroups   bool     `json:"include_groups"`
+	ActiveHours     string   `json:"active_hours"`
+	ActiveDays      []string `json:"active_days"`
+	Timezone        string   `json:"timezone"`
+	CooldownMinutes int      `json:"cooldown_minutes"`
+	ReplyType       string   `json:"reply_type"`
+	ReplyText       string   `json:"reply_text"`
+	TemplateID      string   `json:"template_id"`
+	MediaURL        string   `json:"media_url"`
+	MediaType       string   `json:"media_type"`
+}
+
+type RuleIdentifierRequest struct {
+	AccountID string `json:"account_id" uri:"accountId"`
+	RuleID    string `json:"rule_id" uri:"ruleId"`
+}
+
+// TestRequest evaluates the rules of an account against a text without replying
+type TestRequest struct {
+	AccountID string `json:"account_id" uri:"accountId"`
+	Text      string `json:"text"`
+	Phone     string `json:"phone"`    // Optional, checks the cooldown of this contact and fills {{phone}}
+	Name      string `json:"name"`     // Optional, fills {{name}}
+	IsGroup   bool   `json:"is_group"` // Evaluate as a message received in a group
+	At        string `json:"at"`       // Optional RFC3339 time to evaluate the time windows at, defaults to now
+}
+
+type TestResponse struct {
+	Matched bool  `json:"matched"`
+	Rule    *Rule `json:"rule,omitempty"`
+	// CoolingDown is set when the rule matched but already replied to the contact within the cooldown
+	CoolingDown bool   `json:"cooling_down"`
+	ReplyType   string `json:"reply_type,omitempty"`
+	Reply       string `json:"reply,omitempty"`
+	MediaURL    string `json:"media_url,omitempty"`
+}
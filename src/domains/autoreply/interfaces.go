@@ -0,0 +1,33 @@
+package autoreply
+
+import (
+	"context"
+	"time"
+)
+
+// IAutoReplyUsecase manages the auto-reply rules of an account and replies to incoming messages with them
+type IAutoReplyUsecase interface {
+	CreateRule(ctx context.Context, request RuleRequest) (response Rule, err error)
+	ListRules(ctx context.Context, accountID string) (response []Rule, err error)
+	GetRule(ctx context.Context, request RuleIdentifierRequest) (response Rule, err error)
+	UpdateRule(ctx context.Context, request RuleRequest) (response Rule, err error)
+	DeleteRule(ctx context.Context, request RuleIdentifierRequest) (err error)
+	TestRules(ctx context.Context, request TestRequest) (response TestResponse, err error)
+
+	// Reply answers an incoming message with the first matching rule and reports whether a rule matched
+	Reply(ctx context.Context, message IncomingMessage) (handled bool, err error)
+}
+
+// IAutoReplyRepository persists auto-reply rules, their hit counters and the last reply per contact
+type IAutoReplyRepository interface {
+	CreateRule(rule *Rule) error
+	GetRule(accountID, ruleID string) (*Rule, error)
+	ListRules(accountID string) ([]*Rule, error) // Ordered by priority, then creation
+	UpdateRule(rule *Rule) error
+	DeleteRule(accountID, ruleID string) error
+
+	// RecordHit increments the hit counter of the rule and remembers when it replied to the contact
+	RecordHit(ruleID, contactJID string, at time.Time) error
+	// GetLastReply returns when the rule last replied to the contact, or the zero time
+	GetLastReply(ruleID, contactJID string) (time.Time, error)
+}
@@ -0,0 +1,77 @@
+package chat
+
+import "time"
+
+// ArchiveVersion is written in the header of every archive, importing a newer version is refused
+const ArchiveVersion = 1
+
+const (
+	ArchiveRecordHeader  = "archive"
+	ArchiveRecordChat    = "chat"
+	ArchiveRecordMessage = "message"
+)
+
+// ArchiveRecord is one line of a JSON Lines chat archive. The header comes first,
+// each chat is followed by its messages oldest first.
+type ArchiveRecord struct {
+	Type    string          `json:"type"`
+	Archive *ArchiveHeader  `json:"archive,omitempty"`
+	Chat    *ArchiveChat    `json:"chat,omitempty"`
+	Message *ArchiveMessage `json:"message,omitempty"`
+}
+
+type ArchiveHeader struct {
+	Version    int       `json:"version"`
+	AccountID  string    `json:"account_id"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+type ArchiveChat struct {
+	JID                 string    `json:"jid"`
+	Name                string    `json:"name"`
+	LastMessageTime     time.Time `json:"last_message_time"`
+	EphemeralExpiration uint32    `json:"ephemeral_expiration"`
+}
+
+// ArchiveMessage keeps the media key and hashes, so the media can still be downloaded after an import.
+// MediaPath is the location of the media inside a zip export, MediaError why it could not be bundled.
+type ArchiveMessage struct {
+	ID            string            `json:"id"`
+	ChatJID       string            `json:"chat_jid"`
+	SenderJID     string            `json:"sender_jid"`
+	Content       string            `json:"content"`
+	Timestamp     time.Time         `json:"timestamp"`
+	IsFromMe      bool              `json:"is_from_me"`
+	MediaType     string            `json:"media_type,omitempty"`
+	Filename      string            `json:"filename,omitempty"`
+	URL           string            `json:"url,omitempty"`
+	MediaKey      []byte            `json:"media_key,omitempty"`
+	FileSHA256    []byte            `json:"file_sha256,omitempty"`
+	FileEncSHA256 []byte            `json:"file_enc_sha256,omitempty"`
+	FileLength    uint64            `json:"file_length,omitempty"`
+	MediaPath     string            `json:"media_path,omitempty"`
+	MediaError    string            `json:"media_error,omitempty"`
+	IsRevoked     bool              `json:"is_revoked,omitempty"`
+	EditHistory   []ArchiveEdit     `json:"edit_history,omitempty"`
+	Reactions     []ArchiveReaction `json:"reactions,omitempty"`
+	Receipts      []ArchiveReceipt  `json:"receipts,omitempty"`
+}
+
+type ArchiveEdit struct {
+	PreviousContent string    `json:"previous_content"`
+	Content         string    `json:"content"`
+	EditedAt        time.Time `json:"edited_at"`
+}
+
+type ArchiveReaction struct {
+	SenderJID string    `json:"sender_jid"`
+	Emoji     string    `json:"emoji"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type ArchiveReceipt struct {
+	Recipient   string     `json:"recipient"`
+	Participant string     `json:"participant,omitempty"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+}
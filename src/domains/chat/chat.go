@@ -1,12 +1,15 @@
 package chat
 
+import "io"
+
 // Request and Response structures for chat operations
 
 type ListChatsRequest struct {
-	Limit    int    `json:"limit" query:"limit"`
-	Offset   int    `json:"offset" query:"offset"`
-	Search   string `json:"search" query:"search"`
-	HasMedia bool   `json:"has_media" query:"has_media"`
+	AccountID string `json:"account_id" query:"account_id"`
+	Limit     int    `json:"limit" query:"limit"`
+	Offset    int    `json:"offset" query:"offset"`
+	Search    string `json:"search" query:"search"`
+	HasMedia  bool   `json:"has_media" query:"has_media"`
 }
 
 type ListChatsResponse struct {
@@ -15,6 +18,7 @@ type ListChatsResponse struct {
 }
 
 type GetChatMessagesRequest struct {
+	AccountID string  `json:"account_id" query:"account_id"`
 	ChatJID   string  `json:"chat_jid" uri:"chat_jid"`
 	Limit     int     `json:"limit" query:"limit"`
 	Offset    int     `json:"offset" query:"offset"`
@@ -31,10 +35,37 @@ type GetChatMessagesResponse struct {
 	ChatInfo   ChatInfo           `json:"chat_info"`
 }
 
+type SearchMessagesRequest struct {
+	AccountID string  `json:"account_id" query:"account_id"`
+	Query     string  `json:"q" query:"q"`
+	ChatJID   string  `json:"chat_jid" query:"chat_jid"`
+	Sender    string  `json:"sender" query:"sender"`
+	MediaType string  `json:"media_type" query:"media_type"`
+	MediaOnly bool    `json:"media_only" query:"media_only"`
+	IsFromMe  *bool   `json:"is_from_me" query:"is_from_me"`
+	StartTime *string `json:"start_time" query:"start_time"`
+	EndTime   *string `json:"end_time" query:"end_time"`
+	Limit     int     `json:"limit" query:"limit"`
+	Offset    int     `json:"offset" query:"offset"`
+}
+
+type SearchMessagesResponse struct {
+	Data       []MessageSearchResult `json:"data"`
+	Pagination PaginationResponse    `json:"pagination"`
+}
+
+// MessageSearchResult is a matching message, Snippet highlights the matched words with <mark> tags
+type MessageSearchResult struct {
+	MessageInfo
+	Snippet string  `json:"snippet"`
+	Score   float64 `json:"score"`
+}
+
 // Pin Chat operations
 type PinChatRequest struct {
-	ChatJID string `json:"chat_jid" uri:"chat_jid"`
-	Pinned  bool   `json:"pinned"`
+	AccountID string `json:"account_id" form:"account_id"`
+	ChatJID   string `json:"chat_jid" uri:"chat_jid"`
+	Pinned    bool   `json:"pinned"`
 }
 
 type PinChatResponse struct {
@@ -53,19 +84,39 @@ type ChatInfo struct {
 	UpdatedAt           string `json:"updated_at"`
 }
 
+// MessageInfo holds the latest content of a message, older versions are listed in EditHistory
 type MessageInfo struct {
-	ID         string `json:"id"`
-	ChatJID    string `json:"chat_jid"`
-	SenderJID  string `json:"sender_jid"`
-	Content    string `json:"content"`
-	Timestamp  string `json:"timestamp"`
-	IsFromMe   bool   `json:"is_from_me"`
-	MediaType  string `json:"media_type"`
-	Filename   string `json:"filename"`
-	URL        string `json:"url"`
-	FileLength uint64 `json:"file_length"`
-	CreatedAt  string `json:"created_at"`
-	UpdatedAt  string `json:"updated_at"`
+	ID          string            `json:"id"`
+	ChatJID     string            `json:"chat_jid"`
+	SenderJID   string            `json:"sender_jid"`
+	Content     string            `json:"content"`
+	Timestamp   string            `json:"timestamp"`
+	IsFromMe    bool              `json:"is_from_me"`
+	MediaType   string            `json:"media_type"`
+	Filename    string            `json:"filename"`
+	URL         string            `json:"url"`
+	FileLength  uint64            `json:"file_length"`
+	IsRevoked   bool              `json:"is_revoked"`
+	EditCount   int               `json:"edit_count"`
+	EditHistory []MessageEditInfo `json:"edit_history,omitempty"`
+	Reactions   []ReactionInfo    `json:"reactions"`
+	DeliveredAt string            `json:"delivered_at,omitempty"`
+	ReadAt      string            `json:"read_at,omitempty"`
+	CreatedAt   string            `json:"created_at"`
+	UpdatedAt   string            `json:"updated_at"`
+}
+
+type MessageEditInfo struct {
+	PreviousContent string `json:"previous_content"`
+	Content         string `json:"content"`
+	EditedAt        string `json:"edited_at"`
+}
+
+// ReactionInfo aggregates the senders that reacted to a message with the same emoji
+type ReactionInfo struct {
+	Emoji   string   `json:"emoji"`
+	Count   int      `json:"count"`
+	Senders []string `json:"senders"`
 }
 
 type PaginationResponse struct {
@@ -73,3 +124,38 @@ type PaginationResponse struct {
 	Offset int `json:"offset"`
 	Total  int `json:"total"`
 }
+
+const (
+	ExportFormatJSONL = "jsonl"
+	ExportFormatTXT   = "txt"
+)
+
+// ExportChatsRequest exports one chat, or every chat of the account when ChatJID is empty
+type ExportChatsRequest struct {
+	AccountID    string  `json:"account_id" query:"account_id"`
+	ChatJID      string  `json:"chat_jid" uri:"chat_jid"`
+	Format       string  `json:"format" query:"format"`
+	IncludeMedia bool    `json:"include_media" query:"include_media"`
+	StartTime    *string `json:"start_time" query:"start_time"`
+	EndTime      *string `json:"end_time" query:"end_time"`
+}
+
+// ChatExport is a validated export, Write streams it once the response headers are sent
+type ChatExport struct {
+	Filename    string
+	ContentType string
+	Write       func(w io.Writer) error
+}
+
+// ImportChatsRequest loads a JSON Lines archive, AccountID overrides the account recorded in the archive
+type ImportChatsRequest struct {
+	AccountID string
+	Archive   io.Reader
+}
+
+type ImportChatsResponse struct {
+	AccountID       string `json:"account_id"`
+	Chats           int    `json:"chats"`
+	Messages        int    `json:"messages"`
+	SkippedMessages int    `json:"skipped_messages"`
+}
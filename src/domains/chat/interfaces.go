@@ -8,5 +8,8 @@ import (
 type IChatUsecase interface {
 	ListChats(ctx context.Context, request ListChatsRequest) (response ListChatsResponse, err error)
 	GetChatMessages(ctx context.Context, request GetChatMessagesRequest) (response GetChatMessagesResponse, err error)
+	SearchMessages(ctx context.Context, request SearchMessagesRequest) (response SearchMessagesResponse, err error)
 	PinChat(ctx context.Context, request PinChatRequest) (response PinChatResponse, err error)
+	ExportChats(ctx context.Context, request ExportChatsRequest) (export ChatExport, err error)
+	ImportChats(ctx context.Context, request ImportChatsRequest) (response ImportChatsResponse, err error)
 }
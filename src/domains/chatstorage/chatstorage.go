@@ -4,6 +4,7 @@ import "time"
 
 // Chat represents a WhatsApp chat/conversation
 type Chat struct {
+	AccountID           string    `db:"account_id"`
 	JID                 string    `db:"jid"`
 	Name                string    `db:"name"`
 	LastMessageTime     time.Time `db:"last_message_time"`
@@ -14,6 +15,7 @@ type Chat struct {
 
 // Message represents a WhatsApp message
 type Message struct {
+	AccountID     string    `db:"account_id"`
 	ID            string    `db:"id"`
 	ChatJID       string    `db:"chat_jid"`
 	Sender        string    `db:"sender"`
@@ -27,10 +29,54 @@ type Message struct {
 	FileSHA256    []byte    `db:"file_sha256"`
 	FileEncSHA256 []byte    `db:"file_enc_sha256"`
 	FileLength    uint64    `db:"file_length"`
+	IsRevoked     bool      `db:"is_revoked"`
+	EditCount     int       `db:"edit_count"`
 	CreatedAt     time.Time `db:"created_at"`
 	UpdatedAt     time.Time `db:"updated_at"`
 }
 
+// MessageReaction represents the current reaction of one sender to a message
+type MessageReaction struct {
+	AccountID string    `db:"account_id"`
+	ChatJID   string    `db:"chat_jid"`
+	MessageID string    `db:"message_id"`
+	Sender    string    `db:"sender"`
+	Emoji     string    `db:"emoji"`
+	Timestamp time.Time `db:"timestamp"`
+}
+
+// MessageEdit represents one edit of a message, keeping the content it replaced
+type MessageEdit struct {
+	AccountID       string    `db:"account_id"`
+	ChatJID         string    `db:"chat_jid"`
+	MessageID       string    `db:"message_id"`
+	PreviousContent string    `db:"previous_content"`
+	Content         string    `db:"content"`
+	EditedAt        time.Time `db:"edited_at"`
+}
+
+// MessageReceipt represents the delivery and read state of a message for one recipient device.
+// In groups the participant is the member the device belongs to.
+type MessageReceipt struct {
+	AccountID   string     `db:"account_id"`
+	ChatJID     string     `db:"chat_jid"`
+	MessageID   string     `db:"message_id"`
+	Recipient   string     `db:"recipient"`
+	Participant string     `db:"participant"`
+	DeliveredAt *time.Time `db:"delivered_at"`
+	ReadAt      *time.Time `db:"read_at"`
+}
+
+// RetentionPolicy overrides the global retention for an account, or for one chat when ChatJID is set.
+// A MaxMessageDays of 0 keeps the messages forever.
+type RetentionPolicy struct {
+	AccountID      string    `db:"account_id"`
+	ChatJID        string    `db:"chat_jid"`
+	MaxMessageDays int       `db:"max_message_days"`
+	KeepStarred    bool      `db:"keep_starred"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
 // MediaInfo represents downloadable media information
 type MediaInfo struct {
 	MessageID     string
@@ -46,6 +92,7 @@ type MediaInfo struct {
 
 // MessageFilter represents query filters for messages
 type MessageFilter struct {
+	AccountID string
 	ChatJID   string
 	Limit     int
 	Offset    int
@@ -53,10 +100,48 @@ type MessageFilter struct {
 	EndTime   *time.Time
 	MediaOnly bool
 	IsFromMe  *bool
+	// OldestFirst pages through a chat in chronological order, as exports do
+	OldestFirst bool
+}
+
+// MessageSearchFilter represents a full-text search across the chats of an account
+type MessageSearchFilter struct {
+	AccountID string
+	Query     string
+	ChatJID   string
+	Sender    string
+	MediaType string
+	MediaOnly bool
+	IsFromMe  *bool
+	StartTime *time.Time
+	EndTime   *time.Time
+	Limit     int
+	Offset    int
+}
+
+// MessageSearchResult is a message matching a search, with the matched text highlighted in Snippet.
+// A higher Score is a better match.
+type MessageSearchResult struct {
+	Message *Message
+	Snippet string
+	Score   float64
+}
+
+// MessagePruneFilter selects the messages older than Before that a retention run removes.
+// Without AllAccounts only the account, or the chat when ChatJID is set, is pruned.
+// Except lists the accounts and chats governed by a policy of their own.
+type MessagePruneFilter struct {
+	Before      time.Time
+	KeepStarred bool
+	AllAccounts bool
+	AccountID   string
+	ChatJID     string
+	Except      []*RetentionPolicy
 }
 
 // ChatFilter represents query filters for chats
 type ChatFilter struct {
+	AccountID  string
 	Limit      int
 	Offset     int
 	SearchName string
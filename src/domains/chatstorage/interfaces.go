@@ -8,29 +8,53 @@ import (
 	"go.mau.fi/whatsmeow/types/events"
 )
 
+// IChatStorageRepository stores chats and messages scoped by account ID.
+// The empty account ID holds the data of the legacy single-device client.
 type IChatStorageRepository interface {
 	// Chat operations
-	CreateMessage(ctx context.Context, evt *events.Message) error
+	CreateMessage(ctx context.Context, accountID string, evt *events.Message) error
 	StoreChat(chat *Chat) error
-	GetChat(jid string) (*Chat, error)
+	GetChat(accountID, jid string) (*Chat, error)
 	GetChats(filter *ChatFilter) ([]*Chat, error)
-	DeleteChat(jid string) error
+	DeleteChat(accountID, jid string) error
 
 	// Message operations
 	StoreMessage(message *Message) error
 	StoreMessagesBatch(messages []*Message) error
-	GetMessageByID(id string) (*Message, error) // New method for efficient ID-only search
+	GetMessageByID(accountID, id string) (*Message, error) // New method for efficient ID-only search
 	GetMessages(filter *MessageFilter) ([]*Message, error)
-	SearchMessages(chatJID, searchText string, limit int) ([]*Message, error) // Database-level search
-	DeleteMessage(id, chatJID string) error
-	StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error
+	SearchMessages(accountID, chatJID, searchText string, limit int) ([]*Message, error) // Database-level search
+	SearchAllMessages(filter *MessageSearchFilter) ([]*MessageSearchResult, int64, error) // Ranked search across chats with the total match count
+	DeleteMessage(accountID, id, chatJID string) error
+	StoreSentMessageWithContext(ctx context.Context, accountID string, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error
+
+	// Reaction, edit and revocation operations
+	StoreReaction(reaction *MessageReaction) error // An empty emoji removes the sender's reaction
+	StoreMessageEdit(edit *MessageEdit) error
+	MarkMessageRevoked(accountID, id, chatJID string) error
+	GetReactions(accountID, chatJID string, messageIDs []string) ([]*MessageReaction, error)
+	GetMessageEdits(accountID, chatJID string, messageIDs []string) ([]*MessageEdit, error)
+
+	// Receipt operations
+	StoreReceipt(receipt *MessageReceipt) error // Only the earliest delivery and read times are kept
+	GetReceipts(accountID string, messageIDs []string) ([]*MessageReceipt, error)
+
+	// Retention operations
+	SetMessageStarred(accountID, chatJID, id string, starred bool) error
+	GetRetentionPolicies() ([]*RetentionPolicy, error)
+	StoreRetentionPolicy(policy *RetentionPolicy) error
+	DeleteRetentionPolicy(accountID, chatJID string) error
+	PruneMessages(filter *MessagePruneFilter) (int64, error) // Also removes the reactions, edits and receipts of pruned messages
+	Vacuum() error
 
 	// Statistics
-	GetChatMessageCount(chatJID string) (int64, error)
+	GetChatMessageCount(accountID, chatJID string) (int64, error)
+	GetAccountChatCount(accountID string) (int64, error)
 	GetTotalMessageCount() (int64, error)
 	GetTotalChatCount() (int64, error)
-	GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string
+	GetChatNameWithPushName(accountID string, jid types.JID, chatJID string, senderUser string, pushName string) string
 	GetStorageStatistics() (chatCount int64, messageCount int64, err error)
+	GetDatabaseSize() (int64, error)
 
 	// Cleanup operations
 	TruncateAllChats() error
@@ -0,0 +1,59 @@
+package event
+
+import (
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+)
+
+// Event types only delivered through the live event stream, next to the webhook event types
+const (
+	EventPresence   = "presence"
+	EventConnection = "connection"
+)
+
+// Connection states carried by the connection event
+const (
+	ConnectionConnected      = "connected"
+	ConnectionDisconnected   = "disconnected"
+	ConnectionLoggedOut      = "logged_out"
+	ConnectionStreamReplaced = "stream_replaced"
+	ConnectionPaired         = "paired"
+)
+
+// Events lists every event type a stream subscriber can filter on
+var Events = append(append([]string{}, domainWebhook.Events...), EventPresence, EventConnection)
+
+// Event is a single entry of the live event stream. The payload is the same body the webhooks receive,
+// and the ID grows with every event so a subscriber can resume after the last one it has seen.
+type Event struct {
+	ID        int64                 `json:"id"`
+	Event     string                `json:"event"`
+	AccountID string                `json:"account_id"`
+	Timestamp time.Time             `json:"timestamp"`
+	Payload   domainWebhook.Payload `json:"payload"`
+}
+
+// PresenceData is the payload of presence events
+type PresenceData struct {
+	From      string `json:"from"`
+	Available bool   `json:"available"`
+	LastSeen  string `json:"last_seen,omitempty"`
+}
+
+// Subscription delivers the buffered events after the cursor followed by the live ones.
+// Events is closed when the subscriber falls too far behind, it can resume from the last ID it received.
+type Subscription struct {
+	Backlog []Event
+	Events  <-chan Event
+	// Truncated is set when events after the cursor were already dropped from the buffer
+	Truncated bool
+	Close     func()
+}
+
+// SubscribeRequest filters the stream on an account and on event types, empty means all of them
+type SubscribeRequest struct {
+	AccountID string   `json:"account_id" query:"account_id"`
+	Events    []string `json:"events" query:"events"`
+	Cursor    int64    `json:"cursor" query:"cursor"`
+}
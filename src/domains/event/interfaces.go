@@ -0,0 +1,8 @@
+package event
+
+import "context"
+
+// IEventUsecase subscribes clients to the live stream of account events
+type IEventUsecase interface {
+	Subscribe(ctx context.Context, request SubscribeRequest) (response *Subscription, err error)
+}
@@ -10,19 +10,23 @@ import (
 // NOTE: IGroupUsecase is now defined in interfaces.go with proper segregation
 
 type JoinGroupWithLinkRequest struct {
-	Link string `json:"link" form:"link"`
+	AccountID string `json:"account_id" form:"account_id"`
+	Link      string `json:"link" form:"link"`
 }
 
 type LeaveGroupRequest struct {
-	GroupID string `json:"group_id" form:"group_id"`
+	AccountID string `json:"account_id" form:"account_id"`
+	GroupID   string `json:"group_id" form:"group_id"`
 }
 
 type CreateGroupRequest struct {
+	AccountID    string   `json:"account_id" form:"account_id"`
 	Title        string   `json:"title" form:"title"`
 	Participants []string `json:"participants" form:"participants"`
 }
 
 type ParticipantRequest struct {
+	AccountID    string                      `json:"account_id" form:"account_id"`
 	GroupID      string                      `json:"group_id" form:"group_id"`
 	Participants []string                    `json:"participants" form:"participants"`
 	Action       whatsmeow.ParticipantChange `json:"action" form:"action"`
@@ -35,7 +39,8 @@ type ParticipantStatus struct {
 }
 
 type GetGroupParticipantsRequest struct {
-	GroupID string `json:"group_id" query:"group_id"`
+	AccountID string `json:"account_id" query:"account_id"`
+	GroupID   string `json:"group_id" query:"group_id"`
 }
 
 type GroupParticipant struct {
@@ -54,7 +59,8 @@ type GetGroupParticipantsResponse struct {
 }
 
 type GetGroupRequestParticipantsRequest struct {
-	GroupID string `json:"group_id" query:"group_id"`
+	AccountID string `json:"account_id" query:"account_id"`
+	GroupID   string `json:"group_id" query:"group_id"`
 }
 
 type GetGroupRequestParticipantsResponse struct {
@@ -63,14 +69,16 @@ type GetGroupRequestParticipantsResponse struct {
 }
 
 type GroupRequestParticipantsRequest struct {
+	AccountID    string                             `json:"account_id" form:"account_id"`
 	GroupID      string                             `json:"group_id" form:"group_id"`
 	Participants []string                           `json:"participants" form:"participants"`
 	Action       whatsmeow.ParticipantRequestChange `json:"action" form:"action"`
 }
 
 type SetGroupPhotoRequest struct {
-	GroupID string                `json:"group_id" form:"group_id"`
-	Photo   *multipart.FileHeader `json:"photo" form:"photo"`
+	AccountID string                `json:"account_id" form:"account_id"`
+	GroupID   string                `json:"group_id" form:"group_id"`
+	Photo     *multipart.FileHeader `json:"photo" form:"photo"`
 }
 
 type SetGroupPhotoResponse struct {
@@ -79,27 +87,32 @@ type SetGroupPhotoResponse struct {
 }
 
 type SetGroupNameRequest struct {
-	GroupID string `json:"group_id" form:"group_id"`
-	Name    string `json:"name" form:"name"`
+	AccountID string `json:"account_id" form:"account_id"`
+	GroupID   string `json:"group_id" form:"group_id"`
+	Name      string `json:"name" form:"name"`
 }
 
 type SetGroupLockedRequest struct {
-	GroupID string `json:"group_id" form:"group_id"`
-	Locked  bool   `json:"locked" form:"locked"`
+	AccountID string `json:"account_id" form:"account_id"`
+	GroupID   string `json:"group_id" form:"group_id"`
+	Locked    bool   `json:"locked" form:"locked"`
 }
 
 type SetGroupAnnounceRequest struct {
-	GroupID  string `json:"group_id" form:"group_id"`
-	Announce bool   `json:"announce" form:"announce"`
+	AccountID string `json:"account_id" form:"account_id"`
+	GroupID   string `json:"group_id" form:"group_id"`
+	Announce  bool   `json:"announce" form:"announce"`
 }
 
 type SetGroupTopicRequest struct {
-	GroupID string `json:"group_id" form:"group_id"`
-	Topic   string `json:"topic" form:"topic"`
+	AccountID string `json:"account_id" form:"account_id"`
+	GroupID   string `json:"group_id" form:"group_id"`
+	Topic     string `json:"topic" form:"topic"`
 }
 
 type GetGroupInfoFromLinkRequest struct {
-	Link string `json:"link" form:"link"`
+	AccountID string `json:"account_id" query:"account_id"`
+	Link      string `json:"link" query:"link"`
 }
 
 type GetGroupInfoFromLinkResponse struct {
@@ -115,12 +128,14 @@ type GetGroupInfoFromLinkResponse struct {
 }
 
 type GroupInfoRequest struct {
-	GroupID string `json:"group_id" query:"group_id"`
+	AccountID string `json:"account_id" query:"account_id"`
+	GroupID   string `json:"group_id" query:"group_id"`
 }
 
 type GetGroupInviteLinkRequest struct {
-	GroupID string `json:"group_id" query:"group_id"`
-	Reset   bool   `json:"reset" query:"reset"`
+	AccountID string `json:"account_id" query:"account_id"`
+	GroupID   string `json:"group_id" query:"group_id"`
+	Reset     bool   `json:"reset" query:"reset"`
 }
 
 type GetGroupInviteLinkResponse struct {
@@ -6,39 +6,46 @@ type GenericResponse struct {
 }
 
 type RevokeRequest struct {
+	AccountID string `json:"account_id" form:"account_id"`
 	MessageID string `json:"message_id" uri:"message_id"`
 	Phone     string `json:"phone" form:"phone"`
 }
 
 type DeleteRequest struct {
+	AccountID string `json:"account_id" form:"account_id"`
 	MessageID string `json:"message_id" uri:"message_id"`
 	Phone     string `json:"phone" form:"phone"`
 }
 
 type ReactionRequest struct {
+	AccountID string `json:"account_id" form:"account_id"`
 	MessageID string `json:"message_id" form:"message_id"`
 	Phone     string `json:"phone" form:"phone"`
 	Emoji     string `json:"emoji" form:"emoji"`
 }
 
 type UpdateMessageRequest struct {
+	AccountID string `json:"account_id" form:"account_id"`
 	MessageID string `json:"message_id" uri:"message_id"`
 	Message   string `json:"message" form:"message"`
 	Phone     string `json:"phone" form:"phone"`
 }
 
 type MarkAsReadRequest struct {
+	AccountID string `json:"account_id" form:"account_id"`
 	MessageID string `json:"message_id" uri:"message_id"`
 	Phone     string `json:"phone" form:"phone"`
 }
 
 type StarRequest struct {
+	AccountID string `json:"account_id" form:"account_id"`
 	MessageID string `json:"message_id" uri:"message_id"`
 	Phone     string `json:"phone" form:"phone"`
 	IsStarred bool   `json:"is_starred"`
 }
 
 type DownloadMediaRequest struct {
+	AccountID string `json:"account_id" query:"account_id"`
 	MessageID string `json:"message_id" uri:"message_id"`
 	Phone     string `json:"phone" form:"phone"`
 }
@@ -51,3 +58,34 @@ type DownloadMediaResponse struct {
 	FilePath  string `json:"file_path"`
 	FileSize  int64  `json:"file_size"`
 }
+
+const (
+	MessageStatusSent      = "sent"
+	MessageStatusDelivered = "delivered"
+	MessageStatusRead      = "read"
+)
+
+type MessageStatusRequest struct {
+	AccountID string `json:"account_id" query:"account_id"`
+	MessageID string `json:"message_id" uri:"message_id"`
+}
+
+// MessageStatusResponse summarizes the receipts of a message. DeliveredAt and ReadAt hold the
+// first delivery and read, the counts are the number of distinct participants.
+type MessageStatusResponse struct {
+	MessageID      string        `json:"message_id"`
+	ChatJID        string        `json:"chat_jid"`
+	Status         string        `json:"status"`
+	DeliveredAt    string        `json:"delivered_at,omitempty"`
+	ReadAt         string        `json:"read_at,omitempty"`
+	DeliveredCount int           `json:"delivered_count"`
+	ReadCount      int           `json:"read_count"`
+	Receipts       []ReceiptInfo `json:"receipts"`
+}
+
+type ReceiptInfo struct {
+	Recipient   string `json:"recipient"`
+	Participant string `json:"participant"`
+	DeliveredAt string `json:"delivered_at,omitempty"`
+	ReadAt      string `json:"read_at,omitempty"`
+}
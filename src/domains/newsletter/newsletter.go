@@ -7,5 +7,6 @@ type INewsletterUsecase interface {
 }
 
 type UnfollowRequest struct {
+	AccountID    string `json:"account_id" form:"account_id"`
 	NewsletterID string `json:"newsletter_id" form:"newsletter_id"`
 }
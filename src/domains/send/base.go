@@ -5,4 +5,19 @@ type BaseRequest struct {
 	Phone       string `json:"phone" form:"phone"`
 	Duration    *int   `json:"duration,omitempty" form:"duration"`
 	IsForwarded bool   `json:"is_forwarded,omitempty" form:"is_forwarded"`
+	// Async queues the message instead of sending it right away; SendAt (RFC3339) implies Async
+	Async  bool    `json:"async,omitempty" form:"async"`
+	SendAt *string `json:"send_at,omitempty" form:"send_at"`
+}
+
+// IsQueued reports whether the message should go through the send queue
+func (r BaseRequest) IsQueued() bool {
+	return r.Async || (r.SendAt != nil && *r.SendAt != "")
+}
+
+// TemplateOptions sends a stored message template instead of raw content.
+// Multipart requests carry Variables as a JSON encoded field.
+type TemplateOptions struct {
+	TemplateID string            `json:"template_id,omitempty" form:"template_id"`
+	Variables  map[string]string `json:"variables,omitempty" form:"-"`
 }
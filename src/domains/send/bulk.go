@@ -0,0 +1,30 @@
+package send
+
+import "mime/multipart"
+
+// BulkRecipient is a single recipient of a bulk send, with the values for the {{name}} placeholders
+type BulkRecipient struct {
+	Phone     string            `json:"phone"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// BulkRequest sends the same message to many recipients. Message is the text, or the caption when
+// an image or file is attached. Media is uploaded once and reused for every recipient.
+type BulkRequest struct {
+	AccountID  string                `json:"account_id" form:"account_id"`
+	Recipients []BulkRecipient       `json:"recipients" form:"-"`
+	Message    string                `json:"message" form:"message"`
+	Image      *multipart.FileHeader `json:"image" form:"image"`
+	ImageURL   *string               `json:"image_url" form:"image_url"`
+	File       *multipart.FileHeader `json:"file" form:"file"`
+	Duration   *int                  `json:"duration,omitempty" form:"duration"`
+	DelayMs    *int                  `json:"delay_ms,omitempty" form:"delay_ms"`
+}
+
+// BulkResult is the outcome of sending to one recipient
+type BulkResult struct {
+	Phone     string `json:"phone"`
+	Success   bool   `json:"success"`
+	MessageID string `json:"message_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
@@ -4,6 +4,7 @@ import "mime/multipart"
 
 type FileRequest struct {
 	BaseRequest
+	TemplateOptions
 	File    *multipart.FileHeader `json:"file" form:"file"`
 	Caption string                `json:"caption" form:"caption"`
 }
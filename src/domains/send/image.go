@@ -4,6 +4,7 @@ import "mime/multipart"
 
 type ImageRequest struct {
 	BaseRequest
+	TemplateOptions
 	Caption  string                `json:"caption" form:"caption"`
 	Image    *multipart.FileHeader `json:"image" form:"image"`
 	ImageURL *string               `json:"image_url" form:"image_url"`
@@ -2,6 +2,7 @@ package send
 
 import (
 	"context"
+	"time"
 )
 
 // ITextSender handles text message sending operations
@@ -32,10 +33,43 @@ type IPresenceSender interface {
 	SendChatPresence(ctx context.Context, request ChatPresenceRequest) (response GenericResponse, err error)
 }
 
+// IBulkSender handles sending one message to many recipients
+type IBulkSender interface {
+	// SendBulk validates the request and uploads media up front, then sends in the background and
+	// reports every recipient on the returned channel, which is closed once all of them are done.
+	SendBulk(ctx context.Context, request BulkRequest) (results <-chan BulkResult, err error)
+}
+
+// IJobTracker reports the state of queued messages
+type IJobTracker interface {
+	GetJob(ctx context.Context, request JobRequest) (response JobResponse, err error)
+}
+
 // ISendUsecase combines all sender interfaces for backward compatibility
 type ISendUsecase interface {
 	ITextSender
 	IMediaSender
 	IInteractionSender
 	IPresenceSender
+	IBulkSender
+	IJobTracker
+}
+
+// ISendJobRepository persists the send queue
+type ISendJobRepository interface {
+	CreateJob(job *Job) error
+	GetJob(jobID string) (*Job, error)
+	ListDueAccounts(now time.Time) ([]string, error)
+	// ClaimDueJob marks the next due job of the account as sending and counts the attempt
+	ClaimDueJob(accountID string, now time.Time) (*Job, error)
+	// LastAttemptAt returns when the account last attempted a queued send, zero when it never did
+	LastAttemptAt(accountID string) (time.Time, error)
+	MarkJobSent(jobID string, messageID string, sentAt time.Time) error
+	MarkJobFailed(jobID string, reason string) error
+	// RetryJob queues a claimed job again for sendAt, keeping its message ID for the next attempt
+	RetryJob(jobID string, messageID string, sendAt time.Time, reason string) error
+	// ExpireJobs expires queued jobs due before queuedBefore
+	ExpireJobs(queuedBefore time.Time) (int64, error)
+	// FailStaleJobs fails jobs claimed before claimedBefore, their delivery state is unknown
+	FailStaleJobs(claimedBefore time.Time, reason string) (int64, error)
 }
@@ -0,0 +1,51 @@
+package send
+
+import "time"
+
+// Send job statuses
+const (
+	JobStatusQueued  = "queued"
+	JobStatusSending = "sending" // Claimed by a worker, never picked up again so a crash can't send it twice
+	JobStatusSent    = "sent"
+	JobStatusFailed  = "failed"
+	JobStatusExpired = "expired" // Still queued when the max age passed, e.g. the account never connected
+)
+
+// Job is a message waiting in the send queue. Message holds the serialized waE2E.Message,
+// so media is uploaded when the job is created and only the send itself is deferred.
+// MessageID is picked on the first attempt and reused by retries, so WhatsApp drops duplicates.
+type Job struct {
+	ID          string     `db:"id"`
+	AccountID   string     `db:"account_id"`
+	Recipient   string     `db:"recipient"`
+	Content     string     `db:"content"`
+	Message     []byte     `db:"message"`
+	Status      string     `db:"status"`
+	MessageID   string     `db:"message_id"`
+	Error       string     `db:"error"`
+	Attempts    int        `db:"attempts"`
+	SendAt      time.Time  `db:"send_at"`
+	SentAt      *time.Time `db:"sent_at"`
+	AttemptedAt *time.Time `db:"attempted_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+type JobRequest struct {
+	JobID string `json:"job_id" uri:"id"`
+	// AccountID optionally restricts the lookup to the jobs of one account
+	AccountID string `json:"account_id" query:"account_id"`
+}
+
+type JobResponse struct {
+	JobID     string     `json:"job_id"`
+	AccountID string     `json:"account_id"`
+	Recipient string     `json:"recipient"`
+	Status    string     `json:"status"`
+	MessageID string     `json:"message_id,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	Attempts  int        `json:"attempts"`
+	SendAt    time.Time  `json:"send_at"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -3,4 +3,5 @@ package send
 type GenericResponse struct {
 	MessageID string `json:"message_id"`
 	Status    string `json:"status"`
+	JobID     string `json:"job_id,omitempty"`
 }
@@ -2,6 +2,7 @@ package send
 
 type MessageRequest struct {
 	BaseRequest
+	TemplateOptions
 	Message        string  `json:"message" form:"message"`
 	ReplyMessageID *string `json:"reply_message_id" form:"reply_message_id"`
 }
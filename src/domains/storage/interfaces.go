@@ -0,0 +1,12 @@
+package storage
+
+import "context"
+
+// IStorageUsecase reports on the chat storage and applies the retention rules to it
+type IStorageUsecase interface {
+	GetStatistics(ctx context.Context) (response Statistics, err error)
+	Prune(ctx context.Context) (response PruneReport, err error)
+	ListRetentionPolicies(ctx context.Context, accountID string) (response []RetentionPolicy, err error)
+	SetRetentionPolicy(ctx context.Context, request RetentionPolicyRequest) (response RetentionPolicy, err error)
+	DeleteRetentionPolicy(ctx context.Context, request RetentionPolicyIdentifierRequest) (err error)
+}
@@ -0,0 +1,62 @@
+package storage
+
+import "time"
+
+// RetentionPolicy overrides the global retention rule for an account, or for a single chat
+// of an account when ChatJID is set. A MaxMessageDays of 0 keeps the messages forever.
+type RetentionPolicy struct {
+	AccountID      string    `json:"account_id"`
+	ChatJID        string    `json:"chat_jid,omitempty"`
+	MaxMessageDays int       `json:"max_message_days"`
+	KeepStarred    bool      `json:"keep_starred"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// RetentionConfig is the global retention rule, applied to accounts and chats without a policy
+type RetentionConfig struct {
+	MaxMessageDays int    `json:"max_message_days"`
+	MaxMediaSize   int64  `json:"max_media_size"`
+	KeepStarred    bool   `json:"keep_starred"`
+	Interval       string `json:"interval"`
+}
+
+// FolderUsage is the number of files and bytes in the media folders
+type FolderUsage struct {
+	Files int   `json:"files"`
+	Bytes int64 `json:"bytes"`
+}
+
+// PruneReport describes a single run of the storage janitor
+type PruneReport struct {
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+	MessagesRemoved int64     `json:"messages_removed"`
+	FilesRemoved    int       `json:"files_removed"`
+	BytesFreed      int64     `json:"bytes_freed"`
+	Vacuumed        bool      `json:"vacuumed"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// Statistics is the current size of the chat storage and media folders
+type Statistics struct {
+	Chats        int64           `json:"chats"`
+	Messages     int64           `json:"messages"`
+	DatabaseSize int64           `json:"database_size"`
+	Media        FolderUsage     `json:"media"`
+	Retention    RetentionConfig `json:"retention"`
+	LastPrune    *PruneReport    `json:"last_prune"`
+}
+
+// Request structures for retention policy operations
+
+type RetentionPolicyRequest struct {
+	AccountID      string `json:"account_id"`
+	ChatJID        string `json:"chat_jid"`
+	MaxMessageDays int    `json:"max_message_days"`
+	KeepStarred    *bool  `json:"keep_starred"`
+}
+
+type RetentionPolicyIdentifierRequest struct {
+	AccountID string `json:"account_id" query:"account_id"`
+	ChatJID   string `json:"chat_jid" query:"chat_jid"`
+}
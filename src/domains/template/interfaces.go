@@ -0,0 +1,21 @@
+package template
+
+import "context"
+
+// ITemplateUsecase defines the interface for message template management
+type ITemplateUsecase interface {
+	CreateTemplate(ctx context.Context, request TemplateRequest) (response Template, err error)
+	ListTemplates(ctx context.Context, accountID string) (response []Template, err error)
+	GetTemplate(ctx context.Context, request TemplateIdentifierRequest) (response Template, err error)
+	UpdateTemplate(ctx context.Context, request UpdateTemplateRequest) (response Template, err error)
+	DeleteTemplate(ctx context.Context, request TemplateIdentifierRequest) (err error)
+}
+
+// ITemplateRepository persists message templates. ListTemplates leaves Media empty.
+type ITemplateRepository interface {
+	CreateTemplate(template *Template) error
+	GetTemplate(accountID, templateID string) (*Template, error)
+	ListTemplates(accountID string) ([]*Template, error)
+	UpdateTemplate(template *Template) error
+	DeleteTemplate(accountID, templateID string) error
+}
@@ -0,0 +1,57 @@
+package template
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+// Media types a template can carry
+const (
+	MediaTypeImage = "image"
+	MediaTypeFile  = "file"
+)
+
+// Template is a canned message of an account. Content may contain {{name}} placeholders
+// that are filled in from the variables given when the template is sent.
+type Template struct {
+	ID        string    `json:"id" db:"id"`
+	AccountID string    `json:"account_id" db:"account_id"`
+	Name      string    `json:"name" db:"name"`
+	Content   string    `json:"content" db:"content"`
+	Variables []string  `json:"variables" db:"-"`
+	MediaType string    `json:"media_type,omitempty" db:"media_type"`
+	MediaName string    `json:"media_name,omitempty" db:"media_name"`
+	MediaMime string    `json:"media_mime,omitempty" db:"media_mime"`
+	Media     []byte    `json:"-" db:"media"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// HasMedia reports whether the template carries an image or a file
+func (t Template) HasMedia() bool {
+	return t.MediaType != "" && len(t.Media) > 0
+}
+
+// Request structures for template operations
+
+type TemplateRequest struct {
+	AccountID string                `json:"account_id" uri:"accountId" form:"-"`
+	Name      string                `json:"name" form:"name"`
+	Content   string                `json:"content" form:"content"`
+	Media     *multipart.FileHeader `json:"media" form:"media"`
+}
+
+type UpdateTemplateRequest struct {
+	AccountID  string                `json:"account_id" uri:"accountId" form:"-"`
+	TemplateID string                `json:"template_id" uri:"templateId" form:"-"`
+	Name       string                `json:"name" form:"name"`
+	Content    string                `json:"content" form:"content"`
+	Media      *multipart.FileHeader `json:"media" form:"media"`
+	// RemoveMedia drops the current media when no new media is uploaded
+	RemoveMedia bool `json:"remove_media" form:"remove_media"`
+}
+
+type TemplateIdentifierRequest struct {
+	AccountID  string `json:"account_id" uri:"accountId"`
+	TemplateID string `json:"template_id" uri:"templateId"`
+}
@@ -6,8 +6,14 @@ import (
 	"go.mau.fi/whatsmeow/types"
 )
 
+// AccountRequest selects the account for the "my" endpoints that take no other input
+type AccountRequest struct {
+	AccountID string `json:"account_id" query:"account_id"`
+}
+
 type InfoRequest struct {
-	Phone string `json:"phone" query:"phone"`
+	AccountID string `json:"account_id" query:"account_id"`
+	Phone     string `json:"phone" query:"phone"`
 }
 
 type InfoResponseDataDevice struct {
@@ -30,6 +36,7 @@ type InfoResponse struct {
 }
 
 type AvatarRequest struct {
+	AccountID   string `json:"account_id" query:"account_id"`
 	Phone       string `json:"phone" query:"phone"`
 	IsPreview   bool   `json:"is_preview" query:"is_preview"`
 	IsCommunity bool   `json:"is_community" query:"is_community"`
@@ -58,7 +65,8 @@ type MyListNewsletterResponse struct {
 }
 
 type ChangeAvatarRequest struct {
-	Avatar *multipart.FileHeader `json:"avatar" form:"avatar"`
+	AccountID string                `json:"account_id" form:"account_id"`
+	Avatar    *multipart.FileHeader `json:"avatar" form:"avatar"`
 }
 
 type MyListContactsResponse struct {
@@ -71,11 +79,13 @@ type MyListContactsResponseData struct {
 }
 
 type ChangePushNameRequest struct {
-	PushName string `json:"push_name" form:"push_name"`
+	AccountID string `json:"account_id" form:"account_id"`
+	PushName  string `json:"push_name" form:"push_name"`
 }
 
 type CheckRequest struct {
-	Phone string `json:"phone" query:"phone"`
+	AccountID string `json:"account_id" query:"account_id"`
+	Phone     string `json:"phone" query:"phone"`
 }
 
 type CheckResponse struct {
@@ -83,7 +93,8 @@ type CheckResponse struct {
 }
 
 type BusinessProfileRequest struct {
-	Phone string `json:"phone" query:"phone"`
+	AccountID string `json:"account_id" query:"account_id"`
+	Phone     string `json:"phone" query:"phone"`
 }
 
 type BusinessProfileCategory struct {
@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"context"
+	"time"
+)
+
+// IWebhookUsecase defines the interface for account webhook endpoints and dead letter management
+type IWebhookUsecase interface {
+	CreateEndpoint(ctx context.Context, request EndpointRequest) (response Endpoint, err error)
+	ListEndpoints(ctx context.Context, accountID string) (response []Endpoint, err error)
+	GetEndpoint(ctx context.Context, request EndpointIdentifierRequest) (response Endpoint, err error)
+	UpdateEndpoint(ctx context.Context, request UpdateEndpointRequest) (response Endpoint, err error)
+	DeleteEndpoint(ctx context.Context, request EndpointIdentifierRequest) (err error)
+	ListDeadLetters(ctx context.Context, request ListDeadLettersRequest) (response ListDeadLettersResponse, err error)
+	ReplayDeadLetters(ctx context.Context, request DeadLettersRequest) (response DeadLettersResponse, err error)
+	PurgeDeadLetters(ctx context.Context, request DeadLettersRequest) (response DeadLettersResponse, err error)
+}
+
+// IWebhookRepository persists webhook deliveries until they are acknowledged.
+// An empty deliveryIDs slice applies replay and purge to every dead delivery of the account.
+type IWebhookRepository interface {
+	CreateEndpoint(endpoint *Endpoint) error
+	GetEndpoint(accountID, endpointID string) (*Endpoint, error)
+	ListEndpoints(accountID string) ([]*Endpoint, error)
+	UpdateEndpoint(endpoint *Endpoint) error
+	DeleteEndpoint(accountID, endpointID string) error
+
+	EnqueueDelivery(delivery *Delivery) error
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]*Delivery, error)
+	DeleteDelivery(id int64) error
+	ScheduleRetry(id int64, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkDead(id int64, attempts int, lastError string) error
+	ListDeadDeliveries(accountID string, limit, offset int) ([]*Delivery, error)
+	CountDeadDeliveries(accountID string) (int64, error)
+	ReplayDeadDeliveries(accountID string, deliveryIDs []int64) (int64, error)
+	PurgeDeadDeliveries(accountID string, deliveryIDs []int64) (int64, error)
+}
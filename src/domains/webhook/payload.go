@@ -0,0 +1,139 @@
+package webhook
+
+import "go.mau.fi/whatsmeow/proto/waE2E"
+
+// Payload is the body of a webhook event as it is sent without the envelope
+type Payload interface {
+	// GetDeliveryID returns the delivery ID shared by the deliveries of the event to every webhook
+	GetDeliveryID() string
+	// EnvelopeData returns what the envelope carries as payload and when the event happened, if known
+	EnvelopeData() (data any, timestamp string)
+}
+
+// PayloadInfo is embedded in every payload but not sent. The delivery ID is generated once when the
+// payload of an event is built, so the deliveries of one event to several webhooks can be deduplicated.
+type PayloadInfo struct {
+	DeliveryID string `json:"-"`
+}
+
+func (i PayloadInfo) GetDeliveryID() string {
+	return i.DeliveryID
+}
+
+// EventBody nests the data of an event under payload, next to the event type and time.
+// Receipt, group, connection and presence events are sent in this shape.
+type EventBody[T any] struct {
+	PayloadInfo
+	Event     string `json:"event"`
+	Timestamp string `json:"timestamp"`
+	Payload   T      `json:"payload"`
+}
+
+func (b EventBody[T]) EnvelopeData() (any, string) {
+	return b.Payload, b.Timestamp
+}
+
+// MessagePayload is the body of message, message.revoke and message.edit events
+type MessagePayload struct {
+	PayloadInfo
+	SenderID  string        `json:"sender_id"`
+	ChatID    string        `json:"chat_id"`
+	From      string        `json:"from,omitempty"`
+	FromLID   string        `json:"from_lid,omitempty"`
+	Message   *MessageText  `json:"message,omitempty"`
+	PushName  string        `json:"pushname,omitempty"`
+	Reaction  *ReactionText `json:"reaction,omitempty"`
+	ViewOnce  bool          `json:"view_once,omitempty"`
+	Forwarded bool          `json:"forwarded,omitempty"`
+	Timestamp string        `json:"timestamp,omitempty"`
+
+	// Set on revoked and edited messages
+	Action           string `json:"action,omitempty"`
+	RevokedMessageID string `json:"revoked_message_id,omitempty"`
+	RevokedFromMe    *bool  `json:"revoked_from_me,omitempty"`
+	RevokedChat      string `json:"revoked_chat,omitempty"`
+	EditedText       string `json:"edited_text,omitempty"`
+
+	Audio        *Media                     `json:"audio,omitempty"`
+	Contact      *waE2E.ContactMessage      `json:"contact,omitempty"`
+	Document     *Media                     `json:"document,omitempty"`
+	Image        *Media                     `json:"image,omitempty"`
+	List         *waE2E.ListMessage         `json:"list,omitempty"`
+	LiveLocation *waE2E.LiveLocationMessage `json:"live_location,omitempty"`
+	Location     *waE2E.LocationMessage     `json:"location,omitempty"`
+	Order        *waE2E.OrderMessage        `json:"order,omitempty"`
+	Sticker      *Media                     `json:"sticker,omitempty"`
+	Video        *Media                     `json:"video,omitempty"`
+}
+
+func (p MessagePayload) EnvelopeData() (any, string) {
+	return p, p.Timestamp
+}
+
+type MessageText struct {
+	Text          string `json:"text"`
+	ID            string `json:"id"`
+	RepliedID     string `json:"replied_id"`
+	QuotedMessage string `json:"quoted_message"`
+}
+
+type ReactionText struct {
+	Message string `json:"message"`
+	ID      string `json:"id"`
+}
+
+// Media is a downloaded media file of a message
+type Media struct {
+	MediaPath string `json:"media_path"`
+	MimeType  string `json:"mime_type"`
+	Caption   string `json:"caption"`
+}
+
+// DeletePayload is the body of message.delete events. The original fields are set when the deleted message was stored.
+type DeletePayload struct {
+	PayloadInfo
+	Action            string `json:"action"`
+	DeletedMessageID  string `json:"deleted_message_id"`
+	SenderID          string `json:"sender_id"`
+	Timestamp         string `json:"timestamp"`
+	From              string `json:"from,omitempty"`
+	ChatID            string `json:"chat_id,omitempty"`
+	OriginalContent   string `json:"original_content,omitempty"`
+	OriginalSender    string `json:"original_sender,omitempty"`
+	OriginalTimestamp string `json:"original_timestamp,omitempty"`
+	WasFromMe         *bool  `json:"was_from_me,omitempty"`
+	OriginalMediaType string `json:"original_media_type,omitempty"`
+	OriginalFilename  string `json:"original_filename,omitempty"`
+}
+
+func (p DeletePayload) EnvelopeData() (any, string) {
+	return p, p.Timestamp
+}
+
+// ReceiptData is the payload of message.ack events
+type ReceiptData struct {
+	IDs                    []string `json:"ids,omitempty"`
+	ChatID                 string   `json:"chat_id"`
+	SenderID               string   `json:"sender_id"`
+	From                   string   `json:"from"`
+	ReceiptType            string   `json:"receipt_type"`
+	ReceiptTypeDescription string   `json:"receipt_type_description"`
+}
+
+// GroupParticipantsData is the payload of group.participants events
+type GroupParticipantsData struct {
+	ChatID string   `json:"chat_id"`
+	Type   string   `json:"type"`
+	JIDs   []string `json:"jids"`
+}
+
+// ConnectionData is the payload of the account lifecycle events. The reason and its code are only
+// set when the server gave one (e.g. on a logout).
+type ConnectionData struct {
+	State      string `json:"state"`
+	AccountID  string `json:"account_id,omitempty"`
+	DeviceID   string `json:"device_id,omitempty"`
+	Phone      string `json:"phone,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	ReasonCode int    `json:"reason_code,omitempty"`
+}
@@ -0,0 +1,139 @@
+package webhook
+
+import "time"
+
+// Delivery statuses stored in the webhook outbox
+const (
+	DeliveryStatusPending = "pending"
+	DeliveryStatusDead    = "dead"
+)
+
+// Event types an account webhook endpoint can subscribe to
+const (
+	EventMessage           = "message"
+	EventReceipt           = "message.ack"
+	EventDelete            = "message.delete"
+	EventRevoke            = "message.revoke"
+	EventEdit              = "message.edit"
+	EventGroupParticipants = "group.participants"
+
+	// Connection lifecycle of the account, also delivered to the global webhooks
+	EventAccountConnected      = "account.connected"
+	EventAccountDisconnected   = "account.disconnected"
+	EventAccountLoggedOut      = "account.logged_out"
+	EventAccountStreamReplaced = "account.stream_replaced"
+	EventAccountPaired         = "account.paired"
+)
+
+// Events lists every event type accepted in an endpoint subscription
+var Events = []string{
+	EventMessage, EventReceipt, EventDelete, EventRevoke, EventEdit, EventGroupParticipants,
+	EventAccountConnected, EventAccountDisconnected, EventAccountLoggedOut, EventAccountStreamReplaced, EventAccountPaired,
+}
+
+// Endpoint is one of the webhook URLs of an account together with the events it receives
+type Endpoint struct {
+	ID        string    `json:"id" db:"id"`
+	AccountID string    `json:"account_id" db:"account_id"`
+	URL       string    `json:"url" db:"url"`
+	Secret    string    `json:"secret" db:"secret"`
+	Events    []string  `json:"events" db:"events"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Subscribes reports whether the endpoint receives the given event type
+func (e Endpoint) Subscribes(event string) bool {
+	for _, subscribed := range e.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvelopeVersion is the version of the webhook envelope format
+const EnvelopeVersion = "1"
+
+// Envelope is the uniform body sent to webhooks when the envelope format is enabled
+type Envelope struct {
+	Version    string `json:"version"`
+	Event      string `json:"event"`
+	AccountID  string `json:"account_id"`
+	DeviceID   string `json:"device_id"`
+	DeliveryID string `json:"delivery_id"`
+	Timestamp  string `json:"timestamp"`
+	Payload    any    `json:"payload"`
+}
+
+// Delivery represents a single webhook payload waiting in the outbox
+type Delivery struct {
+	ID            int64     `json:"id" db:"id"`
+	AccountID     string    `json:"account_id" db:"account_id"`
+	Event         string    `json:"event" db:"event"`
+	URL           string    `json:"url" db:"url"`
+	Payload       string    `json:"payload" db:"payload"`
+	Signature     string    `json:"-" db:"signature"`
+	Status        string    `json:"status" db:"status"`
+	Attempts      int       `json:"attempts" db:"attempts"`
+	LastError     string    `json:"last_error" db:"last_error"`
+	NextAttemptAt time.Time `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Request structures for endpoint operations
+
+type EndpointRequest struct {
+	AccountID string   `json:"account_id" uri:"accountId"`
+	URL       string   `json:"url"`
+	Secret    string   `json:"secret"`
+	Events    []string `json:"events"`
+}
+
+type UpdateEndpointRequest struct {
+	AccountID  string   `json:"account_id" uri:"accountId"`
+	EndpointID string   `json:"endpoint_id" uri:"webhookId"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	Events     []string `json:"events"`
+}
+
+type EndpointIdentifierRequest struct {
+	AccountID  string `json:"account_id" uri:"accountId"`
+	EndpointID string `json:"endpoint_id" uri:"webhookId"`
+}
+
+// Request and Response structures for dead letter operations
+
+// ListDeadLettersRequest lists the dead letters of an account, or with Global those of the
+// global webhooks, which belong to no account
+type ListDeadLettersRequest struct {
+	AccountID string `json:"account_id" uri:"accountId"`
+	Global    bool   `json:"-"`
+	Limit     int    `json:"limit" query:"limit"`
+	Offset    int    `json:"offset" query:"offset"`
+}
+
+type ListDeadLettersResponse struct {
+	Data       []Delivery         `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// DeadLettersRequest picks dead letters of an account, or with Global those of the global webhooks
+type DeadLettersRequest struct {
+	AccountID   string  `json:"account_id" uri:"accountId"`
+	Global      bool    `json:"-"`
+	DeliveryIDs []int64 `json:"delivery_ids"`
+}
+
+type DeadLettersResponse struct {
+	AccountID string `json:"account_id"`
+	Affected  int64  `json:"affected"`
+}
+
+type PaginationResponse struct {
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+	Total  int64 `json:"total"`
+}
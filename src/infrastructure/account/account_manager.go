@@ -10,6 +10,7 @@ import (
 type AccountManager struct {
 	clients map[string]*whatsmeow.Client
 	dbs     map[string]*sqlstore.Container
+	stopped map[*whatsmeow.Client]chan struct{}
 	mutex   sync.RWMutex
 }
 
@@ -17,6 +18,7 @@ func NewAccountManager() *AccountManager {
 	return &AccountManager{
 		clients: make(map[string]*whatsmeow.Client),
 		dbs:     make(map[string]*sqlstore.Container),
+		stopped: make(map[*whatsmeow.Client]chan struct{}),
 	}
 }
 
@@ -29,6 +31,9 @@ func (am *AccountManager) GetClient(accountID string) *whatsmeow.Client {
 func (am *AccountManager) SetClient(accountID string, client *whatsmeow.Client, db *sqlstore.Container) {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
+	if previous, exists := am.clients[accountID]; exists && previous != client {
+		am.stop(previous)
+	}
 	am.clients[accountID] = client
 	am.dbs[accountID] = db
 }
@@ -37,12 +42,56 @@ func (am *AccountManager) RemoveClient(accountID string) {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 	if client, exists := am.clients[accountID]; exists {
+		am.stop(client)
 		client.Disconnect()
 		delete(am.clients, accountID)
 		delete(am.dbs, accountID)
 	}
 }
 
+// Stopped returns a channel that is closed once the client is removed or replaced by another
+// client of its account, whoever keeps the client connected has to let go of it then
+func (am *AccountManager) Stopped(client *whatsmeow.Client) <-chan struct{} {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+	stopped, exists := am.stopped[client]
+	if !exists {
+		stopped = make(chan struct{})
+		am.stopped[client] = stopped
+	}
+	return stopped
+}
+
+// Forget drops the stopped channel of a client once nothing waits on it anymore, so a removed or
+// replaced client isn't kept reachable for the life of the process
+func (am *AccountManager) Forget(client *whatsmeow.Client) {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+	delete(am.stopped, client)
+}
+
+// IsRegistered reports whether the client is the current client of the account
+func (am *AccountManager) IsRegistered(accountID string, client *whatsmeow.Client) bool {
+	am.mutex.RLock()
+	defer am.mutex.RUnlock()
+	return am.clients[accountID] == client
+}
+
+// stop closes the stopped channel of a client, the caller holds the lock
+func (am *AccountManager) stop(client *whatsmeow.Client) {
+	stopped, exists := am.stopped[client]
+	if !exists {
+		stopped = make(chan struct{})
+		// Keep the closed channel until Forget, so a late Stopped call still sees the client as stopped
+		am.stopped[client] = stopped
+	}
+	select {
+	case <-stopped:
+	default:
+		close(stopped)
+	}
+}
+
 func (am *AccountManager) ListClients() map[string]*whatsmeow.Client {
 	am.mutex.RLock()
 	defer am.mutex.RUnlock()
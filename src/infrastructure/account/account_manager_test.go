@@ -0,0 +1,45 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/store"
+)
+
+func TestAccountManagerStoppedClients(t *testing.T) {
+	am := NewAccountManager()
+	first, second := whatsmeow.NewClient(&store.Device{}, nil), whatsmeow.NewClient(&store.Device{}, nil)
+
+	am.SetClient("acc-1", first, nil)
+	firstStopped := am.Stopped(first)
+	secondStopped := am.Stopped(second)
+	assert.Len(t, am.stopped, 2)
+
+	// Replacing the client of the account stops the previous one
+	am.SetClient("acc-1", second, nil)
+	assert.True(t, isClosed(firstStopped))
+	assert.False(t, isClosed(secondStopped))
+	assert.True(t, am.IsRegistered("acc-1", second))
+
+	// A late watcher of a stopped client still sees it as stopped until it is forgotten
+	assert.True(t, isClosed(am.Stopped(first)))
+	am.Forget(first)
+	assert.Len(t, am.stopped, 1)
+
+	am.RemoveClient("acc-1")
+	assert.True(t, isClosed(secondStopped))
+	am.Forget(second)
+	assert.Empty(t, am.stopped)
+	assert.Empty(t, am.ListClients())
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
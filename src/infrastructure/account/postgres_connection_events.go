@@ -0,0 +1,60 @@
+package account
+
+import (
+	"time"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+)
+
+// AddConnectionEvent records a connection state transition of an account
+func (r *PostgresRepository) AddConnectionEvent(event *domainAccount.ConnectionEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	return r.db.QueryRow(`
+		INSERT INTO account_connection_events (account_id, state, reason, attempt, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, event.AccountID, event.State, event.Reason, event.Attempt, event.CreatedAt).Scan(&event.ID)
+}
+
+// ListConnectionEvents returns the connection state transitions of an account, newest first
+func (r *PostgresRepository) ListConnectionEvents(accountID string, limit, offset int) ([]*domainAccount.ConnectionEvent, error) {
+	rows, err := r.db.Query(`
+		SELECT id, account_id, state, reason, attempt, created_at
+		FROM account_connection_events
+		WHERE account_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, accountID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domainAccount.ConnectionEvent
+	for rows.Next() {
+		event := &domainAccount.ConnectionEvent{}
+		if err := rows.Scan(&event.ID, &event.AccountID, &event.State, &event.Reason, &event.Attempt, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// PruneConnectionEvents deletes all but the newest keep connection events of an account
+func (r *PostgresRepository) PruneConnectionEvents(accountID string, keep int) error {
+	_, err := r.db.Exec(`
+		DELETE FROM account_connection_events
+		WHERE account_id = $1 AND id NOT IN (
+			SELECT id FROM account_connection_events
+			WHERE account_id = $2
+			ORDER BY created_at DESC, id DESC
+			LIMIT $3
+		)
+	`, accountID, accountID, keep)
+	return err
+}
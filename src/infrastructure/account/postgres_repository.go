@@ -0,0 +1,227 @@
+package account
+
+import (
+	"database/sql"
+	"fmt"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	"github.com/sirupsen/logrus"
+)
+
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRepository(db *sql.DB) domainAccount.IAccountRepository {
+	repo := &PostgresRepository{db: db}
+	if err := repo.InitializeSchema(); err != nil {
+		logrus.Errorf("Failed to migrate account tables: %v", err)
+	}
+	return repo
+}
+
+func (r *PostgresRepository) CreateAccount(account *domainAccount.Account) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO accounts (id, status, phone_number, device_id, created_at, last_connected)
+			  VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err = tx.Exec(query, account.ID, account.Status, account.PhoneNumber,
+		account.DeviceID, account.CreatedAt, account.LastConnected)
+	if err != nil {
+		return err
+	}
+
+	// Create webhook entry
+	webhookQuery := `INSERT INTO account_webhooks (account_id, webhook_url, webhook_secret) VALUES ($1, '', '')`
+	if _, err = tx.Exec(webhookQuery, account.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresRepository) GetAccount(accountID string) (*domainAccount.Account, error) {
+	query := `SELECT id, status, phone_number, device_id, created_at, last_connected
+			  FROM accounts WHERE id = $1`
+
+	account, err := r.scanAccount(r.db.QueryRow(query, accountID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return account, err
+}
+
+func (r *PostgresRepository) UpdateAccount(account *domainAccount.Account) error {
+	query := `UPDATE accounts SET status = $1, phone_number = $2, device_id = $3, last_connected = $4
+			  WHERE id = $5`
+
+	_, err := r.db.Exec(query, account.Status, account.PhoneNumber,
+		account.DeviceID, account.LastConnected, account.ID)
+	return err
+}
+
+func (r *PostgresRepository) DeleteAccount(accountID string) error {
+	_, err := r.db.Exec(`DELETE FROM accounts WHERE id = $1`, accountID)
+	return err
+}
+
+func (r *PostgresRepository) ListAccounts() ([]*domainAccount.Account, error) {
+	query := `SELECT id, status, phone_number, device_id, created_at, last_connected
+			  FROM accounts ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*domainAccount.Account
+	for rows.Next() {
+		account, err := r.scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+func (r *PostgresRepository) SetWebhook(accountID string, webhookURL string, secret string) error {
+	query := `UPDATE account_webhooks SET webhook_url = $1, webhook_secret = $2 WHERE account_id = $3`
+	_, err := r.db.Exec(query, webhookURL, secret, accountID)
+	return err
+}
+
+func (r *PostgresRepository) GetWebhook(accountID string) (*domainAccount.WebhookInfo, error) {
+	query := `SELECT webhook_url, webhook_secret FROM account_webhooks WHERE account_id = $1`
+
+	webhook := &domainAccount.WebhookInfo{}
+	err := r.db.QueryRow(query, accountID).Scan(&webhook.URL, &webhook.Secret)
+	if err == sql.ErrNoRows {
+		return &domainAccount.WebhookInfo{}, nil
+	}
+
+	return webhook, err
+}
+
+func (r *PostgresRepository) scanAccount(scanner interface{ Scan(...any) error }) (*domainAccount.Account, error) {
+	account := &domainAccount.Account{}
+	var lastConnected sql.NullTime
+	err := scanner.Scan(&account.ID, &account.Status, &account.PhoneNumber,
+		&account.DeviceID, &account.CreatedAt, &lastConnected)
+	if err != nil {
+		return nil, err
+	}
+
+	account.LastConnected = lastConnected.Time
+	return account, nil
+}
+
+// _____________________________________________________________________________________________________________________
+
+// InitializeSchema creates or migrates the account tables.
+// The version is kept in its own table, so the accounts can share a database with the chat storage and its schema_info.
+func (r *PostgresRepository) InitializeSchema() error {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS account_schema_info (
+			version INTEGER PRIMARY KEY DEFAULT 0,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	var version int
+	if err := r.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM account_schema_info").Scan(&version); err != nil {
+		return err
+	}
+
+	migrations := r.getMigrations()
+	for i := version; i < len(migrations); i++ {
+		if err := r.runMigration(migrations[i], i+1); err != nil {
+			return fmt.Errorf("failed to run migration %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// runMigration executes a migration
+func (r *PostgresRepository) runMigration(migration string, version int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(migration); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO account_schema_info (version) VALUES ($1)
+			  ON CONFLICT (version) DO UPDATE SET updated_at = CURRENT_TIMESTAMP`
+	if _, err := tx.Exec(query, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// getMigrations returns all account table migrations
+func (r *PostgresRepository) getMigrations() []string {
+	return []string{
+		// Migration 1: Accounts and their legacy single webhook
+		`
+		CREATE TABLE IF NOT EXISTS accounts (
+			id TEXT PRIMARY KEY,
+			status TEXT DEFAULT 'disconnected',
+			phone_number TEXT DEFAULT '',
+			device_id TEXT DEFAULT '',
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			last_connected TIMESTAMPTZ
+		);
+
+		CREATE TABLE IF NOT EXISTS account_webhooks (
+			account_id TEXT PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+			webhook_url TEXT DEFAULT '',
+			webhook_secret TEXT DEFAULT ''
+		);
+		`,
+		// Migration 2: Connection state transitions recorded by the connection supervisor
+		`
+		CREATE TABLE IF NOT EXISTS account_connection_events (
+			id BIGSERIAL PRIMARY KEY,
+			account_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+			state TEXT NOT NULL,
+			reason TEXT DEFAULT '',
+			attempt INTEGER DEFAULT 0,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_account_connection_events_account ON account_connection_events(account_id, created_at);
+		`,
+		// Migration 3: Per-account overrides of the global settings, NULL uses the global value
+		`
+		CREATE TABLE IF NOT EXISTS account_settings (
+			account_id TEXT PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+			auto_mark_read BOOLEAN,
+			auto_reply_message TEXT,
+			device_name TEXT,
+			max_image_size BIGINT,
+			max_file_size BIGINT,
+			max_video_size BIGINT,
+			disappearing_duration INTEGER,
+			webhook_secret TEXT,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);
+		`,
+	}
+}
@@ -0,0 +1,184 @@
+package account
+
+import (
+	"testing"
+	"time"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runRepository runs a case against an account repository of every backend
+func runRepository(t *testing.T, fn func(t *testing.T, repo domainAccount.IAccountRepository)) {
+	storagetest.Run(t, func(t *testing.T, backend storagetest.Backend) {
+		if backend.Name == storagetest.Postgres {
+			fn(t, NewPostgresRepository(backend.DB))
+			return
+		}
+		fn(t, NewSQLiteRepository(backend.DB))
+	})
+}
+
+func TestRepositoryAccounts(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainAccount.IAccountRepository) {
+		createdAt := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+		require.NoError(t, repo.CreateAccount(&domainAccount.Account{
+			ID: "business", Status: domainAccount.StatusDisconnected, CreatedAt: createdAt,
+		}))
+		require.NoError(t, repo.CreateAccount(&domainAccount.Account{
+			ID: "personal", Status: domainAccount.StatusDisconnected, CreatedAt: createdAt.Add(time.Hour),
+		}))
+
+		// Account IDs are unique
+		assert.Error(t, repo.CreateAccount(&domainAccount.Account{ID: "business", CreatedAt: createdAt}))
+
+		account, err := repo.GetAccount("business")
+		require.NoError(t, err)
+		require.NotNil(t, account)
+		assert.Equal(t, domainAccount.StatusDisconnected, account.Status)
+		assert.True(t, account.CreatedAt.Equal(createdAt))
+		assert.True(t, account.LastConnected.IsZero())
+
+		missing, err := repo.GetAccount("missing")
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+
+		connectedAt := createdAt.Add(2 * time.Hour)
+		account.Status = domainAccount.StatusLoggedIn
+		account.PhoneNumber = "6281234567890"
+		account.DeviceID = "6281234567890:12@s.whatsapp.net"
+		account.LastConnected = connectedAt
+		require.NoError(t, repo.UpdateAccount(account))
+
+		account, err = repo.GetAccount("business")
+		require.NoError(t, err)
+		assert.Equal(t, domainAccount.StatusLoggedIn, account.Status)
+		assert.Equal(t, "6281234567890", account.PhoneNumber)
+		assert.Equal(t, "6281234567890:12@s.whatsapp.net", account.DeviceID)
+		assert.True(t, account.LastConnected.Equal(connectedAt))
+
+		accounts, err := repo.ListAccounts()
+		require.NoError(t, err)
+		require.Len(t, accounts, 2)
+		assert.Equal(t, "personal", accounts[0].ID)
+		assert.Equal(t, "business", accounts[1].ID)
+	})
+}
+
+func TestRepositoryWebhooks(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainAccount.IAccountRepository) {
+		require.NoError(t, repo.CreateAccount(&domainAccount.Account{ID: "business", CreatedAt: time.Now()}))
+
+		webhook, err := repo.GetWebhook("business")
+		require.NoError(t, err)
+		assert.Equal(t, &domainAccount.WebhookInfo{}, webhook)
+
+		require.NoError(t, repo.SetWebhook("business", "https://example.com/hook", "secret"))
+		webhook, err = repo.GetWebhook("business")
+		require.NoError(t, err)
+		assert.Equal(t, &domainAccount.WebhookInfo{URL: "https://example.com/hook", Secret: "secret"}, webhook)
+
+		// Deleting the account removes its webhook
+		require.NoError(t, repo.DeleteAccount("business"))
+		account, err := repo.GetAccount("business")
+		require.NoError(t, err)
+		assert.Nil(t, account)
+
+		webhook, err = repo.GetWebhook("business")
+		require.NoError(t, err)
+		assert.Equal(t, &domainAccount.WebhookInfo{}, webhook)
+	})
+}
+
+func TestRepositoryConnectionEvents(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainAccount.IAccountRepository) {
+		require.NoError(t, repo.CreateAccount(&domainAccount.Account{ID: "business", CreatedAt: time.Now()}))
+
+		startedAt := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+		transitions := []*domainAccount.ConnectionEvent{
+			{AccountID: "business", State: domainAccount.ConnectionConnected, CreatedAt: startedAt},
+			{AccountID: "business", State: domainAccount.ConnectionDisconnected, CreatedAt: startedAt.Add(time.Minute)},
+			{AccountID: "business", State: domainAccount.ConnectionReconnectFailed, Reason: "dial timeout", Attempt: 1, CreatedAt: startedAt.Add(2 * time.Minute)},
+		}
+		for _, event := range transitions {
+			require.NoError(t, repo.AddConnectionEvent(event))
+			assert.NotZero(t, event.ID)
+		}
+
+		events, err := repo.ListConnectionEvents("business", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		assert.Equal(t, domainAccount.ConnectionReconnectFailed, events[0].State)
+		assert.Equal(t, "dial timeout", events[0].Reason)
+		assert.Equal(t, 1, events[0].Attempt)
+		assert.True(t, events[0].CreatedAt.Equal(startedAt.Add(2*time.Minute)))
+		assert.Equal(t, domainAccount.ConnectionConnected, events[2].State)
+
+		page, err := repo.ListConnectionEvents("business", 1, 1)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, domainAccount.ConnectionDisconnected, page[0].State)
+
+		// Pruning keeps the newest events
+		require.NoError(t, repo.PruneConnectionEvents("business", 2))
+		events, err = repo.ListConnectionEvents("business", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, domainAccount.ConnectionReconnectFailed, events[0].State)
+		assert.Equal(t, domainAccount.ConnectionDisconnected, events[1].State)
+
+		// Events of an account are removed along with it
+		require.NoError(t, repo.DeleteAccount("business"))
+		events, err = repo.ListConnectionEvents("business", 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+}
+
+func TestRepositorySettings(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainAccount.IAccountRepository) {
+		require.NoError(t, repo.CreateAccount(&domainAccount.Account{ID: "business", CreatedAt: time.Now()}))
+
+		// An account without overrides uses every global value
+		settings, err := repo.GetSettings("business")
+		require.NoError(t, err)
+		assert.Equal(t, &domainAccount.Settings{}, settings)
+
+		autoMarkRead := false
+		deviceName := "Sales Desk"
+		maxImageSize := int64(5 * 1024 * 1024)
+		duration := 604800
+		settings.AutoMarkRead = &autoMarkRead
+		settings.DeviceName = &deviceName
+		settings.MaxImageSize = &maxImageSize
+		settings.DisappearingDuration = &duration
+		require.NoError(t, repo.SaveSettings("business", settings))
+
+		saved, err := repo.GetSettings("business")
+		require.NoError(t, err)
+		assert.Equal(t, settings, saved)
+		assert.Nil(t, saved.AutoReplyMessage)
+		assert.Nil(t, saved.WebhookSecret)
+
+		secret := "per-account-secret"
+		saved.DeviceName = nil
+		saved.WebhookSecret = &secret
+		require.NoError(t, repo.SaveSettings("business", saved))
+
+		updated, err := repo.GetSettings("business")
+		require.NoError(t, err)
+		assert.Nil(t, updated.DeviceName)
+		require.NotNil(t, updated.WebhookSecret)
+		assert.Equal(t, secret, *updated.WebhookSecret)
+		require.NotNil(t, updated.AutoMarkRead)
+		assert.False(t, *updated.AutoMarkRead)
+
+		// Settings of an account are removed along with it
+		require.NoError(t, repo.DeleteAccount("business"))
+		settings, err = repo.GetSettings("business")
+		require.NoError(t, err)
+		assert.Equal(t, &domainAccount.Settings{}, settings)
+	})
+}
@@ -0,0 +1,65 @@
+package account
+
+import (
+	"time"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+)
+
+// AddConnectionEvent records a connection state transition of an account
+func (r *SQLiteRepository) AddConnectionEvent(event *domainAccount.ConnectionEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	result, err := r.db.Exec(`
+		INSERT INTO account_connection_events (account_id, state, reason, attempt, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, event.AccountID, event.State, event.Reason, event.Attempt, event.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	event.ID, err = result.LastInsertId()
+	return err
+}
+
+// ListConnectionEvents returns the connection state transitions of an account, newest first
+func (r *SQLiteRepository) ListConnectionEvents(accountID string, limit, offset int) ([]*domainAccount.ConnectionEvent, error) {
+	rows, err := r.db.Query(`
+		SELECT id, account_id, state, reason, attempt, created_at
+		FROM account_connection_events
+		WHERE account_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, accountID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domainAccount.ConnectionEvent
+	for rows.Next() {
+		event := &domainAccount.ConnectionEvent{}
+		if err := rows.Scan(&event.ID, &event.AccountID, &event.State, &event.Reason, &event.Attempt, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// PruneConnectionEvents deletes all but the newest keep connection events of an account
+func (r *SQLiteRepository) PruneConnectionEvents(accountID string, keep int) error {
+	_, err := r.db.Exec(`
+		DELETE FROM account_connection_events
+		WHERE account_id = ? AND id NOT IN (
+			SELECT id FROM account_connection_events
+			WHERE account_id = ?
+			ORDER BY created_at DESC, id DESC
+			LIMIT ?
+		)
+	`, accountID, accountID, keep)
+	return err
+}
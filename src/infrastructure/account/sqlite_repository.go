@@ -33,6 +33,29 @@ func (r *SQLiteRepository) initTables() {
 			webhook_secret TEXT DEFAULT '',
 			FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
 		)`,
+		`CREATE TABLE IF NOT EXISTS account_connection_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id TEXT NOT NULL,
+			state TEXT NOT NULL,
+			reason TEXT DEFAULT '',
+			attempt INTEGER DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_account_connection_events_account ON account_connection_events(account_id, created_at)`,
+		`CREATE TABLE IF NOT EXISTS account_settings (
+			account_id TEXT PRIMARY KEY,
+			auto_mark_read BOOLEAN,
+			auto_reply_message TEXT,
+			device_name TEXT,
+			max_image_size INTEGER,
+			max_file_size INTEGER,
+			max_video_size INTEGER,
+			disappearing_duration INTEGER,
+			webhook_secret TEXT,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+		)`,
 	}
 
 	for _, query := range queries {
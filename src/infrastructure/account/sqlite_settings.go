@@ -0,0 +1,53 @@
+package account
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+)
+
+// GetSettings returns the setting overrides of an account, empty when it has none
+func (r *SQLiteRepository) GetSettings(accountID string) (*domainAccount.Settings, error) {
+	settings := &domainAccount.Settings{}
+	err := r.db.QueryRow(`
+		SELECT auto_mark_read, auto_reply_message, device_name, max_image_size, max_file_size,
+			max_video_size, disappearing_duration, webhook_secret
+		FROM account_settings
+		WHERE account_id = ?
+	`, accountID).Scan(
+		&settings.AutoMarkRead, &settings.AutoReplyMessage, &settings.DeviceName, &settings.MaxImageSize,
+		&settings.MaxFileSize, &settings.MaxVideoSize, &settings.DisappearingDuration, &settings.WebhookSecret,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// SaveSettings stores the setting overrides of an account, replacing the previous ones
+func (r *SQLiteRepository) SaveSettings(accountID string, settings *domainAccount.Settings) error {
+	_, err := r.db.Exec(`
+		INSERT INTO account_settings (
+			account_id, auto_mark_read, auto_reply_message, device_name, max_image_size, max_file_size,
+			max_video_size, disappearing_duration, webhook_secret, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(account_id) DO UPDATE SET
+			auto_mark_read = excluded.auto_mark_read,
+			auto_reply_message = excluded.auto_reply_message,
+			device_name = excluded.device_name,
+			max_image_size = excluded.max_image_size,
+			max_file_size = excluded.max_file_size,
+			max_video_size = excluded.max_video_size,
+			disappearing_duration = excluded.disappearing_duration,
+			webhook_secret = excluded.webhook_secret,
+			updated_at = excluded.updated_at
+	`, accountID, settings.AutoMarkRead, settings.AutoReplyMessage, settings.DeviceName, settings.MaxImageSize,
+		settings.MaxFileSize, settings.MaxVideoSize, settings.DisappearingDuration, settings.WebhookSecret, time.Now())
+	return err
+}
@@ -0,0 +1,109 @@
+package apikey
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	domainApiKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/sirupsen/logrus"
+)
+
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRepository(db *sql.DB) domainApiKey.IAPIKeyRepository {
+	repo := &PostgresRepository{db: db}
+	repo.initTables()
+	return repo
+}
+
+func (r *PostgresRepository) initTables() {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS api_keys (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			prefix TEXT NOT NULL,
+			key_hash TEXT NOT NULL UNIQUE,
+			account_ids TEXT NOT NULL DEFAULT '',
+			scopes TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL,
+			last_used_at TIMESTAMPTZ,
+			revoked_at TIMESTAMPTZ
+		)`,
+	}
+
+	for _, query := range queries {
+		if _, err := r.db.Exec(query); err != nil {
+			logrus.Errorf("Failed to create table: %v", err)
+		}
+	}
+}
+
+func (r *PostgresRepository) CreateAPIKey(key *domainApiKey.APIKey) error {
+	key.CreatedAt = time.Now().UTC()
+
+	query := `INSERT INTO api_keys (id, name, prefix, key_hash, account_ids, scopes, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := r.db.Exec(query, key.ID, key.Name, key.Prefix, key.KeyHash,
+		strings.Join(key.AccountIDs, ","), strings.Join(key.Scopes, ","), key.CreatedAt)
+	return err
+}
+
+func (r *PostgresRepository) GetAPIKey(keyID string) (*domainApiKey.APIKey, error) {
+	query := `SELECT id, name, prefix, key_hash, account_ids, scopes, created_at, last_used_at, revoked_at
+			  FROM api_keys WHERE id = $1`
+
+	key, err := scanAPIKey(r.db.QueryRow(query, keyID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return key, err
+}
+
+func (r *PostgresRepository) GetAPIKeyByHash(keyHash string) (*domainApiKey.APIKey, error) {
+	query := `SELECT id, name, prefix, key_hash, account_ids, scopes, created_at, last_used_at, revoked_at
+			  FROM api_keys WHERE key_hash = $1`
+
+	key, err := scanAPIKey(r.db.QueryRow(query, keyHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return key, err
+}
+
+func (r *PostgresRepository) ListAPIKeys() ([]*domainApiKey.APIKey, error) {
+	query := `SELECT id, name, prefix, key_hash, account_ids, scopes, created_at, last_used_at, revoked_at
+			  FROM api_keys ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*domainApiKey.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *PostgresRepository) RevokeAPIKey(keyID string, revokedAt time.Time) error {
+	_, err := r.db.Exec(`UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`, revokedAt.UTC(), keyID)
+	return err
+}
+
+func (r *PostgresRepository) TouchAPIKey(keyID string, usedAt time.Time) error {
+	_, err := r.db.Exec(`UPDATE api_keys SET last_used_at = $1 WHERE id = $2`, usedAt.UTC(), keyID)
+	return err
+}
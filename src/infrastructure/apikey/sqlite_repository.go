@@ -0,0 +1,142 @@
+package apikey
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	domainApiKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/sirupsen/logrus"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) domainApiKey.IAPIKeyRepository {
+	repo := &SQLiteRepository{db: db}
+	repo.initTables()
+	return repo
+}
+
+func (r *SQLiteRepository) initTables() {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS api_keys (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			prefix TEXT NOT NULL,
+			key_hash TEXT NOT NULL UNIQUE,
+			account_ids TEXT NOT NULL DEFAULT '',
+			scopes TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL,
+			last_used_at DATETIME,
+			revoked_at DATETIME
+		)`,
+	}
+
+	for _, query := range queries {
+		if _, err := r.db.Exec(query); err != nil {
+			logrus.Errorf("Failed to create table: %v", err)
+		}
+	}
+}
+
+func (r *SQLiteRepository) CreateAPIKey(key *domainApiKey.APIKey) error {
+	key.CreatedAt = time.Now().UTC()
+
+	query := `INSERT INTO api_keys (id, name, prefix, key_hash, account_ids, scopes, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, key.ID, key.Name, key.Prefix, key.KeyHash,
+		strings.Join(key.AccountIDs, ","), strings.Join(key.Scopes, ","), key.CreatedAt)
+	return err
+}
+
+func (r *SQLiteRepository) GetAPIKey(keyID string) (*domainApiKey.APIKey, error) {
+	query := `SELECT id, name, prefix, key_hash, account_ids, scopes, created_at, last_used_at, revoked_at
+			  FROM api_keys WHERE id = ?`
+
+	key, err := scanAPIKey(r.db.QueryRow(query, keyID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return key, err
+}
+
+func (r *SQLiteRepository) GetAPIKeyByHash(keyHash string) (*domainApiKey.APIKey, error) {
+	query := `SELECT id, name, prefix, key_hash, account_ids, scopes, created_at, last_used_at, revoked_at
+			  FROM api_keys WHERE key_hash = ?`
+
+	key, err := scanAPIKey(r.db.QueryRow(query, keyHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return key, err
+}
+
+func (r *SQLiteRepository) ListAPIKeys() ([]*domainApiKey.APIKey, error) {
+	query := `SELECT id, name, prefix, key_hash, account_ids, scopes, created_at, last_used_at, revoked_at
+			  FROM api_keys ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*domainApiKey.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *SQLiteRepository) RevokeAPIKey(keyID string, revokedAt time.Time) error {
+	_, err := r.db.Exec(`UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`, revokedAt.UTC(), keyID)
+	return err
+}
+
+func (r *SQLiteRepository) TouchAPIKey(keyID string, usedAt time.Time) error {
+	_, err := r.db.Exec(`UPDATE api_keys SET last_used_at = ? WHERE id = ?`, usedAt.UTC(), keyID)
+	return err
+}
+
+func scanAPIKey(scanner interface{ Scan(...any) error }) (*domainApiKey.APIKey, error) {
+	key := &domainApiKey.APIKey{}
+	var (
+		accountIDs string
+		scopes     string
+		lastUsedAt sql.NullTime
+		revokedAt  sql.NullTime
+	)
+
+	err := scanner.Scan(&key.ID, &key.Name, &key.Prefix, &key.KeyHash, &accountIDs, &scopes,
+		&key.CreatedAt, &lastUsedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	key.AccountIDs = splitList(accountIDs)
+	key.Scopes = splitList(scopes)
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, nil
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
@@ -0,0 +1,50 @@
+package autoreply
+
+import (
+	"database/sql"
+	"strings"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+)
+
+// ruleColumns are the columns scanRule reads, in order
+const ruleColumns = `id, account_id, name, enabled, priority, match_type, pattern, case_sensitive, include_groups,
+	active_hours, active_days, timezone, cooldown_minutes, reply_type, reply_text, template_id, media_url, media_type,
+	hit_count, last_hit_at, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRule(row rowScanner) (*domainAutoReply.Rule, error) {
+	rule := &domainAutoReply.Rule{}
+	var activeDays string
+	var lastHitAt sql.NullTime
+	err := row.Scan(
+		&rule.ID, &rule.AccountID, &rule.Name, &rule.Enabled, &rule.Priority, &rule.MatchType, &rule.Pattern,
+		&rule.CaseSensitive, &rule.IncludeGroups, &rule.ActiveHours, &activeDays, &rule.Timezone,
+		&rule.CooldownMinutes, &rule.ReplyType, &rule.ReplyText, &rule.TemplateID, &rule.MediaURL, &rule.MediaType,
+		&rule.HitCount, &lastHitAt, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.ActiveDays = splitDays(activeDays)
+	if lastHitAt.Valid {
+		rule.LastHitAt = &lastHitAt.Time
+	}
+	return rule, nil
+}
+
+// joinDays stores the active days as a comma separated list
+func joinDays(days []string) string {
+	return strings.Join(days, ",")
+}
+
+func splitDays(days string) []string {
+	if days == "" {
+		return []string{}
+	}
+	return strings.Split(days, ",")
+}
@@ -0,0 +1,98 @@
+package autoreply
+
+import (
+	"testing"
+	"time"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	infraAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/account"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runRepository runs a case against an auto-reply repository of every backend, with the account "default"
+func runRepository(t *testing.T, fn func(t *testing.T, repo domainAutoReply.IAutoReplyRepository)) {
+	storagetest.Run(t, func(t *testing.T, backend storagetest.Backend) {
+		accountRepo := infraAccount.NewSQLiteRepository(backend.DB)
+		repo := NewSQLiteRepository(backend.DB)
+		if backend.Name == storagetest.Postgres {
+			accountRepo = infraAccount.NewPostgresRepository(backend.DB)
+			repo = NewPostgresRepository(backend.DB)
+		}
+		require.NoError(t, accountRepo.CreateAccount(&domainAccount.Account{ID: "default", CreatedAt: time.Now()}))
+		fn(t, repo)
+	})
+}
+
+func TestRepositoryRules(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainAutoReply.IAutoReplyRepository) {
+		pricing := &domainAutoReply.Rule{
+			ID: "pricing", AccountID: "default", Name: "pricing", Enabled: true, Priority: 10,
+			MatchType: domainAutoReply.MatchKeyword, Pattern: "price,pricing",
+			ReplyType: domainAutoReply.ReplyText, ReplyText: "See our price list",
+		}
+		closed := &domainAutoReply.Rule{
+			ID: "closed", AccountID: "default", Name: "closed", Enabled: true, Priority: 1,
+			MatchType: domainAutoReply.MatchAny, ActiveHours: "17:00-08:00", ActiveDays: []string{"sat", "sun"},
+			Timezone: "Asia/Jakarta", CooldownMinutes: 60, ReplyType: domainAutoReply.ReplyText, ReplyText: "We are closed",
+		}
+		require.NoError(t, repo.CreateRule(pricing))
+		require.NoError(t, repo.CreateRule(closed))
+
+		// Rule names are unique per account
+		assert.Error(t, repo.CreateRule(&domainAutoReply.Rule{ID: "other", AccountID: "default", Name: "pricing",
+			MatchType: domainAutoReply.MatchAny, ReplyType: domainAutoReply.ReplyText}))
+
+		rules, err := repo.ListRules("default")
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		assert.Equal(t, "closed", rules[0].ID, "rules are ordered by priority")
+		assert.Equal(t, []string{"sat", "sun"}, rules[0].ActiveDays)
+		assert.Equal(t, []string{}, rules[1].ActiveDays)
+		assert.Nil(t, rules[1].LastHitAt)
+
+		rule, err := repo.GetRule("default", "pricing")
+		require.NoError(t, err)
+		require.NotNil(t, rule)
+		rule.Enabled = false
+		rule.Pattern = "price"
+		require.NoError(t, repo.UpdateRule(rule))
+		rule, err = repo.GetRule("default", "pricing")
+		require.NoError(t, err)
+		assert.False(t, rule.Enabled)
+		assert.Equal(t, "price", rule.Pattern)
+
+		missing, err := repo.GetRule("default", "missing")
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+
+		contact := "6281234567890@s.whatsapp.net"
+		lastReply, err := repo.GetLastReply("closed", contact)
+		require.NoError(t, err)
+		assert.True(t, lastReply.IsZero())
+
+		hitAt := time.Date(2025, 1, 4, 20, 0, 0, 0, time.UTC)
+		require.NoError(t, repo.RecordHit("closed", contact, hitAt))
+		require.NoError(t, repo.RecordHit("closed", contact, hitAt.Add(2*time.Hour)))
+
+		lastReply, err = repo.GetLastReply("closed", contact)
+		require.NoError(t, err)
+		assert.True(t, lastReply.Equal(hitAt.Add(2*time.Hour)))
+		rule, err = repo.GetRule("default", "closed")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), rule.HitCount)
+		require.NotNil(t, rule.LastHitAt)
+		assert.True(t, rule.LastHitAt.Equal(hitAt.Add(2*time.Hour)))
+
+		// Deleting a rule drops its cooldowns
+		require.NoError(t, repo.DeleteRule("default", "closed"))
+		lastReply, err = repo.GetLastReply("closed", contact)
+		require.NoError(t, err)
+		assert.True(t, lastReply.IsZero())
+		rules, err = repo.ListRules("default")
+		require.NoError(t, err)
+		assert.Len(t, rules, 1)
+	})
+}
@@ -0,0 +1,165 @@
+package autoreply
+
+import (
+	"database/sql"
+	"time"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	"github.com/sirupsen/logrus"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) domainAutoReply.IAutoReplyRepository {
+	repo := &SQLiteRepository{db: db}
+	repo.initTables()
+	return repo
+}
+
+func (r *SQLiteRepository) initTables() {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS auto_reply_rules (
+			id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			priority INTEGER NOT NULL DEFAULT 0,
+			match_type TEXT NOT NULL,
+			pattern TEXT NOT NULL DEFAULT '',
+			case_sensitive BOOLEAN NOT NULL DEFAULT FALSE,
+			include_groups BOOLEAN NOT NULL DEFAULT FALSE,
+			active_hours TEXT NOT NULL DEFAULT '',
+			active_days TEXT NOT NULL DEFAULT '',
+			timezone TEXT NOT NULL DEFAULT '',
+			cooldown_minutes INTEGER NOT NULL DEFAULT 0,
+			reply_type TEXT NOT NULL,
+			reply_text TEXT NOT NULL DEFAULT '',
+			template_id TEXT NOT NULL DEFAULT '',
+			media_url TEXT NOT NULL DEFAULT '',
+			media_type TEXT NOT NULL DEFAULT '',
+			hit_count INTEGER NOT NULL DEFAULT 0,
+			last_hit_at DATETIME,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			UNIQUE (account_id, name),
+			FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_auto_reply_rules_account ON auto_reply_rules(account_id, priority)`,
+		`CREATE TABLE IF NOT EXISTS auto_reply_cooldowns (
+			rule_id TEXT NOT NULL,
+			contact_jid TEXT NOT NULL,
+			replied_at DATETIME NOT NULL,
+			PRIMARY KEY (rule_id, contact_jid),
+			FOREIGN KEY (rule_id) REFERENCES auto_reply_rules(id) ON DELETE CASCADE
+		)`,
+	}
+
+	for _, query := range queries {
+		if _, err := r.db.Exec(query); err != nil {
+			logrus.Errorf("Failed to create table: %v", err)
+		}
+	}
+}
+
+func (r *SQLiteRepository) CreateRule(rule *domainAutoReply.Rule) error {
+	now := time.Now().UTC()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	query := `INSERT INTO auto_reply_rules (id, account_id, name, enabled, priority, match_type, pattern, case_sensitive,
+				include_groups, active_hours, active_days, timezone, cooldown_minutes, reply_type, reply_text, template_id,
+				media_url, media_type, hit_count, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?)`
+
+	_, err := r.db.Exec(query, rule.ID, rule.AccountID, rule.Name, rule.Enabled, rule.Priority, rule.MatchType,
+		rule.Pattern, rule.CaseSensitive, rule.IncludeGroups, rule.ActiveHours, joinDays(rule.ActiveDays),
+		rule.Timezone, rule.CooldownMinutes, rule.ReplyType, rule.ReplyText, rule.TemplateID, rule.MediaURL,
+		rule.MediaType, rule.CreatedAt, rule.UpdatedAt)
+	return err
+}
+
+func (r *SQLiteRepository) GetRule(accountID, ruleID string) (*domainAutoReply.Rule, error) {
+	query := `SELECT ` + ruleColumns + ` FROM auto_reply_rules WHERE account_id = ? AND id = ?`
+
+	rule, err := scanRule(r.db.QueryRow(query, accountID, ruleID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (r *SQLiteRepository) ListRules(accountID string) ([]*domainAutoReply.Rule, error) {
+	query := `SELECT ` + ruleColumns + ` FROM auto_reply_rules WHERE account_id = ? ORDER BY priority ASC, created_at ASC`
+
+	rows, err := r.db.Query(query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domainAutoReply.Rule
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (r *SQLiteRepository) UpdateRule(rule *domainAutoReply.Rule) error {
+	rule.UpdatedAt = time.Now().UTC()
+
+	query := `UPDATE auto_reply_rules
+			  SET name = ?, enabled = ?, priority = ?, match_type = ?, pattern = ?, case_sensitive = ?, include_groups = ?,
+				active_hours = ?, active_days = ?, timezone = ?, cooldown_minutes = ?, reply_type = ?, reply_text = ?,
+				template_id = ?, media_url = ?, media_type = ?, updated_at = ?
+			  WHERE account_id = ? AND id = ?`
+
+	_, err := r.db.Exec(query, rule.Name, rule.Enabled, rule.Priority, rule.MatchType, rule.Pattern,
+		rule.CaseSensitive, rule.IncludeGroups, rule.ActiveHours, joinDays(rule.ActiveDays), rule.Timezone,
+		rule.CooldownMinutes, rule.ReplyType, rule.ReplyText, rule.TemplateID, rule.MediaURL, rule.MediaType,
+		rule.UpdatedAt, rule.AccountID, rule.ID)
+	return err
+}
+
+func (r *SQLiteRepository) DeleteRule(accountID, ruleID string) error {
+	_, err := r.db.Exec(`DELETE FROM auto_reply_rules WHERE account_id = ? AND id = ?`, accountID, ruleID)
+	return err
+}
+
+func (r *SQLiteRepository) RecordHit(ruleID, contactJID string, at time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE auto_reply_rules SET hit_count = hit_count + 1, last_hit_at = ? WHERE id = ?`, at, ruleID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO auto_reply_cooldowns (rule_id, contact_jid, replied_at) VALUES (?, ?, ?)
+			ON CONFLICT(rule_id, contact_jid) DO UPDATE SET replied_at = excluded.replied_at`, ruleID, contactJID, at); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *SQLiteRepository) GetLastReply(ruleID, contactJID string) (time.Time, error) {
+	var repliedAt time.Time
+	err := r.db.QueryRow(`SELECT replied_at FROM auto_reply_cooldowns WHERE rule_id = ? AND contact_jid = ?`,
+		ruleID, contactJID).Scan(&repliedAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	return repliedAt, err
+}
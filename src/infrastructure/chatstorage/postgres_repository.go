@@ -0,0 +1,849 @@
+package chatstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/lib/pq"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// PostgresRepository implements Repository using PostgreSQL
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL repository
+func NewPostgresRepository(db *sql.DB) domainChatStorage.IChatStorageRepository {
+	return &PostgresRepository{db: db}
+}
+
+const postgresMessageColumns = `account_id, id, chat_jid, sender, content, timestamp, is_from_me,
+			media_type, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, is_revoked, edit_count, created_at, updated_at`
+
+const postgresUpsertMessage = `
+		INSERT INTO messages (
+			account_id, id, chat_jid, sender, content, timestamp, is_from_me,
+			media_type, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (account_id, id, chat_jid) DO UPDATE SET
+			sender = excluded.sender,
+			content = CASE WHEN messages.edit_count > 0 THEN messages.content ELSE excluded.content END,
+			timestamp = excluded.timestamp,
+			is_from_me = excluded.is_from_me,
+			media_type = excluded.media_type,
+			filename = excluded.filename,
+			url = excluded.url,
+			media_key = excluded.media_key,
+			file_sha256 = excluded.file_sha256,
+			file_enc_sha256 = excluded.file_enc_sha256,
+			file_length = excluded.file_length,
+			updated_at = excluded.updated_at
+	`
+
+var postgresPendingEditStatements = [2]string{rebind(pendingEditStatements[0]), rebind(pendingEditStatements[1])}
+
+// StoreChat creates or updates a chat
+func (r *PostgresRepository) StoreChat(chat *domainChatStorage.Chat) error {
+	now := time.Now()
+	chat.UpdatedAt = now
+
+	query := `
+		INSERT INTO chats (account_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (account_id, jid) DO UPDATE SET
+			name = excluded.name,
+			last_message_time = excluded.last_message_time,
+			ephemeral_expiration = excluded.ephemeral_expiration,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query, chat.AccountID, chat.JID, chat.Name, chat.LastMessageTime, chat.EphemeralExpiration, now, chat.UpdatedAt)
+	return err
+}
+
+// GetChat retrieves a chat of an account by JID
+func (r *PostgresRepository) GetChat(accountID, jid string) (*domainChatStorage.Chat, error) {
+	query := `
+		SELECT account_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at
+		FROM chats
+		WHERE account_id = $1 AND jid = $2
+	`
+
+	chat, err := r.scanChat(r.db.QueryRow(query, accountID, jid))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return chat, err
+}
+
+// GetMessageByID retrieves a message by its ID from any chat of an account
+func (r *PostgresRepository) GetMessageByID(accountID, id string) (*domainChatStorage.Message, error) {
+	query := `
+		SELECT ` + postgresMessageColumns + `
+		FROM messages
+		WHERE account_id = $1 AND id = $2
+		LIMIT 1
+	`
+
+	message, err := r.scanMessage(r.db.QueryRow(query, accountID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return message, err
+}
+
+// GetChats retrieves chats with filtering
+func (r *PostgresRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*domainChatStorage.Chat, error) {
+	conditions := []string{"c.account_id = ?"}
+	args := []any{filter.AccountID}
+
+	if filter.SearchName != "" {
+		conditions = append(conditions, "c.name ILIKE ?")
+		args = append(args, "%"+filter.SearchName+"%")
+	}
+
+	// A chat with several media messages is listed once
+	if filter.HasMedia {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM messages m WHERE m.account_id = c.account_id AND m.chat_jid = c.jid AND m.media_type != '')")
+	}
+
+	query := `
+		SELECT c.account_id, c.jid, c.name, c.last_message_time, c.ephemeral_expiration, c.created_at, c.updated_at
+		FROM chats c
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY c.last_message_time DESC
+	`
+
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chats []*domainChatStorage.Chat
+	for rows.Next() {
+		chat, err := r.scanChat(rows)
+		if err != nil {
+			return nil, err
+		}
+		chats = append(chats, chat)
+	}
+
+	return chats, rows.Err()
+}
+
+// DeleteChat deletes a chat of an account and all its messages
+func (r *PostgresRepository) DeleteChat(accountID, jid string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Reactions, edits and receipts have no foreign key since they may arrive before their message
+	for _, table := range []string{"message_reactions", "message_edits", "message_receipts", "messages"} {
+		if _, err = tx.Exec("DELETE FROM "+table+" WHERE account_id = $1 AND chat_jid = $2", accountID, jid); err != nil {
+			return err
+		}
+	}
+
+	if _, err = tx.Exec("DELETE FROM chats WHERE account_id = $1 AND jid = $2", accountID, jid); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// StoreMessage creates or updates a message
+func (r *PostgresRepository) StoreMessage(message *domainChatStorage.Message) error {
+	now := time.Now()
+	message.CreatedAt = now
+	message.UpdatedAt = now
+
+	// Skip empty messages
+	if message.Content == "" && message.MediaType == "" {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(postgresUpsertMessage,
+		message.AccountID, message.ID, message.ChatJID, message.Sender, message.Content,
+		message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
+		message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
+		message.FileLength, message.CreatedAt, message.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if err := applyPendingEdits(tx, postgresPendingEditStatements, message); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// StoreMessagesBatch creates or updates multiple messages in a single transaction
+func (r *PostgresRepository) StoreMessagesBatch(messages []*domainChatStorage.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(postgresUpsertMessage)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, message := range messages {
+		// Skip empty messages
+		if message.Content == "" && message.MediaType == "" {
+			continue
+		}
+
+		message.CreatedAt = now
+		message.UpdatedAt = now
+
+		_, err = stmt.Exec(
+			message.AccountID, message.ID, message.ChatJID, message.Sender, message.Content,
+			message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
+			message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
+			message.FileLength, message.CreatedAt, message.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to store message %s: %w", message.ID, err)
+		}
+		if err := applyPendingEdits(tx, postgresPendingEditStatements, message); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetMessages retrieves messages with filtering
+func (r *PostgresRepository) GetMessages(filter *domainChatStorage.MessageFilter) ([]*domainChatStorage.Message, error) {
+	conditions := []string{"account_id = ?", "chat_jid = ?"}
+	args := []any{filter.AccountID, filter.ChatJID}
+
+	if filter.StartTime != nil {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, *filter.StartTime)
+	}
+
+	if filter.EndTime != nil {
+		conditions = append(conditions, "timestamp <= ?")
+		args = append(args, *filter.EndTime)
+	}
+
+	if filter.MediaOnly {
+		conditions = append(conditions, "media_type != ''")
+	}
+
+	if filter.IsFromMe != nil {
+		conditions = append(conditions, "is_from_me = ?")
+		args = append(args, *filter.IsFromMe)
+	}
+
+	query := `
+		SELECT ` + postgresMessageColumns + `
+		FROM messages
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + messageOrder(filter) + `
+	`
+
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	return r.queryMessages(rebind(query), args...)
+}
+
+// SearchMessages performs database-level search for messages containing specific text
+func (r *PostgresRepository) SearchMessages(accountID, chatJID, searchText string, limit int) ([]*domainChatStorage.Message, error) {
+	if strings.TrimSpace(searchText) == "" {
+		return []*domainChatStorage.Message{}, nil
+	}
+
+	conditions := []string{"account_id = ?", "chat_jid = ?"}
+	args := []any{accountID, chatJID}
+
+	if terms := postgresSearchTerms(searchText); len(terms) > 0 {
+		// Every word has to match the start of a word in the message
+		conditions = append(conditions, postgresSearchVector+" @@ to_tsquery('simple', ?)")
+		args = append(args, buildTSQuery(terms))
+	} else {
+		conditions = append(conditions, "content ILIKE ?")
+		args = append(args, "%"+searchText+"%")
+	}
+
+	query := `
+		SELECT ` + postgresMessageColumns + `
+		FROM messages
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY timestamp DESC
+	`
+
+	if limit > 0 {
+		if limit > 1000 {
+			limit = 1000
+		}
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	messages, err := r.queryMessages(rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search messages: %w", err)
+	}
+	if messages == nil {
+		messages = []*domainChatStorage.Message{}
+	}
+
+	return messages, nil
+}
+
+// DeleteMessage deletes a specific message along with its reactions and edit history
+func (r *PostgresRepository) DeleteMessage(accountID, id, chatJID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"message_reactions", "message_edits"} {
+		if _, err = tx.Exec("DELETE FROM "+table+" WHERE account_id = $1 AND message_id = $2 AND chat_jid = $3", accountID, id, chatJID); err != nil {
+			return err
+		}
+	}
+
+	if _, err = tx.Exec("DELETE FROM message_receipts WHERE account_id = $1 AND message_id = $2", accountID, id); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec("DELETE FROM messages WHERE account_id = $1 AND id = $2 AND chat_jid = $3", accountID, id, chatJID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// StoreReaction creates or replaces the reaction of a sender to a message, an empty emoji removes it
+func (r *PostgresRepository) StoreReaction(reaction *domainChatStorage.MessageReaction) error {
+	if reaction.Emoji == "" {
+		_, err := r.db.Exec(
+			"DELETE FROM message_reactions WHERE account_id = $1 AND chat_jid = $2 AND message_id = $3 AND sender = $4",
+			reaction.AccountID, reaction.ChatJID, reaction.MessageID, reaction.Sender,
+		)
+		return err
+	}
+
+	query := `
+		INSERT INTO message_reactions (account_id, chat_jid, message_id, sender, emoji, timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (account_id, chat_jid, message_id, sender) DO UPDATE SET
+			emoji = excluded.emoji,
+			timestamp = excluded.timestamp
+	`
+
+	_, err := r.db.Exec(query, reaction.AccountID, reaction.ChatJID, reaction.MessageID, reaction.Sender, reaction.Emoji, reaction.Timestamp)
+	return err
+}
+
+// StoreMessageEdit records an edit in the history and replaces the message content with the edited text
+func (r *PostgresRepository) StoreMessageEdit(edit *domainChatStorage.MessageEdit) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The edited message may not be stored, in which case only the history is kept
+	err = tx.QueryRow(
+		"SELECT COALESCE(content, '') FROM messages WHERE account_id = $1 AND id = $2 AND chat_jid = $3 FOR UPDATE",
+		edit.AccountID, edit.MessageID, edit.ChatJID,
+	).Scan(&edit.PreviousContent)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get edited message: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO message_edits (account_id, chat_jid, message_id, previous_content, content, edited_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, edit.AccountID, edit.ChatJID, edit.MessageID, edit.PreviousContent, edit.Content, edit.EditedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store message edit: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE messages SET content = $1, edit_count = edit_count + 1, updated_at = $2
+		WHERE account_id = $3 AND id = $4 AND chat_jid = $5
+	`, edit.Content, time.Now(), edit.AccountID, edit.MessageID, edit.ChatJID)
+	if err != nil {
+		return fmt.Errorf("failed to update edited message: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// MarkMessageRevoked flags a message as revoked by its sender, its content is kept
+func (r *PostgresRepository) MarkMessageRevoked(accountID, id, chatJID string) error {
+	_, err := r.db.Exec(
+		"UPDATE messages SET is_revoked = TRUE, updated_at = $1 WHERE account_id = $2 AND id = $3 AND chat_jid = $4",
+		time.Now(), accountID, id, chatJID,
+	)
+	return err
+}
+
+// GetReactions retrieves the reactions to the given messages of a chat, oldest first
+func (r *PostgresRepository) GetReactions(accountID, chatJID string, messageIDs []string) ([]*domainChatStorage.MessageReaction, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT account_id, chat_jid, message_id, sender, emoji, timestamp
+		FROM message_reactions
+		WHERE account_id = $1 AND chat_jid = $2 AND message_id = ANY($3)
+		ORDER BY timestamp ASC
+	`
+
+	rows, err := r.db.Query(query, accountID, chatJID, pq.Array(messageIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reactions []*domainChatStorage.MessageReaction
+	for rows.Next() {
+		reaction := &domainChatStorage.MessageReaction{}
+		err := rows.Scan(&reaction.AccountID, &reaction.ChatJID, &reaction.MessageID, &reaction.Sender, &reaction.Emoji, &reaction.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		reactions = append(reactions, reaction)
+	}
+
+	return reactions, rows.Err()
+}
+
+// GetMessageEdits retrieves the edit history of the given messages of a chat, oldest first
+func (r *PostgresRepository) GetMessageEdits(accountID, chatJID string, messageIDs []string) ([]*domainChatStorage.MessageEdit, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT account_id, chat_jid, message_id, COALESCE(previous_content, ''), COALESCE(content, ''), edited_at
+		FROM message_edits
+		WHERE account_id = $1 AND chat_jid = $2 AND message_id = ANY($3)
+		ORDER BY edited_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(query, accountID, chatJID, pq.Array(messageIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edits []*domainChatStorage.MessageEdit
+	for rows.Next() {
+		edit := &domainChatStorage.MessageEdit{}
+		err := rows.Scan(&edit.AccountID, &edit.ChatJID, &edit.MessageID, &edit.PreviousContent, &edit.Content, &edit.EditedAt)
+		if err != nil {
+			return nil, err
+		}
+		edits = append(edits, edit)
+	}
+
+	return edits, rows.Err()
+}
+
+// StoreReceipt records the delivery or read time of a message for a recipient device, keeping the earliest of each.
+// A read receipt also marks the message as delivered when no delivery receipt was seen.
+func (r *PostgresRepository) StoreReceipt(receipt *domainChatStorage.MessageReceipt) error {
+	deliveredAt := receipt.DeliveredAt
+	if deliveredAt == nil {
+		deliveredAt = receipt.ReadAt
+	}
+
+	// LEAST ignores NULL values, so the first time seen is kept as well
+	query := `
+		INSERT INTO message_receipts (account_id, chat_jid, message_id, recipient, participant, delivered_at, read_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (account_id, message_id, recipient) DO UPDATE SET
+			delivered_at = LEAST(message_receipts.delivered_at, excluded.delivered_at),
+			read_at = LEAST(message_receipts.read_at, excluded.read_at)
+	`
+
+	_, err := r.db.Exec(query, receipt.AccountID, receipt.ChatJID, receipt.MessageID, receipt.Recipient,
+		receipt.Participant, deliveredAt, receipt.ReadAt)
+	return err
+}
+
+// GetReceipts retrieves the receipts of the given messages of an account.
+// Receipts are matched by message ID only, since their chat may be reported as a LID instead of a phone number.
+func (r *PostgresRepository) GetReceipts(accountID string, messageIDs []string) ([]*domainChatStorage.MessageReceipt, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT account_id, chat_jid, message_id, recipient, participant, delivered_at, read_at
+		FROM message_receipts
+		WHERE account_id = $1 AND message_id = ANY($2)
+		ORDER BY participant ASC, recipient ASC
+	`
+
+	rows, err := r.db.Query(query, accountID, pq.Array(messageIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receipts []*domainChatStorage.MessageReceipt
+	for rows.Next() {
+		receipt := &domainChatStorage.MessageReceipt{}
+		var deliveredAt, readAt sql.NullTime
+		err := rows.Scan(&receipt.AccountID, &receipt.ChatJID, &receipt.MessageID, &receipt.Recipient,
+			&receipt.Participant, &deliveredAt, &readAt)
+		if err != nil {
+			return nil, err
+		}
+		if deliveredAt.Valid {
+			receipt.DeliveredAt = &deliveredAt.Time
+		}
+		if readAt.Valid {
+			receipt.ReadAt = &readAt.Time
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, rows.Err()
+}
+
+// GetChatMessageCount returns the number of messages in a chat of an account
+func (r *PostgresRepository) GetChatMessageCount(accountID, chatJID string) (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM messages WHERE account_id = $1 AND chat_jid = $2", accountID, chatJID)
+}
+
+// GetAccountChatCount returns the number of chats of an account
+func (r *PostgresRepository) GetAccountChatCount(accountID string) (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM chats WHERE account_id = $1", accountID)
+}
+
+// GetTotalMessageCount returns the total number of messages
+func (r *PostgresRepository) GetTotalMessageCount() (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM messages")
+}
+
+// GetTotalChatCount returns the total number of chats
+func (r *PostgresRepository) GetTotalChatCount() (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM chats")
+}
+
+// TruncateAllChats deletes all chats, messages and their reactions, edits and receipts
+func (r *PostgresRepository) TruncateAllChats() error {
+	if _, err := r.db.Exec("TRUNCATE message_reactions, message_edits, message_receipts, messages, chats"); err != nil {
+		return fmt.Errorf("failed to truncate chats: %w", err)
+	}
+	return nil
+}
+
+// GetChatNameWithPushName determines the appropriate name for a chat with pushname support
+func (r *PostgresRepository) GetChatNameWithPushName(accountID string, jid types.JID, chatJID string, senderUser string, pushName string) string {
+	return chatNameWithPushName(r, accountID, jid, chatJID, senderUser, pushName)
+}
+
+func (r *PostgresRepository) CreateMessage(ctx context.Context, accountID string, evt *events.Message) error {
+	return createMessage(ctx, r, accountID, evt)
+}
+
+// GetStorageStatistics returns current storage statistics for logging purposes
+func (r *PostgresRepository) GetStorageStatistics() (chatCount int64, messageCount int64, err error) {
+	return storageStatistics(r)
+}
+
+// TruncateAllDataWithLogging performs truncation with detailed logging
+func (r *PostgresRepository) TruncateAllDataWithLogging(logPrefix string) error {
+	return truncateAllDataWithLogging(r, logPrefix)
+}
+
+// StoreSentMessageWithContext stores a message that was sent by the user with context cancellation support
+func (r *PostgresRepository) StoreSentMessageWithContext(ctx context.Context, accountID string, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error {
+	return storeSentMessage(ctx, r, accountID, messageID, senderJID, recipientJID, content, timestamp)
+}
+
+// queryMessages is a private helper running a query that selects postgresMessageColumns
+func (r *PostgresRepository) queryMessages(query string, args ...any) ([]*domainChatStorage.Message, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*domainChatStorage.Message
+	for rows.Next() {
+		message, err := r.scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+// getCount is a private helper for count queries
+func (r *PostgresRepository) getCount(query string, args ...any) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+// scanMessage is a private helper for scanning message rows
+func (r *PostgresRepository) scanMessage(scanner interface{ Scan(...any) error }) (*domainChatStorage.Message, error) {
+	message := &domainChatStorage.Message{}
+	err := scanner.Scan(
+		&message.AccountID, &message.ID, &message.ChatJID, &message.Sender, &message.Content,
+		&message.Timestamp, &message.IsFromMe, &message.MediaType, &message.Filename,
+		&message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
+		&message.FileLength, &message.IsRevoked, &message.EditCount, &message.CreatedAt, &message.UpdatedAt,
+	)
+	return message, err
+}
+
+// scanChat is a private helper for scanning chat rows
+func (r *PostgresRepository) scanChat(scanner interface{ Scan(...any) error }) (*domainChatStorage.Chat, error) {
+	chat := &domainChatStorage.Chat{}
+	err := scanner.Scan(
+		&chat.AccountID, &chat.JID, &chat.Name, &chat.LastMessageTime, &chat.EphemeralExpiration,
+		&chat.CreatedAt, &chat.UpdatedAt,
+	)
+	return chat, err
+}
+
+// rebind is a private helper turning the ? placeholders of a dynamically built query into $n placeholders
+func rebind(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c != '?' {
+			b.WriteRune(c)
+			continue
+		}
+		n++
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+// _____________________________________________________________________________________________________________________
+
+// InitializeSchema creates or migrates the database schema
+func (r *PostgresRepository) InitializeSchema() error {
+	version, err := r.getSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	migrations := r.getMigrations()
+	for i := version; i < len(migrations); i++ {
+		if err := r.runMigration(migrations[i], i+1); err != nil {
+			return fmt.Errorf("failed to run migration %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// getSchemaVersion returns the current schema version
+func (r *PostgresRepository) getSchemaVersion() (int, error) {
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_info (
+			version INTEGER PRIMARY KEY DEFAULT 0,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int
+	err = r.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_info").Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// runMigration executes a migration
+func (r *PostgresRepository) runMigration(migration string, version int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Without arguments the statements of a migration are sent in one simple query
+	if _, err := tx.Exec(migration); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO schema_info (version) VALUES ($1)
+			  ON CONFLICT (version) DO UPDATE SET updated_at = CURRENT_TIMESTAMP`
+	if _, err := tx.Exec(query, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// getMigrations returns all database migrations.
+// The versions are counted separately from the SQLite schema, which started without accounts.
+func (r *PostgresRepository) getMigrations() []string {
+	return []string{
+		// Migration 1: Chats and messages scoped by account, with their reactions, edits and receipts
+		`
+		CREATE TABLE IF NOT EXISTS chats (
+			account_id TEXT NOT NULL DEFAULT '',
+			jid TEXT NOT NULL,
+			name TEXT NOT NULL,
+			last_message_time TIMESTAMPTZ NOT NULL,
+			ephemeral_expiration BIGINT DEFAULT 0,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (account_id, jid)
+		);
+
+		CREATE TABLE IF NOT EXISTS messages (
+			account_id TEXT NOT NULL DEFAULT '',
+			id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			sender TEXT NOT NULL,
+			content TEXT,
+			timestamp TIMESTAMPTZ NOT NULL,
+			is_from_me BOOLEAN DEFAULT FALSE,
+			media_type TEXT,
+			filename TEXT,
+			url TEXT,
+			media_key BYTEA,
+			file_sha256 BYTEA,
+			file_enc_sha256 BYTEA,
+			file_length BIGINT DEFAULT 0,
+			is_revoked BOOLEAN DEFAULT FALSE,
+			edit_count INTEGER DEFAULT 0,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (account_id, id, chat_jid),
+			FOREIGN KEY (account_id, chat_jid) REFERENCES chats(account_id, jid) ON DELETE CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS message_reactions (
+			account_id TEXT NOT NULL DEFAULT '',
+			chat_jid TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			sender TEXT NOT NULL,
+			emoji TEXT NOT NULL,
+			timestamp TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (account_id, chat_jid, message_id, sender)
+		);
+
+		CREATE TABLE IF NOT EXISTS message_edits (
+			id BIGSERIAL PRIMARY KEY,
+			account_id TEXT NOT NULL DEFAULT '',
+			chat_jid TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			previous_content TEXT,
+			content TEXT,
+			edited_at TIMESTAMPTZ NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS message_receipts (
+			account_id TEXT NOT NULL DEFAULT '',
+			chat_jid TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			recipient TEXT NOT NULL,
+			participant TEXT NOT NULL,
+			delivered_at TIMESTAMPTZ,
+			read_at TIMESTAMPTZ,
+			PRIMARY KEY (account_id, message_id, recipient)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_messages_account_chat ON messages(account_id, chat_jid);
+		CREATE INDEX IF NOT EXISTS idx_messages_timestamp ON messages(timestamp);
+		CREATE INDEX IF NOT EXISTS idx_messages_media_type ON messages(media_type);
+		CREATE INDEX IF NOT EXISTS idx_messages_sender ON messages(sender);
+		CREATE INDEX IF NOT EXISTS idx_messages_id ON messages(account_id, id);
+		CREATE INDEX IF NOT EXISTS idx_chats_account_last_message ON chats(account_id, last_message_time);
+		CREATE INDEX IF NOT EXISTS idx_chats_name ON chats(name);
+		CREATE INDEX IF NOT EXISTS idx_message_edits_message ON message_edits(account_id, chat_jid, message_id);
+		CREATE INDEX IF NOT EXISTS idx_message_receipts_chat ON message_receipts(account_id, chat_jid);
+		`,
+
+		// Migration 2: Full-text index for message search
+		`
+		CREATE INDEX IF NOT EXISTS idx_messages_content_fts ON messages USING GIN (` + postgresSearchVector + `);
+		`,
+
+		// Migration 3: Starred messages and retention policies per account or chat
+		`
+		ALTER TABLE messages ADD COLUMN IF NOT EXISTS is_starred BOOLEAN DEFAULT FALSE;
+
+		CREATE TABLE IF NOT EXISTS retention_policies (
+			account_id TEXT NOT NULL DEFAULT '',
+			chat_jid TEXT NOT NULL DEFAULT '',
+			max_message_days INTEGER NOT NULL DEFAULT 0,
+			keep_starred BOOLEAN NOT NULL DEFAULT TRUE,
+			updated_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (account_id, chat_jid)
+		);
+		`,
+	}
+}
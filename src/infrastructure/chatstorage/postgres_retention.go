@@ -0,0 +1,97 @@
+package chatstorage
+
+import (
+	"fmt"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+// SetMessageStarred flags a message as starred, starred messages can be kept out of retention runs
+func (r *PostgresRepository) SetMessageStarred(accountID, chatJID, id string, starred bool) error {
+	_, err := r.db.Exec(
+		"UPDATE messages SET is_starred = $1, updated_at = $2 WHERE account_id = $3 AND id = $4 AND chat_jid = $5",
+		starred, time.Now(), accountID, id, chatJID,
+	)
+	return err
+}
+
+// GetRetentionPolicies retrieves every retention policy, account-wide policies before the chats of the account
+func (r *PostgresRepository) GetRetentionPolicies() ([]*domainChatStorage.RetentionPolicy, error) {
+	rows, err := r.db.Query(`
+		SELECT account_id, chat_jid, max_message_days, keep_starred, updated_at
+		FROM retention_policies
+		ORDER BY account_id ASC, chat_jid ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []*domainChatStorage.RetentionPolicy
+	for rows.Next() {
+		policy := &domainChatStorage.RetentionPolicy{}
+		if err := rows.Scan(&policy.AccountID, &policy.ChatJID, &policy.MaxMessageDays, &policy.KeepStarred, &policy.UpdatedAt); err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, rows.Err()
+}
+
+// StoreRetentionPolicy creates or replaces the policy of an account or chat
+func (r *PostgresRepository) StoreRetentionPolicy(policy *domainChatStorage.RetentionPolicy) error {
+	policy.UpdatedAt = time.Now()
+
+	_, err := r.db.Exec(`
+		INSERT INTO retention_policies (account_id, chat_jid, max_message_days, keep_starred, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (account_id, chat_jid) DO UPDATE SET
+			max_message_days = EXCLUDED.max_message_days,
+			keep_starred = EXCLUDED.keep_starred,
+			updated_at = EXCLUDED.updated_at
+	`, policy.AccountID, policy.ChatJID, policy.MaxMessageDays, policy.KeepStarred, policy.UpdatedAt)
+	return err
+}
+
+func (r *PostgresRepository) DeleteRetentionPolicy(accountID, chatJID string) error {
+	_, err := r.db.Exec("DELETE FROM retention_policies WHERE account_id = $1 AND chat_jid = $2", accountID, chatJID)
+	return err
+}
+
+// PruneMessages removes the messages selected by the filter and returns how many were removed
+func (r *PostgresRepository) PruneMessages(filter *domainChatStorage.MessagePruneFilter) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	conditions, args := pruneConditions(filter)
+	var removed int64
+	for _, statement := range pruneStatements(conditions) {
+		result, err := tx.Exec(rebind(statement), args...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to prune messages: %w", err)
+		}
+		// The last statement removes the messages themselves
+		removed, _ = result.RowsAffected()
+	}
+
+	return removed, tx.Commit()
+}
+
+// Vacuum reclaims the space of removed rows for reuse and refreshes the planner statistics,
+// the files are not shrunk since that would lock the tables
+func (r *PostgresRepository) Vacuum() error {
+	_, err := r.db.Exec("VACUUM (ANALYZE) messages, message_reactions, message_edits, message_receipts")
+	return err
+}
+
+// GetDatabaseSize returns the size of the whole database in bytes
+func (r *PostgresRepository) GetDatabaseSize() (int64, error) {
+	var size int64
+	err := r.db.QueryRow("SELECT pg_database_size(current_database())").Scan(&size)
+	return size, err
+}
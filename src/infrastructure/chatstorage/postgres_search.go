@@ -0,0 +1,104 @@
+package chatstorage
+
+import (
+	"fmt"
+	"strings"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+// postgresSearchVector is the indexed expression, queries have to repeat it exactly for the GIN index to be used.
+// The simple configuration neither stems nor drops stop words, so any word of any language can be found.
+const postgresSearchVector = "to_tsvector('simple', COALESCE(content, ''))"
+
+// SearchAllMessages searches the messages of an account across chats, best matches first.
+// Every word of the query has to match the start of a word in the message.
+func (r *PostgresRepository) SearchAllMessages(filter *domainChatStorage.MessageSearchFilter) ([]*domainChatStorage.MessageSearchResult, int64, error) {
+	terms := postgresSearchTerms(filter.Query)
+	if len(terms) == 0 {
+		return []*domainChatStorage.MessageSearchResult{}, 0, nil
+	}
+
+	conditions, args := searchFilterConditions(filter)
+	conditions = append(conditions, postgresSearchVector+" @@ query")
+	args = append([]any{buildTSQuery(terms)}, args...)
+
+	from := "messages m, to_tsquery('simple', ?) query"
+	where := strings.Join(conditions, " AND ")
+
+	total, err := r.getCount(rebind("SELECT COUNT(*) FROM "+from+" WHERE "+where), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	headline := fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=%d, MinWords=%d, MaxFragments=1, FragmentDelimiter=…",
+		searchHighlightStart, searchHighlightEnd, searchSnippetTokens, searchSnippetTokens/2)
+
+	query := `
+		SELECT m.account_id, m.id, m.chat_jid, m.sender, m.content, m.timestamp, m.is_from_me,
+			m.media_type, m.filename, m.url, m.media_key, m.file_sha256,
+			m.file_enc_sha256, m.file_length, m.is_revoked, m.edit_count, m.created_at, m.updated_at,
+			ts_headline('simple', COALESCE(m.content, ''), query, '` + headline + `'),
+			ts_rank(` + postgresSearchVector + `, query) AS score
+		FROM ` + from + `
+		WHERE ` + where + `
+		ORDER BY score DESC, m.timestamp DESC`
+
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(rebind(query), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search messages: %w", err)
+	}
+	defer rows.Close()
+
+	results := []*domainChatStorage.MessageSearchResult{}
+	for rows.Next() {
+		message := &domainChatStorage.Message{}
+		result := &domainChatStorage.MessageSearchResult{Message: message}
+		err := rows.Scan(
+			&message.AccountID, &message.ID, &message.ChatJID, &message.Sender, &message.Content,
+			&message.Timestamp, &message.IsFromMe, &message.MediaType, &message.Filename,
+			&message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
+			&message.FileLength, &message.IsRevoked, &message.EditCount, &message.CreatedAt, &message.UpdatedAt,
+			&result.Snippet, &result.Score,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan message: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating messages: %w", err)
+	}
+
+	return results, total, nil
+}
+
+// postgresSearchTerms splits a search text into words, dropping the characters to_tsquery treats as syntax
+func postgresSearchTerms(text string) []string {
+	return strings.FieldsFunc(text, func(c rune) bool {
+		return strings.ContainsRune("&|!():*<>'\"\\ \t\r\n", c)
+	})
+}
+
+// buildTSQuery turns search terms into a tsquery that matches all of them as prefixes
+func buildTSQuery(terms []string) string {
+	prefixes := make([]string, 0, len(terms))
+	for _, term := range terms {
+		prefixes = append(prefixes, term+":*")
+	}
+	return strings.Join(prefixes, " & ")
+}
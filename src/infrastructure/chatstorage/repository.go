@@ -0,0 +1,363 @@
+package chatstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// The functions below only go through the repository interface, so the SQLite and
+// PostgreSQL repositories share them instead of each keeping a copy.
+
+// chatNameWithPushName determines the appropriate name for a chat with pushname support
+func chatNameWithPushName(repo domainChatStorage.IChatStorageRepository, accountID string, jid types.JID, chatJID string, senderUser string, pushName string) string {
+	// First, check if chat already exists with a name
+	existingChat, err := repo.GetChat(accountID, chatJID)
+	if err == nil && existingChat != nil && existingChat.Name != "" {
+		// If we have a pushname and the existing name is just a phone number/JID user, update it
+		if pushName != "" && (existingChat.Name == jid.User || existingChat.Name == senderUser) {
+			return pushName
+		}
+		return existingChat.Name
+	}
+
+	// Determine chat type and name
+	var name string
+
+	switch jid.Server {
+	case "g.us":
+		// This is a group chat
+		// For now, use a generic name - this can be enhanced later with group info
+		name = fmt.Sprintf("Group %s", jid.User)
+	case "newsletter":
+		// This is a newsletter/channel
+		name = fmt.Sprintf("Newsletter %s", jid.User)
+	default:
+		// This is an individual contact
+		// Priority: pushName > senderUser > JID user
+		if pushName != "" && pushName != senderUser && pushName != jid.User {
+			name = pushName
+		} else if senderUser != "" {
+			name = senderUser
+		} else {
+			name = jid.User
+		}
+	}
+
+	return name
+}
+
+// createMessage stores an incoming or synced message and its chat, or applies it to an earlier message
+func createMessage(ctx context.Context, repo domainChatStorage.IChatStorageRepository, accountID string, evt *events.Message) error {
+	if evt == nil || evt.Message == nil {
+		return nil
+	}
+
+	// Reactions, edits and revocations change an earlier message instead of adding one
+	if reaction := evt.Message.GetReactionMessage(); reaction != nil {
+		return repo.StoreReaction(&domainChatStorage.MessageReaction{
+			AccountID: accountID,
+			ChatJID:   evt.Info.Chat.String(),
+			MessageID: reaction.GetKey().GetID(),
+			Sender:    evt.Info.Sender.ToNonAD().String(),
+			Emoji:     reaction.GetText(),
+			Timestamp: evt.Info.Timestamp,
+		})
+	}
+	if protocolMessage := evt.Message.GetProtocolMessage(); protocolMessage != nil {
+		switch protocolMessage.GetType() {
+		case waE2E.ProtocolMessage_REVOKE:
+			return repo.MarkMessageRevoked(accountID, protocolMessage.GetKey().GetID(), evt.Info.Chat.String())
+		case waE2E.ProtocolMessage_MESSAGE_EDIT:
+			return repo.StoreMessageEdit(&domainChatStorage.MessageEdit{
+				AccountID: accountID,
+				ChatJID:   evt.Info.Chat.String(),
+				MessageID: protocolMessage.GetKey().GetID(),
+				Content:   utils.ExtractMessageTextFromProto(protocolMessage.GetEditedMessage()),
+				EditedAt:  evt.Info.Timestamp,
+			})
+		}
+	}
+
+	// Extract chat and sender information
+	chatJID := evt.Info.Chat.String()
+	// Store the full sender JID (user@server) to ensure consistency between received and sent messages
+	sender := evt.Info.Sender.String()
+
+	// Get appropriate chat name using pushname if available
+	chatName := repo.GetChatNameWithPushName(accountID, evt.Info.Chat, chatJID, evt.Info.Sender.User, evt.Info.PushName)
+
+	// Get existing chat to preserve ephemeral_expiration if needed
+	existingChat, err := repo.GetChat(accountID, chatJID)
+	if err != nil {
+		return fmt.Errorf("failed to get existing chat: %w", err)
+	}
+
+	// Extract ephemeral expiration from incoming message
+	ephemeralExpiration := utils.ExtractEphemeralExpiration(evt.Message)
+
+	// Create or update chat
+	chat := &domainChatStorage.Chat{
+		AccountID:       accountID,
+		JID:             chatJID,
+		Name:            chatName,
+		LastMessageTime: evt.Info.Timestamp,
+	}
+
+	// Set ephemeral expiration: use incoming message value if > 0, otherwise preserve existing
+	if ephemeralExpiration > 0 {
+		chat.EphemeralExpiration = ephemeralExpiration
+	} else if existingChat != nil {
+		// Preserve existing ephemeral_expiration if incoming message doesn't have one
+		chat.EphemeralExpiration = existingChat.EphemeralExpiration
+	}
+
+	// Store or update the chat
+	if err := repo.StoreChat(chat); err != nil {
+		return fmt.Errorf("failed to store chat: %w", err)
+	}
+
+	// Extract message content and media info
+	content := utils.ExtractMessageTextFromProto(evt.Message)
+	mediaType, filename, url, mediaKey, fileSHA256, fileEncSHA256, fileLength := utils.ExtractMediaInfo(evt.Message)
+
+	// Skip if there's no content and no media
+	if content == "" && mediaType == "" {
+		logrus.Debugf("Skipping message %s - no content or media", evt.Info.ID)
+		return nil
+	}
+
+	// Create message object
+	message := &domainChatStorage.Message{
+		AccountID:     accountID,
+		ID:            evt.Info.ID,
+		ChatJID:       chatJID,
+		Sender:        sender,
+		Content:       content,
+		Timestamp:     evt.Info.Timestamp,
+		IsFromMe:      evt.Info.IsFromMe,
+		MediaType:     mediaType,
+		Filename:      filename,
+		URL:           url,
+		MediaKey:      mediaKey,
+		FileSHA256:    fileSHA256,
+		FileEncSHA256: fileEncSHA256,
+		FileLength:    fileLength,
+	}
+
+	// Store the message
+	return repo.StoreMessage(message)
+}
+
+// storageStatistics returns current storage statistics for logging purposes
+func storageStatistics(repo domainChatStorage.IChatStorageRepository) (chatCount int64, messageCount int64, err error) {
+	// Count all chats using efficient query
+	chatCount, err = repo.GetTotalChatCount()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get chat count: %w", err)
+	}
+
+	// Count all messages
+	messageCount, err = repo.GetTotalMessageCount()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get message count: %w", err)
+	}
+
+	return chatCount, messageCount, nil
+}
+
+// truncateAllDataWithLogging performs truncation with detailed logging
+func truncateAllDataWithLogging(repo domainChatStorage.IChatStorageRepository, logPrefix string) error {
+	// Get statistics before truncation
+	chatCount, messageCount, err := repo.GetStorageStatistics()
+	if err != nil {
+		logrus.Warnf("[%s] Failed to get storage statistics before truncation: %v", logPrefix, err)
+	} else {
+		logrus.Infof("[%s] Storage before truncation: %d chats, %d messages", logPrefix, chatCount, messageCount)
+	}
+
+	// Perform truncation
+	if err := repo.TruncateAllChats(); err != nil {
+		return fmt.Errorf("failed to truncate chatstorage data: %w", err)
+	}
+
+	// Verify truncation
+	chatCountAfter, messageCountAfter, err := repo.GetStorageStatistics()
+	if err != nil {
+		logrus.Warnf("[%s] Failed to get storage statistics after truncation: %v", logPrefix, err)
+	} else {
+		logrus.Infof("[%s] Storage after truncation: %d chats, %d messages", logPrefix, chatCountAfter, messageCountAfter)
+		if chatCountAfter == 0 && messageCountAfter == 0 {
+			logrus.Infof("[%s] ✅ Chatstorage truncation completed successfully", logPrefix)
+		} else {
+			logrus.Warnf("[%s] ⚠️ Truncation may not have completed fully", logPrefix)
+		}
+	}
+
+	return nil
+}
+
+// storeSentMessage stores a message that was sent by the user with context cancellation support
+func storeSentMessage(ctx context.Context, repo domainChatStorage.IChatStorageRepository, accountID string, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error {
+	// Check if context is already cancelled before starting
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	// Ensure JID is properly formatted
+	jid, err := types.ParseJID(recipientJID)
+	if err != nil {
+		return fmt.Errorf("invalid JID format: %w", err)
+	}
+
+	chatJID := jid.String()
+
+	// Get chat name (no pushname available for sent messages)
+	chatName := repo.GetChatNameWithPushName(accountID, jid, chatJID, jid.User, "")
+
+	// Check context again before database operations
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	// Get existing chat to preserve ephemeral_expiration
+	existingChat, err := repo.GetChat(accountID, chatJID)
+	if err != nil {
+		return fmt.Errorf("failed to get existing chat: %w", err)
+	}
+
+	// Store or update chat, preserving existing ephemeral_expiration
+	chat := &domainChatStorage.Chat{
+		AccountID:       accountID,
+		JID:             chatJID,
+		Name:            chatName,
+		LastMessageTime: timestamp,
+	}
+
+	// Preserve existing ephemeral_expiration if chat exists
+	if existingChat != nil {
+		chat.EphemeralExpiration = existingChat.EphemeralExpiration
+	}
+
+	if err := repo.StoreChat(chat); err != nil {
+		return fmt.Errorf("failed to store chat: %w", err)
+	}
+
+	// Check context one more time before storing message
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	// Store the sent message
+	message := &domainChatStorage.Message{
+		AccountID: accountID,
+		ID:        messageID,
+		ChatJID:   chatJID,
+		Sender:    senderJID,
+		Content:   content,
+		Timestamp: timestamp,
+		IsFromMe:  true,
+	}
+
+	return repo.StoreMessage(message)
+}
+
+// messageOrder sorts messages newest first unless the filter asks for chronological order,
+// the ID breaks ties so paging with an offset neither repeats nor skips messages
+func messageOrder(filter *domainChatStorage.MessageFilter) string {
+	if filter.OldestFirst {
+		return "timestamp ASC, id ASC"
+	}
+	return "timestamp DESC, id DESC"
+}
+
+// pruneConditions builds the WHERE clause of a retention run with ? placeholders
+func pruneConditions(filter *domainChatStorage.MessagePruneFilter) (string, []any) {
+	conditions := []string{"timestamp < ?"}
+	args := []any{filter.Before}
+
+	if !filter.AllAccounts {
+		conditions = append(conditions, "account_id = ?")
+		args = append(args, filter.AccountID)
+		if filter.ChatJID != "" {
+			conditions = append(conditions, "chat_jid = ?")
+			args = append(args, filter.ChatJID)
+		}
+	}
+
+	if filter.KeepStarred {
+		conditions = append(conditions, "is_starred = FALSE")
+	}
+
+	for _, policy := range filter.Except {
+		if policy.ChatJID == "" {
+			conditions = append(conditions, "account_id != ?")
+			args = append(args, policy.AccountID)
+		} else {
+			conditions = append(conditions, "NOT (account_id = ? AND chat_jid = ?)")
+			args = append(args, policy.AccountID, policy.ChatJID)
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// pruneStatements removes the reactions, edits and receipts of the pruned messages before the messages
+// themselves, they have no foreign key since they may arrive before their message
+func pruneStatements(conditions string) []string {
+	return []string{
+		"DELETE FROM message_reactions WHERE (account_id, chat_jid, message_id) IN (SELECT account_id, chat_jid, id FROM messages WHERE " + conditions + ")",
+		"DELETE FROM message_edits WHERE (account_id, chat_jid, message_id) IN (SELECT account_id, chat_jid, id FROM messages WHERE " + conditions + ")",
+		"DELETE FROM message_receipts WHERE (account_id, message_id) IN (SELECT account_id, id FROM messages WHERE " + conditions + ")",
+		"DELETE FROM messages WHERE " + conditions,
+	}
+}
+
+// pendingEditStatements apply the edits that arrived before their message was stored, e.g. when the
+// original comes in later through history sync. The message shows its latest edit and counts all of
+// them, and every edit gets the content it replaced: the edit before it, or the original content for
+// the first one. Both take the message's account, chat and ID, the second one the original content first.
+var pendingEditStatements = [2]string{
+	`UPDATE messages SET
+		content = (SELECT e.content FROM message_edits e
+			WHERE e.account_id = messages.account_id AND e.chat_jid = messages.chat_jid AND e.message_id = messages.id
+			ORDER BY e.edited_at DESC, e.id DESC LIMIT 1),
+		edit_count = (SELECT COUNT(*) FROM message_edits e
+			WHERE e.account_id = messages.account_id AND e.chat_jid = messages.chat_jid AND e.message_id = messages.id)
+	WHERE account_id = ? AND chat_jid = ? AND id = ? AND edit_count = 0
+		AND EXISTS (SELECT 1 FROM message_edits e
+			WHERE e.account_id = messages.account_id AND e.chat_jid = messages.chat_jid AND e.message_id = messages.id)`,
+	`UPDATE message_edits SET
+		previous_content = COALESCE((SELECT p.content FROM message_edits p
+			WHERE p.account_id = message_edits.account_id AND p.chat_jid = message_edits.chat_jid AND p.message_id = message_edits.message_id
+				AND (p.edited_at < message_edits.edited_at OR (p.edited_at = message_edits.edited_at AND p.id < message_edits.id))
+			ORDER BY p.edited_at DESC, p.id DESC LIMIT 1), ?)
+	WHERE account_id = ? AND chat_jid = ? AND message_id = ? AND COALESCE(previous_content, '') = ''`,
+}
+
+// applyPendingEdits runs the pending edit statements, with the repository's placeholders, for a message
+// within the transaction that stored it
+func applyPendingEdits(tx *sql.Tx, statements [2]string, message *domainChatStorage.Message) error {
+	if _, err := tx.Exec(statements[0], message.AccountID, message.ChatJID, message.ID); err != nil {
+		return fmt.Errorf("failed to apply pending edits of message %s: %w", message.ID, err)
+	}
+	if _, err := tx.Exec(statements[1], message.Content, message.AccountID, message.ChatJID, message.ID); err != nil {
+		return fmt.Errorf("failed to fill edit history of message %s: %w", message.ID, err)
+	}
+	return nil
+}
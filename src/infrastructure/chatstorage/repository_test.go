@@ -0,0 +1,592 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAccount = "acc-1"
+	testChat    = "6281234567890@s.whatsapp.net"
+	testGroup   = "120363000000000000@g.us"
+)
+
+var testTime = time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+
+// runRepository runs a case against a migrated chat storage of every backend
+func runRepository(t *testing.T, fn func(t *testing.T, repo domainChatStorage.IChatStorageRepository)) {
+	storagetest.Run(t, func(t *testing.T, backend storagetest.Backend) {
+		var repo domainChatStorage.IChatStorageRepository
+		if backend.Name == storagetest.Postgres {
+			repo = NewPostgresRepository(backend.DB)
+		} else {
+			repo = NewStorageRepository(backend.DB)
+		}
+
+		require.NoError(t, repo.InitializeSchema())
+		// Migrations that already ran are skipped
+		require.NoError(t, repo.InitializeSchema())
+
+		fn(t, repo)
+	})
+}
+
+func storeTestMessage(t *testing.T, repo domainChatStorage.IChatStorageRepository, message *domainChatStorage.Message) {
+	t.Helper()
+	if message.AccountID == "" {
+		message.AccountID = testAccount
+	}
+	if message.ChatJID == "" {
+		message.ChatJID = testChat
+	}
+	if message.Sender == "" {
+		message.Sender = testChat
+	}
+
+	require.NoError(t, repo.StoreChat(&domainChatStorage.Chat{
+		AccountID:       message.AccountID,
+		JID:             message.ChatJID,
+		Name:            "Chat " + message.ChatJID,
+		LastMessageTime: message.Timestamp,
+	}))
+	require.NoError(t, repo.StoreMessage(message))
+}
+
+func messageIDs(messages []*domainChatStorage.Message) []string {
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+	return ids
+}
+
+func TestRepositoryChatsAndMessages(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainChatStorage.IChatStorageRepository) {
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m1", Content: "first", Timestamp: testTime})
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m2", Content: "second", Timestamp: testTime.Add(time.Minute), IsFromMe: true})
+		storeTestMessage(t, repo, &domainChatStorage.Message{
+			ID: "m3", Timestamp: testTime.Add(2 * time.Minute), MediaType: "image", Filename: "photo.jpg",
+			MediaKey: []byte{1, 2, 3}, FileLength: 1024,
+		})
+		storeTestMessage(t, repo, &domainChatStorage.Message{AccountID: "acc-2", ID: "m1", Content: "other account", Timestamp: testTime})
+
+		// Messages without content and media are skipped
+		require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{AccountID: testAccount, ID: "empty", ChatJID: testChat, Sender: testChat, Timestamp: testTime}))
+
+		chat, err := repo.GetChat(testAccount, testChat)
+		require.NoError(t, err)
+		require.NotNil(t, chat)
+		assert.Equal(t, "Chat "+testChat, chat.Name)
+
+		missing, err := repo.GetChat(testAccount, testGroup)
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+
+		messages, err := repo.GetMessages(&domainChatStorage.MessageFilter{AccountID: testAccount, ChatJID: testChat, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"m3", "m2", "m1"}, messageIDs(messages))
+
+		fromMe := true
+		messages, err = repo.GetMessages(&domainChatStorage.MessageFilter{AccountID: testAccount, ChatJID: testChat, IsFromMe: &fromMe})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"m2"}, messageIDs(messages))
+
+		messages, err = repo.GetMessages(&domainChatStorage.MessageFilter{AccountID: testAccount, ChatJID: testChat, MediaOnly: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{"m3"}, messageIDs(messages))
+		assert.Equal(t, []byte{1, 2, 3}, messages[0].MediaKey)
+		assert.Equal(t, uint64(1024), messages[0].FileLength)
+
+		start, end := testTime.Add(30*time.Second), testTime.Add(90*time.Second)
+		messages, err = repo.GetMessages(&domainChatStorage.MessageFilter{AccountID: testAccount, ChatJID: testChat, StartTime: &start, EndTime: &end})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"m2"}, messageIDs(messages))
+
+		messages, err = repo.GetMessages(&domainChatStorage.MessageFilter{AccountID: testAccount, ChatJID: testChat, Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"m2"}, messageIDs(messages))
+
+		messages, err = repo.GetMessages(&domainChatStorage.MessageFilter{AccountID: testAccount, ChatJID: testChat, Limit: 2, Offset: 1, OldestFirst: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"m2", "m3"}, messageIDs(messages))
+
+		// Messages with the same timestamp are paged by ID, each one shows up on exactly one page
+		for _, id := range []string{"t1", "t2", "t3"} {
+			storeTestMessage(t, repo, &domainChatStorage.Message{ChatJID: testGroup, ID: id, Content: id, Timestamp: testTime})
+		}
+		var paged []string
+		for offset := 0; offset < 3; offset++ {
+			messages, err = repo.GetMessages(&domainChatStorage.MessageFilter{AccountID: testAccount, ChatJID: testGroup, Limit: 1, Offset: offset})
+			require.NoError(t, err)
+			paged = append(paged, messageIDs(messages)...)
+		}
+		assert.Equal(t, []string{"t3", "t2", "t1"}, paged)
+
+		message, err := repo.GetMessageByID(testAccount, "m1")
+		require.NoError(t, err)
+		require.NotNil(t, message)
+		assert.Equal(t, "first", message.Content)
+		assert.True(t, message.Timestamp.Equal(testTime))
+
+		message, err = repo.GetMessageByID("acc-2", "m1")
+		require.NoError(t, err)
+		require.NotNil(t, message)
+		assert.Equal(t, "other account", message.Content)
+
+		count, err := repo.GetChatMessageCount(testAccount, testChat)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+
+		chats, messageCount, err := repo.GetStorageStatistics()
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), chats)
+		assert.Equal(t, int64(7), messageCount)
+	})
+}
+
+func TestRepositoryGetChats(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainChatStorage.IChatStorageRepository) {
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m1", MediaType: "image", Timestamp: testTime})
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m2", MediaType: "video", Timestamp: testTime})
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m3", ChatJID: testGroup, Content: "hello", Timestamp: testTime.Add(time.Hour)})
+
+		chats, err := repo.GetChats(&domainChatStorage.ChatFilter{AccountID: testAccount})
+		require.NoError(t, err)
+		require.Len(t, chats, 2)
+		assert.Equal(t, testGroup, chats[0].JID)
+
+		// A chat with several media messages is listed once
+		chats, err = repo.GetChats(&domainChatStorage.ChatFilter{AccountID: testAccount, HasMedia: true})
+		require.NoError(t, err)
+		require.Len(t, chats, 1)
+		assert.Equal(t, testChat, chats[0].JID)
+
+		chats, err = repo.GetChats(&domainChatStorage.ChatFilter{AccountID: testAccount, SearchName: "g.us"})
+		require.NoError(t, err)
+		require.Len(t, chats, 1)
+		assert.Equal(t, testGroup, chats[0].JID)
+
+		count, err := repo.GetAccountChatCount(testAccount)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
+func TestRepositoryMessageEdits(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainChatStorage.IChatStorageRepository) {
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m1", Content: "original", Timestamp: testTime})
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m2", Content: "untouched", Timestamp: testTime})
+
+		require.NoError(t, repo.StoreMessageEdit(&domainChatStorage.MessageEdit{
+			AccountID: testAccount, ChatJID: testChat, MessageID: "m1", Content: "edited", EditedAt: testTime.Add(time.Minute),
+		}))
+
+		// A later sync of the original message keeps the edited content
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m1", Content: "original", Timestamp: testTime})
+
+		message, err := repo.GetMessageByID(testAccount, "m1")
+		require.NoError(t, err)
+		assert.Equal(t, "edited", message.Content)
+		assert.Equal(t, 1, message.EditCount)
+
+		require.NoError(t, repo.StoreMessageEdit(&domainChatStorage.MessageEdit{
+			AccountID: testAccount, ChatJID: testChat, MessageID: "m1", Content: "edited again", EditedAt: testTime.Add(2 * time.Minute),
+		}))
+
+		message, err = repo.GetMessageByID(testAccount, "m1")
+		require.NoError(t, err)
+		assert.Equal(t, "edited again", message.Content)
+		assert.Equal(t, 2, message.EditCount)
+
+		// Every edit keeps the content it replaced, oldest first
+		edits, err := repo.GetMessageEdits(testAccount, testChat, []string{"m1", "m2"})
+		require.NoError(t, err)
+		require.Len(t, edits, 2)
+		assert.Equal(t, "original", edits[0].PreviousContent)
+		assert.Equal(t, "edited", edits[0].Content)
+		assert.Equal(t, "edited", edits[1].PreviousContent)
+		assert.Equal(t, "edited again", edits[1].Content)
+		assert.True(t, edits[1].EditedAt.Equal(testTime.Add(2*time.Minute)))
+
+		message, err = repo.GetMessageByID(testAccount, "m2")
+		require.NoError(t, err)
+		assert.Equal(t, 0, message.EditCount)
+
+		// An edit of a message that isn't stored only ends up in the history
+		require.NoError(t, repo.StoreMessageEdit(&domainChatStorage.MessageEdit{
+			AccountID: testAccount, ChatJID: testChat, MessageID: "unknown", Content: "edited", EditedAt: testTime,
+		}))
+		edits, err = repo.GetMessageEdits(testAccount, testChat, []string{"unknown"})
+		require.NoError(t, err)
+		require.Len(t, edits, 1)
+		assert.Empty(t, edits[0].PreviousContent)
+
+		// Edits that arrive before their message are applied once it is stored
+		for i, content := range []string{"late edit", "late edit again"} {
+			require.NoError(t, repo.StoreMessageEdit(&domainChatStorage.MessageEdit{
+				AccountID: testAccount, ChatJID: testChat, MessageID: "late", Content: content, EditedAt: testTime.Add(time.Duration(i+1) * time.Minute),
+			}))
+		}
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "late", Content: "late original", Timestamp: testTime})
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "late", Content: "late original", Timestamp: testTime})
+
+		message, err = repo.GetMessageByID(testAccount, "late")
+		require.NoError(t, err)
+		assert.Equal(t, "late edit again", message.Content)
+		assert.Equal(t, 2, message.EditCount)
+
+		edits, err = repo.GetMessageEdits(testAccount, testChat, []string{"late"})
+		require.NoError(t, err)
+		require.Len(t, edits, 2)
+		assert.Equal(t, "late original", edits[0].PreviousContent)
+		assert.Equal(t, "late edit", edits[1].PreviousContent)
+
+		// The same goes for messages stored in a batch
+		require.NoError(t, repo.StoreMessageEdit(&domainChatStorage.MessageEdit{
+			AccountID: testAccount, ChatJID: testChat, MessageID: "late-batch", Content: "batch edit", EditedAt: testTime.Add(time.Minute),
+		}))
+		require.NoError(t, repo.StoreMessagesBatch([]*domainChatStorage.Message{
+			{AccountID: testAccount, ChatJID: testChat, ID: "late-batch", Sender: testChat, Content: "batch original", Timestamp: testTime},
+		}))
+
+		message, err = repo.GetMessageByID(testAccount, "late-batch")
+		require.NoError(t, err)
+		assert.Equal(t, "batch edit", message.Content)
+		assert.Equal(t, 1, message.EditCount)
+
+		edits, err = repo.GetMessageEdits(testAccount, testChat, []string{"late-batch"})
+		require.NoError(t, err)
+		require.Len(t, edits, 1)
+		assert.Equal(t, "batch original", edits[0].PreviousContent)
+
+		edits, err = repo.GetMessageEdits("acc-2", testChat, []string{"m1"})
+		require.NoError(t, err)
+		assert.Empty(t, edits, "edits are scoped by account")
+
+		edits, err = repo.GetMessageEdits(testAccount, testChat, nil)
+		require.NoError(t, err)
+		assert.Empty(t, edits)
+	})
+}
+
+func TestRepositoryReactions(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainChatStorage.IChatStorageRepository) {
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m1", Content: "hello", Timestamp: testTime})
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m2", Content: "world", Timestamp: testTime})
+
+		reaction := &domainChatStorage.MessageReaction{
+			AccountID: testAccount, ChatJID: testChat, MessageID: "m1", Sender: testChat, Emoji: "👍", Timestamp: testTime,
+		}
+		require.NoError(t, repo.StoreReaction(reaction))
+		require.NoError(t, repo.StoreReaction(&domainChatStorage.MessageReaction{
+			AccountID: testAccount, ChatJID: testChat, MessageID: "m1", Sender: "6289876543210@s.whatsapp.net", Emoji: "😂", Timestamp: testTime.Add(time.Minute),
+		}))
+		require.NoError(t, repo.StoreReaction(&domainChatStorage.MessageReaction{
+			AccountID: testAccount, ChatJID: testChat, MessageID: "m2", Sender: testChat, Emoji: "🔥", Timestamp: testTime,
+		}))
+
+		// A new reaction of the same sender replaces the previous one
+		reaction.Emoji = "❤️"
+		reaction.Timestamp = testTime.Add(2 * time.Minute)
+		require.NoError(t, repo.StoreReaction(reaction))
+
+		reactions, err := repo.GetReactions(testAccount, testChat, []string{"m1"})
+		require.NoError(t, err)
+		require.Len(t, reactions, 2)
+		assert.Equal(t, "😂", reactions[0].Emoji)
+		assert.Equal(t, "❤️", reactions[1].Emoji)
+		assert.Equal(t, testChat, reactions[1].Sender)
+		assert.True(t, reactions[1].Timestamp.Equal(testTime.Add(2*time.Minute)))
+
+		// An empty emoji removes the reaction of the sender only
+		reaction.Emoji = ""
+		require.NoError(t, repo.StoreReaction(reaction))
+		reactions, err = repo.GetReactions(testAccount, testChat, []string{"m1", "m2"})
+		require.NoError(t, err)
+		require.Len(t, reactions, 2)
+		assert.ElementsMatch(t, []string{"😂", "🔥"}, []string{reactions[0].Emoji, reactions[1].Emoji})
+
+		// Removing a reaction that doesn't exist is not an error
+		require.NoError(t, repo.StoreReaction(reaction))
+
+		reactions, err = repo.GetReactions("acc-2", testChat, []string{"m1", "m2"})
+		require.NoError(t, err)
+		assert.Empty(t, reactions, "reactions are scoped by account")
+
+		reactions, err = repo.GetReactions(testAccount, testChat, nil)
+		require.NoError(t, err)
+		assert.Empty(t, reactions)
+	})
+}
+
+func TestRepositoryRevocations(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainChatStorage.IChatStorageRepository) {
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m1", Content: "original", Timestamp: testTime})
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m2", Content: "kept", Timestamp: testTime})
+		storeTestMessage(t, repo, &domainChatStorage.Message{AccountID: "acc-2", ID: "m1", Content: "other account", Timestamp: testTime})
+
+		require.NoError(t, repo.StoreMessageEdit(&domainChatStorage.MessageEdit{
+			AccountID: testAccount, ChatJID: testChat, MessageID: "m1", Content: "edited", EditedAt: testTime.Add(time.Minute),
+		}))
+		require.NoError(t, repo.MarkMessageRevoked(testAccount, "m1", testChat))
+
+		// The revoked message keeps its latest content
+		message, err := repo.GetMessageByID(testAccount, "m1")
+		require.NoError(t, err)
+		assert.True(t, message.IsRevoked)
+		assert.Equal(t, "edited", message.Content)
+		assert.Equal(t, 1, message.EditCount)
+
+		// A later sync of the original message doesn't bring it back
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m1", Content: "original", Timestamp: testTime})
+		message, err = repo.GetMessageByID(testAccount, "m1")
+		require.NoError(t, err)
+		assert.True(t, message.IsRevoked)
+
+		message, err = repo.GetMessageByID(testAccount, "m2")
+		require.NoError(t, err)
+		assert.False(t, message.IsRevoked)
+
+		message, err = repo.GetMessageByID("acc-2", "m1")
+		require.NoError(t, err)
+		assert.False(t, message.IsRevoked, "revocations are scoped by account")
+
+		// Revoking a message that isn't stored is not an error
+		require.NoError(t, repo.MarkMessageRevoked(testAccount, "unknown", testChat))
+	})
+}
+
+func TestRepositoryReceipts(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainChatStorage.IChatStorageRepository) {
+		delivered := testTime.Add(time.Minute)
+		earlier := testTime.Add(30 * time.Second)
+		read := testTime.Add(2 * time.Minute)
+		receipt := func(deliveredAt, readAt *time.Time) *domainChatStorage.MessageReceipt {
+			return &domainChatStorage.MessageReceipt{
+				AccountID: testAccount, ChatJID: testChat, MessageID: "m1",
+				Recipient: testChat, Participant: testChat, DeliveredAt: deliveredAt, ReadAt: readAt,
+			}
+		}
+
+		require.NoError(t, repo.StoreReceipt(receipt(&delivered, nil)))
+		require.NoError(t, repo.StoreReceipt(receipt(&earlier, nil)))
+		require.NoError(t, repo.StoreReceipt(receipt(&read, nil)))
+		require.NoError(t, repo.StoreReceipt(receipt(nil, &read)))
+
+		receipts, err := repo.GetReceipts(testAccount, []string{"m1"})
+		require.NoError(t, err)
+		require.Len(t, receipts, 1)
+		require.NotNil(t, receipts[0].DeliveredAt)
+		require.NotNil(t, receipts[0].ReadAt)
+		assert.True(t, receipts[0].DeliveredAt.Equal(earlier))
+		assert.True(t, receipts[0].ReadAt.Equal(read))
+
+		// A read receipt without an earlier delivery receipt counts as delivered too
+		other := receipt(nil, &read)
+		other.Recipient = "6289999999999@s.whatsapp.net"
+		other.Participant = other.Recipient
+		require.NoError(t, repo.StoreReceipt(other))
+
+		receipts, err = repo.GetReceipts(testAccount, []string{"m1"})
+		require.NoError(t, err)
+		require.Len(t, receipts, 2)
+		require.NotNil(t, receipts[1].DeliveredAt)
+		assert.True(t, receipts[1].DeliveredAt.Equal(read))
+	})
+}
+
+func TestRepositorySearch(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainChatStorage.IChatStorageRepository) {
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m1", Content: "The invoice is attached", Timestamp: testTime})
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m2", Content: "Invoices for march are due", Timestamp: testTime.Add(time.Minute)})
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m3", Content: "See you tomorrow", Timestamp: testTime.Add(2 * time.Minute)})
+		storeTestMessage(t, repo, &domainChatStorage.Message{
+			ID: "m4", ChatJID: testGroup, Sender: "6281111111111:3@s.whatsapp.net", Content: "invoice paid", Timestamp: testTime.Add(3 * time.Minute),
+		})
+
+		messages, err := repo.SearchMessages(testAccount, testChat, "invoice", 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"m1", "m2"}, messageIDs(messages))
+
+		messages, err = repo.SearchMessages(testAccount, testChat, "   ", 10)
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+
+		results, total, err := repo.SearchAllMessages(&domainChatStorage.MessageSearchFilter{AccountID: testAccount, Query: "invoice", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.Len(t, results, 3)
+		for _, result := range results {
+			assert.Contains(t, result.Snippet, "nvoice")
+		}
+
+		// Every word has to match
+		results, total, err = repo.SearchAllMessages(&domainChatStorage.MessageSearchFilter{AccountID: testAccount, Query: "invoice march", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, results, 1)
+		assert.Equal(t, "m2", results[0].Message.ID)
+
+		// The sender filter matches the messages of all devices of a user
+		results, _, err = repo.SearchAllMessages(&domainChatStorage.MessageSearchFilter{
+			AccountID: testAccount, Query: "invoice", Sender: "6281111111111@s.whatsapp.net", Limit: 10,
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "m4", results[0].Message.ID)
+
+		results, total, err = repo.SearchAllMessages(&domainChatStorage.MessageSearchFilter{AccountID: "acc-2", Query: "invoice", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.Empty(t, results)
+
+		// Edited content is searchable and deleted messages are not
+		require.NoError(t, repo.StoreMessageEdit(&domainChatStorage.MessageEdit{
+			AccountID: testAccount, ChatJID: testChat, MessageID: "m3", Content: "Invoice tomorrow", EditedAt: testTime,
+		}))
+		require.NoError(t, repo.DeleteMessage(testAccount, "m1", testChat))
+
+		messages, err = repo.SearchMessages(testAccount, testChat, "invoice", 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"m2", "m3"}, messageIDs(messages))
+	})
+}
+
+func TestRepositoryDeleteAndTruncate(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainChatStorage.IChatStorageRepository) {
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m1", Content: "hello", Timestamp: testTime})
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "m2", ChatJID: testGroup, Content: "group", Timestamp: testTime})
+		require.NoError(t, repo.StoreReaction(&domainChatStorage.MessageReaction{
+			AccountID: testAccount, ChatJID: testChat, MessageID: "m1", Sender: testChat, Emoji: "👍", Timestamp: testTime,
+		}))
+
+		require.NoError(t, repo.DeleteChat(testAccount, testChat))
+
+		chat, err := repo.GetChat(testAccount, testChat)
+		require.NoError(t, err)
+		assert.Nil(t, chat)
+		reactions, err := repo.GetReactions(testAccount, testChat, []string{"m1"})
+		require.NoError(t, err)
+		assert.Empty(t, reactions)
+
+		count, err := repo.GetTotalMessageCount()
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		require.NoError(t, repo.TruncateAllDataWithLogging("TEST"))
+		chats, messages, err := repo.GetStorageStatistics()
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), chats)
+		assert.Equal(t, int64(0), messages)
+	})
+}
+
+func TestRepositoryRetention(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainChatStorage.IChatStorageRepository) {
+		old, recent := testTime, testTime.Add(48*time.Hour)
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "old", Content: "old", Timestamp: old})
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "starred", Content: "keep me", Timestamp: old})
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "recent", Content: "recent", Timestamp: recent})
+		storeTestMessage(t, repo, &domainChatStorage.Message{ID: "group-old", ChatJID: testGroup, Content: "group", Timestamp: old})
+		storeTestMessage(t, repo, &domainChatStorage.Message{AccountID: "acc-2", ID: "other-old", Content: "other", Timestamp: old})
+		require.NoError(t, repo.SetMessageStarred(testAccount, testChat, "starred", true))
+		require.NoError(t, repo.StoreReaction(&domainChatStorage.MessageReaction{
+			AccountID: testAccount, ChatJID: testChat, MessageID: "old", Sender: testChat, Emoji: "👍", Timestamp: old,
+		}))
+
+		require.NoError(t, repo.StoreRetentionPolicy(&domainChatStorage.RetentionPolicy{AccountID: testAccount, MaxMessageDays: 30, KeepStarred: true}))
+		require.NoError(t, repo.StoreRetentionPolicy(&domainChatStorage.RetentionPolicy{AccountID: testAccount, ChatJID: testGroup}))
+		// Storing a policy again replaces it
+		require.NoError(t, repo.StoreRetentionPolicy(&domainChatStorage.RetentionPolicy{AccountID: testAccount, MaxMessageDays: 1, KeepStarred: true}))
+
+		policies, err := repo.GetRetentionPolicies()
+		require.NoError(t, err)
+		require.Len(t, policies, 2)
+		assert.Equal(t, "", policies[0].ChatJID)
+		assert.Equal(t, 1, policies[0].MaxMessageDays)
+		assert.True(t, policies[0].KeepStarred)
+		assert.Equal(t, testGroup, policies[1].ChatJID)
+
+		// The account policy leaves out starred messages and the chat with a policy of its own
+		removed, err := repo.PruneMessages(&domainChatStorage.MessagePruneFilter{
+			Before: recent.Add(-time.Hour), KeepStarred: true, AccountID: testAccount, Except: policies[1:],
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), removed)
+
+		messages, err := repo.GetMessages(&domainChatStorage.MessageFilter{AccountID: testAccount, ChatJID: testChat})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"starred", "recent"}, messageIDs(messages))
+		reactions, err := repo.GetReactions(testAccount, testChat, []string{"old"})
+		require.NoError(t, err)
+		assert.Empty(t, reactions)
+
+		// The global run leaves out the accounts with a policy
+		removed, err = repo.PruneMessages(&domainChatStorage.MessagePruneFilter{
+			Before: recent, AllAccounts: true, Except: policies[:1],
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), removed)
+		message, err := repo.GetMessageByID("acc-2", "other-old")
+		require.NoError(t, err)
+		assert.Nil(t, message)
+		message, err = repo.GetMessageByID(testAccount, "group-old")
+		require.NoError(t, err)
+		assert.NotNil(t, message)
+
+		require.NoError(t, repo.DeleteRetentionPolicy(testAccount, testGroup))
+		policies, err = repo.GetRetentionPolicies()
+		require.NoError(t, err)
+		assert.Len(t, policies, 1)
+
+		require.NoError(t, repo.Vacuum())
+		size, err := repo.GetDatabaseSize()
+		require.NoError(t, err)
+		assert.Positive(t, size)
+	})
+}
+
+func TestSQLiteMigrationKeepsMessagesWithoutChat(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, backend storagetest.Backend) {
+		if backend.Name != storagetest.SQLite {
+			t.Skip("the account migration only exists for SQLite")
+		}
+
+		// A database from before the account migration, with messages whose chat row is missing.
+		// One connection, so the foreign_keys pragma applies to every statement.
+		backend.DB.SetMaxOpenConns(1)
+		repo := &SQLiteRepository{db: backend.DB}
+		_, err := repo.getSchemaVersion()
+		require.NoError(t, err)
+		for version, migration := range repo.getMigrations()[:2] {
+			require.NoError(t, repo.runMigration(migration, version+1))
+		}
+		_, err = backend.DB.Exec("PRAGMA foreign_keys = OFF")
+		require.NoError(t, err)
+		_, err = backend.DB.Exec(`INSERT INTO chats (jid, name, last_message_time) VALUES (?, 'Chat', ?)`, testChat, testTime)
+		require.NoError(t, err)
+		for _, message := range []struct{ id, chat string }{{"m1", testChat}, {"m2", testGroup}, {"m3", testGroup}} {
+			_, err = backend.DB.Exec(`INSERT INTO messages (id, chat_jid, sender, content, timestamp, media_type, filename, url) VALUES (?, ?, ?, 'hello', ?, '', '', '')`,
+				message.id, message.chat, testChat, testTime)
+			require.NoError(t, err)
+		}
+		_, err = backend.DB.Exec("PRAGMA foreign_keys = ON")
+		require.NoError(t, err)
+
+		require.NoError(t, repo.InitializeSchema())
+
+		messages, err := repo.GetMessages(&domainChatStorage.MessageFilter{ChatJID: testGroup, Limit: 10})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"m2", "m3"}, messageIDs(messages))
+
+		chat, err := repo.GetChat("", testGroup)
+		require.NoError(t, err)
+		require.NotNil(t, chat)
+		assert.Equal(t, testGroup, chat.Name)
+	})
+}
@@ -8,15 +8,36 @@ import (
 	"time"
 
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
-	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
+// sqliteUpsertMessage stores a message, a message that was edited keeps its edited content
+const sqliteUpsertMessage = `
+		INSERT INTO messages (
+			account_id, id, chat_jid, sender, content, timestamp, is_from_me,
+			media_type, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(account_id, id, chat_jid) DO UPDATE SET
+			sender = excluded.sender,
+			content = CASE WHEN messages.edit_count > 0 THEN messages.content ELSE excluded.content END,
+			timestamp = excluded.timestamp,
+			is_from_me = excluded.is_from_me,
+			media_type = excluded.media_type,
+			filename = excluded.filename,
+			url = excluded.url,
+			media_key = excluded.media_key,
+			file_sha256 = excluded.file_sha256,
+			file_enc_sha256 = excluded.file_enc_sha256,
+			file_length = excluded.file_length,
+			updated_at = excluded.updated_at
+	`
+
 // SQLiteRepository implements Repository using SQLite
 type SQLiteRepository struct {
-	db *sql.DB
+	db         *sql.DB
+	ftsEnabled bool
 }
 
 // NewSQLiteRepository creates a new SQLite repository
@@ -30,28 +51,28 @@ func (r *SQLiteRepository) StoreChat(chat *domainChatStorage.Chat) error {
 	chat.UpdatedAt = now
 
 	query := `
-		INSERT INTO chats (jid, name, last_message_time, ephemeral_expiration, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
-		ON CONFLICT(jid) DO UPDATE SET
+		INSERT INTO chats (account_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(account_id, jid) DO UPDATE SET
 			name = excluded.name,
 			last_message_time = excluded.last_message_time,
 			ephemeral_expiration = excluded.ephemeral_expiration,
 			updated_at = excluded.updated_at
 	`
 
-	_, err := r.db.Exec(query, chat.JID, chat.Name, chat.LastMessageTime, chat.EphemeralExpiration, now, chat.UpdatedAt)
+	_, err := r.db.Exec(query, chat.AccountID, chat.JID, chat.Name, chat.LastMessageTime, chat.EphemeralExpiration, now, chat.UpdatedAt)
 	return err
 }
 
-// GetChat retrieves a chat by JID
-func (r *SQLiteRepository) GetChat(jid string) (*domainChatStorage.Chat, error) {
+// GetChat retrieves a chat of an account by JID
+func (r *SQLiteRepository) GetChat(accountID, jid string) (*domainChatStorage.Chat, error) {
 	query := `
-		SELECT jid, name, last_message_time, ephemeral_expiration, created_at, updated_at
+		SELECT account_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at
 		FROM chats
-		WHERE jid = ?
+		WHERE account_id = ? AND jid = ?
 	`
 
-	chat, err := r.scanChat(r.db.QueryRow(query, jid))
+	chat, err := r.scanChat(r.db.QueryRow(query, accountID, jid))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -59,19 +80,19 @@ func (r *SQLiteRepository) GetChat(jid string) (*domainChatStorage.Chat, error)
 	return chat, err
 }
 
-// GetMessageByID retrieves a message by its ID from any chat
+// GetMessageByID retrieves a message by its ID from any chat of an account
 // This is more efficient than searching through all chats
-func (r *SQLiteRepository) GetMessageByID(id string) (*domainChatStorage.Message, error) {
+func (r *SQLiteRepository) GetMessageByID(accountID, id string) (*domainChatStorage.Message, error) {
 	query := `
-		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
+		SELECT account_id, id, chat_jid, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			file_enc_sha256, file_length, is_revoked, edit_count, created_at, updated_at
 		FROM messages
-		WHERE id = ?
+		WHERE account_id = ? AND id = ?
 		LIMIT 1
 	`
 
-	message, err := r.scanMessage(r.db.QueryRow(query, id))
+	message, err := r.scanMessage(r.db.QueryRow(query, accountID, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -85,23 +106,24 @@ func (r *SQLiteRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*do
 	var args []any
 
 	query := `
-		SELECT c.jid, c.name, c.last_message_time, c.ephemeral_expiration, c.created_at, c.updated_at
+		SELECT c.account_id, c.jid, c.name, c.last_message_time, c.ephemeral_expiration, c.created_at, c.updated_at
 		FROM chats c
 	`
 
+	conditions = append(conditions, "c.account_id = ?")
+	args = append(args, filter.AccountID)
+
 	if filter.SearchName != "" {
 		conditions = append(conditions, "c.name LIKE ?")
 		args = append(args, "%"+filter.SearchName+"%")
 	}
 
+	// A chat with several media messages is listed once
 	if filter.HasMedia {
-		query += " INNER JOIN messages m ON c.jid = m.chat_jid"
-		conditions = append(conditions, "m.media_type != ''")
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM messages m WHERE m.account_id = c.account_id AND m.chat_jid = c.jid AND m.media_type != '')")
 	}
 
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	query += " WHERE " + strings.Join(conditions, " AND ")
 
 	query += " ORDER BY c.last_message_time DESC"
 
@@ -138,22 +160,29 @@ func (r *SQLiteRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*do
 	return chats, rows.Err()
 }
 
-// DeleteChat deletes a chat and all its messages
-func (r *SQLiteRepository) DeleteChat(jid string) error {
+// DeleteChat deletes a chat of an account and all its messages
+func (r *SQLiteRepository) DeleteChat(accountID, jid string) error {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
+	// Reactions, edits and receipts have no foreign key since they may arrive before their message
+	for _, table := range []string{"message_reactions", "message_edits", "message_receipts"} {
+		if _, err = tx.Exec("DELETE FROM "+table+" WHERE account_id = ? AND chat_jid = ?", accountID, jid); err != nil {
+			return err
+		}
+	}
+
 	// Delete messages first (foreign key constraint)
-	_, err = tx.Exec("DELETE FROM messages WHERE chat_jid = ?", jid)
+	_, err = tx.Exec("DELETE FROM messages WHERE account_id = ? AND chat_jid = ?", accountID, jid)
 	if err != nil {
 		return err
 	}
 
 	// Delete chat
-	_, err = tx.Exec("DELETE FROM chats WHERE jid = ?", jid)
+	_, err = tx.Exec("DELETE FROM chats WHERE account_id = ? AND jid = ?", accountID, jid)
 	if err != nil {
 		return err
 	}
@@ -173,35 +202,26 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 		return nil
 	}
 
-	query := `
-		INSERT INTO messages (
-			id, chat_jid, sender, content, timestamp, is_from_me, 
-			media_type, filename, url, media_key, file_sha256, 
-			file_enc_sha256, file_length, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id, chat_jid) DO UPDATE SET
-			sender = excluded.sender,
-			content = excluded.content,
-			timestamp = excluded.timestamp,
-			is_from_me = excluded.is_from_me,
-			media_type = excluded.media_type,
-			filename = excluded.filename,
-			url = excluded.url,
-			media_key = excluded.media_key,
-			file_sha256 = excluded.file_sha256,
-			file_enc_sha256 = excluded.file_enc_sha256,
-			file_length = excluded.file_length,
-			updated_at = excluded.updated_at
-	`
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	_, err := r.db.Exec(query,
-		message.ID, message.ChatJID, message.Sender, message.Content,
+	_, err = tx.Exec(sqliteUpsertMessage,
+		message.AccountID, message.ID, message.ChatJID, message.Sender, message.Content,
 		message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
 		message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
 		message.FileLength, message.CreatedAt, message.UpdatedAt,
 	)
+	if err != nil {
+		return err
+	}
+	if err := applyPendingEdits(tx, pendingEditStatements, message); err != nil {
+		return err
+	}
 
-	return err
+	return tx.Commit()
 }
 
 // StoreMessagesBatch creates or updates multiple messages in a single transaction
@@ -217,26 +237,7 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 	defer tx.Rollback()
 
 	// Prepare the statement once for better performance
-	stmt, err := tx.Prepare(`
-		INSERT INTO messages (
-			id, chat_jid, sender, content, timestamp, is_from_me, 
-			media_type, filename, url, media_key, file_sha256, 
-			file_enc_sha256, file_length, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id, chat_jid) DO UPDATE SET
-			sender = excluded.sender,
-			content = excluded.content,
-			timestamp = excluded.timestamp,
-			is_from_me = excluded.is_from_me,
-			media_type = excluded.media_type,
-			filename = excluded.filename,
-			url = excluded.url,
-			media_key = excluded.media_key,
-			file_sha256 = excluded.file_sha256,
-			file_enc_sha256 = excluded.file_enc_sha256,
-			file_length = excluded.file_length,
-			updated_at = excluded.updated_at
-	`)
+	stmt, err := tx.Prepare(sqliteUpsertMessage)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
@@ -253,7 +254,7 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 		message.UpdatedAt = now
 
 		_, err = stmt.Exec(
-			message.ID, message.ChatJID, message.Sender, message.Content,
+			message.AccountID, message.ID, message.ChatJID, message.Sender, message.Content,
 			message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
 			message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
 			message.FileLength, message.CreatedAt, message.UpdatedAt,
@@ -261,6 +262,9 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 		if err != nil {
 			return fmt.Errorf("failed to store message %s: %w", message.ID, err)
 		}
+		if err := applyPendingEdits(tx, pendingEditStatements, message); err != nil {
+			return err
+		}
 	}
 
 	return tx.Commit()
@@ -271,8 +275,8 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 	var conditions []string
 	var args []any
 
-	conditions = append(conditions, "chat_jid = ?")
-	args = append(args, filter.ChatJID)
+	conditions = append(conditions, "account_id = ?", "chat_jid = ?")
+	args = append(args, filter.AccountID, filter.ChatJID)
 
 	if filter.StartTime != nil {
 		conditions = append(conditions, "timestamp >= ?")
@@ -294,12 +298,12 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 	}
 
 	query := `
-		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
+		SELECT account_id, id, chat_jid, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			file_enc_sha256, file_length, is_revoked, edit_count, created_at, updated_at
 		FROM messages
 		WHERE ` + strings.Join(conditions, " AND ") + `
-		ORDER BY timestamp DESC
+		ORDER BY ` + messageOrder(filter) + `
 	`
 
 	// Safely add LIMIT and OFFSET using parameterized values
@@ -336,7 +340,7 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 }
 
 // SearchMessages performs database-level search for messages containing specific text
-func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int) ([]*domainChatStorage.Message, error) {
+func (r *SQLiteRepository) SearchMessages(accountID, chatJID, searchText string, limit int) ([]*domainChatStorage.Message, error) {
 	// Return empty results for empty search text
 	if strings.TrimSpace(searchText) == "" {
 		return []*domainChatStorage.Message{}, nil
@@ -345,18 +349,19 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 	var conditions []string
 	var args []any
 
-	// Always filter by chat JID
-	conditions = append(conditions, "chat_jid = ?")
-	args = append(args, chatJID)
+	// Always filter by account and chat JID
+	conditions = append(conditions, "account_id = ?", "chat_jid = ?")
+	args = append(args, accountID, chatJID)
 
-	// Add search condition using LIKE operator for case-insensitive search
+	// Add search condition using LIKE operator for case-insensitive search. The search stays a substring
+	// match even with the full-text index, which only matches word prefixes (see SearchAllMessages).
 	conditions = append(conditions, "LOWER(content) LIKE ?")
 	args = append(args, "%"+strings.ToLower(searchText)+"%")
 
 	query := `
-		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
+		SELECT account_id, id, chat_jid, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			file_enc_sha256, file_length, is_revoked, edit_count, created_at, updated_at
 		FROM messages
 		WHERE ` + strings.Join(conditions, " AND ") + `
 		ORDER BY timestamp DESC
@@ -394,12 +399,245 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 	return messages, nil
 }
 
-// DeleteMessage deletes a specific message
-func (r *SQLiteRepository) DeleteMessage(id, chatJID string) error {
-	_, err := r.db.Exec("DELETE FROM messages WHERE id = ? AND chat_jid = ?", id, chatJID)
+// DeleteMessage deletes a specific message along with its reactions and edit history
+func (r *SQLiteRepository) DeleteMessage(accountID, id, chatJID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"message_reactions", "message_edits"} {
+		if _, err = tx.Exec("DELETE FROM "+table+" WHERE account_id = ? AND message_id = ? AND chat_jid = ?", accountID, id, chatJID); err != nil {
+			return err
+		}
+	}
+
+	if _, err = tx.Exec("DELETE FROM message_receipts WHERE account_id = ? AND message_id = ?", accountID, id); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec("DELETE FROM messages WHERE account_id = ? AND id = ? AND chat_jid = ?", accountID, id, chatJID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// StoreReaction creates or replaces the reaction of a sender to a message, an empty emoji removes it
+func (r *SQLiteRepository) StoreReaction(reaction *domainChatStorage.MessageReaction) error {
+	if reaction.Emoji == "" {
+		_, err := r.db.Exec(
+			"DELETE FROM message_reactions WHERE account_id = ? AND chat_jid = ? AND message_id = ? AND sender = ?",
+			reaction.AccountID, reaction.ChatJID, reaction.MessageID, reaction.Sender,
+		)
+		return err
+	}
+
+	query := `
+		INSERT INTO message_reactions (account_id, chat_jid, message_id, sender, emoji, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(account_id, chat_jid, message_id, sender) DO UPDATE SET
+			emoji = excluded.emoji,
+			timestamp = excluded.timestamp
+	`
+
+	_, err := r.db.Exec(query, reaction.AccountID, reaction.ChatJID, reaction.MessageID, reaction.Sender, reaction.Emoji, reaction.Timestamp)
 	return err
 }
 
+// StoreMessageEdit records an edit in the history and replaces the message content with the edited text
+func (r *SQLiteRepository) StoreMessageEdit(edit *domainChatStorage.MessageEdit) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The edited message may not be stored, in which case only the history is kept
+	err = tx.QueryRow(
+		"SELECT COALESCE(content, '') FROM messages WHERE account_id = ? AND id = ? AND chat_jid = ?",
+		edit.AccountID, edit.MessageID, edit.ChatJID,
+	).Scan(&edit.PreviousContent)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get edited message: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO message_edits (account_id, chat_jid, message_id, previous_content, content, edited_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, edit.AccountID, edit.ChatJID, edit.MessageID, edit.PreviousContent, edit.Content, edit.EditedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store message edit: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE messages SET content = ?, edit_count = edit_count + 1, updated_at = ?
+		WHERE account_id = ? AND id = ? AND chat_jid = ?
+	`, edit.Content, time.Now(), edit.AccountID, edit.MessageID, edit.ChatJID)
+	if err != nil {
+		return fmt.Errorf("failed to update edited message: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// MarkMessageRevoked flags a message as revoked by its sender, its content is kept
+func (r *SQLiteRepository) MarkMessageRevoked(accountID, id, chatJID string) error {
+	_, err := r.db.Exec(
+		"UPDATE messages SET is_revoked = TRUE, updated_at = ? WHERE account_id = ? AND id = ? AND chat_jid = ?",
+		time.Now(), accountID, id, chatJID,
+	)
+	return err
+}
+
+// GetReactions retrieves the reactions to the given messages of a chat, oldest first
+func (r *SQLiteRepository) GetReactions(accountID, chatJID string, messageIDs []string) ([]*domainChatStorage.MessageReaction, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT account_id, chat_jid, message_id, sender, emoji, timestamp
+		FROM message_reactions
+		WHERE account_id = ? AND chat_jid = ? AND message_id IN (` + placeholders(len(messageIDs)) + `)
+		ORDER BY timestamp ASC
+	`
+
+	rows, err := r.db.Query(query, messageArgs(accountID, chatJID, messageIDs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reactions []*domainChatStorage.MessageReaction
+	for rows.Next() {
+		reaction := &domainChatStorage.MessageReaction{}
+		err := rows.Scan(&reaction.AccountID, &reaction.ChatJID, &reaction.MessageID, &reaction.Sender, &reaction.Emoji, &reaction.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		reactions = append(reactions, reaction)
+	}
+
+	return reactions, rows.Err()
+}
+
+// GetMessageEdits retrieves the edit history of the given messages of a chat, oldest first
+func (r *SQLiteRepository) GetMessageEdits(accountID, chatJID string, messageIDs []string) ([]*domainChatStorage.MessageEdit, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT account_id, chat_jid, message_id, COALESCE(previous_content, ''), COALESCE(content, ''), edited_at
+		FROM message_edits
+		WHERE account_id = ? AND chat_jid = ? AND message_id IN (` + placeholders(len(messageIDs)) + `)
+		ORDER BY edited_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(query, messageArgs(accountID, chatJID, messageIDs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edits []*domainChatStorage.MessageEdit
+	for rows.Next() {
+		edit := &domainChatStorage.MessageEdit{}
+		err := rows.Scan(&edit.AccountID, &edit.ChatJID, &edit.MessageID, &edit.PreviousContent, &edit.Content, &edit.EditedAt)
+		if err != nil {
+			return nil, err
+		}
+		edits = append(edits, edit)
+	}
+
+	return edits, rows.Err()
+}
+
+// StoreReceipt records the delivery or read time of a message for a recipient device, keeping the earliest of each.
+// A read receipt also marks the message as delivered when no delivery receipt was seen.
+func (r *SQLiteRepository) StoreReceipt(receipt *domainChatStorage.MessageReceipt) error {
+	deliveredAt := receipt.DeliveredAt
+	if deliveredAt == nil {
+		deliveredAt = receipt.ReadAt
+	}
+
+	query := `
+		INSERT INTO message_receipts (account_id, chat_jid, message_id, recipient, participant, delivered_at, read_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(account_id, message_id, recipient) DO UPDATE SET
+			delivered_at = COALESCE(MIN(message_receipts.delivered_at, excluded.delivered_at), message_receipts.delivered_at, excluded.delivered_at),
+			read_at = COALESCE(MIN(message_receipts.read_at, excluded.read_at), message_receipts.read_at, excluded.read_at)
+	`
+
+	_, err := r.db.Exec(query, receipt.AccountID, receipt.ChatJID, receipt.MessageID, receipt.Recipient,
+		receipt.Participant, deliveredAt, receipt.ReadAt)
+	return err
+}
+
+// GetReceipts retrieves the receipts of the given messages of an account.
+// Receipts are matched by message ID only, since their chat may be reported as a LID instead of a phone number.
+func (r *SQLiteRepository) GetReceipts(accountID string, messageIDs []string) ([]*domainChatStorage.MessageReceipt, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT account_id, chat_jid, message_id, recipient, participant, delivered_at, read_at
+		FROM message_receipts
+		WHERE account_id = ? AND message_id IN (` + placeholders(len(messageIDs)) + `)
+		ORDER BY participant ASC, recipient ASC
+	`
+
+	args := make([]any, 0, len(messageIDs)+1)
+	args = append(args, accountID)
+	for _, id := range messageIDs {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receipts []*domainChatStorage.MessageReceipt
+	for rows.Next() {
+		receipt := &domainChatStorage.MessageReceipt{}
+		var deliveredAt, readAt sql.NullTime
+		err := rows.Scan(&receipt.AccountID, &receipt.ChatJID, &receipt.MessageID, &receipt.Recipient,
+			&receipt.Participant, &deliveredAt, &readAt)
+		if err != nil {
+			return nil, err
+		}
+		if deliveredAt.Valid {
+			receipt.DeliveredAt = &deliveredAt.Time
+		}
+		if readAt.Valid {
+			receipt.ReadAt = &readAt.Time
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, rows.Err()
+}
+
+// placeholders is a private helper returning n comma separated query placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// messageArgs is a private helper building the arguments for an account, a chat and a list of message IDs
+func messageArgs(accountID, chatJID string, messageIDs []string) []any {
+	args := make([]any, 0, len(messageIDs)+2)
+	args = append(args, accountID, chatJID)
+	for _, id := range messageIDs {
+		args = append(args, id)
+	}
+	return args
+}
+
 // getCount is a private helper for count queries
 func (r *SQLiteRepository) getCount(query string, args ...any) (int64, error) {
 	var count int64
@@ -411,10 +649,10 @@ func (r *SQLiteRepository) getCount(query string, args ...any) (int64, error) {
 func (r *SQLiteRepository) scanMessage(scanner interface{ Scan(...any) error }) (*domainChatStorage.Message, error) {
 	message := &domainChatStorage.Message{}
 	err := scanner.Scan(
-		&message.ID, &message.ChatJID, &message.Sender, &message.Content,
+		&message.AccountID, &message.ID, &message.ChatJID, &message.Sender, &message.Content,
 		&message.Timestamp, &message.IsFromMe, &message.MediaType, &message.Filename,
 		&message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
-		&message.FileLength, &message.CreatedAt, &message.UpdatedAt,
+		&message.FileLength, &message.IsRevoked, &message.EditCount, &message.CreatedAt, &message.UpdatedAt,
 	)
 	return message, err
 }
@@ -423,15 +661,20 @@ func (r *SQLiteRepository) scanMessage(scanner interface{ Scan(...any) error })
 func (r *SQLiteRepository) scanChat(scanner interface{ Scan(...any) error }) (*domainChatStorage.Chat, error) {
 	chat := &domainChatStorage.Chat{}
 	err := scanner.Scan(
-		&chat.JID, &chat.Name, &chat.LastMessageTime, &chat.EphemeralExpiration,
+		&chat.AccountID, &chat.JID, &chat.Name, &chat.LastMessageTime, &chat.EphemeralExpiration,
 		&chat.CreatedAt, &chat.UpdatedAt,
 	)
 	return chat, err
 }
 
-// GetChatMessageCount returns the number of messages in a chat
-func (r *SQLiteRepository) GetChatMessageCount(chatJID string) (int64, error) {
-	return r.getCount("SELECT COUNT(*) FROM messages WHERE chat_jid = ?", chatJID)
+// GetChatMessageCount returns the number of messages in a chat of an account
+func (r *SQLiteRepository) GetChatMessageCount(accountID, chatJID string) (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM messages WHERE account_id = ? AND chat_jid = ?", accountID, chatJID)
+}
+
+// GetAccountChatCount returns the number of chats of an account
+func (r *SQLiteRepository) GetAccountChatCount(accountID string) (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM chats WHERE account_id = ?", accountID)
 }
 
 // GetTotalMessageCount returns the total number of messages
@@ -453,6 +696,13 @@ func (r *SQLiteRepository) TruncateAllChats() error {
 	}
 	defer tx.Rollback()
 
+	// Delete reactions, edit history and receipts
+	for _, table := range []string{"message_reactions", "message_edits", "message_receipts"} {
+		if _, err = tx.Exec("DELETE FROM " + table); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", table, err)
+		}
+	}
+
 	// Delete messages first (foreign key constraint)
 	_, err = tx.Exec("DELETE FROM messages")
 	if err != nil {
@@ -469,231 +719,27 @@ func (r *SQLiteRepository) TruncateAllChats() error {
 }
 
 // GetChatNameWithPushName determines the appropriate name for a chat with pushname support
-func (r *SQLiteRepository) GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string {
-	// First, check if chat already exists with a name
-	existingChat, err := r.GetChat(chatJID)
-	if err == nil && existingChat != nil && existingChat.Name != "" {
-		// If we have a pushname and the existing name is just a phone number/JID user, update it
-		if pushName != "" && (existingChat.Name == jid.User || existingChat.Name == senderUser) {
-			return pushName
-		}
-		return existingChat.Name
-	}
-
-	// Determine chat type and name
-	var name string
-
-	switch jid.Server {
-	case "g.us":
-		// This is a group chat
-		// For now, use a generic name - this can be enhanced later with group info
-		name = fmt.Sprintf("Group %s", jid.User)
-	case "newsletter":
-		// This is a newsletter/channel
-		name = fmt.Sprintf("Newsletter %s", jid.User)
-	default:
-		// This is an individual contact
-		// Priority: pushName > senderUser > JID user
-		if pushName != "" && pushName != senderUser && pushName != jid.User {
-			name = pushName
-		} else if senderUser != "" {
-			name = senderUser
-		} else {
-			name = jid.User
-		}
-	}
-
-	return name
+func (r *SQLiteRepository) GetChatNameWithPushName(accountID string, jid types.JID, chatJID string, senderUser string, pushName string) string {
+	return chatNameWithPushName(r, accountID, jid, chatJID, senderUser, pushName)
 }
 
-func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Message) error {
-	if evt == nil || evt.Message == nil {
-		return nil
-	}
-
-	// Extract chat and sender information
-	chatJID := evt.Info.Chat.String()
-	// Store the full sender JID (user@server) to ensure consistency between received and sent messages
-	sender := evt.Info.Sender.String()
-
-	// Get appropriate chat name using pushname if available
-	chatName := r.GetChatNameWithPushName(evt.Info.Chat, chatJID, evt.Info.Sender.User, evt.Info.PushName)
-
-	// Get existing chat to preserve ephemeral_expiration if needed
-	existingChat, err := r.GetChat(chatJID)
-	if err != nil {
-		return fmt.Errorf("failed to get existing chat: %w", err)
-	}
-
-	// Extract ephemeral expiration from incoming message
-	ephemeralExpiration := utils.ExtractEphemeralExpiration(evt.Message)
-
-	// Create or update chat
-	chat := &domainChatStorage.Chat{
-		JID:             chatJID,
-		Name:            chatName,
-		LastMessageTime: evt.Info.Timestamp,
-	}
-
-	// Set ephemeral expiration: use incoming message value if > 0, otherwise preserve existing
-	if ephemeralExpiration > 0 {
-		chat.EphemeralExpiration = ephemeralExpiration
-	} else if existingChat != nil {
-		// Preserve existing ephemeral_expiration if incoming message doesn't have one
-		chat.EphemeralExpiration = existingChat.EphemeralExpiration
-	}
-
-	// Store or update the chat
-	if err := r.StoreChat(chat); err != nil {
-		return fmt.Errorf("failed to store chat: %w", err)
-	}
-
-	// Extract message content and media info
-	content := utils.ExtractMessageTextFromProto(evt.Message)
-	mediaType, filename, url, mediaKey, fileSHA256, fileEncSHA256, fileLength := utils.ExtractMediaInfo(evt.Message)
-
-	// Skip if there's no content and no media
-	if content == "" && mediaType == "" {
-		logrus.Debugf("Skipping message %s - no content or media", evt.Info.ID)
-		return nil
-	}
-
-	// Create message object
-	message := &domainChatStorage.Message{
-		ID:            evt.Info.ID,
-		ChatJID:       chatJID,
-		Sender:        sender,
-		Content:       content,
-		Timestamp:     evt.Info.Timestamp,
-		IsFromMe:      evt.Info.IsFromMe,
-		MediaType:     mediaType,
-		Filename:      filename,
-		URL:           url,
-		MediaKey:      mediaKey,
-		FileSHA256:    fileSHA256,
-		FileEncSHA256: fileEncSHA256,
-		FileLength:    fileLength,
-	}
-
-	// Store the message
-	return r.StoreMessage(message)
+func (r *SQLiteRepository) CreateMessage(ctx context.Context, accountID string, evt *events.Message) error {
+	return createMessage(ctx, r, accountID, evt)
 }
 
 // GetStorageStatistics returns current storage statistics for logging purposes
 func (r *SQLiteRepository) GetStorageStatistics() (chatCount int64, messageCount int64, err error) {
-	// Count all chats using efficient query
-	chatCount, err = r.GetTotalChatCount()
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to get chat count: %w", err)
-	}
-
-	// Count all messages
-	messageCount, err = r.GetTotalMessageCount()
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to get message count: %w", err)
-	}
-
-	return chatCount, messageCount, nil
+	return storageStatistics(r)
 }
 
 // TruncateAllDataWithLogging performs truncation with detailed logging
 func (r *SQLiteRepository) TruncateAllDataWithLogging(logPrefix string) error {
-	// Get statistics before truncation
-	chatCount, messageCount, err := r.GetStorageStatistics()
-	if err != nil {
-		logrus.Warnf("[%s] Failed to get storage statistics before truncation: %v", logPrefix, err)
-	} else {
-		logrus.Infof("[%s] Storage before truncation: %d chats, %d messages", logPrefix, chatCount, messageCount)
-	}
-
-	// Perform truncation
-	if err := r.TruncateAllChats(); err != nil {
-		return fmt.Errorf("failed to truncate chatstorage data: %w", err)
-	}
-
-	// Verify truncation
-	chatCountAfter, messageCountAfter, err := r.GetStorageStatistics()
-	if err != nil {
-		logrus.Warnf("[%s] Failed to get storage statistics after truncation: %v", logPrefix, err)
-	} else {
-		logrus.Infof("[%s] Storage after truncation: %d chats, %d messages", logPrefix, chatCountAfter, messageCountAfter)
-		if chatCountAfter == 0 && messageCountAfter == 0 {
-			logrus.Infof("[%s] ✅ Chatstorage truncation completed successfully", logPrefix)
-		} else {
-			logrus.Warnf("[%s] ⚠️ Truncation may not have completed fully", logPrefix)
-		}
-	}
-
-	return nil
+	return truncateAllDataWithLogging(r, logPrefix)
 }
 
 // StoreSentMessageWithContext stores a message that was sent by the user with context cancellation support
-func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error {
-	// Check if context is already cancelled before starting
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
-	}
-
-	// Ensure JID is properly formatted
-	jid, err := types.ParseJID(recipientJID)
-	if err != nil {
-		return fmt.Errorf("invalid JID format: %w", err)
-	}
-
-	chatJID := jid.String()
-
-	// Get chat name (no pushname available for sent messages)
-	chatName := r.GetChatNameWithPushName(jid, chatJID, jid.User, "")
-
-	// Check context again before database operations
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
-	}
-
-	// Get existing chat to preserve ephemeral_expiration
-	existingChat, err := r.GetChat(chatJID)
-	if err != nil {
-		return fmt.Errorf("failed to get existing chat: %w", err)
-	}
-
-	// Store or update chat, preserving existing ephemeral_expiration
-	chat := &domainChatStorage.Chat{
-		JID:             chatJID,
-		Name:            chatName,
-		LastMessageTime: timestamp,
-	}
-
-	// Preserve existing ephemeral_expiration if chat exists
-	if existingChat != nil {
-		chat.EphemeralExpiration = existingChat.EphemeralExpiration
-	}
-
-	if err := r.StoreChat(chat); err != nil {
-		return fmt.Errorf("failed to store chat: %w", err)
-	}
-
-	// Check context one more time before storing message
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
-	}
-
-	// Store the sent message
-	message := &domainChatStorage.Message{
-		ID:        messageID,
-		ChatJID:   chatJID,
-		Sender:    senderJID,
-		Content:   content,
-		Timestamp: timestamp,
-		IsFromMe:  true,
-	}
-
-	return r.StoreMessage(message)
+func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, accountID string, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error {
+	return storeSentMessage(ctx, r, accountID, messageID, senderJID, recipientJID, content, timestamp)
 }
 
 // _____________________________________________________________________________________________________________________
@@ -714,7 +760,7 @@ func (r *SQLiteRepository) InitializeSchema() error {
 		}
 	}
 
-	return nil
+	return r.initFullTextSearch()
 }
 
 // getSchemaVersion returns the current schema version
@@ -810,5 +856,136 @@ func (r *SQLiteRepository) getMigrations() []string {
 		`
 		CREATE INDEX IF NOT EXISTS idx_messages_id ON messages(id);
 		`,
+
+		// Migration 3: Scope chats and messages by account. SQLite cannot alter a primary key,
+		// so both tables are rebuilt and existing rows are assigned to the legacy account ('')
+		`
+		ALTER TABLE messages RENAME TO messages_old;
+		ALTER TABLE chats RENAME TO chats_old;
+
+		CREATE TABLE chats (
+			account_id TEXT NOT NULL DEFAULT '',
+			jid TEXT NOT NULL,
+			name TEXT NOT NULL,
+			last_message_time TIMESTAMP NOT NULL,
+			ephemeral_expiration INTEGER DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (account_id, jid)
+		);
+
+		CREATE TABLE messages (
+			account_id TEXT NOT NULL DEFAULT '',
+			id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			sender TEXT NOT NULL,
+			content TEXT,
+			timestamp TIMESTAMP NOT NULL,
+			is_from_me BOOLEAN DEFAULT FALSE,
+			media_type TEXT,
+			filename TEXT,
+			url TEXT,
+			media_key BLOB,
+			file_sha256 BLOB,
+			file_enc_sha256 BLOB,
+			file_length INTEGER DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (account_id, id, chat_jid),
+			FOREIGN KEY (account_id, chat_jid) REFERENCES chats(account_id, jid) ON DELETE CASCADE
+		);
+
+		INSERT INTO chats (account_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at)
+		SELECT '', jid, name, last_message_time, ephemeral_expiration, created_at, updated_at FROM chats_old;
+
+		-- Messages whose chat row is missing (older databases didn't enforce foreign keys) are kept
+		-- under a chat named after its JID instead of being dropped
+		INSERT INTO chats (account_id, jid, name, last_message_time)
+		SELECT '', chat_jid, chat_jid, MAX(timestamp) FROM messages_old
+		WHERE chat_jid NOT IN (SELECT jid FROM chats_old)
+		GROUP BY chat_jid;
+
+		INSERT INTO messages (
+			account_id, id, chat_jid, sender, content, timestamp, is_from_me,
+			media_type, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, created_at, updated_at
+		)
+		SELECT '', id, chat_jid, sender, content, timestamp, is_from_me,
+			media_type, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, created_at, updated_at
+		FROM messages_old;
+
+		DROP TABLE messages_old;
+		DROP TABLE chats_old;
+
+		CREATE INDEX IF NOT EXISTS idx_messages_account_chat ON messages(account_id, chat_jid);
+		CREATE INDEX IF NOT EXISTS idx_messages_chat_jid ON messages(chat_jid);
+		CREATE INDEX IF NOT EXISTS idx_messages_timestamp ON messages(timestamp);
+		CREATE INDEX IF NOT EXISTS idx_messages_media_type ON messages(media_type);
+		CREATE INDEX IF NOT EXISTS idx_messages_sender ON messages(sender);
+		CREATE INDEX IF NOT EXISTS idx_messages_id ON messages(account_id, id);
+		CREATE INDEX IF NOT EXISTS idx_chats_account_last_message ON chats(account_id, last_message_time);
+		CREATE INDEX IF NOT EXISTS idx_chats_last_message ON chats(last_message_time);
+		CREATE INDEX IF NOT EXISTS idx_chats_name ON chats(name);
+		`,
+
+		// Migration 4: Track revocations, edits and reactions of messages
+		`
+		ALTER TABLE messages ADD COLUMN is_revoked BOOLEAN DEFAULT FALSE;
+		ALTER TABLE messages ADD COLUMN edit_count INTEGER DEFAULT 0;
+
+		CREATE TABLE IF NOT EXISTS message_reactions (
+			account_id TEXT NOT NULL DEFAULT '',
+			chat_jid TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			sender TEXT NOT NULL,
+			emoji TEXT NOT NULL,
+			timestamp TIMESTAMP NOT NULL,
+			PRIMARY KEY (account_id, chat_jid, message_id, sender)
+		);
+
+		CREATE TABLE IF NOT EXISTS message_edits (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id TEXT NOT NULL DEFAULT '',
+			chat_jid TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			previous_content TEXT,
+			content TEXT,
+			edited_at TIMESTAMP NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_message_edits_message ON message_edits(account_id, chat_jid, message_id);
+		`,
+
+		// Migration 5: Delivery and read receipts per recipient device
+		`
+		CREATE TABLE IF NOT EXISTS message_receipts (
+			account_id TEXT NOT NULL DEFAULT '',
+			chat_jid TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			recipient TEXT NOT NULL,
+			participant TEXT NOT NULL,
+			delivered_at TIMESTAMP,
+			read_at TIMESTAMP,
+			PRIMARY KEY (account_id, message_id, recipient)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_message_receipts_chat ON message_receipts(account_id, chat_jid);
+		`,
+
+		// Migration 6: Starred messages and retention policies per account or chat
+		`
+		ALTER TABLE messages ADD COLUMN is_starred BOOLEAN DEFAULT FALSE;
+
+		CREATE TABLE IF NOT EXISTS retention_policies (
+			account_id TEXT NOT NULL DEFAULT '',
+			chat_jid TEXT NOT NULL DEFAULT '',
+			max_message_days INTEGER NOT NULL DEFAULT 0,
+			keep_starred BOOLEAN NOT NULL DEFAULT TRUE,
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (account_id, chat_jid)
+		);
+		`,
 	}
 }
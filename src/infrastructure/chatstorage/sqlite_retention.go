@@ -0,0 +1,100 @@
+package chatstorage
+
+import (
+	"fmt"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+// SetMessageStarred flags a message as starred, starred messages can be kept out of retention runs
+func (r *SQLiteRepository) SetMessageStarred(accountID, chatJID, id string, starred bool) error {
+	_, err := r.db.Exec(
+		"UPDATE messages SET is_starred = ?, updated_at = ? WHERE account_id = ? AND id = ? AND chat_jid = ?",
+		starred, time.Now(), accountID, id, chatJID,
+	)
+	return err
+}
+
+// GetRetentionPolicies retrieves every retention policy, account-wide policies before the chats of the account
+func (r *SQLiteRepository) GetRetentionPolicies() ([]*domainChatStorage.RetentionPolicy, error) {
+	rows, err := r.db.Query(`
+		SELECT account_id, chat_jid, max_message_days, keep_starred, updated_at
+		FROM retention_policies
+		ORDER BY account_id ASC, chat_jid ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []*domainChatStorage.RetentionPolicy
+	for rows.Next() {
+		policy := &domainChatStorage.RetentionPolicy{}
+		if err := rows.Scan(&policy.AccountID, &policy.ChatJID, &policy.MaxMessageDays, &policy.KeepStarred, &policy.UpdatedAt); err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, rows.Err()
+}
+
+// StoreRetentionPolicy creates or replaces the policy of an account or chat
+func (r *SQLiteRepository) StoreRetentionPolicy(policy *domainChatStorage.RetentionPolicy) error {
+	policy.UpdatedAt = time.Now()
+
+	_, err := r.db.Exec(`
+		INSERT INTO retention_policies (account_id, chat_jid, max_message_days, keep_starred, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(account_id, chat_jid) DO UPDATE SET
+			max_message_days = excluded.max_message_days,
+			keep_starred = excluded.keep_starred,
+			updated_at = excluded.updated_at
+	`, policy.AccountID, policy.ChatJID, policy.MaxMessageDays, policy.KeepStarred, policy.UpdatedAt)
+	return err
+}
+
+func (r *SQLiteRepository) DeleteRetentionPolicy(accountID, chatJID string) error {
+	_, err := r.db.Exec("DELETE FROM retention_policies WHERE account_id = ? AND chat_jid = ?", accountID, chatJID)
+	return err
+}
+
+// PruneMessages removes the messages selected by the filter and returns how many were removed
+func (r *SQLiteRepository) PruneMessages(filter *domainChatStorage.MessagePruneFilter) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	conditions, args := pruneConditions(filter)
+	var removed int64
+	for _, statement := range pruneStatements(conditions) {
+		result, err := tx.Exec(statement, args...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to prune messages: %w", err)
+		}
+		// The last statement removes the messages themselves
+		removed, _ = result.RowsAffected()
+	}
+
+	return removed, tx.Commit()
+}
+
+// Vacuum rebuilds the database file so the space of removed rows is returned to the disk,
+// then truncates the write-ahead log that the rebuild went through
+func (r *SQLiteRepository) Vacuum() error {
+	if _, err := r.db.Exec("VACUUM"); err != nil {
+		return err
+	}
+	_, err := r.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
+	return err
+}
+
+// GetDatabaseSize returns the size of the database file in bytes
+func (r *SQLiteRepository) GetDatabaseSize() (int64, error) {
+	var size int64
+	err := r.db.QueryRow("SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()").Scan(&size)
+	return size, err
+}
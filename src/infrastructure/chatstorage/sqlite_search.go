@@ -0,0 +1,240 @@
+package chatstorage
+
+import (
+	"fmt"
+	"strings"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	searchHighlightStart = "<mark>"
+	searchHighlightEnd   = "</mark>"
+	searchSnippetTokens  = 16
+)
+
+// fullTextSearchTriggers keep the external content FTS table in sync with the messages table.
+// Upserts in StoreMessage and edits in StoreMessageEdit go through the update trigger.
+var fullTextSearchTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS messages_fts_ai AFTER INSERT ON messages BEGIN
+		INSERT INTO messages_fts(rowid, content) VALUES (new.rowid, new.content);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS messages_fts_ad AFTER DELETE ON messages BEGIN
+		INSERT INTO messages_fts(messages_fts, rowid, content) VALUES ('delete', old.rowid, old.content);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS messages_fts_au AFTER UPDATE OF content ON messages BEGIN
+		INSERT INTO messages_fts(messages_fts, rowid, content) VALUES ('delete', old.rowid, old.content);
+		INSERT INTO messages_fts(rowid, content) VALUES (new.rowid, new.content);
+	END`,
+}
+
+// initFullTextSearch sets up the FTS5 index when SQLite was built with it (go build -tags sqlite_fts5).
+// It runs on every start instead of being a migration, so a binary without FTS5 can still open the
+// database: its triggers are dropped and the index is rebuilt once a binary with FTS5 starts again.
+func (r *SQLiteRepository) initFullTextSearch() error {
+	var available bool
+	if err := r.db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&available); err != nil {
+		return fmt.Errorf("failed to check FTS5 support: %w", err)
+	}
+
+	if !available {
+		logrus.Warn("[CHAT_STORAGE] SQLite was built without FTS5, message search falls back to LIKE scans")
+		for _, trigger := range []string{"messages_fts_ai", "messages_fts_ad", "messages_fts_au"} {
+			if _, err := r.db.Exec("DROP TRIGGER IF EXISTS " + trigger); err != nil {
+				return fmt.Errorf("failed to drop trigger %s: %w", trigger, err)
+			}
+		}
+		r.ftsEnabled = false
+		return nil
+	}
+
+	// Without the insert trigger the index is missing or stale and has to be rebuilt
+	var triggers int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name = 'messages_fts_ai'").Scan(&triggers); err != nil {
+		return fmt.Errorf("failed to check FTS triggers: %w", err)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts USING fts5(
+			content,
+			content = 'messages',
+			content_rowid = 'rowid',
+			tokenize = 'unicode61 remove_diacritics 2'
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create FTS table: %w", err)
+	}
+
+	for _, trigger := range fullTextSearchTriggers {
+		if _, err := tx.Exec(trigger); err != nil {
+			return fmt.Errorf("failed to create FTS trigger: %w", err)
+		}
+	}
+
+	if triggers == 0 {
+		logrus.Info("[CHAT_STORAGE] Building full-text search index for stored messages")
+		if _, err := tx.Exec("INSERT INTO messages_fts(messages_fts) VALUES ('rebuild')"); err != nil {
+			return fmt.Errorf("failed to build FTS index: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	r.ftsEnabled = true
+	return nil
+}
+
+// SearchAllMessages searches the messages of an account across chats, best matches first.
+// Every word of the query has to match the start of a word in the message.
+func (r *SQLiteRepository) SearchAllMessages(filter *domainChatStorage.MessageSearchFilter) ([]*domainChatStorage.MessageSearchResult, int64, error) {
+	terms := searchTerms(filter.Query)
+	if len(terms) == 0 {
+		return []*domainChatStorage.MessageSearchResult{}, 0, nil
+	}
+
+	conditions, args := searchFilterConditions(filter)
+
+	var from, columns, order string
+	if r.ftsEnabled {
+		from = "messages_fts JOIN messages m ON m.rowid = messages_fts.rowid"
+		conditions = append([]string{"messages_fts MATCH ?"}, conditions...)
+		args = append([]any{buildFTSQuery(terms)}, args...)
+		columns = fmt.Sprintf("snippet(messages_fts, 0, '%s', '%s', '…', %d), -bm25(messages_fts)",
+			searchHighlightStart, searchHighlightEnd, searchSnippetTokens)
+		order = "bm25(messages_fts) ASC, m.timestamp DESC"
+	} else {
+		from = "messages m"
+		for _, term := range terms {
+			conditions = append(conditions, "LOWER(m.content) LIKE ?")
+			args = append(args, "%"+strings.ToLower(term)+"%")
+		}
+		columns = "COALESCE(m.content, ''), 0"
+		order = "m.timestamp DESC"
+	}
+	where := strings.Join(conditions, " AND ")
+
+	total, err := r.getCount("SELECT COUNT(*) FROM "+from+" WHERE "+where, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	query := `
+		SELECT m.account_id, m.id, m.chat_jid, m.sender, m.content, m.timestamp, m.is_from_me,
+			m.media_type, m.filename, m.url, m.media_key, m.file_sha256,
+			m.file_enc_sha256, m.file_length, m.is_revoked, m.edit_count, m.created_at, m.updated_at,
+			` + columns + `
+		FROM ` + from + `
+		WHERE ` + where + `
+		ORDER BY ` + order
+
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search messages: %w", err)
+	}
+	defer rows.Close()
+
+	results := []*domainChatStorage.MessageSearchResult{}
+	for rows.Next() {
+		message := &domainChatStorage.Message{}
+		result := &domainChatStorage.MessageSearchResult{Message: message}
+		err := rows.Scan(
+			&message.AccountID, &message.ID, &message.ChatJID, &message.Sender, &message.Content,
+			&message.Timestamp, &message.IsFromMe, &message.MediaType, &message.Filename,
+			&message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
+			&message.FileLength, &message.IsRevoked, &message.EditCount, &message.CreatedAt, &message.UpdatedAt,
+			&result.Snippet, &result.Score,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan message: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating messages: %w", err)
+	}
+
+	return results, total, nil
+}
+
+// searchFilterConditions is a private helper building the non-text conditions of a search
+func searchFilterConditions(filter *domainChatStorage.MessageSearchFilter) ([]string, []any) {
+	conditions := []string{"m.account_id = ?"}
+	args := []any{filter.AccountID}
+
+	if filter.ChatJID != "" {
+		conditions = append(conditions, "m.chat_jid = ?")
+		args = append(args, filter.ChatJID)
+	}
+
+	// Messages from our other devices are stored with the device in the sender JID
+	if filter.Sender != "" {
+		user, server, _ := strings.Cut(filter.Sender, "@")
+		conditions = append(conditions, "(m.sender = ? OR m.sender LIKE ?)")
+		args = append(args, filter.Sender, user+":%@"+server)
+	}
+
+	if filter.MediaType != "" {
+		conditions = append(conditions, "m.media_type = ?")
+		args = append(args, filter.MediaType)
+	} else if filter.MediaOnly {
+		conditions = append(conditions, "m.media_type != ''")
+	}
+
+	if filter.IsFromMe != nil {
+		conditions = append(conditions, "m.is_from_me = ?")
+		args = append(args, *filter.IsFromMe)
+	}
+
+	if filter.StartTime != nil {
+		conditions = append(conditions, "m.timestamp >= ?")
+		args = append(args, *filter.StartTime)
+	}
+
+	if filter.EndTime != nil {
+		conditions = append(conditions, "m.timestamp <= ?")
+		args = append(args, *filter.EndTime)
+	}
+
+	return conditions, args
+}
+
+// searchTerms splits a search text into words, dropping the characters FTS5 treats as syntax
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(text, func(c rune) bool {
+		return c == '"' || c == '*' || c == '^' || c == ':' || c == '(' || c == ')' || c == '{' || c == '}' ||
+			strings.ContainsRune(" \t\r\n", c)
+	})
+}
+
+// buildFTSQuery turns search terms into an FTS5 query that matches all of them as quoted prefixes
+func buildFTSQuery(terms []string) string {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		quoted = append(quoted, `"`+term+`"*`)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,176 @@
+package sendqueue
+
+import (
+	"database/sql"
+	"time"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/sirupsen/logrus"
+)
+
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRepository(db *sql.DB) domainSend.ISendJobRepository {
+	repo := &PostgresRepository{db: db}
+	repo.initTables()
+	return repo
+}
+
+func (r *PostgresRepository) initTables() {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS send_jobs (
+			id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			recipient TEXT NOT NULL,
+			content TEXT NOT NULL DEFAULT '',
+			message BYTEA NOT NULL,
+			status TEXT NOT NULL DEFAULT 'queued',
+			message_id TEXT NOT NULL DEFAULT '',
+			error TEXT NOT NULL DEFAULT '',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			send_at TIMESTAMPTZ NOT NULL,
+			sent_at TIMESTAMPTZ,
+			attempted_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+		)`,
+		// Queues created before attempts were tracked
+		`ALTER TABLE send_jobs ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE send_jobs ADD COLUMN IF NOT EXISTS attempted_at TIMESTAMPTZ`,
+		`CREATE INDEX IF NOT EXISTS idx_send_jobs_due ON send_jobs(status, send_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_send_jobs_account_due ON send_jobs(account_id, status, send_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_send_jobs_account_attempted ON send_jobs(account_id, attempted_at)`,
+	}
+
+	for _, query := range queries {
+		if _, err := r.db.Exec(query); err != nil {
+			logrus.Errorf("Failed to create table: %v", err)
+		}
+	}
+}
+
+func (r *PostgresRepository) CreateJob(job *domainSend.Job) error {
+	now := time.Now().UTC()
+	if job.Status == "" {
+		job.Status = domainSend.JobStatusQueued
+	}
+	if job.SendAt.IsZero() {
+		job.SendAt = now
+	}
+	job.SendAt = job.SendAt.UTC()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	query := `INSERT INTO send_jobs (id, account_id, recipient, content, message, status, send_at, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.db.Exec(query, job.ID, job.AccountID, job.Recipient, job.Content, job.Message,
+		job.Status, job.SendAt, job.CreatedAt, job.UpdatedAt)
+	return err
+}
+
+func (r *PostgresRepository) GetJob(jobID string) (*domainSend.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM send_jobs WHERE id = $1`
+
+	job, err := scanJob(r.db.QueryRow(query, jobID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return job, err
+}
+
+func (r *PostgresRepository) ListDueAccounts(now time.Time) ([]string, error) {
+	query := `SELECT DISTINCT account_id FROM send_jobs WHERE status = $1 AND send_at <= $2`
+
+	rows, err := r.db.Query(query, domainSend.JobStatusQueued, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accountIDs []string
+	for rows.Next() {
+		var accountID string
+		if err := rows.Scan(&accountID); err != nil {
+			return nil, err
+		}
+		accountIDs = append(accountIDs, accountID)
+	}
+
+	return accountIDs, rows.Err()
+}
+
+// ClaimDueJob selects and claims the next due job in a single statement, so two workers can't claim the same job.
+// A job claimed by another instance sharing the database is skipped instead of waited for.
+func (r *PostgresRepository) ClaimDueJob(accountID string, now time.Time) (*domainSend.Job, error) {
+	query := `UPDATE send_jobs SET status = $1, attempts = attempts + 1, attempted_at = $2, updated_at = $2
+			  WHERE id = (
+				  SELECT id FROM send_jobs
+				  WHERE account_id = $3 AND status = $4 AND send_at <= $5
+				  ORDER BY send_at ASC, created_at ASC
+				  LIMIT 1
+				  FOR UPDATE SKIP LOCKED
+			  )
+			  RETURNING ` + jobColumns
+
+	job, err := scanJob(r.db.QueryRow(query, domainSend.JobStatusSending, now.UTC(), accountID, domainSend.JobStatusQueued, now.UTC()))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return job, err
+}
+
+func (r *PostgresRepository) LastAttemptAt(accountID string) (time.Time, error) {
+	query := `SELECT attempted_at FROM send_jobs
+			  WHERE account_id = $1 AND attempted_at IS NOT NULL
+			  ORDER BY attempted_at DESC
+			  LIMIT 1`
+
+	var attemptedAt time.Time
+	err := r.db.QueryRow(query, accountID).Scan(&attemptedAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+
+	return attemptedAt, err
+}
+
+func (r *PostgresRepository) MarkJobSent(jobID string, messageID string, sentAt time.Time) error {
+	query := `UPDATE send_jobs SET status = $1, message_id = $2, sent_at = $3, updated_at = $4 WHERE id = $5`
+	_, err := r.db.Exec(query, domainSend.JobStatusSent, messageID, sentAt.UTC(), time.Now().UTC(), jobID)
+	return err
+}
+
+func (r *PostgresRepository) MarkJobFailed(jobID string, reason string) error {
+	query := `UPDATE send_jobs SET status = $1, error = $2, updated_at = $3 WHERE id = $4`
+	_, err := r.db.Exec(query, domainSend.JobStatusFailed, reason, time.Now().UTC(), jobID)
+	return err
+}
+
+func (r *PostgresRepository) RetryJob(jobID string, messageID string, sendAt time.Time, reason string) error {
+	query := `UPDATE send_jobs SET status = $1, message_id = $2, send_at = $3, error = $4, updated_at = $5 WHERE id = $6`
+	_, err := r.db.Exec(query, domainSend.JobStatusQueued, messageID, sendAt.UTC(), reason, time.Now().UTC(), jobID)
+	return err
+}
+
+func (r *PostgresRepository) ExpireJobs(queuedBefore time.Time) (int64, error) {
+	query := `UPDATE send_jobs SET status = $1, updated_at = $2 WHERE status = $3 AND send_at < $4`
+	result, err := r.db.Exec(query, domainSend.JobStatusExpired, time.Now().UTC(), domainSend.JobStatusQueued, queuedBefore.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *PostgresRepository) FailStaleJobs(claimedBefore time.Time, reason string) (int64, error) {
+	query := `UPDATE send_jobs SET status = $1, error = $2, updated_at = $3 WHERE status = $4 AND attempted_at < $5`
+	result, err := r.db.Exec(query, domainSend.JobStatusFailed, reason, time.Now().UTC(), domainSend.JobStatusSending, claimedBefore.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,129 @@
+package sendqueue
+
+import (
+	"testing"
+	"time"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runRepository runs a case against a send queue repository of every backend
+func runRepository(t *testing.T, fn func(t *testing.T, repo domainSend.ISendJobRepository)) {
+	storagetest.Run(t, func(t *testing.T, backend storagetest.Backend) {
+		repo := NewSQLiteRepository(backend.DB)
+		if backend.Name == storagetest.Postgres {
+			repo = NewPostgresRepository(backend.DB)
+		}
+		fn(t, repo)
+	})
+}
+
+func queueJob(t *testing.T, repo domainSend.ISendJobRepository, id string, sendAt time.Time) {
+	require.NoError(t, repo.CreateJob(&domainSend.Job{
+		ID: id, AccountID: "default", Recipient: "6281234567890@s.whatsapp.net", Message: []byte{1}, SendAt: sendAt,
+	}))
+}
+
+func TestRepositoryClaimDueJob(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainSend.ISendJobRepository) {
+		now := time.Now()
+		queueJob(t, repo, "second", now.Add(-time.Minute))
+		queueJob(t, repo, "first", now.Add(-time.Hour))
+		queueJob(t, repo, "later", now.Add(time.Hour))
+
+		lastAttempt, err := repo.LastAttemptAt("default")
+		require.NoError(t, err)
+		assert.True(t, lastAttempt.IsZero())
+
+		job, err := repo.ClaimDueJob("default", now)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, "first", job.ID)
+		assert.Equal(t, domainSend.JobStatusSending, job.Status)
+		assert.Equal(t, 1, job.Attempts)
+
+		// A claimed job is never handed out again
+		job, err = repo.ClaimDueJob("default", now)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, "second", job.ID)
+
+		job, err = repo.ClaimDueJob("default", now)
+		require.NoError(t, err)
+		assert.Nil(t, job, "jobs scheduled later aren't due")
+
+		lastAttempt, err = repo.LastAttemptAt("default")
+		require.NoError(t, err)
+		assert.WithinDuration(t, now, lastAttempt, time.Second)
+
+		accountIDs, err := repo.ListDueAccounts(now)
+		require.NoError(t, err)
+		assert.Empty(t, accountIDs)
+	})
+}
+
+func TestRepositoryRetryJob(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainSend.ISendJobRepository) {
+		now := time.Now()
+		queueJob(t, repo, "job", now.Add(-time.Minute))
+
+		job, err := repo.ClaimDueJob("default", now)
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		require.NoError(t, repo.RetryJob(job.ID, "3EB0ABC", now.Add(time.Minute), "timed out"))
+
+		job, err = repo.GetJob("job")
+		require.NoError(t, err)
+		assert.Equal(t, domainSend.JobStatusQueued, job.Status)
+		assert.Equal(t, "3EB0ABC", job.MessageID)
+		assert.Equal(t, "timed out", job.Error)
+
+		job, err = repo.ClaimDueJob("default", now.Add(2*time.Minute))
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, 2, job.Attempts)
+		assert.Equal(t, "3EB0ABC", job.MessageID, "retries reuse the message ID")
+
+		require.NoError(t, repo.MarkJobSent(job.ID, "3EB0ABC", now))
+		job, err = repo.GetJob("job")
+		require.NoError(t, err)
+		assert.Equal(t, domainSend.JobStatusSent, job.Status)
+		require.NotNil(t, job.SentAt)
+	})
+}
+
+func TestRepositoryExpireAndFailStaleJobs(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainSend.ISendJobRepository) {
+		now := time.Now()
+		queueJob(t, repo, "old", now.Add(-48*time.Hour))
+		queueJob(t, repo, "claimed", now.Add(-49*time.Hour))
+		queueJob(t, repo, "recent", now.Add(-time.Hour))
+
+		job, err := repo.ClaimDueJob("default", now.Add(-49*time.Hour))
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		assert.Equal(t, "claimed", job.ID)
+
+		// Claimed jobs are left to the stale check, even when they are old
+		expired, err := repo.ExpireJobs(now.Add(-24 * time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), expired)
+
+		failed, err := repo.FailStaleJobs(now.Add(-time.Hour), "interrupted")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), failed)
+
+		for id, status := range map[string]string{
+			"old":     domainSend.JobStatusExpired,
+			"claimed": domainSend.JobStatusFailed,
+			"recent":  domainSend.JobStatusQueued,
+		} {
+			job, err := repo.GetJob(id)
+			require.NoError(t, err)
+			assert.Equal(t, status, job.Status, id)
+		}
+	})
+}
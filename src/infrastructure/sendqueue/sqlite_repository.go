@@ -0,0 +1,236 @@
+package sendqueue
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/sirupsen/logrus"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) domainSend.ISendJobRepository {
+	repo := &SQLiteRepository{db: db}
+	repo.initTables()
+	return repo
+}
+
+func (r *SQLiteRepository) initTables() {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS send_jobs (
+			id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			recipient TEXT NOT NULL,
+			content TEXT NOT NULL DEFAULT '',
+			message BLOB NOT NULL,
+			status TEXT NOT NULL DEFAULT 'queued',
+			message_id TEXT NOT NULL DEFAULT '',
+			error TEXT NOT NULL DEFAULT '',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			send_at DATETIME NOT NULL,
+			sent_at DATETIME,
+			attempted_at DATETIME,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_send_jobs_due ON send_jobs(status, send_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_send_jobs_account_due ON send_jobs(account_id, status, send_at)`,
+	}
+
+	for _, query := range queries {
+		if _, err := r.db.Exec(query); err != nil {
+			logrus.Errorf("Failed to create table: %v", err)
+		}
+	}
+
+	// Queues created before attempts were tracked
+	r.ensureColumn("send_jobs", "attempts", "INTEGER NOT NULL DEFAULT 0")
+	r.ensureColumn("send_jobs", "attempted_at", "DATETIME")
+	if _, err := r.db.Exec(`CREATE INDEX IF NOT EXISTS idx_send_jobs_account_attempted ON send_jobs(account_id, attempted_at)`); err != nil {
+		logrus.Errorf("Failed to create index: %v", err)
+	}
+}
+
+// ensureColumn adds a column to an existing table when it is missing
+func (r *SQLiteRepository) ensureColumn(table, column, definition string) {
+	var count int
+	query := `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`
+	if err := r.db.QueryRow(query, table, column).Scan(&count); err != nil {
+		logrus.Errorf("Failed to inspect table %s: %v", table, err)
+		return
+	}
+	if count > 0 {
+		return
+	}
+
+	if _, err := r.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		logrus.Errorf("Failed to add column %s.%s: %v", table, column, err)
+	}
+}
+
+func (r *SQLiteRepository) CreateJob(job *domainSend.Job) error {
+	now := time.Now().UTC()
+	if job.Status == "" {
+		job.Status = domainSend.JobStatusQueued
+	}
+	if job.SendAt.IsZero() {
+		job.SendAt = now
+	}
+	job.SendAt = job.SendAt.UTC()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	query := `INSERT INTO send_jobs (id, account_id, recipient, content, message, status, send_at, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, job.ID, job.AccountID, job.Recipient, job.Content, job.Message,
+		job.Status, job.SendAt, job.CreatedAt, job.UpdatedAt)
+	return err
+}
+
+func (r *SQLiteRepository) GetJob(jobID string) (*domainSend.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM send_jobs WHERE id = ?`
+
+	job, err := scanJob(r.db.QueryRow(query, jobID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return job, err
+}
+
+func (r *SQLiteRepository) ListDueAccounts(now time.Time) ([]string, error) {
+	query := `SELECT DISTINCT account_id FROM send_jobs WHERE status = ? AND send_at <= ?`
+
+	rows, err := r.db.Query(query, domainSend.JobStatusQueued, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accountIDs []string
+	for rows.Next() {
+		var accountID string
+		if err := rows.Scan(&accountID); err != nil {
+			return nil, err
+		}
+		accountIDs = append(accountIDs, accountID)
+	}
+
+	return accountIDs, rows.Err()
+}
+
+// ClaimDueJob selects and claims the next due job in one transaction, SQLite serializes writers
+// so two workers can't claim the same job.
+func (r *SQLiteRepository) ClaimDueJob(accountID string, now time.Time) (*domainSend.Job, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + jobColumns + `
+			  FROM send_jobs
+			  WHERE account_id = ? AND status = ? AND send_at <= ?
+			  ORDER BY send_at ASC, created_at ASC
+			  LIMIT 1`
+
+	job, err := scanJob(tx.QueryRow(query, accountID, domainSend.JobStatusQueued, now.UTC()))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	claimedAt := now.UTC()
+	job.Status = domainSend.JobStatusSending
+	job.Attempts++
+	job.AttemptedAt = &claimedAt
+	job.UpdatedAt = claimedAt
+
+	update := `UPDATE send_jobs SET status = ?, attempts = ?, attempted_at = ?, updated_at = ? WHERE id = ?`
+	if _, err := tx.Exec(update, job.Status, job.Attempts, claimedAt, claimedAt, job.ID); err != nil {
+		return nil, err
+	}
+
+	return job, tx.Commit()
+}
+
+func (r *SQLiteRepository) LastAttemptAt(accountID string) (time.Time, error) {
+	query := `SELECT attempted_at FROM send_jobs
+			  WHERE account_id = ? AND attempted_at IS NOT NULL
+			  ORDER BY attempted_at DESC
+			  LIMIT 1`
+
+	var attemptedAt time.Time
+	err := r.db.QueryRow(query, accountID).Scan(&attemptedAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+
+	return attemptedAt, err
+}
+
+func (r *SQLiteRepository) MarkJobSent(jobID string, messageID string, sentAt time.Time) error {
+	query := `UPDATE send_jobs SET status = ?, message_id = ?, sent_at = ?, updated_at = ? WHERE id = ?`
+	_, err := r.db.Exec(query, domainSend.JobStatusSent, messageID, sentAt.UTC(), time.Now().UTC(), jobID)
+	return err
+}
+
+func (r *SQLiteRepository) MarkJobFailed(jobID string, reason string) error {
+	query := `UPDATE send_jobs SET status = ?, error = ?, updated_at = ? WHERE id = ?`
+	_, err := r.db.Exec(query, domainSend.JobStatusFailed, reason, time.Now().UTC(), jobID)
+	return err
+}
+
+func (r *SQLiteRepository) RetryJob(jobID string, messageID string, sendAt time.Time, reason string) error {
+	query := `UPDATE send_jobs SET status = ?, message_id = ?, send_at = ?, error = ?, updated_at = ? WHERE id = ?`
+	_, err := r.db.Exec(query, domainSend.JobStatusQueued, messageID, sendAt.UTC(), reason, time.Now().UTC(), jobID)
+	return err
+}
+
+func (r *SQLiteRepository) ExpireJobs(queuedBefore time.Time) (int64, error) {
+	query := `UPDATE send_jobs SET status = ?, updated_at = ? WHERE status = ? AND send_at < ?`
+	result, err := r.db.Exec(query, domainSend.JobStatusExpired, time.Now().UTC(), domainSend.JobStatusQueued, queuedBefore.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *SQLiteRepository) FailStaleJobs(claimedBefore time.Time, reason string) (int64, error) {
+	query := `UPDATE send_jobs SET status = ?, error = ?, updated_at = ? WHERE status = ? AND attempted_at < ?`
+	result, err := r.db.Exec(query, domainSend.JobStatusFailed, reason, time.Now().UTC(), domainSend.JobStatusSending, claimedBefore.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const jobColumns = `id, account_id, recipient, content, message, status, message_id, error, attempts, send_at, sent_at, attempted_at, created_at, updated_at`
+
+func scanJob(scanner interface{ Scan(...any) error }) (*domainSend.Job, error) {
+	job := &domainSend.Job{}
+	var sentAt, attemptedAt sql.NullTime
+	err := scanner.Scan(
+		&job.ID, &job.AccountID, &job.Recipient, &job.Content, &job.Message,
+		&job.Status, &job.MessageID, &job.Error, &job.Attempts,
+		&job.SendAt, &sentAt, &attemptedAt, &job.CreatedAt, &job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if sentAt.Valid {
+		job.SentAt = &sentAt.Time
+	}
+	if attemptedAt.Valid {
+		job.AttemptedAt = &attemptedAt.Time
+	}
+	return job, nil
+}
@@ -0,0 +1,124 @@
+package template
+
+import (
+	"database/sql"
+	"time"
+
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	"github.com/sirupsen/logrus"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) domainTemplate.ITemplateRepository {
+	repo := &SQLiteRepository{db: db}
+	repo.initTables()
+	return repo
+}
+
+func (r *SQLiteRepository) initTables() {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS message_templates (
+			id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			content TEXT NOT NULL DEFAULT '',
+			media_type TEXT NOT NULL DEFAULT '',
+			media_name TEXT NOT NULL DEFAULT '',
+			media_mime TEXT NOT NULL DEFAULT '',
+			media BLOB,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			UNIQUE (account_id, name),
+			FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_message_templates_account ON message_templates(account_id)`,
+	}
+
+	for _, query := range queries {
+		if _, err := r.db.Exec(query); err != nil {
+			logrus.Errorf("Failed to create table: %v", err)
+		}
+	}
+}
+
+func (r *SQLiteRepository) CreateTemplate(template *domainTemplate.Template) error {
+	now := time.Now().UTC()
+	template.CreatedAt = now
+	template.UpdatedAt = now
+
+	query := `INSERT INTO message_templates (id, account_id, name, content, media_type, media_name, media_mime, media, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, template.ID, template.AccountID, template.Name, template.Content,
+		template.MediaType, template.MediaName, template.MediaMime, template.Media,
+		template.CreatedAt, template.UpdatedAt)
+	return err
+}
+
+func (r *SQLiteRepository) GetTemplate(accountID, templateID string) (*domainTemplate.Template, error) {
+	query := `SELECT id, account_id, name, content, media_type, media_name, media_mime, media, created_at, updated_at
+			  FROM message_templates WHERE account_id = ? AND id = ?`
+
+	template := &domainTemplate.Template{}
+	err := r.db.QueryRow(query, accountID, templateID).Scan(
+		&template.ID, &template.AccountID, &template.Name, &template.Content,
+		&template.MediaType, &template.MediaName, &template.MediaMime, &template.Media,
+		&template.CreatedAt, &template.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (r *SQLiteRepository) ListTemplates(accountID string) ([]*domainTemplate.Template, error) {
+	query := `SELECT id, account_id, name, content, media_type, media_name, media_mime, created_at, updated_at
+			  FROM message_templates WHERE account_id = ? ORDER BY name ASC`
+
+	rows, err := r.db.Query(query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*domainTemplate.Template
+	for rows.Next() {
+		template := &domainTemplate.Template{}
+		err := rows.Scan(
+			&template.ID, &template.AccountID, &template.Name, &template.Content,
+			&template.MediaType, &template.MediaName, &template.MediaMime,
+			&template.CreatedAt, &template.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, rows.Err()
+}
+
+func (r *SQLiteRepository) UpdateTemplate(template *domainTemplate.Template) error {
+	template.UpdatedAt = time.Now().UTC()
+
+	query := `UPDATE message_templates
+			  SET name = ?, content = ?, media_type = ?, media_name = ?, media_mime = ?, media = ?, updated_at = ?
+			  WHERE account_id = ? AND id = ?`
+
+	_, err := r.db.Exec(query, template.Name, template.Content,
+		template.MediaType, template.MediaName, template.MediaMime, template.Media,
+		template.UpdatedAt, template.AccountID, template.ID)
+	return err
+}
+
+func (r *SQLiteRepository) DeleteTemplate(accountID, templateID string) error {
+	_, err := r.db.Exec(`DELETE FROM message_templates WHERE account_id = ? AND id = ?`, accountID, templateID)
+	return err
+}
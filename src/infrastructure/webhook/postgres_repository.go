@@ -0,0 +1,257 @@
+package webhook
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRepository(db *sql.DB) domainWebhook.IWebhookRepository {
+	repo := &PostgresRepository{db: db}
+	repo.initTables()
+	return repo
+}
+
+func (r *PostgresRepository) initTables() {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS webhook_outbox (
+			id BIGSERIAL PRIMARY KEY,
+			account_id TEXT NOT NULL DEFAULT '',
+			event TEXT NOT NULL DEFAULT '',
+			url TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			signature TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt_at TIMESTAMPTZ NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS webhook_endpoints (
+			id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL DEFAULT '',
+			events TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_account ON webhook_endpoints(account_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_outbox_due ON webhook_outbox(status, next_attempt_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_outbox_account ON webhook_outbox(account_id, status)`,
+	}
+
+	for _, query := range queries {
+		if _, err := r.db.Exec(query); err != nil {
+			logrus.Errorf("Failed to create table: %v", err)
+		}
+	}
+}
+
+func (r *PostgresRepository) CreateEndpoint(endpoint *domainWebhook.Endpoint) error {
+	now := time.Now().UTC()
+	endpoint.CreatedAt = now
+	endpoint.UpdatedAt = now
+
+	query := `INSERT INTO webhook_endpoints (id, account_id, url, secret, events, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := r.db.Exec(query, endpoint.ID, endpoint.AccountID, endpoint.URL, endpoint.Secret,
+		strings.Join(endpoint.Events, ","), endpoint.CreatedAt, endpoint.UpdatedAt)
+	return err
+}
+
+func (r *PostgresRepository) GetEndpoint(accountID, endpointID string) (*domainWebhook.Endpoint, error) {
+	query := `SELECT id, account_id, url, secret, events, created_at, updated_at
+			  FROM webhook_endpoints WHERE account_id = $1 AND id = $2`
+
+	endpoint, err := scanEndpoint(r.db.QueryRow(query, accountID, endpointID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return endpoint, err
+}
+
+func (r *PostgresRepository) ListEndpoints(accountID string) ([]*domainWebhook.Endpoint, error) {
+	query := `SELECT id, account_id, url, secret, events, created_at, updated_at
+			  FROM webhook_endpoints WHERE account_id = $1 ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.Query(query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var endpoints []*domainWebhook.Endpoint
+	for rows.Next() {
+		endpoint, err := scanEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, rows.Err()
+}
+
+func (r *PostgresRepository) UpdateEndpoint(endpoint *domainWebhook.Endpoint) error {
+	endpoint.UpdatedAt = time.Now().UTC()
+
+	query := `UPDATE webhook_endpoints SET url = $1, secret = $2, events = $3, updated_at = $4
+			  WHERE account_id = $5 AND id = $6`
+
+	_, err := r.db.Exec(query, endpoint.URL, endpoint.Secret, strings.Join(endpoint.Events, ","),
+		endpoint.UpdatedAt, endpoint.AccountID, endpoint.ID)
+	return err
+}
+
+func (r *PostgresRepository) DeleteEndpoint(accountID, endpointID string) error {
+	_, err := r.db.Exec(`DELETE FROM webhook_endpoints WHERE account_id = $1 AND id = $2`, accountID, endpointID)
+	return err
+}
+
+func (r *PostgresRepository) EnqueueDelivery(delivery *domainWebhook.Delivery) error {
+	now := time.Now().UTC()
+	if delivery.Status == "" {
+		delivery.Status = domainWebhook.DeliveryStatusPending
+	}
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = now
+	}
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	query := `INSERT INTO webhook_outbox (account_id, event, url, payload, signature, status, attempts, last_error, next_attempt_at, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			  RETURNING id`
+
+	return r.db.QueryRow(query, delivery.AccountID, delivery.Event, delivery.URL, delivery.Payload, delivery.Signature,
+		delivery.Status, delivery.Attempts, delivery.LastError, delivery.NextAttemptAt, delivery.CreatedAt, delivery.UpdatedAt,
+	).Scan(&delivery.ID)
+}
+
+// ClaimDueDeliveries returns pending deliveries that are due and pushes their next attempt
+// forward by the lease, so a crash mid-delivery only results in a later retry.
+// Rows claimed by another instance sharing the database are skipped instead of waited for.
+func (r *PostgresRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]*domainWebhook.Delivery, error) {
+	query := `UPDATE webhook_outbox SET next_attempt_at = $1
+			  WHERE id IN (
+				  SELECT id FROM webhook_outbox
+				  WHERE status = $2 AND next_attempt_at <= $3
+				  ORDER BY next_attempt_at ASC, id ASC
+				  LIMIT $4
+				  FOR UPDATE SKIP LOCKED
+			  )
+			  RETURNING id, account_id, event, url, payload, signature, status, attempts, last_error, next_attempt_at, created_at, updated_at`
+
+	rows, err := r.db.Query(query, now.Add(lease).UTC(), domainWebhook.DeliveryStatusPending, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domainWebhook.Delivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *PostgresRepository) DeleteDelivery(id int64) error {
+	_, err := r.db.Exec(`DELETE FROM webhook_outbox WHERE id = $1`, id)
+	return err
+}
+
+func (r *PostgresRepository) ScheduleRetry(id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	query := `UPDATE webhook_outbox SET attempts = $1, next_attempt_at = $2, last_error = $3, updated_at = $4 WHERE id = $5`
+	_, err := r.db.Exec(query, attempts, nextAttemptAt.UTC(), lastError, time.Now().UTC(), id)
+	return err
+}
+
+func (r *PostgresRepository) MarkDead(id int64, attempts int, lastError string) error {
+	query := `UPDATE webhook_outbox SET status = $1, attempts = $2, last_error = $3, updated_at = $4 WHERE id = $5`
+	_, err := r.db.Exec(query, domainWebhook.DeliveryStatusDead, attempts, lastError, time.Now().UTC(), id)
+	return err
+}
+
+func (r *PostgresRepository) ListDeadDeliveries(accountID string, limit, offset int) ([]*domainWebhook.Delivery, error) {
+	query := `SELECT id, account_id, event, url, payload, signature, status, attempts, last_error, next_attempt_at, created_at, updated_at
+			  FROM webhook_outbox
+			  WHERE account_id = $1 AND status = $2
+			  ORDER BY updated_at DESC, id DESC
+			  LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(query, accountID, domainWebhook.DeliveryStatusDead, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domainWebhook.Delivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *PostgresRepository) CountDeadDeliveries(accountID string) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM webhook_outbox WHERE account_id = $1 AND status = $2`
+	err := r.db.QueryRow(query, accountID, domainWebhook.DeliveryStatusDead).Scan(&count)
+	return count, err
+}
+
+// ReplayDeadDeliveries moves dead deliveries back to pending with a fresh attempt budget.
+// created_at is reset as well, otherwise the max age would dead-letter them again right away.
+func (r *PostgresRepository) ReplayDeadDeliveries(accountID string, deliveryIDs []int64) (int64, error) {
+	now := time.Now().UTC()
+	query := `UPDATE webhook_outbox
+			  SET status = $1, attempts = 0, last_error = '', next_attempt_at = $2, created_at = $2, updated_at = $2
+			  WHERE account_id = $3 AND status = $4`
+	args := []any{domainWebhook.DeliveryStatusPending, now, accountID, domainWebhook.DeliveryStatusDead}
+
+	if len(deliveryIDs) > 0 {
+		query += " AND id = ANY($5)"
+		args = append(args, pq.Array(deliveryIDs))
+	}
+	return r.execAffected(query, args...)
+}
+
+func (r *PostgresRepository) PurgeDeadDeliveries(accountID string, deliveryIDs []int64) (int64, error) {
+	query := `DELETE FROM webhook_outbox WHERE account_id = $1 AND status = $2`
+	args := []any{accountID, domainWebhook.DeliveryStatusDead}
+
+	if len(deliveryIDs) > 0 {
+		query += " AND id = ANY($3)"
+		args = append(args, pq.Array(deliveryIDs))
+	}
+	return r.execAffected(query, args...)
+}
+
+func (r *PostgresRepository) execAffected(query string, args ...any) (int64, error) {
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
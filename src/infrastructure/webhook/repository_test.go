@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runRepository runs a case against a webhook repository of every backend
+func runRepository(t *testing.T, fn func(t *testing.T, repo domainWebhook.IWebhookRepository)) {
+	storagetest.Run(t, func(t *testing.T, backend storagetest.Backend) {
+		repo := NewSQLiteRepository(backend.DB)
+		if backend.Name == storagetest.Postgres {
+			repo = NewPostgresRepository(backend.DB)
+		}
+		fn(t, repo)
+	})
+}
+
+func enqueueDelivery(t *testing.T, repo domainWebhook.IWebhookRepository, accountID string) *domainWebhook.Delivery {
+	delivery := &domainWebhook.Delivery{
+		AccountID: accountID,
+		Event:     domainWebhook.EventMessage,
+		URL:       "https://example.com/hook",
+		Payload:   `{"event":"message"}`,
+		Signature: "signature",
+	}
+	require.NoError(t, repo.EnqueueDelivery(delivery))
+	return delivery
+}
+
+// deadDelivery enqueues a delivery and moves it to the dead letters of the account
+func deadDelivery(t *testing.T, repo domainWebhook.IWebhookRepository, accountID string) *domainWebhook.Delivery {
+	delivery := enqueueDelivery(t, repo, accountID)
+	require.NoError(t, repo.MarkDead(delivery.ID, 3, "webhook returned status 500"))
+	return delivery
+}
+
+func TestRepositoryClaimDueDeliveries(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainWebhook.IWebhookRepository) {
+		first := enqueueDelivery(t, repo, "a")
+		second := enqueueDelivery(t, repo, "b")
+
+		now := time.Now()
+		deliveries, err := repo.ClaimDueDeliveries(now, time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		assert.Equal(t, first.ID, deliveries[0].ID)
+		assert.Equal(t, second.ID, deliveries[1].ID)
+		assert.Equal(t, `{"event":"message"}`, deliveries[0].Payload)
+		assert.Equal(t, "signature", deliveries[0].Signature)
+
+		// The lease hides claimed deliveries from a second claim until it runs out
+		deliveries, err = repo.ClaimDueDeliveries(now, time.Minute, 10)
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+
+		deliveries, err = repo.ClaimDueDeliveries(now.Add(2*time.Minute), time.Minute, 1)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1, "the limit caps a claim")
+		assert.Equal(t, first.ID, deliveries[0].ID)
+
+		require.NoError(t, repo.DeleteDelivery(second.ID))
+		deliveries, err = repo.ClaimDueDeliveries(now.Add(2*time.Minute), time.Minute, 10)
+		require.NoError(t, err)
+		assert.Empty(t, deliveries, "delivered rows are removed")
+	})
+}
+
+func TestRepositoryScheduleRetry(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainWebhook.IWebhookRepository) {
+		delivery := enqueueDelivery(t, repo, "a")
+
+		now := time.Now()
+		deliveries, err := repo.ClaimDueDeliveries(now, time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.NoError(t, repo.ScheduleRetry(delivery.ID, 1, now.Add(10*time.Minute), "webhook returned status 502"))
+
+		// The retry replaces the lease, the delivery is due again at its next attempt
+		deliveries, err = repo.ClaimDueDeliveries(now.Add(5*time.Minute), time.Minute, 10)
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+
+		deliveries, err = repo.ClaimDueDeliveries(now.Add(10*time.Minute), time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+		assert.Equal(t, "webhook returned status 502", deliveries[0].LastError)
+		assert.Equal(t, domainWebhook.DeliveryStatusPending, deliveries[0].Status)
+	})
+}
+
+func TestRepositoryDeadLetters(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainWebhook.IWebhookRepository) {
+		dead := deadDelivery(t, repo, "a")
+		enqueueDelivery(t, repo, "a")
+
+		// Dead deliveries are never claimed
+		deliveries, err := repo.ClaimDueDeliveries(time.Now().Add(time.Hour), time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.NotEqual(t, dead.ID, deliveries[0].ID)
+
+		deliveries, err = repo.ListDeadDeliveries("a", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, dead.ID, deliveries[0].ID)
+		assert.Equal(t, domainWebhook.DeliveryStatusDead, deliveries[0].Status)
+		assert.Equal(t, 3, deliveries[0].Attempts)
+		assert.Equal(t, "webhook returned status 500", deliveries[0].LastError)
+
+		count, err := repo.CountDeadDeliveries("a")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+func TestRepositoryReplayDeadDeliveries(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainWebhook.IWebhookRepository) {
+		replayed := deadDelivery(t, repo, "a")
+		deadDelivery(t, repo, "a")
+		other := deadDelivery(t, repo, "b")
+
+		affected, err := repo.ReplayDeadDeliveries("a", []int64{replayed.ID, other.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), affected, "deliveries of another account aren't replayed")
+
+		// A replayed delivery is pending again with a fresh attempt budget
+		deliveries, err := repo.ClaimDueDeliveries(time.Now().Add(time.Second), time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, replayed.ID, deliveries[0].ID)
+		assert.Equal(t, domainWebhook.DeliveryStatusPending, deliveries[0].Status)
+		assert.Zero(t, deliveries[0].Attempts)
+		assert.Empty(t, deliveries[0].LastError)
+
+		// Without IDs every dead delivery of the account is replayed
+		affected, err = repo.ReplayDeadDeliveries("a", nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), affected)
+
+		deliveries, err = repo.ListDeadDeliveries("a", 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+
+		deliveries, err = repo.ListDeadDeliveries("b", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, other.ID, deliveries[0].ID)
+	})
+}
+
+func TestRepositoryPurgeDeadDeliveries(t *testing.T) {
+	runRepository(t, func(t *testing.T, repo domainWebhook.IWebhookRepository) {
+		purged := deadDelivery(t, repo, "a")
+		deadDelivery(t, repo, "a")
+		other := deadDelivery(t, repo, "b")
+		pending := enqueueDelivery(t, repo, "a")
+
+		affected, err := repo.PurgeDeadDeliveries("a", []int64{purged.ID, other.ID, pending.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), affected, "only dead deliveries of the account are purged")
+
+		count, err := repo.CountDeadDeliveries("a")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		affected, err = repo.PurgeDeadDeliveries("a", nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), affected)
+
+		count, err = repo.CountDeadDeliveries("a")
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		count, err = repo.CountDeadDeliveries("b")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count, "another account keeps its dead letters")
+
+		deliveries, err := repo.ClaimDueDeliveries(time.Now().Add(time.Second), time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1, "pending deliveries aren't purged")
+		assert.Equal(t, pending.ID, deliveries[0].ID)
+	})
+}
@@ -0,0 +1,311 @@
+package webhook
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/sirupsen/logrus"
+)
+
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteRepository(db *sql.DB) domainWebhook.IWebhookRepository {
+	repo := &SQLiteRepository{db: db}
+	repo.initTables()
+	return repo
+}
+
+func (r *SQLiteRepository) initTables() {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS webhook_outbox (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id TEXT NOT NULL DEFAULT '',
+			event TEXT NOT NULL DEFAULT '',
+			url TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			signature TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt_at DATETIME NOT NULL,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS webhook_endpoints (
+			id TEXT PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			url TEXT NOT NULL,
+			secret TEXT NOT NULL DEFAULT '',
+			events TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_account ON webhook_endpoints(account_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_outbox_due ON webhook_outbox(status, next_attempt_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_outbox_account ON webhook_outbox(account_id, status)`,
+	}
+
+	for _, query := range queries {
+		if _, err := r.db.Exec(query); err != nil {
+			logrus.Errorf("Failed to create table: %v", err)
+		}
+	}
+}
+
+func (r *SQLiteRepository) CreateEndpoint(endpoint *domainWebhook.Endpoint) error {
+	now := time.Now().UTC()
+	endpoint.CreatedAt = now
+	endpoint.UpdatedAt = now
+
+	query := `INSERT INTO webhook_endpoints (id, account_id, url, secret, events, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.Exec(query, endpoint.ID, endpoint.AccountID, endpoint.URL, endpoint.Secret,
+		strings.Join(endpoint.Events, ","), endpoint.CreatedAt, endpoint.UpdatedAt)
+	return err
+}
+
+func (r *SQLiteRepository) GetEndpoint(accountID, endpointID string) (*domainWebhook.Endpoint, error) {
+	query := `SELECT id, account_id, url, secret, events, created_at, updated_at
+			  FROM webhook_endpoints WHERE account_id = ? AND id = ?`
+
+	endpoint, err := scanEndpoint(r.db.QueryRow(query, accountID, endpointID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return endpoint, err
+}
+
+func (r *SQLiteRepository) ListEndpoints(accountID string) ([]*domainWebhook.Endpoint, error) {
+	query := `SELECT id, account_id, url, secret, events, created_at, updated_at
+			  FROM webhook_endpoints WHERE account_id = ? ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.Query(query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var endpoints []*domainWebhook.Endpoint
+	for rows.Next() {
+		endpoint, err := scanEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, rows.Err()
+}
+
+func (r *SQLiteRepository) UpdateEndpoint(endpoint *domainWebhook.Endpoint) error {
+	endpoint.UpdatedAt = time.Now().UTC()
+
+	query := `UPDATE webhook_endpoints SET url = ?, secret = ?, events = ?, updated_at = ?
+			  WHERE account_id = ? AND id = ?`
+
+	_, err := r.db.Exec(query, endpoint.URL, endpoint.Secret, strings.Join(endpoint.Events, ","),
+		endpoint.UpdatedAt, endpoint.AccountID, endpoint.ID)
+	return err
+}
+
+func (r *SQLiteRepository) DeleteEndpoint(accountID, endpointID string) error {
+	_, err := r.db.Exec(`DELETE FROM webhook_endpoints WHERE account_id = ? AND id = ?`, accountID, endpointID)
+	return err
+}
+
+func (r *SQLiteRepository) EnqueueDelivery(delivery *domainWebhook.Delivery) error {
+	now := time.Now().UTC()
+	if delivery.Status == "" {
+		delivery.Status = domainWebhook.DeliveryStatusPending
+	}
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = now
+	}
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	query := `INSERT INTO webhook_outbox (account_id, event, url, payload, signature, status, attempts, last_error, next_attempt_at, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.Exec(query, delivery.AccountID, delivery.Event, delivery.URL, delivery.Payload, delivery.Signature,
+		delivery.Status, delivery.Attempts, delivery.LastError, delivery.NextAttemptAt, delivery.CreatedAt, delivery.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	delivery.ID, err = result.LastInsertId()
+	return err
+}
+
+// ClaimDueDeliveries returns pending deliveries that are due and pushes their next attempt
+// forward by the lease, so a crash mid-delivery only results in a later retry.
+func (r *SQLiteRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]*domainWebhook.Delivery, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT id, account_id, event, url, payload, signature, status, attempts, last_error, next_attempt_at, created_at, updated_at
+			  FROM webhook_outbox
+			  WHERE status = ? AND next_attempt_at <= ?
+			  ORDER BY next_attempt_at ASC, id ASC
+			  LIMIT ?`
+
+	rows, err := tx.Query(query, domainWebhook.DeliveryStatusPending, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []*domainWebhook.Delivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	leaseUntil := now.Add(lease).UTC()
+	for _, delivery := range deliveries {
+		if _, err := tx.Exec(`UPDATE webhook_outbox SET next_attempt_at = ? WHERE id = ?`, leaseUntil, delivery.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return deliveries, tx.Commit()
+}
+
+func (r *SQLiteRepository) DeleteDelivery(id int64) error {
+	_, err := r.db.Exec(`DELETE FROM webhook_outbox WHERE id = ?`, id)
+	return err
+}
+
+func (r *SQLiteRepository) ScheduleRetry(id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	query := `UPDATE webhook_outbox SET attempts = ?, next_attempt_at = ?, last_error = ?, updated_at = ? WHERE id = ?`
+	_, err := r.db.Exec(query, attempts, nextAttemptAt.UTC(), lastError, time.Now().UTC(), id)
+	return err
+}
+
+func (r *SQLiteRepository) MarkDead(id int64, attempts int, lastError string) error {
+	query := `UPDATE webhook_outbox SET status = ?, attempts = ?, last_error = ?, updated_at = ? WHERE id = ?`
+	_, err := r.db.Exec(query, domainWebhook.DeliveryStatusDead, attempts, lastError, time.Now().UTC(), id)
+	return err
+}
+
+func (r *SQLiteRepository) ListDeadDeliveries(accountID string, limit, offset int) ([]*domainWebhook.Delivery, error) {
+	query := `SELECT id, account_id, event, url, payload, signature, status, attempts, last_error, next_attempt_at, created_at, updated_at
+			  FROM webhook_outbox
+			  WHERE account_id = ? AND status = ?
+			  ORDER BY updated_at DESC, id DESC
+			  LIMIT ? OFFSET ?`
+
+	rows, err := r.db.Query(query, accountID, domainWebhook.DeliveryStatusDead, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domainWebhook.Delivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *SQLiteRepository) CountDeadDeliveries(accountID string) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM webhook_outbox WHERE account_id = ? AND status = ?`
+	err := r.db.QueryRow(query, accountID, domainWebhook.DeliveryStatusDead).Scan(&count)
+	return count, err
+}
+
+// ReplayDeadDeliveries moves dead deliveries back to pending with a fresh attempt budget.
+// created_at is reset as well, otherwise the max age would dead-letter them again right away.
+func (r *SQLiteRepository) ReplayDeadDeliveries(accountID string, deliveryIDs []int64) (int64, error) {
+	now := time.Now().UTC()
+	query := `UPDATE webhook_outbox
+			  SET status = ?, attempts = 0, last_error = '', next_attempt_at = ?, created_at = ?, updated_at = ?
+			  WHERE account_id = ? AND status = ?`
+	args := []any{domainWebhook.DeliveryStatusPending, now, now, now, accountID, domainWebhook.DeliveryStatusDead}
+
+	query, args = appendIDFilter(query, args, deliveryIDs)
+	return r.execAffected(query, args...)
+}
+
+func (r *SQLiteRepository) PurgeDeadDeliveries(accountID string, deliveryIDs []int64) (int64, error) {
+	query := `DELETE FROM webhook_outbox WHERE account_id = ? AND status = ?`
+	args := []any{accountID, domainWebhook.DeliveryStatusDead}
+
+	query, args = appendIDFilter(query, args, deliveryIDs)
+	return r.execAffected(query, args...)
+}
+
+func (r *SQLiteRepository) execAffected(query string, args ...any) (int64, error) {
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanEndpoint(scanner interface{ Scan(...any) error }) (*domainWebhook.Endpoint, error) {
+	endpoint := &domainWebhook.Endpoint{}
+	var events string
+	err := scanner.Scan(
+		&endpoint.ID, &endpoint.AccountID, &endpoint.URL, &endpoint.Secret,
+		&events, &endpoint.CreatedAt, &endpoint.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.Events = []string{}
+	if events != "" {
+		endpoint.Events = strings.Split(events, ",")
+	}
+	return endpoint, nil
+}
+
+func scanDelivery(scanner interface{ Scan(...any) error }) (*domainWebhook.Delivery, error) {
+	delivery := &domainWebhook.Delivery{}
+	err := scanner.Scan(
+		&delivery.ID, &delivery.AccountID, &delivery.Event, &delivery.URL, &delivery.Payload, &delivery.Signature,
+		&delivery.Status, &delivery.Attempts, &delivery.LastError,
+		&delivery.NextAttemptAt, &delivery.CreatedAt, &delivery.UpdatedAt,
+	)
+	return delivery, err
+}
+
+// appendIDFilter restricts a query to the given delivery IDs, if any
+func appendIDFilter(query string, args []any, deliveryIDs []int64) (string, []any) {
+	if len(deliveryIDs) == 0 {
+		return query, args
+	}
+
+	placeholders := make([]string, len(deliveryIDs))
+	for i, id := range deliveryIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	return fmt.Sprintf("%s AND id IN (%s)", query, strings.Join(placeholders, ", ")), args
+}
@@ -0,0 +1,48 @@
+package whatsapp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	"go.mau.fi/whatsmeow"
+)
+
+// ReleaseSession disconnects the client of a managed account without logging it out. Its supervisor
+// stops reconnecting and marks the account released, so the session can be restored on another
+// instance. Reconnecting the account takes the session back.
+func ReleaseSession(client *whatsmeow.Client) {
+	if supervisor, ok := supervisors.Load(client); ok {
+		supervisor.(*connectionSupervisor).notify(domainAccount.ConnectionReleased, "session released for a backup")
+	}
+	client.Disconnect()
+}
+
+// StoreFilePath returns the file of a SQLite store URI, other databases can't be copied as a file
+func StoreFilePath(uri string) (string, error) {
+	path, ok := strings.CutPrefix(uri, "file:")
+	if !ok {
+		return "", errors.New("only SQLite stores (file: URIs) can be backed up and restored")
+	}
+	path, _, _ = strings.Cut(path, "?")
+	return path, nil
+}
+
+// SnapshotStore copies the SQLite store at uri into dest. VACUUM INTO gives a consistent copy
+// while the client keeps writing to the store.
+func SnapshotStore(ctx context.Context, uri string, dest string) error {
+	if _, err := StoreFilePath(uri); err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite3", uri)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, "VACUUM INTO ?", dest)
+	return err
+}
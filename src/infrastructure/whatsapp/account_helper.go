@@ -1,8 +1,14 @@
 package whatsapp
 
 import (
+	"context"
+	"fmt"
+
 	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainApiKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
 	infraAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/account"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
 	"go.mau.fi/whatsmeow"
 )
 
@@ -23,6 +29,38 @@ func GetAccountIDFromClient(client *whatsmeow.Client) string {
 	return ""
 }
 
+// AuthorizeAccount checks the API key the request authenticated with, if any, allows the account.
+// The legacy client (an empty account ID) is only reachable by keys allowed on every account.
+func AuthorizeAccount(ctx context.Context, accountID string) error {
+	key := domainApiKey.FromContext(ctx)
+	if key == nil || key.AllowsAccount(accountID) {
+		return nil
+	}
+	if accountID == "" {
+		return pkgError.ForbiddenError("account_id is required for this API key")
+	}
+	return pkgError.ForbiddenError(fmt.Sprintf("API key is not allowed to access account %s", accountID))
+}
+
+// GetClientForAccount resolves the WhatsApp client for the given account through the account manager.
+// An empty account ID falls back to the legacy global client to keep single-device requests working.
+// The account has to be allowed by the API key of the request, see AuthorizeAccount.
+func GetClientForAccount(ctx context.Context, accountID string) (*whatsmeow.Client, error) {
+	if err := AuthorizeAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	if accountID == "" {
+		return GetClient(), nil
+	}
+
+	client := infraAccount.GlobalAccountManager.GetClient(accountID)
+	if client == nil {
+		return nil, pkgError.NotFoundError("Account not found or not connected")
+	}
+	return client, nil
+}
+
 // GetAccountRepoFromGlobalVars gets account repository from global variables
 // This is a temporary solution until we refactor to dependency injection
 func GetAccountRepoFromGlobalVars() domainAccount.IAccountRepository {
@@ -36,4 +74,16 @@ var globalAccountRepo domainAccount.IAccountRepository
 // SetGlobalAccountRepo sets the global account repository
 func SetGlobalAccountRepo(repo domainAccount.IAccountRepository) {
 	globalAccountRepo = repo
-}
\ No newline at end of file
+}
+
+// ConnectionStates reports the connection state of the legacy global client and every managed account
+func ConnectionStates() []metrics.ConnectionState {
+	var states []metrics.ConnectionState
+	if client := GetClient(); client != nil {
+		states = append(states, metrics.ConnectionState{Connected: client.IsConnected(), LoggedIn: client.IsLoggedIn()})
+	}
+	for accountID, client := range infraAccount.GlobalAccountManager.ListClients() {
+		states = append(states, metrics.ConnectionState{AccountID: accountID, Connected: client.IsConnected(), LoggedIn: client.IsLoggedIn()})
+	}
+	return states
+}
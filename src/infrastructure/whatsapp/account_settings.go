@@ -0,0 +1,90 @@
+package whatsapp
+
+import (
+	"fmt"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waCompanionReg"
+	"go.mau.fi/whatsmeow/proto/waWa6"
+	"go.mau.fi/whatsmeow/store"
+	"google.golang.org/protobuf/proto"
+)
+
+// EffectiveSettings merges the overrides of an account over the global configuration
+func EffectiveSettings(overrides *domainAccount.Settings) domainAccount.EffectiveSettings {
+	effective := domainAccount.EffectiveSettings{
+		AutoMarkRead:     config.WhatsappAutoMarkRead,
+		AutoReplyMessage: config.WhatsappAutoReplyMessage,
+		DeviceName:       fmt.Sprintf("%s %s", config.AppOs, config.AppVersion),
+		MaxImageSize:     config.WhatsappSettingMaxImageSize,
+		MaxFileSize:      config.WhatsappSettingMaxFileSize,
+		MaxVideoSize:     config.WhatsappSettingMaxVideoSize,
+		WebhookSecret:    config.WhatsappWebhookSecret,
+	}
+	if overrides == nil {
+		return effective
+	}
+
+	if overrides.AutoMarkRead != nil {
+		effective.AutoMarkRead = *overrides.AutoMarkRead
+	}
+	if overrides.AutoReplyMessage != nil {
+		effective.AutoReplyMessage = *overrides.AutoReplyMessage
+	}
+	if overrides.DeviceName != nil {
+		effective.DeviceName = *overrides.DeviceName
+	}
+	if overrides.MaxImageSize != nil {
+		effective.MaxImageSize = *overrides.MaxImageSize
+	}
+	if overrides.MaxFileSize != nil {
+		effective.MaxFileSize = *overrides.MaxFileSize
+	}
+	if overrides.MaxVideoSize != nil {
+		effective.MaxVideoSize = *overrides.MaxVideoSize
+	}
+	if overrides.DisappearingDuration != nil {
+		effective.DisappearingDuration = *overrides.DisappearingDuration
+	}
+	if overrides.WebhookSecret != nil {
+		effective.WebhookSecret = *overrides.WebhookSecret
+	}
+	return effective
+}
+
+// GetAccountSettings returns the settings in use for the account. The legacy client (empty account),
+// accounts without overrides and accounts whose overrides can't be loaded use the global configuration.
+func GetAccountSettings(accountID string) domainAccount.EffectiveSettings {
+	accountRepo := GetAccountRepoFromGlobalVars()
+	if accountID == "" || accountRepo == nil {
+		return EffectiveSettings(nil)
+	}
+
+	overrides, err := accountRepo.GetSettings(accountID)
+	if err != nil {
+		logrus.Errorf("Failed to load settings of account %s, using the global settings: %v", accountID, err)
+		return EffectiveSettings(nil)
+	}
+	return EffectiveSettings(overrides)
+}
+
+// accountClientPayload returns the payload the client of a managed account connects with. The device
+// properties are process-wide in whatsmeow, so the device name of the account is swapped in here and
+// shows up in the linked devices of the phone once the account pairs.
+func accountClientPayload(device *store.Device, accountID string) func() *waWa6.ClientPayload {
+	return func() *waWa6.ClientPayload {
+		payload := device.GetClientPayload()
+		if payload.GetDevicePairingData() == nil {
+			return payload
+		}
+
+		props := proto.Clone(store.DeviceProps).(*waCompanionReg.DeviceProps)
+		props.Os = proto.String(GetAccountSettings(accountID).DeviceName)
+		if encoded, err := proto.Marshal(props); err == nil {
+			payload.DevicePairingData.DeviceProps = encoded
+		}
+		return payload
+	}
+}
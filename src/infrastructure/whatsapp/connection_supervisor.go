@@ -0,0 +1,212 @@
+package whatsapp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	infraAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/account"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+)
+
+// supervisorHealthInterval is how often the supervisor checks that a paired client is still connected
+const supervisorHealthInterval = time.Minute
+
+// supervisors maps the clients of managed accounts to their supervisor
+var supervisors sync.Map
+
+// connectionSupervisor owns the connection of a managed account. It records every state transition
+// in the connection history, keeps the account status up to date and reconnects dropped connections
+// with a jittered exponential backoff. A session replaced elsewhere, logged out or released for a
+// backup is left alone until the account connects again. The supervisor stops when its client is
+// removed from the account manager or replaced there.
+type connectionSupervisor struct {
+	client      *whatsmeow.Client
+	accountID   string
+	transitions chan connectionTransition
+}
+
+type connectionTransition struct {
+	state  string
+	reason string
+}
+
+// newConnectionSupervisor starts the supervisor of the account client. Auto reconnect of whatsmeow is
+// turned off, reconnecting is done here so every attempt shows up in the history.
+func newConnectionSupervisor(client *whatsmeow.Client, accountID string) *connectionSupervisor {
+	client.EnableAutoReconnect = false
+
+	s := &connectionSupervisor{
+		client:      client,
+		accountID:   accountID,
+		transitions: make(chan connectionTransition, 32),
+	}
+	supervisors.Store(client, s)
+	go s.run()
+	return s
+}
+
+// notify hands a transition to the supervisor without blocking the event handler
+func (s *connectionSupervisor) notify(state string, reason string) {
+	select {
+	case s.transitions <- connectionTransition{state: state, reason: reason}:
+	default:
+		logrus.Warnf("[SUPERVISOR] Dropped %s transition of account %s, supervisor is busy", state, s.accountID)
+	}
+}
+
+func (s *connectionSupervisor) run() {
+	defer supervisors.Delete(s.client)
+	defer infraAccount.GlobalAccountManager.Forget(s.client)
+
+	var (
+		attempt int
+		halted  = s.released() // the session was replaced, logged out or released, don't reconnect on our own
+		retry   *time.Timer
+		retryC  <-chan time.Time
+		stopped = infraAccount.GlobalAccountManager.Stopped(s.client)
+	)
+
+	schedule := func() {
+		delay := utils.Backoff(attempt+1, config.ReconnectMinBackoff, config.ReconnectMaxBackoff)
+		if retry != nil {
+			retry.Stop()
+		}
+		retry = time.NewTimer(delay)
+		retryC = retry.C
+		logrus.Infof("[SUPERVISOR] Reconnecting account %s in %s", s.accountID, delay.Round(time.Millisecond))
+	}
+	cancel := func() {
+		if retry != nil {
+			retry.Stop()
+		}
+		retry, retryC = nil, nil
+	}
+
+	health := time.NewTicker(supervisorHealthInterval)
+	defer health.Stop()
+	defer cancel()
+
+	for {
+		select {
+		case <-stopped:
+			// The account was deleted or its client replaced, e.g. by a restore
+			logrus.Infof("[SUPERVISOR] Account %s client was removed, stopping supervisor", s.accountID)
+			return
+
+		case transition := <-s.transitions:
+			s.record(transition.state, transition.reason, 0)
+
+			switch transition.state {
+			case domainAccount.ConnectionConnected:
+				attempt, halted = 0, false
+				cancel()
+				s.updateAccount(domainAccount.StatusConnected)
+			case domainAccount.ConnectionDisconnected:
+				s.updateAccount(domainAccount.StatusDisconnected)
+				if !halted {
+					schedule()
+				}
+			case domainAccount.ConnectionStreamReplaced, domainAccount.ConnectionLoggedOut:
+				halted = true
+				cancel()
+				s.updateAccount(domainAccount.StatusDisconnected)
+			case domainAccount.ConnectionReleased:
+				halted = true
+				cancel()
+				s.updateAccount(domainAccount.StatusReleased)
+			}
+
+		case <-retryC:
+			retry, retryC = nil, nil
+			// A client that isn't registered (yet) for the account must not connect, it would run
+			// next to the registered client of the same device
+			if halted || s.client.IsConnected() || !infraAccount.GlobalAccountManager.IsRegistered(s.accountID, s.client) {
+				continue
+			}
+
+			attempt++
+			s.record(domainAccount.ConnectionReconnecting, "", attempt)
+			if err := s.client.Connect(); err != nil {
+				logrus.Warnf("[SUPERVISOR] Reconnect attempt %d of account %s failed: %v", attempt, s.accountID, err)
+				s.record(domainAccount.ConnectionReconnectFailed, err.Error(), attempt)
+				schedule()
+			}
+
+		case <-health.C:
+			registered := infraAccount.GlobalAccountManager.IsRegistered(s.accountID, s.client)
+			paired := s.client.Store != nil && s.client.Store.ID != nil
+			if registered && paired && !halted && retryC == nil && !s.client.IsConnected() {
+				schedule()
+			}
+		}
+	}
+}
+
+// released reports whether the session of the account was handed to another instance, a restarted
+// instance keeps it disconnected until the account is reconnected
+func (s *connectionSupervisor) released() bool {
+	accountRepo := GetAccountRepoFromGlobalVars()
+	if accountRepo == nil {
+		return false
+	}
+
+	account, err := accountRepo.GetAccount(s.accountID)
+	return err == nil && account != nil && account.Status == domainAccount.StatusReleased
+}
+
+// record appends a transition to the connection history of the account
+func (s *connectionSupervisor) record(state string, reason string, attempt int) {
+	accountRepo := GetAccountRepoFromGlobalVars()
+	if accountRepo == nil {
+		return
+	}
+
+	event := &domainAccount.ConnectionEvent{
+		AccountID: s.accountID,
+		State:     state,
+		Reason:    reason,
+		Attempt:   attempt,
+	}
+	if err := accountRepo.AddConnectionEvent(event); err != nil {
+		logrus.Errorf("[SUPERVISOR] Failed to record %s of account %s: %v", state, s.accountID, err)
+		return
+	}
+
+	if config.ConnectionHistory > 0 {
+		if err := accountRepo.PruneConnectionEvents(s.accountID, config.ConnectionHistory); err != nil {
+			logrus.Errorf("[SUPERVISOR] Failed to prune connection history of account %s: %v", s.accountID, err)
+		}
+	}
+}
+
+// updateAccount stores the status of the account, along with the device and last connection time once connected
+func (s *connectionSupervisor) updateAccount(status string) {
+	accountRepo := GetAccountRepoFromGlobalVars()
+	if accountRepo == nil {
+		return
+	}
+
+	account, err := accountRepo.GetAccount(s.accountID)
+	if err != nil || account == nil {
+		if err != nil {
+			logrus.Errorf("[SUPERVISOR] Failed to load account %s: %v", s.accountID, err)
+		}
+		return
+	}
+
+	account.Status = status
+	if status == domainAccount.StatusConnected {
+		account.LastConnected = time.Now()
+		if s.client.Store != nil && s.client.Store.ID != nil {
+			account.DeviceID = s.client.Store.ID.String()
+			account.PhoneNumber = s.client.Store.ID.User
+		}
+	}
+	if err := accountRepo.UpdateAccount(account); err != nil {
+		logrus.Errorf("[SUPERVISOR] Failed to update account %s: %v", s.accountID, err)
+	}
+}
@@ -0,0 +1,70 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainEvent "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/event"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// lifecycleWebhookExitTimeout bounds how long the legacy client waits for its stream_replaced webhook before exiting
+const lifecycleWebhookExitTimeout = 10 * time.Second
+
+// accountLifecycleEvents maps the connection states to the webhook events of the account lifecycle
+var accountLifecycleEvents = map[string]string{
+	domainEvent.ConnectionConnected:      domainWebhook.EventAccountConnected,
+	domainEvent.ConnectionDisconnected:   domainWebhook.EventAccountDisconnected,
+	domainEvent.ConnectionLoggedOut:      domainWebhook.EventAccountLoggedOut,
+	domainEvent.ConnectionStreamReplaced: domainWebhook.EventAccountStreamReplaced,
+	domainEvent.ConnectionPaired:         domainWebhook.EventAccountPaired,
+}
+
+// createConnectionPayload creates a payload for connection state changes of a client. The reason
+// and its code are only set when the server gave one (e.g. on a logout).
+func createConnectionPayload(accountID string, device types.JID, event string, state string, reason string, reasonCode int) domainWebhook.EventBody[domainWebhook.ConnectionData] {
+	payload := domainWebhook.ConnectionData{
+		State:      state,
+		AccountID:  accountID,
+		Reason:     reason,
+		ReasonCode: reasonCode,
+	}
+	if !device.IsEmpty() {
+		payload.DeviceID = device.String()
+		payload.Phone = device.User
+	}
+
+	return domainWebhook.EventBody[domainWebhook.ConnectionData]{
+		PayloadInfo: newPayloadInfo(),
+		Event:       event,
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Payload:     payload,
+	}
+}
+
+// forwardAccountEventToWebhooks delivers an account lifecycle event to the webhook of the account,
+// its endpoints subscribed to the event and the global webhooks
+func forwardAccountEventToWebhooks(ctx context.Context, accountID string, event string, payload domainWebhook.Payload) {
+	if accountID != "" {
+		if accountRepo := GetAccountRepoFromGlobalVars(); accountRepo != nil {
+			if err := submitWebhookForAccount(ctx, event, payload, accountID, accountRepo); err != nil {
+				logrus.Errorf("Failed to forward %s event to account webhook: %v", event, err)
+			}
+		}
+	}
+
+	if endpoints := getSubscribedEndpoints(accountID, event); len(endpoints) > 0 {
+		if err := submitWebhookToEndpoints(ctx, accountID, event, endpoints, payload); err != nil {
+			logrus.Errorf("Failed to forward %s event to account webhook endpoints: %v", event, err)
+		}
+	}
+
+	for _, url := range config.WhatsappWebhook {
+		if err := submitWebhook(ctx, accountID, event, payload, url); err != nil {
+			logrus.Errorf("Failed to forward %s event to webhook: %v", event, err)
+		}
+	}
+}
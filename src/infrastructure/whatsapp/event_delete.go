@@ -6,20 +6,16 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
 // forwardDeleteToWebhook sends a delete event to webhook
-func forwardDeleteToWebhook(ctx context.Context, evt *events.DeleteForMe, message *domainChatStorage.Message) error {
+func forwardDeleteToWebhook(ctx context.Context, accountID string, payload domainWebhook.DeletePayload) error {
 	logrus.Infof("Forwarding delete event to %d configured webhook(s)", len(config.WhatsappWebhook))
-	payload, err := createDeletePayload(ctx, evt, message)
-	if err != nil {
-		return err
-	}
-
 	for _, url := range config.WhatsappWebhook {
-		if err = submitWebhook(ctx, payload, url); err != nil {
+		if err := submitWebhook(ctx, accountID, domainWebhook.EventDelete, payload, url); err != nil {
 			return err
 		}
 	}
@@ -29,33 +25,34 @@ func forwardDeleteToWebhook(ctx context.Context, evt *events.DeleteForMe, messag
 }
 
 // createDeletePayload creates a webhook payload for delete events
-func createDeletePayload(_ context.Context, evt *events.DeleteForMe, message *domainChatStorage.Message) (map[string]any, error) {
-	body := make(map[string]any)
-
+func createDeletePayload(evt *events.DeleteForMe, message *domainChatStorage.Message) domainWebhook.DeletePayload {
 	// Basic delete event information
-	body["action"] = "event.delete_for_me"
-	body["deleted_message_id"] = evt.MessageID
-	body["sender_id"] = evt.SenderJID.User
-	body["timestamp"] = time.Now().Format(time.RFC3339)
+	body := domainWebhook.DeletePayload{
+		PayloadInfo:      newPayloadInfo(),
+		Action:           "event.delete_for_me",
+		DeletedMessageID: evt.MessageID,
+		SenderID:         evt.SenderJID.User,
+		Timestamp:        time.Now().Format(time.RFC3339),
+	}
 
 	// Include original message information if available
 	if message != nil {
-		body["chat_id"] = message.ChatJID
-		body["original_content"] = message.Content
-		body["original_sender"] = message.Sender
-		body["original_timestamp"] = message.Timestamp.Format(time.RFC3339)
-		body["was_from_me"] = message.IsFromMe
+		body.ChatID = message.ChatJID
+		body.OriginalContent = message.Content
+		body.OriginalSender = message.Sender
+		body.OriginalTimestamp = message.Timestamp.Format(time.RFC3339)
+		body.WasFromMe = &message.IsFromMe
 
 		if message.MediaType != "" {
-			body["original_media_type"] = message.MediaType
-			body["original_filename"] = message.Filename
+			body.OriginalMediaType = message.MediaType
+			body.OriginalFilename = message.Filename
 		}
 	}
 
 	// Parse sender JID for proper formatting
 	if evt.SenderJID.Server != "" {
-		body["from"] = evt.SenderJID.String()
+		body.From = evt.SenderJID.String()
 	}
 
-	return body, nil
+	return body
 }
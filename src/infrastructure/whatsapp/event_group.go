@@ -7,33 +7,24 @@ import (
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
 // createGroupInfoPayload creates a webhook payload for group information events
-func createGroupInfoPayload(evt *events.GroupInfo, actionType string, jids []types.JID) map[string]any {
-	body := make(map[string]any)
-
-	// Create payload structure matching the expected format
-	payload := make(map[string]any)
-
-	// Add group chat ID
-	payload["chat_id"] = evt.JID.String()
-
-	// Add action type and affected users
-	payload["type"] = actionType
-	payload["jids"] = jidsToStrings(jids)
-
-	// Wrap in payload structure
-	body["payload"] = payload
-
-	// Add metadata for webhook processing
-	body["event"] = "group.participants"
-	body["timestamp"] = evt.Timestamp.Format(time.RFC3339)
-
-	return body
+func createGroupInfoPayload(evt *events.GroupInfo, actionType string, jids []types.JID) domainWebhook.EventBody[domainWebhook.GroupParticipantsData] {
+	return domainWebhook.EventBody[domainWebhook.GroupParticipantsData]{
+		PayloadInfo: newPayloadInfo(),
+		Event:       domainWebhook.EventGroupParticipants,
+		Timestamp:   evt.Timestamp.Format(time.RFC3339),
+		Payload: domainWebhook.GroupParticipantsData{
+			ChatID: evt.JID.String(),
+			Type:   actionType,
+			JIDs:   jidsToStrings(jids),
+		},
+	}
 }
 
 // jidsToStrings converts a slice of JIDs to a slice of strings
@@ -49,49 +40,80 @@ func jidsToStrings(jids []types.JID) []string {
 	return result
 }
 
-// forwardGroupInfoToWebhook forwards group information events to the configured webhook URLs
-func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo) error {
-	logrus.Infof("Forwarding group info event to %d configured webhook(s)", len(config.WhatsappWebhook))
+// groupParticipantAction is a single participant change carried by a group info event
+type groupParticipantAction struct {
+	actionType string
+	jids       []types.JID
+}
 
-	// Send separate webhook events for each action type
-	actions := []struct {
-		actionType string
-		jids       []types.JID
-	}{
+func groupInfoActions(evt *events.GroupInfo) []groupParticipantAction {
+	return []groupParticipantAction{
 		{"join", evt.Join},
 		{"leave", evt.Leave},
 		{"promote", evt.Promote},
 		{"demote", evt.Demote},
 	}
+}
 
-	for _, action := range actions {
+// groupParticipantsPayload is the payload of one participant change of a group info event
+type groupParticipantsPayload struct {
+	action  groupParticipantAction
+	payload domainWebhook.EventBody[domainWebhook.GroupParticipantsData]
+}
+
+// createGroupInfoPayloads creates a payload for every participant change of a group info event,
+// so each change is delivered as a separate event with its own delivery ID
+func createGroupInfoPayloads(evt *events.GroupInfo) []groupParticipantsPayload {
+	var payloads []groupParticipantsPayload
+	for _, action := range groupInfoActions(evt) {
 		if len(action.jids) > 0 {
-			payload := createGroupInfoPayload(evt, action.actionType, action.jids)
-
-			// Collect errors from all webhook URLs instead of failing fast
-			var errors []error
-			for _, url := range config.WhatsappWebhook {
-				if err := submitWebhook(ctx, payload, url); err != nil {
-					errors = append(errors, fmt.Errorf("webhook %s failed: %w", url, err))
-				}
-			}
+			payloads = append(payloads, groupParticipantsPayload{action, createGroupInfoPayload(evt, action.actionType, action.jids)})
+		}
+	}
+	return payloads
+}
+
+// forwardGroupInfoToEndpoints forwards group participant changes to the given account webhook endpoints
+func forwardGroupInfoToEndpoints(ctx context.Context, accountID string, endpoints []*domainWebhook.Endpoint, payloads []groupParticipantsPayload) error {
+	for _, p := range payloads {
+		if err := submitWebhookToEndpoints(ctx, accountID, domainWebhook.EventGroupParticipants, endpoints, p.payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			// If all webhooks failed, return combined error
-			if len(errors) == len(config.WhatsappWebhook) && len(errors) > 0 {
-				var errMessages []string
-				for _, err := range errors {
-					errMessages = append(errMessages, err.Error())
-				}
-				return fmt.Errorf("all webhook URLs failed: %s", strings.Join(errMessages, "; "))
+// forwardGroupInfoToWebhook forwards group information events to the configured webhook URLs
+func forwardGroupInfoToWebhook(ctx context.Context, accountID string, payloads []groupParticipantsPayload) error {
+	logrus.Infof("Forwarding group info event to %d configured webhook(s)", len(config.WhatsappWebhook))
+
+	// Send separate webhook events for each action type
+	for _, p := range payloads {
+		action := p.action
+
+		// Collect errors from all webhook URLs instead of failing fast
+		var errors []error
+		for _, url := range config.WhatsappWebhook {
+			if err := submitWebhook(ctx, accountID, domainWebhook.EventGroupParticipants, p.payload, url); err != nil {
+				errors = append(errors, fmt.Errorf("webhook %s failed: %w", url, err))
 			}
+		}
 
-			// Log partial failures
-			if len(errors) > 0 {
-				logrus.Warnf("Some webhook URLs failed for group %s event: %v", action.actionType, errors)
+		// If all webhooks failed, return combined error
+		if len(errors) == len(config.WhatsappWebhook) && len(errors) > 0 {
+			var errMessages []string
+			for _, err := range errors {
+				errMessages = append(errMessages, err.Error())
 			}
+			return fmt.Errorf("all webhook URLs failed: %s", strings.Join(errMessages, "; "))
+		}
 
-			logrus.Infof("Group %s event forwarded to webhook: %d users %s", action.actionType, len(action.jids), action.actionType)
+		// Log partial failures
+		if len(errors) > 0 {
+			logrus.Warnf("Some webhook URLs failed for group %s event: %v", action.actionType, errors)
 		}
+
+		logrus.Infof("Group %s event forwarded to webhook: %d users %s", action.actionType, len(action.jids), action.actionType)
 	}
 
 	return nil
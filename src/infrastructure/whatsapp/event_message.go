@@ -10,22 +10,21 @@ import (
 	"go.mau.fi/whatsmeow/types"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
 )
 
 // forwardMessageToWebhook is a helper function to forward message event to webhook url
-func forwardMessageToWebhook(ctx context.Context, evt *events.Message) error {
+func forwardMessageToWebhook(ctx context.Context, accountID string, event string, payload domainWebhook.MessagePayload) error {
 	logrus.Infof("Forwarding message event to %d configured webhook(s)", len(config.WhatsappWebhook))
-	payload, err := createMessagePayload(ctx, evt)
-	if err != nil {
-		return err
-	}
-
 	for _, url := range config.WhatsappWebhook {
-		if err = submitWebhook(ctx, payload, url); err != nil {
+		if err := submitWebhook(ctx, accountID, event, payload, url); err != nil {
 			return err
 		}
 	}
@@ -34,18 +33,35 @@ func forwardMessageToWebhook(ctx context.Context, evt *events.Message) error {
 	return nil
 }
 
-func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]any, error) {
+// messageWebhookEvent returns the webhook event type a message event is delivered as
+func messageWebhookEvent(evt *events.Message) string {
+	if protocolMessage := evt.Message.GetProtocolMessage(); protocolMessage != nil {
+		switch protocolMessage.GetType() {
+		case waE2E.ProtocolMessage_REVOKE:
+			return domainWebhook.EventRevoke
+		case waE2E.ProtocolMessage_MESSAGE_EDIT:
+			return domainWebhook.EventEdit
+		}
+	}
+	return domainWebhook.EventMessage
+}
+
+func createMessagePayload(ctx context.Context, client *whatsmeow.Client, evt *events.Message) (domainWebhook.MessagePayload, error) {
 	message := utils.BuildEventMessage(evt)
 	waReaction := utils.BuildEventReaction(evt)
-	forwarded := utils.BuildForwarded(evt)
-
-	body := make(map[string]any)
 
-	body["sender_id"] = evt.Info.Sender.User
-	body["chat_id"] = evt.Info.Chat.User
+	body := domainWebhook.MessagePayload{
+		PayloadInfo: newPayloadInfo(),
+		SenderID:    evt.Info.Sender.User,
+		ChatID:      evt.Info.Chat.User,
+		PushName:    evt.Info.PushName,
+		ViewOnce:    evt.IsViewOnce,
+		Forwarded:   utils.BuildForwarded(evt),
+		Timestamp:   evt.Info.Timestamp.Format(time.RFC3339),
+	}
 
 	if from := evt.Info.SourceString(); from != "" {
-		body["from"] = from
+		body.From = from
 
 		from_user, from_group := from, ""
 		if strings.Contains(from, " in ") {
@@ -54,20 +70,20 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 		}
 
 		if strings.HasSuffix(from_user, "@lid") {
-			body["from_lid"] = from_user
+			body.FromLID = from_user
 			lid, err := types.ParseJID(from_user)
 			if err != nil {
 				logrus.Errorf("Error when parse jid: %v", err)
 			} else {
-				pn, err := cli.Store.LIDs.GetPNForLID(ctx, lid)
+				pn, err := client.Store.LIDs.GetPNForLID(ctx, lid)
 				if err != nil {
 					logrus.Errorf("Error when get pn for lid %s: %v", lid.String(), err)
 				}
 				if !pn.IsEmpty() {
 					if from_group != "" {
-						body["from"] = fmt.Sprintf("%s in %s", pn.String(), from_group)
+						body.From = fmt.Sprintf("%s in %s", pn.String(), from_group)
 					} else {
-						body["from"] = pn.String()
+						body.From = pn.String()
 					}
 				}
 			}
@@ -84,7 +100,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 			if err != nil {
 				logrus.Errorf("Error when parse jid: %v", err)
 			} else {
-				pn, err := cli.Store.LIDs.GetPNForLID(ctx, lid)
+				pn, err := client.Store.LIDs.GetPNForLID(ctx, lid)
 				if err != nil {
 					logrus.Errorf("Error when get pn for lid %s: %v", lid.String(), err)
 				}
@@ -93,22 +109,15 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 				}
 			}
 		}
-		body["message"] = message
-	}
-	if pushname := evt.Info.PushName; pushname != "" {
-		body["pushname"] = pushname
+		body.Message = &domainWebhook.MessageText{
+			Text:          message.Text,
+			ID:            message.ID,
+			RepliedID:     message.RepliedId,
+			QuotedMessage: message.QuotedMessage,
+		}
 	}
 	if waReaction.Message != "" {
-		body["reaction"] = waReaction
-	}
-	if evt.IsViewOnce {
-		body["view_once"] = evt.IsViewOnce
-	}
-	if forwarded {
-		body["forwarded"] = forwarded
-	}
-	if timestamp := evt.Info.Timestamp.Format(time.RFC3339); timestamp != "" {
-		body["timestamp"] = timestamp
+		body.Reaction = &domainWebhook.ReactionText{Message: waReaction.Message, ID: waReaction.ID}
 	}
 
 	// Handle protocol messages (revoke, etc.)
@@ -117,90 +126,70 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 
 		switch protocolType {
 		case "REVOKE":
-			body["action"] = "message_revoked"
+			body.Action = "message_revoked"
 			if key := protocolMessage.GetKey(); key != nil {
-				body["revoked_message_id"] = key.GetID()
-				body["revoked_from_me"] = key.GetFromMe()
-				if key.GetRemoteJID() != "" {
-					body["revoked_chat"] = key.GetRemoteJID()
-				}
+				body.RevokedMessageID = key.GetID()
+				body.RevokedFromMe = proto.Bool(key.GetFromMe())
+				body.RevokedChat = key.GetRemoteJID()
 			}
 		case "MESSAGE_EDIT":
-			body["action"] = "message_edited"
+			body.Action = "message_edited"
 			if editedMessage := protocolMessage.GetEditedMessage(); editedMessage != nil {
 				if editedText := editedMessage.GetExtendedTextMessage(); editedText != nil {
-					body["edited_text"] = editedText.GetText()
+					body.EditedText = editedText.GetText()
 				} else if editedConv := editedMessage.GetConversation(); editedConv != "" {
-					body["edited_text"] = editedConv
+					body.EditedText = editedConv
 				}
 			}
 		}
 	}
 
-	if audioMedia := evt.Message.GetAudioMessage(); audioMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, audioMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download audio from %s: %v", evt.Info.SourceString(), err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download audio: %v", err))
+	var err error
+	if media := evt.Message.GetAudioMessage(); media != nil {
+		if body.Audio, err = extractWebhookMedia(ctx, client, evt, "audio", media); err != nil {
+			return body, err
 		}
-		body["audio"] = path
-	}
-
-	if contactMessage := evt.Message.GetContactMessage(); contactMessage != nil {
-		body["contact"] = contactMessage
 	}
-
-	if documentMedia := evt.Message.GetDocumentMessage(); documentMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, documentMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download document from %s: %v", evt.Info.SourceString(), err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download document: %v", err))
+	if media := evt.Message.GetDocumentMessage(); media != nil {
+		if body.Document, err = extractWebhookMedia(ctx, client, evt, "document", media); err != nil {
+			return body, err
 		}
-		body["document"] = path
 	}
-
-	if imageMedia := evt.Message.GetImageMessage(); imageMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, imageMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download image from %s: %v", evt.Info.SourceString(), err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download image: %v", err))
+	if media := evt.Message.GetImageMessage(); media != nil {
+		if body.Image, err = extractWebhookMedia(ctx, client, evt, "image", media); err != nil {
+			return body, err
 		}
-		body["image"] = path
-	}
-
-	if listMessage := evt.Message.GetListMessage(); listMessage != nil {
-		body["list"] = listMessage
-	}
-
-	if liveLocationMessage := evt.Message.GetLiveLocationMessage(); liveLocationMessage != nil {
-		body["live_location"] = liveLocationMessage
-	}
-
-	if locationMessage := evt.Message.GetLocationMessage(); locationMessage != nil {
-		body["location"] = locationMessage
 	}
-
-	if orderMessage := evt.Message.GetOrderMessage(); orderMessage != nil {
-		body["order"] = orderMessage
-	}
-
-	if stickerMedia := evt.Message.GetStickerMessage(); stickerMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, stickerMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download sticker from %s: %v", evt.Info.SourceString(), err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download sticker: %v", err))
+	if media := evt.Message.GetStickerMessage(); media != nil {
+		if body.Sticker, err = extractWebhookMedia(ctx, client, evt, "sticker", media); err != nil {
+			return body, err
 		}
-		body["sticker"] = path
 	}
-
-	if videoMedia := evt.Message.GetVideoMessage(); videoMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, videoMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download video from %s: %v", evt.Info.SourceString(), err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download video: %v", err))
+	if media := evt.Message.GetVideoMessage(); media != nil {
+		if body.Video, err = extractWebhookMedia(ctx, client, evt, "video", media); err != nil {
+			return body, err
 		}
-		body["video"] = path
 	}
 
+	body.Contact = evt.Message.GetContactMessage()
+	body.List = evt.Message.GetListMessage()
+	body.LiveLocation = evt.Message.GetLiveLocationMessage()
+	body.Location = evt.Message.GetLocationMessage()
+	body.Order = evt.Message.GetOrderMessage()
+
 	return body, nil
 }
+
+// extractWebhookMedia downloads a media file of the message into the media directory
+func extractWebhookMedia(ctx context.Context, client *whatsmeow.Client, evt *events.Message, mediaType string, file whatsmeow.DownloadableMessage) (*domainWebhook.Media, error) {
+	extracted, err := utils.ExtractMedia(ctx, client, config.PathMedia, file)
+	if err != nil {
+		logrus.Errorf("Failed to download %s from %s: %v", mediaType, evt.Info.SourceString(), err)
+		return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download %s: %v", mediaType, err))
+	}
+	return &domainWebhook.Media{
+		MediaPath: extracted.MediaPath,
+		MimeType:  extracted.MimeType,
+		Caption:   extracted.Caption,
+	}, nil
+}
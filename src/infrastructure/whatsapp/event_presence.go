@@ -0,0 +1,27 @@
+package whatsapp
+
+import (
+	"time"
+
+	domainEvent "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/event"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// createPresencePayload creates a payload for contact presence (online/offline) events
+func createPresencePayload(evt *events.Presence) domainWebhook.EventBody[domainEvent.PresenceData] {
+	payload := domainEvent.PresenceData{
+		From:      evt.From.String(),
+		Available: !evt.Unavailable,
+	}
+	if !evt.LastSeen.IsZero() {
+		payload.LastSeen = evt.LastSeen.Format(time.RFC3339)
+	}
+
+	return domainWebhook.EventBody[domainEvent.PresenceData]{
+		PayloadInfo: newPayloadInfo(),
+		Event:       domainEvent.EventPresence,
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Payload:     payload,
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
@@ -35,46 +36,32 @@ func getReceiptTypeDescription(evt types.ReceiptType) string {
 }
 
 // createReceiptPayload creates a webhook payload for message acknowledgement (receipt) events
-func createReceiptPayload(evt *events.Receipt) map[string]any {
-	body := make(map[string]any)
-
-	// Create payload structure matching the expected format
-	payload := make(map[string]any)
-
-	// Add message ID (use first message ID if multiple)
-	if len(evt.MessageIDs) > 0 {
-		payload["ids"] = evt.MessageIDs
-	}
-
-	// Add from field (the chat where the message was sent)
-	payload["chat_id"] = evt.Chat
-	payload["sender_id"] = evt.Sender
-	payload["from"] = evt.SourceString()
-
+func createReceiptPayload(evt *events.Receipt) domainWebhook.EventBody[domainWebhook.ReceiptData] {
+	receiptType := string(evt.Type)
 	if evt.Type == types.ReceiptTypeDelivered {
-		payload["receipt_type"] = "delivered"
-	} else {
-		payload["receipt_type"] = evt.Type
+		receiptType = "delivered"
 	}
-	payload["receipt_type_description"] = getReceiptTypeDescription(evt.Type)
 
-	// Wrap in payload structure
-	body["payload"] = payload
-
-	// Add metadata for webhook processing
-	body["event"] = "message.ack"
-	body["timestamp"] = evt.Timestamp.Format(time.RFC3339)
-
-	return body
+	return domainWebhook.EventBody[domainWebhook.ReceiptData]{
+		PayloadInfo: newPayloadInfo(),
+		Event:       domainWebhook.EventReceipt,
+		Timestamp:   evt.Timestamp.Format(time.RFC3339),
+		Payload: domainWebhook.ReceiptData{
+			IDs:                    evt.MessageIDs,
+			ChatID:                 evt.Chat.String(),
+			SenderID:               evt.Sender.String(),
+			From:                   evt.SourceString(),
+			ReceiptType:            receiptType,
+			ReceiptTypeDescription: getReceiptTypeDescription(evt.Type),
+		},
+	}
 }
 
 // forwardReceiptToWebhook forwards message acknowledgement events to the configured webhook URLs
-func forwardReceiptToWebhook(ctx context.Context, evt *events.Receipt) error {
+func forwardReceiptToWebhook(ctx context.Context, accountID string, payload domainWebhook.Payload) error {
 	logrus.Infof("Forwarding message ack event to %d configured webhook(s)", len(config.WhatsappWebhook))
-	payload := createReceiptPayload(evt)
-
 	for _, url := range config.WhatsappWebhook {
-		if err := submitWebhook(ctx, payload, url); err != nil {
+		if err := submitWebhook(ctx, accountID, domainWebhook.EventReceipt, payload, url); err != nil {
 			return err
 		}
 	}
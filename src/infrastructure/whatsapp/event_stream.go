@@ -0,0 +1,161 @@
+package whatsapp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainEvent "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/event"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+)
+
+// eventSubscriberBuffer is the number of live events a subscriber can fall behind before it is dropped
+const eventSubscriberBuffer = 256
+
+// eventStream keeps the latest events in a ring buffer and fans them out to the subscribers.
+// Events are only recorded for accounts that have a subscriber, or had one within the resume window,
+// so the message payloads (which download media) aren't built for nobody.
+type eventStream struct {
+	mu sync.Mutex
+	// lastID starts at the boot time in microseconds, so the IDs of a new process are always above
+	// the cursors handed out by the previous one and a stale cursor is reported as truncated
+	lastID      int64
+	buffer      []domainEvent.Event
+	start       int
+	subscribers map[*eventSubscriber]struct{}
+	leftAt      map[string]time.Time // account (empty for all accounts) -> when its last subscriber left
+}
+
+type eventSubscriber struct {
+	accountID string          // empty receives every account
+	events    map[string]bool // empty receives every event type
+	ch        chan domainEvent.Event
+}
+
+func (s *eventSubscriber) wants(evt *domainEvent.Event) bool {
+	if s.accountID != "" && s.accountID != evt.AccountID {
+		return false
+	}
+	return len(s.events) == 0 || s.events[evt.Event]
+}
+
+var liveEvents = &eventStream{
+	lastID:      time.Now().UnixMicro(),
+	subscribers: make(map[*eventSubscriber]struct{}),
+	leftAt:      make(map[string]time.Time),
+}
+
+// eventStreamActive reports whether events of the account should be recorded for the stream
+func eventStreamActive(accountID string) bool {
+	if config.EventStreamBuffer <= 0 {
+		return false
+	}
+
+	liveEvents.mu.Lock()
+	defer liveEvents.mu.Unlock()
+
+	for subscriber := range liveEvents.subscribers {
+		if subscriber.accountID == "" || subscriber.accountID == accountID {
+			return true
+		}
+	}
+	for _, key := range []string{"", accountID} {
+		if leftAt, ok := liveEvents.leftAt[key]; ok {
+			if time.Since(leftAt) < config.EventStreamResumeWindow {
+				return true
+			}
+			delete(liveEvents.leftAt, key)
+		}
+	}
+	return false
+}
+
+// publishEvent records an event of the account and hands it to the subscribers that want it.
+// A subscriber that can't keep up is dropped instead of blocking the event handler.
+func publishEvent(accountID string, event string, payload domainWebhook.Payload) {
+	if !eventStreamActive(accountID) {
+		return
+	}
+
+	liveEvents.mu.Lock()
+	defer liveEvents.mu.Unlock()
+
+	liveEvents.lastID++
+	evt := domainEvent.Event{
+		ID:        liveEvents.lastID,
+		Event:     event,
+		AccountID: accountID,
+		Timestamp: time.Now().UTC(),
+		Payload:   payload,
+	}
+
+	if size := config.EventStreamBuffer; len(liveEvents.buffer) < size {
+		liveEvents.buffer = append(liveEvents.buffer, evt)
+	} else {
+		liveEvents.buffer[liveEvents.start] = evt
+		liveEvents.start = (liveEvents.start + 1) % len(liveEvents.buffer)
+	}
+
+	for subscriber := range liveEvents.subscribers {
+		if !subscriber.wants(&evt) {
+			continue
+		}
+		select {
+		case subscriber.ch <- evt:
+		default:
+			liveEvents.removeLocked(subscriber)
+		}
+	}
+}
+
+// SubscribeEvents returns the buffered events after the cursor and subscribes to the live ones of
+// the account, or of every account when accountID is empty. A cursor of 0 only receives live events.
+func SubscribeEvents(accountID string, events []string, cursor int64) *domainEvent.Subscription {
+	subscriber := &eventSubscriber{
+		accountID: accountID,
+		events:    make(map[string]bool, len(events)),
+		ch:        make(chan domainEvent.Event, eventSubscriberBuffer),
+	}
+	for _, event := range events {
+		subscriber.events[event] = true
+	}
+
+	liveEvents.mu.Lock()
+	defer liveEvents.mu.Unlock()
+
+	subscription := &domainEvent.Subscription{Events: subscriber.ch}
+	if cursor > 0 {
+		oldestID := liveEvents.lastID + 1
+		for i := range liveEvents.buffer {
+			evt := liveEvents.buffer[(liveEvents.start+i)%len(liveEvents.buffer)]
+			if i == 0 {
+				oldestID = evt.ID
+			}
+			if evt.ID > cursor && subscriber.wants(&evt) {
+				subscription.Backlog = append(subscription.Backlog, evt)
+			}
+		}
+		subscription.Truncated = cursor < oldestID-1
+	}
+
+	liveEvents.subscribers[subscriber] = struct{}{}
+
+	var once sync.Once
+	subscription.Close = func() {
+		once.Do(func() {
+			liveEvents.mu.Lock()
+			defer liveEvents.mu.Unlock()
+			liveEvents.removeLocked(subscriber)
+		})
+	}
+	return subscription
+}
+
+func (s *eventStream) removeLocked(subscriber *eventSubscriber) {
+	if _, ok := s.subscribers[subscriber]; !ok {
+		return
+	}
+	delete(s.subscribers, subscriber)
+	close(subscriber.ch)
+	s.leftAt[subscriber.accountID] = time.Now()
+}
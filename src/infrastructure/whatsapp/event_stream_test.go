@@ -0,0 +1,138 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainEvent "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/event"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetEventStream gives the test an empty event stream keeping size events, the last event ID
+// handed out is returned
+func resetEventStream(t *testing.T, size int) int64 {
+	previous, previousSize := liveEvents, config.EventStreamBuffer
+	t.Cleanup(func() {
+		liveEvents, config.EventStreamBuffer = previous, previousSize
+	})
+
+	config.EventStreamBuffer = size
+	liveEvents = &eventStream{
+		lastID:      time.Now().UnixMicro(),
+		subscribers: make(map[*eventSubscriber]struct{}),
+		leftAt:      make(map[string]time.Time),
+	}
+	return liveEvents.lastID
+}
+
+func publishTestEvent(accountID string, event string) {
+	publishEvent(accountID, event, domainWebhook.EventBody[domainWebhook.ConnectionData]{Event: event})
+}
+
+func eventIDs(events []domainEvent.Event) []int64 {
+	ids := make([]int64, 0, len(events))
+	for _, evt := range events {
+		ids = append(ids, evt.ID)
+	}
+	return ids
+}
+
+func TestSubscribeEventsBacklog(t *testing.T) {
+	lastID := resetEventStream(t, 5)
+
+	live := SubscribeEvents("a", nil, 0)
+	defer live.Close()
+	assert.Empty(t, live.Backlog, "a subscription without cursor only receives live events")
+	for range 3 {
+		publishTestEvent("a", domainWebhook.EventMessage)
+	}
+
+	resumed := SubscribeEvents("a", nil, lastID+1)
+	defer resumed.Close()
+	assert.False(t, resumed.Truncated)
+	assert.Equal(t, []int64{lastID + 2, lastID + 3}, eventIDs(resumed.Backlog))
+
+	// A cursor right before the oldest event misses nothing
+	resumed = SubscribeEvents("a", nil, lastID)
+	defer resumed.Close()
+	assert.False(t, resumed.Truncated)
+	assert.Equal(t, []int64{lastID + 1, lastID + 2, lastID + 3}, eventIDs(resumed.Backlog))
+
+	resumed = SubscribeEvents("a", nil, lastID+3)
+	defer resumed.Close()
+	assert.False(t, resumed.Truncated)
+	assert.Empty(t, resumed.Backlog)
+}
+
+func TestSubscribeEventsTruncated(t *testing.T) {
+	lastID := resetEventStream(t, 2)
+
+	live := SubscribeEvents("a", nil, 0)
+	defer live.Close()
+	for range 4 {
+		publishTestEvent("a", domainWebhook.EventMessage)
+	}
+
+	// The buffer only kept the last two events, the ones after the cursor before them are lost
+	resumed := SubscribeEvents("a", nil, lastID+1)
+	defer resumed.Close()
+	assert.True(t, resumed.Truncated)
+	assert.Equal(t, []int64{lastID + 3, lastID + 4}, eventIDs(resumed.Backlog))
+
+	resumed = SubscribeEvents("a", nil, lastID+2)
+	defer resumed.Close()
+	assert.False(t, resumed.Truncated)
+	assert.Equal(t, []int64{lastID + 3, lastID + 4}, eventIDs(resumed.Backlog))
+}
+
+func TestSubscribeEventsFilters(t *testing.T) {
+	lastID := resetEventStream(t, 10)
+
+	messages := SubscribeEvents("a", []string{domainWebhook.EventMessage}, 0)
+	defer messages.Close()
+
+	// Nobody follows account b, its events aren't recorded
+	publishTestEvent("b", domainWebhook.EventMessage)
+	assert.Empty(t, liveEvents.buffer)
+
+	all := SubscribeEvents("", nil, 0)
+	defer all.Close()
+	publishTestEvent("a", domainWebhook.EventMessage)
+	publishTestEvent("a", domainWebhook.EventReceipt)
+	publishTestEvent("b", domainWebhook.EventMessage)
+
+	require.Len(t, messages.Events, 1)
+	evt := <-messages.Events
+	assert.Equal(t, "a", evt.AccountID)
+	assert.Equal(t, domainWebhook.EventMessage, evt.Event)
+	assert.Len(t, all.Events, 3)
+
+	resumed := SubscribeEvents("b", []string{domainWebhook.EventMessage}, lastID)
+	defer resumed.Close()
+	require.Len(t, resumed.Backlog, 1)
+	assert.Equal(t, "b", resumed.Backlog[0].AccountID)
+}
+
+func TestPublishEventDropsSlowSubscriber(t *testing.T) {
+	resetEventStream(t, 10)
+
+	slow := SubscribeEvents("a", nil, 0)
+	for range eventSubscriberBuffer + 1 {
+		publishTestEvent("a", domainWebhook.EventMessage)
+	}
+
+	// The subscriber gets what fit in its channel, then the channel is closed
+	received := 0
+	for range slow.Events {
+		received++
+	}
+	assert.Equal(t, eventSubscriberBuffer, received)
+	assert.Empty(t, liveEvents.subscribers)
+	slow.Close()
+
+	// Its account stays recorded within the resume window, so it can catch up with a cursor
+	assert.True(t, eventStreamActive("a"))
+}
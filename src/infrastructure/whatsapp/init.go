@@ -7,14 +7,20 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"go.mau.fi/whatsmeow/proto/waHistorySync"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainEvent "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/event"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
 	"github.com/sirupsen/logrus"
@@ -44,6 +50,8 @@ var (
 	log           waLog.Logger
 	historySyncID int32
 	startupTime   = time.Now().Unix()
+
+	globalAutoReply domainAutoReply.IAutoReplyUsecase // Answers incoming messages with the rules of their account
 )
 
 // InitWaDB initializes the WhatsApp database connection
@@ -102,6 +110,22 @@ func syncKeysDevice(ctx context.Context, db, keysDB *sqlstore.Container) {
 
 // InitWaCLI initializes the WhatsApp client
 func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore.Container, chatStorageRepo domainChatStorage.IChatStorageRepository) *whatsmeow.Client {
+	// Set global database reference for remote logout cleanup
+	db = storeContainer
+	keysDB = keysStoreContainer
+
+	cli = newWaClient(ctx, "", storeContainer, keysStoreContainer, chatStorageRepo)
+	return cli
+}
+
+// InitAccountWaCLI initializes a WhatsApp client for a managed account without
+// replacing the global client and database used by the legacy single-device API
+func InitAccountWaCLI(ctx context.Context, accountID string, storeContainer, keysStoreContainer *sqlstore.Container, chatStorageRepo domainChatStorage.IChatStorageRepository) *whatsmeow.Client {
+	return newWaClient(ctx, accountID, storeContainer, keysStoreContainer, chatStorageRepo)
+}
+
+// newWaClient builds a client on top of the given device store and registers an event handler bound to it
+func newWaClient(ctx context.Context, accountID string, storeContainer, keysStoreContainer *sqlstore.Container, chatStorageRepo domainChatStorage.IChatStorageRepository) *whatsmeow.Client {
 	device, err := storeContainer.GetFirstDevice(ctx)
 	if err != nil {
 		log.Errorf("Failed to get device: %v", err)
@@ -118,15 +142,11 @@ func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore
 	store.DeviceProps.PlatformType = &config.AppPlatform
 	store.DeviceProps.Os = &osName
 
-	// Set global database reference for remote logout cleanup
-	db = storeContainer
-	keysDB = keysStoreContainer
-
 	// Configure a separated database for accelerating encryption caching
-	if keysDB != nil && device.ID != nil {
+	if keysStoreContainer != nil && device.ID != nil {
 		innerStore := sqlstore.NewSQLStore(keysStoreContainer, *device.ID)
 
-		syncKeysDevice(ctx, db, keysDB)
+		syncKeysDevice(ctx, storeContainer, keysStoreContainer)
 		device.Identities = innerStore
 		device.Sessions = innerStore
 		device.PreKeys = innerStore
@@ -136,15 +156,27 @@ func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore
 	}
 
 	// Create and configure the client
-	cli = whatsmeow.NewClient(device, waLog.Stdout("Client", config.WhatsappLogLevel, true))
-	cli.EnableAutoReconnect = true
-	cli.AutoTrustIdentity = true
-
-	cli.AddEventHandler(func(rawEvt interface{}) {
-		handler(ctx, rawEvt, chatStorageRepo)
+	client := whatsmeow.NewClient(device, waLog.Stdout("Client", config.WhatsappLogLevel, true))
+	client.EnableAutoReconnect = true
+	client.AutoTrustIdentity = true
+
+	evtHandler := &eventHandler{
+		client:             client,
+		accountID:          accountID,
+		storeContainer:     storeContainer,
+		keysStoreContainer: keysStoreContainer,
+		chatStorageRepo:    chatStorageRepo,
+	}
+	evtHandler.deviceJID()
+	if accountID != "" {
+		client.GetClientPayload = accountClientPayload(device, accountID)
+		evtHandler.supervisor = newConnectionSupervisor(client, accountID)
+	}
+	client.AddEventHandler(func(rawEvt interface{}) {
+		evtHandler.handle(ctx, rawEvt)
 	})
 
-	return cli
+	return client
 }
 
 // UpdateGlobalClient updates the global cli variable with a new client instance
@@ -440,43 +472,69 @@ func handleRemoteLogout(ctx context.Context, chatStorageRepo domainChatStorage.I
 	logrus.Info("[REMOTE_LOGOUT] Remote logout cleanup completed successfully")
 }
 
-// handler is the main event handler for WhatsApp events
-func handler(ctx context.Context, rawEvt any, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+// eventHandler handles the events of a single client, so everything triggered by an
+// event (replies, media downloads, webhooks, storage) goes through the client and
+// account that received it
+type eventHandler struct {
+	client             *whatsmeow.Client
+	accountID          string // empty for the legacy global client
+	storeContainer     *sqlstore.Container
+	keysStoreContainer *sqlstore.Container
+	chatStorageRepo    domainChatStorage.IChatStorageRepository
+	supervisor         *connectionSupervisor // nil for the legacy global client, which relies on whatsmeow auto reconnect
+
+	deviceMu sync.Mutex
+	device   types.JID // last device the client was paired with, kept for the logout events
+
+	lifecycleWebhooks sync.WaitGroup // account lifecycle events still being handed to the webhooks
+}
+
+// handle is the main event handler for WhatsApp events
+func (h *eventHandler) handle(ctx context.Context, rawEvt any) {
 	switch evt := rawEvt.(type) {
 	case *events.DeleteForMe:
-		handleDeleteForMe(ctx, evt, chatStorageRepo)
+		h.handleDeleteForMe(ctx, evt)
+	case *events.Star:
+		h.handleStar(ctx, evt)
 	case *events.AppStateSyncComplete:
-		handleAppStateSyncComplete(ctx, evt)
+		h.handleAppStateSyncComplete(ctx, evt)
 	case *events.PairSuccess:
-		handlePairSuccess(ctx, evt)
+		h.handlePairSuccess(ctx, evt)
 	case *events.LoggedOut:
-		handleLoggedOut(ctx, chatStorageRepo)
-	case *events.Connected, *events.PushNameSetting:
-		handleConnectionEvents(ctx)
+		h.handleLoggedOut(ctx, evt)
+	case *events.Connected:
+		h.connectionStateChanged(ctx, domainEvent.ConnectionConnected, "", 0)
+		h.superviseConnection(domainAccount.ConnectionConnected, "")
+		h.handleConnectionEvents(ctx)
+	case *events.PushNameSetting:
+		h.handleConnectionEvents(ctx)
+	case *events.Disconnected:
+		h.connectionStateChanged(ctx, domainEvent.ConnectionDisconnected, "", 0)
+		h.superviseConnection(domainAccount.ConnectionDisconnected, "")
 	case *events.StreamReplaced:
-		handleStreamReplaced(ctx)
+		h.handleStreamReplaced(ctx)
 	case *events.Message:
-		handleMessage(ctx, evt, chatStorageRepo)
+		h.handleMessage(ctx, evt)
 	case *events.Receipt:
-		handleReceipt(ctx, evt)
+		h.handleReceipt(ctx, evt)
 	case *events.Presence:
-		handlePresence(ctx, evt)
+		h.handlePresence(ctx, evt)
 	case *events.HistorySync:
-		handleHistorySync(ctx, evt, chatStorageRepo)
+		h.handleHistorySync(ctx, evt)
 	case *events.AppState:
 		handleAppState(ctx, evt)
 	case *events.GroupInfo:
-		handleGroupInfo(ctx, evt)
+		h.handleGroupInfo(ctx, evt)
 	}
 }
 
 // Event handler functions
 
-func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+func (h *eventHandler) handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe) {
 	log.Infof("Deleted message %s for %s", evt.MessageID, evt.SenderJID.String())
 
 	// Find the message to get its chat JID
-	message, err := chatStorageRepo.GetMessageByID(evt.MessageID)
+	message, err := h.chatStorageRepo.GetMessageByID(h.accountID, evt.MessageID)
 	if err != nil {
 		log.Errorf("Failed to find message %s for deletion: %v", evt.MessageID, err)
 		return
@@ -488,25 +546,50 @@ func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorage
 	}
 
 	// Delete the message from database
-	if err := chatStorageRepo.DeleteMessage(evt.MessageID, message.ChatJID); err != nil {
+	if err := h.chatStorageRepo.DeleteMessage(h.accountID, evt.MessageID, message.ChatJID); err != nil {
 		log.Errorf("Failed to delete message %s from database: %v", evt.MessageID, err)
 	} else {
 		log.Infof("Successfully deleted message %s from database", evt.MessageID)
 	}
 
+	// The payload is built once, so every delivery of the event shares its delivery ID
+	payload := createDeletePayload(evt, message)
+
+	// Send delete event to the account webhook endpoints subscribed to it
+	if endpoints := getSubscribedEndpoints(h.accountID, domainWebhook.EventDelete); len(endpoints) > 0 {
+		go func() {
+			if err := submitWebhookToEndpoints(ctx, h.accountID, domainWebhook.EventDelete, endpoints, payload); err != nil {
+				log.Errorf("Failed to forward delete event to account webhook endpoints: %v", err)
+			}
+		}()
+	}
+
+	// Publish the delete event to the live event stream
+	publishEvent(h.accountID, domainWebhook.EventDelete, payload)
+
 	// Send webhook notification for delete event
 	if len(config.WhatsappWebhook) > 0 {
 		go func() {
-			if err := forwardDeleteToWebhook(ctx, evt, message); err != nil {
+			if err := forwardDeleteToWebhook(ctx, h.accountID, payload); err != nil {
 				log.Errorf("Failed to forward delete event to webhook: %v", err)
 			}
 		}()
 	}
 }
 
-func handleAppStateSyncComplete(_ context.Context, evt *events.AppStateSyncComplete) {
-	if len(cli.Store.PushName) > 0 && evt.Name == appstate.WAPatchCriticalBlock {
-		if err := cli.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
+// handleStar keeps the starred flag of stored messages in sync, so retention can leave them out
+func (h *eventHandler) handleStar(_ context.Context, evt *events.Star) {
+	starred := evt.Action.GetStarred()
+	if err := h.chatStorageRepo.SetMessageStarred(h.accountID, evt.ChatJID.String(), evt.MessageID, starred); err != nil {
+		log.Errorf("Failed to update starred flag of message %s: %v", evt.MessageID, err)
+		return
+	}
+	log.Debugf("Message %s in %s starred: %t", evt.MessageID, evt.ChatJID.String(), starred)
+}
+
+func (h *eventHandler) handleAppStateSyncComplete(_ context.Context, evt *events.AppStateSyncComplete) {
+	if len(h.client.Store.PushName) > 0 && evt.Name == appstate.WAPatchCriticalBlock {
+		if err := h.client.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
 			log.Warnf("Failed to send available presence: %v", err)
 		} else {
 			log.Infof("Marked self as available")
@@ -514,19 +597,67 @@ func handleAppStateSyncComplete(_ context.Context, evt *events.AppStateSyncCompl
 	}
 }
 
-func handlePairSuccess(ctx context.Context, evt *events.PairSuccess) {
+func (h *eventHandler) handlePairSuccess(ctx context.Context, evt *events.PairSuccess) {
 	websocket.Broadcast <- websocket.BroadcastMessage{
 		Code:    "LOGIN_SUCCESS",
 		Message: fmt.Sprintf("Successfully pair with %s", evt.ID.String()),
 	}
-	syncKeysDevice(ctx, db, keysDB)
+	h.connectionStateChanged(ctx, domainEvent.ConnectionPaired, "", 0)
+	syncKeysDevice(ctx, h.storeContainer, h.keysStoreContainer)
 }
 
-func handleLoggedOut(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+// connectionStateChanged publishes a connection state change of the client to the live event stream
+// and delivers the matching account lifecycle event to the webhooks in the background
+func (h *eventHandler) connectionStateChanged(ctx context.Context, state string, reason string, reasonCode int) {
+	device := h.deviceJID()
+	publishEvent(h.accountID, domainEvent.EventConnection, createConnectionPayload(h.accountID, device, domainEvent.EventConnection, state, reason, reasonCode))
+
+	event, ok := accountLifecycleEvents[state]
+	if !ok {
+		return
+	}
+	payload := createConnectionPayload(h.accountID, device, event, state, reason, reasonCode)
+	publishEvent(h.accountID, event, payload)
+
+	h.lifecycleWebhooks.Add(1)
+	go func() {
+		defer h.lifecycleWebhooks.Done()
+		forwardAccountEventToWebhooks(ctx, h.accountID, event, payload)
+	}()
+}
+
+// deviceJID returns the device the client is paired with. It is remembered, because whatsmeow
+// clears the device store before the logout event is handled.
+func (h *eventHandler) deviceJID() types.JID {
+	h.deviceMu.Lock()
+	defer h.deviceMu.Unlock()
+
+	if h.client.Store != nil && h.client.Store.ID != nil {
+		h.device = *h.client.Store.ID
+	}
+	return h.device
+}
+
+// superviseConnection hands a connection state change of a managed account to its supervisor
+func (h *eventHandler) superviseConnection(state string, reason string) {
+	if h.supervisor != nil {
+		h.supervisor.notify(state, reason)
+	}
+}
+
+func (h *eventHandler) handleLoggedOut(ctx context.Context, evt *events.LoggedOut) {
+	h.connectionStateChanged(ctx, domainEvent.ConnectionLoggedOut, evt.Reason.String(), int(evt.Reason))
+	h.superviseConnection(domainAccount.ConnectionLoggedOut, evt.Reason.String())
+
+	if h.accountID != "" {
+		h.handleAccountLoggedOut()
+		return
+	}
+
 	logrus.Warn("[REMOTE_LOGOUT] Received LoggedOut event - user logged out from phone")
 
 	// Perform comprehensive cleanup
-	handleRemoteLogout(ctx, chatStorageRepo)
+	handleRemoteLogout(ctx, h.chatStorageRepo)
 
 	// Broadcast final notification that cleanup is complete and ready for new login
 	websocket.Broadcast <- websocket.BroadcastMessage{
@@ -536,25 +667,64 @@ func handleLoggedOut(ctx context.Context, chatStorageRepo domainChatStorage.ICha
 	}
 }
 
-func handleConnectionEvents(_ context.Context) {
-	if len(cli.Store.PushName) == 0 {
+// handleAccountLoggedOut notifies the clients that a managed account was logged out. The supervisor
+// marks the account as disconnected, and the device store has already been cleared by whatsmeow,
+// so the same client can be paired again without touching other accounts.
+func (h *eventHandler) handleAccountLoggedOut() {
+	logrus.Warnf("[REMOTE_LOGOUT] Account %s was logged out from phone", h.accountID)
+
+	websocket.Broadcast <- websocket.BroadcastMessage{
+		Code:    "LOGOUT_COMPLETE",
+		Message: fmt.Sprintf("Account %s was logged out - ready for new login", h.accountID),
+		Result:  map[string]any{"account_id": h.accountID},
+	}
+}
+
+func (h *eventHandler) handleConnectionEvents(_ context.Context) {
+	if len(h.client.Store.PushName) == 0 {
 		return
 	}
 
 	// Send presence available when connecting and when the pushname is changed.
 	// This makes sure that outgoing messages always have the right pushname.
-	if err := cli.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
+	if err := h.client.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
 		log.Warnf("Failed to send available presence: %v", err)
 	} else {
 		log.Infof("Marked self as available")
 	}
 }
 
-func handleStreamReplaced(_ context.Context) {
+func (h *eventHandler) handleStreamReplaced(ctx context.Context) {
+	h.connectionStateChanged(ctx, domainEvent.ConnectionStreamReplaced, "", 0)
+	h.superviseConnection(domainAccount.ConnectionStreamReplaced, "")
+
+	if h.accountID != "" {
+		// Another instance took over this account, stop it here without taking down the other accounts
+		logrus.Warnf("Stream replaced for account %s, the session is now active elsewhere", h.accountID)
+		return
+	}
+
+	// Give the stream_replaced webhook a chance to go out before the process exits
+	h.waitLifecycleWebhooks(lifecycleWebhookExitTimeout)
 	os.Exit(0)
 }
 
-func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+// waitLifecycleWebhooks waits until the pending account lifecycle events were handed to the webhooks
+func (h *eventHandler) waitLifecycleWebhooks(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		h.lifecycleWebhooks.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		logrus.Warn("Timed out waiting for the account lifecycle webhooks")
+	}
+}
+
+func (h *eventHandler) handleMessage(ctx context.Context, evt *events.Message) {
 	// Log message metadata
 	metaParts := buildMessageMetaParts(evt)
 	log.Infof("Received message %s from %s (%s): %+v",
@@ -563,23 +733,26 @@ func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo dom
 		strings.Join(metaParts, ", "),
 		evt.Message,
 	)
+	if !evt.Info.IsFromMe {
+		metrics.MessageReceived(h.accountID, evt.Message)
+	}
 
-	if err := chatStorageRepo.CreateMessage(ctx, evt); err != nil {
+	if err := h.chatStorageRepo.CreateMessage(ctx, h.accountID, evt); err != nil {
 		// Log storage errors to avoid silent failures that could lead to data loss
 		log.Errorf("Failed to store incoming message %s: %v", evt.Info.ID, err)
 	}
 
 	// Handle image message if present
-	handleImageMessage(ctx, evt)
+	h.handleImageMessage(ctx, evt)
 
 	// Auto-mark message as read if configured
-	handleAutoMarkRead(ctx, evt)
+	h.handleAutoMarkRead(ctx, evt)
 
 	// Handle auto-reply if configured
-	handleAutoReply(ctx, evt, chatStorageRepo)
+	h.handleAutoReply(ctx, evt)
 
 	// Forward to webhook if configured
-	handleWebhookForward(ctx, evt)
+	h.handleWebhookForward(ctx, evt)
 }
 
 func buildMessageMetaParts(evt *events.Message) []string {
@@ -599,9 +772,9 @@ func buildMessageMetaParts(evt *events.Message) []string {
 	return metaParts
 }
 
-func handleImageMessage(ctx context.Context, evt *events.Message) {
+func (h *eventHandler) handleImageMessage(ctx context.Context, evt *events.Message) {
 	if img := evt.Message.GetImageMessage(); img != nil {
-		if path, err := utils.ExtractMedia(ctx, cli, config.PathStorages, img); err != nil {
+		if path, err := utils.ExtractMedia(ctx, h.client, config.PathStorages, img); err != nil {
 			log.Errorf("Failed to download image: %v", err)
 		} else {
 			log.Infof("Image downloaded to %s", path)
@@ -609,9 +782,9 @@ func handleImageMessage(ctx context.Context, evt *events.Message) {
 	}
 }
 
-func handleAutoMarkRead(_ context.Context, evt *events.Message) {
-	// Only mark read if auto-mark read is enabled and message is incoming
-	if !config.WhatsappAutoMarkRead || evt.Info.IsFromMe {
+func (h *eventHandler) handleAutoMarkRead(_ context.Context, evt *events.Message) {
+	// Only mark read if auto-mark read is enabled for the account and message is incoming
+	if evt.Info.IsFromMe || !GetAccountSettings(h.accountID).AutoMarkRead {
 		return
 	}
 
@@ -621,25 +794,21 @@ func handleAutoMarkRead(_ context.Context, evt *events.Message) {
 	chat := evt.Info.Chat
 	sender := evt.Info.Sender
 
-	if err := cli.MarkRead(context.Background(), messageIDs, timestamp, chat, sender); err != nil {
+	if err := h.client.MarkRead(context.Background(), messageIDs, timestamp, chat, sender); err != nil {
 		log.Warnf("Failed to mark message %s as read: %v", evt.Info.ID, err)
 	} else {
 		log.Debugf("Marked message %s as read", evt.Info.ID)
 	}
 }
 
-func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
-	if config.WhatsappAutoReplyMessage == "" {
-		return
-	}
-
-	// Skip groups, broadcasts, and self messages
-	if utils.IsGroupJID(evt.Info.Chat.String()) || evt.Info.IsIncomingBroadcast() || evt.Info.IsFromMe {
-		return
-	}
+// SetAutoReply sets the usecase that answers incoming messages with the auto-reply rules of their account
+func SetAutoReply(usecase domainAutoReply.IAutoReplyUsecase) {
+	globalAutoReply = usecase
+}
 
-	// Only reply to direct 1:1 chats (e.g., *@s.whatsapp.net)
-	if evt.Info.Chat.Server != types.DefaultUserServer {
+func (h *eventHandler) handleAutoReply(ctx context.Context, evt *events.Message) {
+	// Skip broadcasts and self messages
+	if evt.Info.IsIncomingBroadcast() || evt.Info.IsFromMe {
 		return
 	}
 
@@ -652,45 +821,38 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 	}
 
 	// Require actual typed text (not captions or synthetic labels)
-	hasText := false
-
-	// Unwrap FutureProof wrappers to access the inner message content first
-	innerMsg := evt.Message
-	for i := 0; i < 3; i++ { // safeguard against excessively nested wrappers
-		if vm := innerMsg.GetViewOnceMessage(); vm != nil && vm.GetMessage() != nil {
-			innerMsg = vm.GetMessage()
-			continue
-		}
-		if em := innerMsg.GetEphemeralMessage(); em != nil && em.GetMessage() != nil {
-			innerMsg = em.GetMessage()
-			continue
-		}
-		if vm2 := innerMsg.GetViewOnceMessageV2(); vm2 != nil && vm2.GetMessage() != nil {
-			innerMsg = vm2.GetMessage()
-			continue
+	text, edited := typedText(evt.Message)
+	if text == "" {
+		return
+	}
+	isGroup := utils.IsGroupJID(evt.Info.Chat.String())
+
+	// The rules of the account take precedence, edits never trigger them
+	if globalAutoReply != nil && h.accountID != "" && !edited {
+		handled, err := globalAutoReply.Reply(ctx, domainAutoReply.IncomingMessage{
+			AccountID: h.accountID,
+			ChatJID:   evt.Info.Chat.String(),
+			SenderJID: evt.Info.Sender.ToNonAD().String(),
+			PushName:  evt.Info.PushName,
+			Text:      text,
+			IsGroup:   isGroup,
+			Timestamp: evt.Info.Timestamp,
+		})
+		if err != nil {
+			log.Errorf("Failed to send auto-reply: %v", err)
 		}
-		if vm2e := innerMsg.GetViewOnceMessageV2Extension(); vm2e != nil && vm2e.GetMessage() != nil {
-			innerMsg = vm2e.GetMessage()
-			continue
+		if handled {
+			return
 		}
-		break
 	}
 
-	// Check for genuine typed text on the unwrapped content
-	if conv := innerMsg.GetConversation(); conv != "" {
-		hasText = true
-	} else if ext := innerMsg.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
-		hasText = true
-	} else if protoMsg := innerMsg.GetProtocolMessage(); protoMsg != nil {
-		if edited := protoMsg.GetEditedMessage(); edited != nil {
-			if ext := edited.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
-				hasText = true
-			} else if conv := edited.GetConversation(); conv != "" {
-				hasText = true
-			}
-		}
+	replyMessage := GetAccountSettings(h.accountID).AutoReplyMessage
+	if replyMessage == "" {
+		return
 	}
-	if !hasText {
+
+	// The fallback auto-reply only answers direct 1:1 chats (e.g., *@s.whatsapp.net)
+	if isGroup || evt.Info.Chat.Server != types.DefaultUserServer {
 		return
 	}
 
@@ -698,10 +860,10 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 	recipientJID := utils.FormatJID(evt.Info.Sender.String())
 
 	// Send the auto-reply message
-	response, err := cli.SendMessage(
+	response, err := h.client.SendMessage(
 		ctx,
 		recipientJID,
-		&waE2E.Message{Conversation: proto.String(config.WhatsappAutoReplyMessage)},
+		&waE2E.Message{Conversation: proto.String(replyMessage)},
 	)
 
 	if err != nil {
@@ -710,21 +872,22 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 	}
 
 	// Store the auto-reply message in chat storage if send was successful
-	if chatStorageRepo != nil {
+	if h.chatStorageRepo != nil {
 		// Get our own JID as sender
 		senderJID := ""
-		if cli.Store.ID != nil {
-			senderJID = cli.Store.ID.String()
+		if h.client.Store.ID != nil {
+			senderJID = h.client.Store.ID.String()
 		}
 
 		// Store the sent auto-reply message
-		if err := chatStorageRepo.StoreSentMessageWithContext(
+		if err := h.chatStorageRepo.StoreSentMessageWithContext(
 			ctx,
-			response.ID,                     // Message ID from WhatsApp response
-			senderJID,                       // Our JID as sender
-			recipientJID.String(),           // Recipient JID
-			config.WhatsappAutoReplyMessage, // Auto-reply content
-			response.Timestamp,              // Timestamp from response
+			h.accountID,           // Account that sent the auto-reply
+			response.ID,           // Message ID from WhatsApp response
+			senderJID,             // Our JID as sender
+			recipientJID.String(), // Recipient JID
+			replyMessage,          // Auto-reply content
+			response.Timestamp,    // Timestamp from response
 		); err != nil {
 			// Log storage error but don't fail the auto-reply
 			log.Errorf("Failed to store auto-reply message in chat storage: %v", err)
@@ -734,7 +897,49 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 	}
 }
 
-func handleWebhookForward(ctx context.Context, evt *events.Message) {
+// typedText returns the typed text of a message, unwrapping view once and ephemeral wrappers.
+// Captions and synthetic labels don't count. Edited reports that the text comes from an edit.
+func typedText(message *waE2E.Message) (text string, edited bool) {
+	// Unwrap FutureProof wrappers to access the inner message content first
+	innerMsg := message
+	for i := 0; i < 3; i++ { // safeguard against excessively nested wrappers
+		if vm := innerMsg.GetViewOnceMessage(); vm != nil && vm.GetMessage() != nil {
+			innerMsg = vm.GetMessage()
+			continue
+		}
+		if em := innerMsg.GetEphemeralMessage(); em != nil && em.GetMessage() != nil {
+			innerMsg = em.GetMessage()
+			continue
+		}
+		if vm2 := innerMsg.GetViewOnceMessageV2(); vm2 != nil && vm2.GetMessage() != nil {
+			innerMsg = vm2.GetMessage()
+			continue
+		}
+		if vm2e := innerMsg.GetViewOnceMessageV2Extension(); vm2e != nil && vm2e.GetMessage() != nil {
+			innerMsg = vm2e.GetMessage()
+			continue
+		}
+		break
+	}
+
+	// Check for genuine typed text on the unwrapped content
+	if conv := innerMsg.GetConversation(); conv != "" {
+		return conv, false
+	} else if ext := innerMsg.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
+		return ext.GetText(), false
+	} else if protoMsg := innerMsg.GetProtocolMessage(); protoMsg != nil {
+		if editedMsg := protoMsg.GetEditedMessage(); editedMsg != nil {
+			if ext := editedMsg.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
+				return ext.GetText(), true
+			} else if conv := editedMsg.GetConversation(); conv != "" {
+				return conv, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (h *eventHandler) handleWebhookForward(ctx context.Context, evt *events.Message) {
 	// Skip webhook for specific protocol messages that shouldn't trigger webhooks
 	if protocolMessage := evt.Message.GetProtocolMessage(); protocolMessage != nil {
 		protocolType := protocolMessage.GetType().String()
@@ -750,36 +955,54 @@ func handleWebhookForward(ctx context.Context, evt *events.Message) {
 		return
 	}
 
-	// First try to send to account-specific webhook
-	accountID := GetAccountIDFromClient(cli)
+	// Send to the account-specific webhooks, the live event stream and the global webhooks
+	accountID := h.accountID
+	event := messageWebhookEvent(evt)
+	var accountRepo domainAccount.IAccountRepository
+	var endpoints []*domainWebhook.Endpoint
 	if accountID != "" {
-		accountRepo := GetAccountRepoFromGlobalVars()
-		if accountRepo != nil {
-			go func(evt *events.Message) {
-				payload, err := createMessagePayload(ctx, evt)
-				if err != nil {
-					logrus.Error("Failed to create message payload: ", err)
-					return
-				}
+		accountRepo = GetAccountRepoFromGlobalVars()
+		endpoints = getSubscribedEndpoints(accountID, event)
+	}
+	streamed := eventStreamActive(accountID)
+	if accountRepo == nil && len(endpoints) == 0 && !streamed && len(config.WhatsappWebhook) == 0 {
+		return
+	}
 
-				if err := submitWebhookForAccount(ctx, payload, accountID, accountRepo); err != nil {
-					logrus.Error("Failed forward to account webhook: ", err)
+	// The payload is built once, so every delivery of the event shares its delivery ID
+	go func(evt *events.Message) {
+		payload, err := createMessagePayload(ctx, h.client, evt)
+		if err != nil {
+			logrus.Error("Failed to create message payload: ", err)
+			return
+		}
+
+		// Fallback to global webhook for backward compatibility
+		if len(config.WhatsappWebhook) > 0 {
+			go func() {
+				if err := forwardMessageToWebhook(ctx, accountID, event, payload); err != nil {
+					logrus.Error("Failed forward to global webhook: ", err)
 				}
-			}(evt)
+			}()
 		}
-	}
 
-	// Fallback to global webhook for backward compatibility
-	if len(config.WhatsappWebhook) > 0 {
-		go func(evt *events.Message) {
-			if err := forwardMessageToWebhook(ctx, evt); err != nil {
-				logrus.Error("Failed forward to global webhook: ", err)
+		if streamed {
+			publishEvent(accountID, event, payload)
+		}
+		if accountRepo != nil {
+			if err := submitWebhookForAccount(ctx, event, payload, accountID, accountRepo); err != nil {
+				logrus.Error("Failed forward to account webhook: ", err)
 			}
-		}(evt)
-	}
+		}
+		if len(endpoints) > 0 {
+			if err := submitWebhookToEndpoints(ctx, accountID, event, endpoints, payload); err != nil {
+				logrus.Error("Failed forward to account webhook endpoints: ", err)
+			}
+		}
+	}(evt)
 }
 
-func handleReceipt(ctx context.Context, evt *events.Receipt) {
+func (h *eventHandler) handleReceipt(ctx context.Context, evt *events.Receipt) {
 	sendReceipt := false
 	switch evt.Type {
 	case types.ReceiptTypeRead, types.ReceiptTypeReadSelf:
@@ -790,18 +1013,67 @@ func handleReceipt(ctx context.Context, evt *events.Receipt) {
 		log.Infof("%s was delivered to %s at %s: %+v", evt.MessageIDs[0], evt.SourceString(), evt.Timestamp, evt)
 	}
 
+	if !sendReceipt {
+		return
+	}
+
+	h.storeReceipt(evt)
+
+	// The payload is built once, so every delivery of the event shares its delivery ID
+	payload := createReceiptPayload(evt)
+
+	// Publish the receipt (ack) event to the live event stream
+	publishEvent(h.accountID, domainWebhook.EventReceipt, payload)
+
+	// Forward receipt (ack) event to the account webhook endpoints subscribed to it
+	if endpoints := getSubscribedEndpoints(h.accountID, domainWebhook.EventReceipt); len(endpoints) > 0 {
+		go func() {
+			if err := submitWebhookToEndpoints(ctx, h.accountID, domainWebhook.EventReceipt, endpoints, payload); err != nil {
+				logrus.Errorf("Failed to forward ack event to account webhook endpoints: %v", err)
+			}
+		}()
+	}
+
 	// Forward receipt (ack) event to webhook if configured
 	// Note: Receipt events are not rate limited as they are critical for message delivery status
-	if len(config.WhatsappWebhook) > 0 && sendReceipt {
-		go func(e *events.Receipt) {
-			if err := forwardReceiptToWebhook(ctx, e); err != nil {
+	if len(config.WhatsappWebhook) > 0 {
+		go func() {
+			if err := forwardReceiptToWebhook(ctx, h.accountID, payload); err != nil {
 				logrus.Errorf("Failed to forward ack event to webhook: %v", err)
 			}
-		}(evt)
+		}()
+	}
+}
+
+// storeReceipt persists delivery and read receipts of the messages this account sent.
+// Receipts from our own devices are skipped since they don't say anything about the recipient.
+func (h *eventHandler) storeReceipt(evt *events.Receipt) {
+	if evt.IsFromMe || (evt.Type != types.ReceiptTypeDelivered && evt.Type != types.ReceiptTypeRead) {
+		return
+	}
+
+	timestamp := evt.Timestamp
+	for _, messageID := range evt.MessageIDs {
+		receipt := &domainChatStorage.MessageReceipt{
+			AccountID:   h.accountID,
+			ChatJID:     evt.Chat.String(),
+			MessageID:   messageID,
+			Recipient:   evt.Sender.String(),
+			Participant: evt.Sender.ToNonAD().String(),
+		}
+		if evt.Type == types.ReceiptTypeRead {
+			receipt.ReadAt = &timestamp
+		} else {
+			receipt.DeliveredAt = &timestamp
+		}
+
+		if err := h.chatStorageRepo.StoreReceipt(receipt); err != nil {
+			log.Errorf("Failed to store receipt of message %s: %v", messageID, err)
+		}
 	}
 }
 
-func handlePresence(_ context.Context, evt *events.Presence) {
+func (h *eventHandler) handlePresence(_ context.Context, evt *events.Presence) {
 	if evt.Unavailable {
 		if evt.LastSeen.IsZero() {
 			log.Infof("%s is now offline", evt.From)
@@ -811,14 +1083,22 @@ func handlePresence(_ context.Context, evt *events.Presence) {
 	} else {
 		log.Infof("%s is now online", evt.From)
 	}
+
+	publishEvent(h.accountID, domainEvent.EventPresence, createPresencePayload(evt))
 }
 
-func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+func (h *eventHandler) handleHistorySync(ctx context.Context, evt *events.HistorySync) {
+	messages := 0
+	for _, conv := range evt.Data.GetConversations() {
+		messages += len(conv.GetMessages())
+	}
+	metrics.HistorySync(h.accountID, evt.Data.GetSyncType().String(), len(evt.Data.GetConversations()), messages)
+
 	id := atomic.AddInt32(&historySyncID, 1)
 	fileName := fmt.Sprintf("%s/history-%d-%s-%d-%s.json",
 		config.PathStorages,
 		startupTime,
-		cli.Store.ID.String(),
+		h.client.Store.ID.String(),
 		id,
 		evt.Data.SyncType.String(),
 	)
@@ -840,8 +1120,8 @@ func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorage
 	log.Infof("Wrote history sync to %s", fileName)
 
 	// Process history sync data to database
-	if chatStorageRepo != nil {
-		if err := processHistorySync(ctx, evt.Data, chatStorageRepo); err != nil {
+	if h.chatStorageRepo != nil {
+		if err := h.processHistorySync(ctx, evt.Data); err != nil {
 			log.Errorf("Failed to process history sync to database: %v", err)
 		}
 	}
@@ -852,7 +1132,7 @@ func handleAppState(_ context.Context, evt *events.AppState) {
 }
 
 // processHistorySync processes history sync data and stores messages in the database
-func processHistorySync(ctx context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+func (h *eventHandler) processHistorySync(ctx context.Context, data *waHistorySync.HistorySync) error {
 	if data == nil {
 		return nil
 	}
@@ -863,10 +1143,10 @@ func processHistorySync(ctx context.Context, data *waHistorySync.HistorySync, ch
 	switch syncType {
 	case waHistorySync.HistorySync_INITIAL_BOOTSTRAP, waHistorySync.HistorySync_RECENT:
 		// Process conversation messages
-		return processConversationMessages(ctx, data, chatStorageRepo)
+		return h.processConversationMessages(ctx, data)
 	case waHistorySync.HistorySync_PUSH_NAME:
 		// Process push names to update chat names
-		return processPushNames(ctx, data, chatStorageRepo)
+		return h.processPushNames(ctx, data)
 	default:
 		// Other sync types are not needed for message storage
 		log.Debugf("Skipping history sync type: %s", syncType.String())
@@ -875,7 +1155,7 @@ func processHistorySync(ctx context.Context, data *waHistorySync.HistorySync, ch
 }
 
 // processConversationMessages processes and stores conversation messages from history sync
-func processConversationMessages(_ context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+func (h *eventHandler) processConversationMessages(_ context.Context, data *waHistorySync.HistorySync) error {
 	conversations := data.GetConversations()
 	log.Infof("Processing %d conversations from history sync", len(conversations))
 
@@ -895,7 +1175,7 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 		displayName := conv.GetDisplayName()
 
 		// Get or create chat
-		chatName := chatStorageRepo.GetChatNameWithPushName(jid, chatJID, "", displayName)
+		chatName := h.chatStorageRepo.GetChatNameWithPushName(h.accountID, jid, chatJID, "", displayName)
 
 		// Extract ephemeral expiration from conversation
 		ephemeralExpiration := conv.GetEphemeralExpiration()
@@ -939,8 +1219,8 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 			isFromMe := msgKey.GetFromMe()
 			if isFromMe {
 				// For self-messages, use the full JID format to match regular message processing
-				if cli.Store.ID != nil {
-					sender = cli.Store.ID.String() // Use full JID instead of just User part
+				if h.client.Store.ID != nil {
+					sender = h.client.Store.ID.String() // Use full JID instead of just User part
 				} else {
 					// Skip messages where we can't determine the sender to avoid NOT NULL violations
 					log.Warnf("Skipping self-message %s: client ID unavailable", messageID)
@@ -978,6 +1258,7 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 
 			// Create message object and add to batch
 			message := &domainChatStorage.Message{
+				AccountID:     h.accountID,
 				ID:            messageID,
 				ChatJID:       chatJID,
 				Sender:        sender,
@@ -999,6 +1280,7 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 		// Store or update the chat with latest message time
 		if len(messageBatch) > 0 {
 			chat := &domainChatStorage.Chat{
+				AccountID:           h.accountID,
 				JID:                 chatJID,
 				Name:                chatName,
 				LastMessageTime:     latestTimestamp,
@@ -1006,13 +1288,13 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 			}
 
 			// Store or update the chat
-			if err := chatStorageRepo.StoreChat(chat); err != nil {
+			if err := h.chatStorageRepo.StoreChat(chat); err != nil {
 				log.Warnf("Failed to store chat %s: %v", chatJID, err)
 				continue
 			}
 
 			// Store messages in batch
-			if err := chatStorageRepo.StoreMessagesBatch(messageBatch); err != nil {
+			if err := h.chatStorageRepo.StoreMessagesBatch(messageBatch); err != nil {
 				log.Warnf("Failed to store messages batch for chat %s: %v", chatJID, err)
 			} else {
 				log.Debugf("Stored %d messages for chat %s", len(messageBatch), chatJID)
@@ -1024,7 +1306,7 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 }
 
 // processPushNames processes push names from history sync to update chat names
-func processPushNames(_ context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+func (h *eventHandler) processPushNames(_ context.Context, data *waHistorySync.HistorySync) error {
 	pushnames := data.GetPushnames()
 	log.Infof("Processing %d push names from history sync", len(pushnames))
 
@@ -1037,7 +1319,7 @@ func processPushNames(_ context.Context, data *waHistorySync.HistorySync, chatSt
 		}
 
 		// Check if chat exists
-		existingChat, err := chatStorageRepo.GetChat(jidStr)
+		existingChat, err := h.chatStorageRepo.GetChat(h.accountID, jidStr)
 		if err != nil || existingChat == nil {
 			// Chat doesn't exist yet, skip
 			continue
@@ -1046,7 +1328,7 @@ func processPushNames(_ context.Context, data *waHistorySync.HistorySync, chatSt
 		// Update chat name if it's different
 		if existingChat.Name != name {
 			existingChat.Name = name
-			if err := chatStorageRepo.StoreChat(existingChat); err != nil {
+			if err := h.chatStorageRepo.StoreChat(existingChat); err != nil {
 				log.Warnf("Failed to update chat name for %s: %v", jidStr, err)
 			} else {
 				log.Debugf("Updated chat name for %s to %s", jidStr, name)
@@ -1057,7 +1339,7 @@ func processPushNames(_ context.Context, data *waHistorySync.HistorySync, chatSt
 	return nil
 }
 
-func handleGroupInfo(ctx context.Context, evt *events.GroupInfo) {
+func (h *eventHandler) handleGroupInfo(ctx context.Context, evt *events.GroupInfo) {
 	// Only process events that have actual changes
 	hasChanges := len(evt.Join) > 0 || len(evt.Leave) > 0 || len(evt.Promote) > 0 || len(evt.Demote) > 0 ||
 		evt.Name != nil || evt.Topic != nil || evt.Locked != nil || evt.Announce != nil
@@ -1080,12 +1362,29 @@ func handleGroupInfo(ctx context.Context, evt *events.GroupInfo) {
 		log.Infof("Group %s: %d users demoted at %s", evt.JID, len(evt.Demote), evt.Timestamp)
 	}
 
+	// The payloads are built once, so every delivery of a participant change shares its delivery ID
+	payloads := createGroupInfoPayloads(evt)
+
+	// Publish group participant changes to the live event stream, one event per action like the webhooks
+	for _, p := range payloads {
+		publishEvent(h.accountID, domainWebhook.EventGroupParticipants, p.payload)
+	}
+
+	// Forward group participant changes to the account webhook endpoints subscribed to them
+	if endpoints := getSubscribedEndpoints(h.accountID, domainWebhook.EventGroupParticipants); len(endpoints) > 0 {
+		go func() {
+			if err := forwardGroupInfoToEndpoints(ctx, h.accountID, endpoints, payloads); err != nil {
+				logrus.Errorf("Failed to forward group info event to account webhook endpoints: %v", err)
+			}
+		}()
+	}
+
 	// Forward group info event to webhook if configured
 	if len(config.WhatsappWebhook) > 0 {
-		go func(e *events.GroupInfo) {
-			if err := forwardGroupInfoToWebhook(ctx, e); err != nil {
+		go func() {
+			if err := forwardGroupInfoToWebhook(ctx, h.accountID, payloads); err != nil {
 				logrus.Errorf("Failed to forward group info event to webhook: %v", err)
 			}
-		}(evt)
+		}()
 	}
 }
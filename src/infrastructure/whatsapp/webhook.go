@@ -7,17 +7,21 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
 // submitWebhookForAccount submits webhook for specific account
-func submitWebhookForAccount(ctx context.Context, payload map[string]any, accountID string, accountRepo domainAccount.IAccountRepository) error {
+func submitWebhookForAccount(ctx context.Context, event string, payload domainWebhook.Payload, accountID string, accountRepo domainAccount.IAccountRepository) error {
 	// Get account webhook configuration
 	webhook, err := accountRepo.GetWebhook(accountID)
 	if err != nil {
@@ -29,54 +33,144 @@ func submitWebhookForAccount(ctx context.Context, payload map[string]any, accoun
 		return nil
 	}
 
-	return submitWebhookToURL(ctx, payload, webhook.URL, webhook.Secret)
+	// Without a secret of its own the webhook is signed with the webhook secret setting of the account
+	secret := webhook.Secret
+	if secret == "" {
+		secret = GetAccountSettings(accountID).WebhookSecret
+	}
+	return submitWebhookToURL(ctx, accountID, event, payload, webhook.URL, secret)
 }
 
-// submitWebhook submits webhook to global webhook URLs (backward compatibility)
-func submitWebhook(ctx context.Context, payload map[string]any, url string) error {
-	return submitWebhookToURL(ctx, payload, url, config.WhatsappWebhookSecret)
+// getSubscribedEndpoints returns the webhook endpoints of the account that receive the given event type
+func getSubscribedEndpoints(accountID string, event string) []*domainWebhook.Endpoint {
+	repo := GetWebhookOutboxFromGlobalVars()
+	if accountID == "" || repo == nil {
+		return nil
+	}
+
+	endpoints, err := repo.ListEndpoints(accountID)
+	if err != nil {
+		logrus.Errorf("Failed to get webhook endpoints for account %s: %v", accountID, err)
+		return nil
+	}
+
+	var subscribed []*domainWebhook.Endpoint
+	for _, endpoint := range endpoints {
+		if endpoint.Subscribes(event) {
+			subscribed = append(subscribed, endpoint)
+		}
+	}
+	return subscribed
 }
 
-func submitWebhookToURL(ctx context.Context, payload map[string]any, url string, secret string) error {
-	client := &http.Client{Timeout: 10 * time.Second}
+// submitWebhookToEndpoints submits the payload to every given endpoint and only fails if all of them fail
+func submitWebhookToEndpoints(ctx context.Context, accountID string, event string, endpoints []*domainWebhook.Endpoint, payload domainWebhook.Payload) error {
+	var errMessages []string
+	for _, endpoint := range endpoints {
+		if err := submitWebhookToURL(ctx, accountID, event, payload, endpoint.URL, endpoint.Secret); err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("webhook %s failed: %v", endpoint.ID, err))
+		}
+	}
 
-	postBody, err := json.Marshal(payload)
+	if len(errMessages) > 0 && len(errMessages) == len(endpoints) {
+		return pkgError.WebhookError(fmt.Sprintf("all webhook endpoints failed: %s", strings.Join(errMessages, "; ")))
+	}
+	if len(errMessages) > 0 {
+		logrus.Warnf("Some webhook endpoints failed for account %s: %v", accountID, errMessages)
+	}
+	return nil
+}
+
+// submitWebhook submits webhook to global webhook URLs (backward compatibility),
+// signed with the webhook secret setting of the account
+func submitWebhook(ctx context.Context, accountID string, event string, payload domainWebhook.Payload, url string) error {
+	return submitWebhookToURL(ctx, accountID, event, payload, url, GetAccountSettings(accountID).WebhookSecret)
+}
+
+// submitWebhookToURL writes the payload to the webhook outbox, where the delivery workers pick it up.
+// Without an outbox the payload is delivered directly with a short in-process retry.
+func submitWebhookToURL(ctx context.Context, accountID string, event string, payload domainWebhook.Payload, url string, secret string) error {
+	var body any = payload
+	if config.WhatsappWebhookEnvelope {
+		body = newWebhookEnvelope(accountID, event, payload)
+	}
+
+	postBody, err := json.Marshal(body)
 	if err != nil {
 		return pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	signature, err := utils.GetMessageDigestOrSignature(postBody, []byte(secret))
 	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
+		return pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
+	}
+
+	metrics.WebhookEnqueued(accountID, event)
+
+	outbox := GetWebhookOutboxFromGlobalVars()
+	if outbox == nil {
+		return deliverWebhookWithRetry(ctx, accountID, url, event, postBody, signature)
 	}
 
-	secretKey := []byte(secret)
-	signature, err := utils.GetMessageDigestOrSignature(postBody, secretKey)
+	delivery := &domainWebhook.Delivery{
+		AccountID: accountID,
+		Event:     event,
+		URL:       url,
+		Payload:   string(postBody),
+		Signature: signature,
+	}
+	if err := outbox.EnqueueDelivery(delivery); err != nil {
+		return pkgError.WebhookError(fmt.Sprintf("error when enqueue webhook delivery %v", err))
+	}
+
+	notifyWebhookWorkers()
+	return nil
+}
+
+// postWebhook performs a single delivery attempt
+func postWebhook(ctx context.Context, url string, event string, postBody []byte, signature string) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(postBody))
 	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
+		return fmt.Errorf("error when create http object %v", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
+	if event != "" {
+		req.Header.Set("X-Webhook-Event", event)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
 
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func deliverWebhookWithRetry(ctx context.Context, accountID string, url string, event string, postBody []byte, signature string) error {
 	var attempt int
 	var maxAttempts = 5
 	var sleepDuration = 1 * time.Second
+	var err error
 
 	for attempt = 0; attempt < maxAttempts; attempt++ {
-		// Create new request body for each attempt
-		req.Body = io.NopCloser(bytes.NewBuffer(postBody))
-		resp, err := client.Do(req)
+		err = postWebhook(ctx, url, event, postBody, signature)
+		metrics.WebhookAttempt(accountID, event, err)
 		if err == nil {
-			defer resp.Body.Close()
-			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-				logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
-				return nil
-			}
-			err = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+			logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
+			return nil
 		}
 		logrus.Warnf("Attempt %d to submit webhook failed: %v", attempt+1, err)
 		if attempt < maxAttempts-1 {
+			metrics.WebhookRetry(accountID, event)
 			time.Sleep(sleepDuration)
 			sleepDuration *= 2
 		}
@@ -84,3 +178,39 @@ func submitWebhookToURL(ctx context.Context, payload map[string]any, url string,
 
 	return pkgError.WebhookError(fmt.Sprintf("error when submit webhook after %d attempts: %v", attempt, err))
 }
+
+// newPayloadInfo generates the delivery ID of an event whose payload is being built
+func newPayloadInfo() domainWebhook.PayloadInfo {
+	return domainWebhook.PayloadInfo{DeliveryID: uuid.NewString()}
+}
+
+// newWebhookEnvelope wraps a payload in the versioned webhook envelope. The delivery ID comes from the
+// payload, so every webhook the event is delivered to gets the same one.
+// Receipt and group payloads already nest their data under "payload" next to the event metadata,
+// so only the nested data is carried over for those.
+func newWebhookEnvelope(accountID string, event string, payload domainWebhook.Payload) domainWebhook.Envelope {
+	data, timestamp := payload.EnvelopeData()
+	envelope := domainWebhook.Envelope{
+		Version:    domainWebhook.EnvelopeVersion,
+		Event:      event,
+		AccountID:  accountID,
+		DeviceID:   webhookDeviceID(accountID),
+		DeliveryID: payload.GetDeliveryID(),
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Payload:    data,
+	}
+	if timestamp != "" {
+		envelope.Timestamp = timestamp
+	}
+
+	return envelope
+}
+
+// webhookDeviceID returns the JID of the device the account is paired with, if any
+func webhookDeviceID(accountID string) string {
+	client, err := GetClientForAccount(context.Background(), accountID)
+	if err != nil || client == nil || client.Store == nil || client.Store.ID == nil {
+		return ""
+	}
+	return client.Store.ID.String()
+}
@@ -0,0 +1,148 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	webhookPollInterval   = 2 * time.Second
+	webhookDeliveryLease  = 2 * time.Minute
+	webhookRetryBaseDelay = 5 * time.Second
+	webhookRetryMaxDelay  = 30 * time.Minute
+	webhookClaimPerWorker = 5
+)
+
+// Global variable to hold the webhook outbox reference
+var globalWebhookOutbox domainWebhook.IWebhookRepository
+
+// webhookWake lets newly enqueued deliveries skip the poll interval
+var webhookWake = make(chan struct{}, 1)
+
+// SetWebhookOutbox sets the global webhook outbox repository
+func SetWebhookOutbox(repo domainWebhook.IWebhookRepository) {
+	globalWebhookOutbox = repo
+}
+
+// GetWebhookOutboxFromGlobalVars returns the webhook outbox, nil when webhooks are delivered directly
+func GetWebhookOutboxFromGlobalVars() domainWebhook.IWebhookRepository {
+	return globalWebhookOutbox
+}
+
+func notifyWebhookWorkers() {
+	select {
+	case webhookWake <- struct{}{}:
+	default:
+	}
+}
+
+// StartWebhookWorkers drains the webhook outbox with a pool of delivery workers until ctx is cancelled.
+// Deliveries left over from a previous run are picked up on the first poll.
+func StartWebhookWorkers(ctx context.Context) {
+	outbox := GetWebhookOutboxFromGlobalVars()
+	if outbox == nil {
+		return
+	}
+
+	workers := config.WhatsappWebhookWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	batchSize := workers * webhookClaimPerWorker
+
+	jobs := make(chan *domainWebhook.Delivery)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range jobs {
+				processWebhookDelivery(ctx, outbox, delivery)
+			}
+		}()
+	}
+
+	logrus.Infof("[WEBHOOK_OUTBOX] Started %d delivery worker(s)", workers)
+
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		deliveries, err := outbox.ClaimDueDeliveries(time.Now(), webhookDeliveryLease, batchSize)
+		if err != nil {
+			logrus.Errorf("[WEBHOOK_OUTBOX] Failed to claim deliveries: %v", err)
+		}
+	dispatch:
+		for _, delivery := range deliveries {
+			select {
+			case jobs <- delivery:
+			case <-ctx.Done():
+				// Deliveries not handed out keep their lease and are picked up again after it
+				break dispatch
+			}
+		}
+
+		// A full batch means there is probably more work waiting, unless the outbox is stopping
+		if len(deliveries) == batchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			close(jobs)
+			wg.Wait()
+			return
+		case <-ticker.C:
+		case <-webhookWake:
+		}
+	}
+}
+
+// processWebhookDelivery makes one attempt and either removes, reschedules or dead-letters the delivery
+func processWebhookDelivery(ctx context.Context, outbox domainWebhook.IWebhookRepository, delivery *domainWebhook.Delivery) {
+	attempts := delivery.Attempts + 1
+
+	err := postWebhook(ctx, delivery.URL, delivery.Event, []byte(delivery.Payload), delivery.Signature)
+	metrics.WebhookAttempt(delivery.AccountID, delivery.Event, err)
+	if err == nil {
+		if err := outbox.DeleteDelivery(delivery.ID); err != nil {
+			logrus.Errorf("[WEBHOOK_OUTBOX] Failed to remove delivered webhook %d: %v", delivery.ID, err)
+		}
+		logrus.Infof("Successfully submitted webhook on attempt %d", attempts)
+		return
+	}
+
+	if time.Since(delivery.CreatedAt) >= config.WhatsappWebhookMaxAge {
+		if err := outbox.MarkDead(delivery.ID, attempts, err.Error()); err != nil {
+			logrus.Errorf("[WEBHOOK_OUTBOX] Failed to dead-letter webhook %d: %v", delivery.ID, err)
+		}
+		metrics.WebhookDeadLetter(delivery.AccountID, delivery.Event)
+		logrus.Errorf("[WEBHOOK_OUTBOX] Webhook %d to %s moved to dead letters after %d attempts: %v", delivery.ID, delivery.URL, attempts, err)
+		return
+	}
+
+	nextAttemptAt := time.Now().Add(webhookRetryDelay(attempts))
+	if err := outbox.ScheduleRetry(delivery.ID, attempts, nextAttemptAt, err.Error()); err != nil {
+		logrus.Errorf("[WEBHOOK_OUTBOX] Failed to reschedule webhook %d: %v", delivery.ID, err)
+	}
+	metrics.WebhookRetry(delivery.AccountID, delivery.Event)
+	logrus.Warnf("Attempt %d to submit webhook %d failed, retrying at %s: %v", attempts, delivery.ID, nextAttemptAt.Format(time.RFC3339), err)
+}
+
+// webhookRetryDelay doubles the base delay for every failed attempt, up to the max delay
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookRetryMaxDelay {
+			return webhookRetryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,60 @@
+package whatsapp
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	infraWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/webhook"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessWebhookDeliveryMaxAge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "storage.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	outbox := infraWebhook.NewSQLiteRepository(db)
+
+	enqueue := func() *domainWebhook.Delivery {
+		delivery := &domainWebhook.Delivery{AccountID: "a", Event: domainWebhook.EventMessage, URL: server.URL, Payload: "{}"}
+		require.NoError(t, outbox.EnqueueDelivery(delivery))
+		return delivery
+	}
+
+	// A failed delivery younger than the max age is retried later
+	young := enqueue()
+	processWebhookDelivery(context.Background(), outbox, young)
+
+	deliveries, err := outbox.ClaimDueDeliveries(time.Now().Add(time.Hour), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, young.ID, deliveries[0].ID)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, "webhook returned status 500", deliveries[0].LastError)
+
+	// Once it failed for longer than the max age it moves to the dead letters
+	old := enqueue()
+	old.Attempts = 4
+	old.CreatedAt = time.Now().Add(-config.WhatsappWebhookMaxAge - time.Minute)
+	processWebhookDelivery(context.Background(), outbox, old)
+
+	dead, err := outbox.ListDeadDeliveries("a", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, old.ID, dead[0].ID)
+	assert.Equal(t, 5, dead[0].Attempts)
+	assert.Equal(t, "webhook returned status 500", dead[0].LastError)
+}
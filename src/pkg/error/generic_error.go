@@ -93,3 +93,20 @@ func (e RequestTimeoutError) ErrCode() string {
 func (e RequestTimeoutError) StatusCode() int {
 	return http.StatusRequestTimeout
 }
+
+type ForbiddenError string
+
+// Error for complying the error interface
+func (e ForbiddenError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e ForbiddenError) ErrCode() string {
+	return "FORBIDDEN"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e ForbiddenError) StatusCode() int {
+	return http.StatusForbidden
+}
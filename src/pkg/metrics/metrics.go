@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+const namespace = "whatsapp"
+
+// Registry holds every collector of the application, it is served on /metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	messagesSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_sent_total",
+		Help:      "Messages sent, by account and media type.",
+	}, []string{"account_id", "media_type"})
+
+	messageSendFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "message_send_failures_total",
+		Help:      "Messages that failed to send, by account and media type.",
+	}, []string{"account_id", "media_type"})
+
+	messageSendDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "message_send_duration_seconds",
+		Help:      "Time taken by WhatsApp to accept a sent message, by account and media type.",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+	}, []string{"account_id", "media_type"})
+
+	messagesReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_received_total",
+		Help:      "Messages received from other users, by account and media type.",
+	}, []string{"account_id", "media_type"})
+
+	webhookEnqueued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Webhook payloads submitted for delivery, by account and event.",
+	}, []string{"account_id", "event"})
+
+	webhookAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_attempts_total",
+		Help:      "Webhook delivery attempts, by account and event.",
+	}, []string{"account_id", "event"})
+
+	webhookFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_failures_total",
+		Help:      "Webhook delivery attempts that failed, by account and event.",
+	}, []string{"account_id", "event"})
+
+	webhookRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_retries_total",
+		Help:      "Webhook deliveries retried after a failed attempt, by account and event.",
+	}, []string{"account_id", "event"})
+
+	webhookDeadLetters = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_dead_letters_total",
+		Help:      "Webhook deliveries given up on and moved to the dead letters, by account and event.",
+	}, []string{"account_id", "event"})
+
+	historySyncs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "history_syncs_total",
+		Help:      "History sync blobs received, by account and sync type.",
+	}, []string{"account_id", "sync_type"})
+
+	historySyncConversations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "history_sync_conversations_total",
+		Help:      "Conversations received through history sync, by account.",
+	}, []string{"account_id"})
+
+	historySyncMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "history_sync_messages_total",
+		Help:      "Messages received through history sync, by account.",
+	}, []string{"account_id"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		messagesSent, messageSendFailures, messageSendDuration, messagesReceived,
+		webhookEnqueued, webhookAttempts, webhookFailures, webhookRetries, webhookDeadLetters,
+		historySyncs, historySyncConversations, historySyncMessages,
+		state,
+	)
+}
+
+// MediaType labels a message by the kind of media it carries, text for anything else
+func MediaType(msg *waE2E.Message) string {
+	if mediaType, _, _, _, _, _, _ := utils.ExtractMediaInfo(msg); mediaType != "" {
+		return mediaType
+	}
+	switch {
+	case msg.GetLocationMessage() != nil, msg.GetLiveLocationMessage() != nil:
+		return "location"
+	case msg.GetContactMessage() != nil, msg.GetContactsArrayMessage() != nil:
+		return "contact"
+	case msg.GetReactionMessage() != nil:
+		return "reaction"
+	case msg.GetPollCreationMessageV3() != nil:
+		return "poll"
+	default:
+		return "text"
+	}
+}
+
+// ObserveMessageSent records a send attempt of the account along with how long it took
+func ObserveMessageSent(accountID string, msg *waE2E.Message, duration time.Duration, err error) {
+	mediaType := MediaType(msg)
+	messageSendDuration.WithLabelValues(accountID, mediaType).Observe(duration.Seconds())
+	if err != nil {
+		messageSendFailures.WithLabelValues(accountID, mediaType).Inc()
+		return
+	}
+	messagesSent.WithLabelValues(accountID, mediaType).Inc()
+}
+
+// MessageReceived records a message of another user received by the account
+func MessageReceived(accountID string, msg *waE2E.Message) {
+	messagesReceived.WithLabelValues(accountID, MediaType(msg)).Inc()
+}
+
+// WebhookEnqueued records a payload submitted for delivery to a webhook
+func WebhookEnqueued(accountID, event string) {
+	webhookEnqueued.WithLabelValues(accountID, event).Inc()
+}
+
+// WebhookAttempt records a single delivery attempt and whether it failed
+func WebhookAttempt(accountID, event string, err error) {
+	webhookAttempts.WithLabelValues(accountID, event).Inc()
+	if err != nil {
+		webhookFailures.WithLabelValues(accountID, event).Inc()
+	}
+}
+
+// WebhookRetry records a failed delivery that will be attempted again
+func WebhookRetry(accountID, event string) {
+	webhookRetries.WithLabelValues(accountID, event).Inc()
+}
+
+// WebhookDeadLetter records a delivery moved to the dead letters
+func WebhookDeadLetter(accountID, event string) {
+	webhookDeadLetters.WithLabelValues(accountID, event).Inc()
+}
+
+// HistorySync records a history sync blob with the number of conversations and messages it carried
+func HistorySync(accountID, syncType string, conversations, messages int) {
+	historySyncs.WithLabelValues(accountID, syncType).Inc()
+	historySyncConversations.WithLabelValues(accountID).Add(float64(conversations))
+	historySyncMessages.WithLabelValues(accountID).Add(float64(messages))
+}
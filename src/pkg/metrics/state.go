@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// ConnectionState is the state of a client at the time of a scrape
+type ConnectionState struct {
+	AccountID string
+	Connected bool
+	LoggedIn  bool
+}
+
+// stateCollector reads the gauges that are cheaper to look up on scrape than to keep in sync:
+// the connection state of every client and the size of the chat storage and media folders
+type stateCollector struct {
+	mu               sync.RWMutex
+	connectionStates func() []ConnectionState
+	chatStorageSize  func() (int64, error)
+
+	connected        *prometheus.Desc
+	loggedIn         *prometheus.Desc
+	chatStorageBytes *prometheus.Desc
+	mediaBytes       *prometheus.Desc
+	mediaFiles       *prometheus.Desc
+}
+
+var state = &stateCollector{
+	connected: prometheus.NewDesc(prometheus.BuildFQName(namespace, "account", "connected"),
+		"Whether the client of the account is connected to WhatsApp.", []string{"account_id"}, nil),
+	loggedIn: prometheus.NewDesc(prometheus.BuildFQName(namespace, "account", "logged_in"),
+		"Whether the client of the account is logged in.", []string{"account_id"}, nil),
+	chatStorageBytes: prometheus.NewDesc(prometheus.BuildFQName(namespace, "chat_storage", "size_bytes"),
+		"Size of the chat storage database.", nil, nil),
+	mediaBytes: prometheus.NewDesc(prometheus.BuildFQName(namespace, "media_storage", "size_bytes"),
+		"Size of the media and sent items folders.", nil, nil),
+	mediaFiles: prometheus.NewDesc(prometheus.BuildFQName(namespace, "media_storage", "files"),
+		"Number of files in the media and sent items folders.", nil, nil),
+}
+
+// SetConnectionStates sets the lookup of the clients reported on every scrape
+func SetConnectionStates(fn func() []ConnectionState) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.connectionStates = fn
+}
+
+// SetChatStorageSize sets the lookup of the chat storage database size
+func SetChatStorageSize(fn func() (int64, error)) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.chatStorageSize = fn
+}
+
+func (c *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.connected
+	ch <- c.loggedIn
+	ch <- c.chatStorageBytes
+	ch <- c.mediaBytes
+	ch <- c.mediaFiles
+}
+
+func (c *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	connectionStates, chatStorageSize := c.connectionStates, c.chatStorageSize
+	c.mu.RUnlock()
+
+	if connectionStates != nil {
+		for _, connection := range connectionStates() {
+			ch <- prometheus.MustNewConstMetric(c.connected, prometheus.GaugeValue, boolValue(connection.Connected), connection.AccountID)
+			ch <- prometheus.MustNewConstMetric(c.loggedIn, prometheus.GaugeValue, boolValue(connection.LoggedIn), connection.AccountID)
+		}
+	}
+
+	if chatStorageSize != nil {
+		if size, err := chatStorageSize(); err != nil {
+			logrus.Warnf("[METRICS] Failed to get chat storage size: %v", err)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.chatStorageBytes, prometheus.GaugeValue, float64(size))
+		}
+	}
+
+	if usage, err := utils.GetFolderUsage(config.PathMedia, config.PathSendItems); err != nil {
+		logrus.Warnf("[METRICS] Failed to get media folder size: %v", err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.mediaBytes, prometheus.GaugeValue, float64(usage.Bytes))
+		ch <- prometheus.MustNewConstMetric(c.mediaFiles, prometheus.GaugeValue, float64(usage.Files))
+	}
+}
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
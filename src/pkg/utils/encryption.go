@@ -0,0 +1,212 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted streams start with a header holding the format magic, the scrypt salt and a random nonce
+// prefix. The data follows in AES-256-GCM sealed chunks whose nonce ends with the chunk counter and a
+// last chunk flag, so reordered, dropped or truncated chunks fail to open.
+const (
+	encryptionMagic           = "WAGOENC1"
+	encryptionSaltSize        = 16
+	encryptionNoncePrefixSize = 7
+	encryptionChunkSize       = 64 * 1024
+)
+
+// ErrDecryption is returned when an encrypted stream can't be opened, the passphrase is wrong or the data was modified
+var ErrDecryption = errors.New("wrong passphrase or corrupted data")
+
+// NewEncryptWriter returns a writer that encrypts everything written to it with the passphrase.
+// Close must be called to write the final chunk, it doesn't close w.
+func NewEncryptWriter(w io.Writer, passphrase string) (io.WriteCloser, error) {
+	header := make([]byte, len(encryptionMagic)+encryptionSaltSize+encryptionNoncePrefixSize)
+	copy(header, encryptionMagic)
+	if _, err := rand.Read(header[len(encryptionMagic):]); err != nil {
+		return nil, err
+	}
+
+	salt := header[len(encryptionMagic) : len(encryptionMagic)+encryptionSaltSize]
+	aead, err := newEncryptionAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		w:      w,
+		aead:   aead,
+		prefix: header[len(header)-encryptionNoncePrefixSize:],
+		buf:    make([]byte, 0, encryptionChunkSize),
+	}, nil
+}
+
+// NewDecryptReader returns a reader of the data encrypted by NewEncryptWriter. Reads fail with
+// ErrDecryption once a chunk doesn't open, so the data read so far can't be trusted as complete.
+func NewDecryptReader(r io.Reader, passphrase string) (io.Reader, error) {
+	header := make([]byte, len(encryptionMagic)+encryptionSaltSize+encryptionNoncePrefixSize)
+	if _, err := io.ReadFull(r, header); err != nil || string(header[:len(encryptionMagic)]) != encryptionMagic {
+		return nil, fmt.Errorf("not an encrypted stream: %w", ErrDecryption)
+	}
+
+	aead, err := newEncryptionAEAD(passphrase, header[len(encryptionMagic):len(encryptionMagic)+encryptionSaltSize])
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		r:      r,
+		aead:   aead,
+		prefix: header[len(header)-encryptionNoncePrefixSize:],
+		sealed: make([]byte, encryptionChunkSize+aead.Overhead()),
+	}, nil
+}
+
+func newEncryptionAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptionNonce builds the nonce of a chunk from the stream prefix, the chunk counter and the last chunk flag
+func encryptionNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 0, encryptionNoncePrefixSize+5)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	buf     []byte
+	closed  bool
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to closed encrypt writer")
+	}
+
+	written := 0
+	for len(p) > 0 {
+		// A full chunk is only sealed once more data follows, the last chunk is sealed by Close
+		if len(e.buf) == encryptionChunkSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+		n := min(encryptionChunkSize-len(e.buf), len(p))
+		e.buf = append(e.buf, p[:n]...)
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.seal(true)
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	if e.counter == ^uint32(0) {
+		return errors.New("encrypted stream is too large")
+	}
+
+	sealed := e.aead.Seal(nil, encryptionNonce(e.prefix, e.counter, last), e.buf, nil)
+	if _, err := e.w.Write(sealed); err != nil {
+		return err
+	}
+	e.counter++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+type decryptReader struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	sealed  []byte
+	plain   []byte
+	done    bool
+	err     error
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		if d.done {
+			return 0, io.EOF
+		}
+		d.err = d.open()
+	}
+
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+// open reads and opens the next chunk. A short chunk has to be the last one, a full chunk is the
+// last one only when nothing follows it.
+func (d *decryptReader) open() error {
+	n, err := io.ReadFull(d.r, d.sealed)
+	switch {
+	case err == io.EOF:
+		// The previous chunk wasn't the last one, the stream was cut
+		return ErrDecryption
+	case err == io.ErrUnexpectedEOF:
+		return d.openChunk(d.sealed[:n], true)
+	case err != nil:
+		return err
+	}
+
+	if d.openChunk(d.sealed, false) == nil {
+		return nil
+	}
+	if err := d.openChunk(d.sealed, true); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(d.r, make([]byte, 1)); err != io.EOF {
+		return ErrDecryption
+	}
+	return nil
+}
+
+func (d *decryptReader) openChunk(sealed []byte, last bool) error {
+	plain, err := d.aead.Open(nil, encryptionNonce(d.prefix, d.counter, last), sealed, nil)
+	if err != nil {
+		return ErrDecryption
+	}
+	d.counter++
+	d.plain = plain
+	d.done = last
+	return nil
+}
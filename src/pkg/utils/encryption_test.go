@@ -0,0 +1,93 @@
+package utils_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type EncryptionTestSuite struct {
+	suite.Suite
+}
+
+func (suite *EncryptionTestSuite) encrypt(plain []byte, passphrase string) []byte {
+	var sealed bytes.Buffer
+	writer, err := utils.NewEncryptWriter(&sealed, passphrase)
+	suite.Require().NoError(err)
+	_, err = writer.Write(plain)
+	suite.Require().NoError(err)
+	suite.Require().NoError(writer.Close())
+	return sealed.Bytes()
+}
+
+func (suite *EncryptionTestSuite) decrypt(sealed []byte, passphrase string) ([]byte, error) {
+	reader, err := utils.NewDecryptReader(bytes.NewReader(sealed), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(reader)
+}
+
+func (suite *EncryptionTestSuite) TestRoundTrip() {
+	sizes := map[string]int{
+		"empty":          0,
+		"small":          100,
+		"one full chunk": 64 * 1024,
+		"several chunks": 3*64*1024 + 17,
+	}
+	for name, size := range sizes {
+		suite.Run(name, func() {
+			plain := make([]byte, size)
+			_, _ = rand.Read(plain)
+
+			sealed := suite.encrypt(plain, "correct horse battery staple")
+			if size > 0 {
+				suite.NotContains(string(sealed), string(plain[:min(size, 64)]))
+			}
+
+			opened, err := suite.decrypt(sealed, "correct horse battery staple")
+			suite.Require().NoError(err)
+			suite.Equal(len(plain), len(opened))
+			suite.True(bytes.Equal(plain, opened))
+		})
+	}
+}
+
+func (suite *EncryptionTestSuite) TestWrongPassphrase() {
+	sealed := suite.encrypt([]byte("device store"), "correct horse battery staple")
+
+	_, err := suite.decrypt(sealed, "wrong passphrase")
+	suite.ErrorIs(err, utils.ErrDecryption)
+}
+
+func (suite *EncryptionTestSuite) TestTamperedData() {
+	plain := make([]byte, 2*64*1024+10)
+	sealed := suite.encrypt(plain, "passphrase")
+
+	suite.Run("modified byte", func() {
+		modified := bytes.Clone(sealed)
+		modified[len(modified)/2] ^= 0xff
+		_, err := suite.decrypt(modified, "passphrase")
+		suite.ErrorIs(err, utils.ErrDecryption)
+	})
+
+	suite.Run("truncated at a chunk boundary", func() {
+		// Header (8 magic, 16 salt, 7 nonce prefix) followed by two full sealed chunks
+		truncated := sealed[:31+2*(64*1024+16)]
+		_, err := suite.decrypt(truncated, "passphrase")
+		suite.ErrorIs(err, utils.ErrDecryption)
+	})
+
+	suite.Run("not encrypted", func() {
+		_, err := suite.decrypt([]byte("plain zip archive"), "passphrase")
+		suite.ErrorIs(err, utils.ErrDecryption)
+	})
+}
+
+func TestEncryptionTestSuite(t *testing.T) {
+	suite.Run(t, new(EncryptionTestSuite))
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"image"
 	_ "image/gif"  // Register GIF format
@@ -9,11 +10,13 @@ import (
 	_ "image/png"  // For PNG encoding
 	"io"
 	"math"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -232,7 +235,11 @@ func ContainsMention(message string) []string {
 	return phoneNumbers
 }
 
-func DownloadImageFromURL(url string) ([]byte, string, error) {
+// ErrImageTooLarge is returned when a downloaded image exceeds the max size
+var ErrImageTooLarge = errors.New("image exceeds the max size")
+
+// DownloadImageFromURL downloads an image of at most maxSize bytes, e.g. the max image size of the sending account
+func DownloadImageFromURL(url string, maxSize int64) ([]byte, string, error) {
 	client := &http.Client{
 		Timeout: 30 * time.Second,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
@@ -257,11 +264,11 @@ func DownloadImageFromURL(url string) ([]byte, string, error) {
 		return nil, "", fmt.Errorf("invalid content type: %s", contentType)
 	}
 	// Check content length if available
-	if contentLength := response.ContentLength; contentLength > int64(config.WhatsappSettingMaxImageSize) {
-		return nil, "", fmt.Errorf("image size %d exceeds maximum allowed size %d", contentLength, config.WhatsappSettingMaxImageSize)
+	if contentLength := response.ContentLength; contentLength > maxSize {
+		return nil, "", fmt.Errorf("%w: size %d exceeds maximum allowed size %d", ErrImageTooLarge, contentLength, maxSize)
 	}
-	// Limit the size from config
-	reader := io.LimitReader(response.Body, int64(config.WhatsappSettingMaxImageSize))
+	// Read one byte past the max size, so an oversized body without content length isn't cut off silently
+	reader := io.LimitReader(response.Body, maxSize+1)
 	// Extract the file name from the URL and remove query parameters if present
 	segments := strings.Split(url, "/")
 	fileName := segments[len(segments)-1]
@@ -281,6 +288,9 @@ func DownloadImageFromURL(url string) ([]byte, string, error) {
 	if err != nil {
 		return nil, "", err
 	}
+	if int64(len(imageData)) > maxSize {
+		return nil, "", fmt.Errorf("%w: more than %d bytes", ErrImageTooLarge, maxSize)
+	}
 	return imageData, fileName, nil
 }
 
@@ -472,3 +482,212 @@ func FormatBusinessHourTime(timeValue any) string {
 
 	return fmt.Sprintf("%02d:%02d", hours, minutes)
 }
+
+// QuietHours is a daily window, in minutes since midnight, during which queued messages are held back
+type QuietHours struct {
+	Start int
+	End   int
+}
+
+// ParseQuietHours parses a "HH:MM-HH:MM" window such as "22:00-07:00". An empty value means no quiet hours.
+func ParseQuietHours(value string) (*QuietHours, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("quiet hours must use the HH:MM-HH:MM format, got %q", value)
+	}
+
+	var minutes [2]int
+	for i, part := range parts {
+		parsed, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("quiet hours must use the HH:MM-HH:MM format, got %q", value)
+		}
+		minutes[i] = parsed.Hour()*60 + parsed.Minute()
+	}
+
+	if minutes[0] == minutes[1] {
+		return nil, fmt.Errorf("quiet hours start and end must differ, got %q", value)
+	}
+
+	return &QuietHours{Start: minutes[0], End: minutes[1]}, nil
+}
+
+// Remaining returns how long the window still lasts at t, or zero when t is outside of it.
+// Windows whose end is before their start wrap past midnight.
+func (q QuietHours) Remaining(t time.Time) time.Duration {
+	now := t.Hour()*60 + t.Minute()
+
+	var left int
+	switch {
+	case q.Start < q.End && now >= q.Start && now < q.End:
+		left = q.End - now
+	case q.Start > q.End && now >= q.Start:
+		left = 24*60 - now + q.End
+	case q.Start > q.End && now < q.End:
+		left = q.End - now
+	default:
+		return 0
+	}
+
+	return time.Duration(left)*time.Minute - time.Duration(t.Second())*time.Second
+}
+
+// Backoff returns the delay before the given retry attempt (starting at 1). The delay doubles with
+// every attempt from min up to max, and a random half of it is dropped so retries don't align.
+func Backoff(attempt int, min, max time.Duration) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	if min <= 0 {
+		return 0
+	}
+
+	delay := max
+	if attempt <= 62 {
+		if exp := min << (attempt - 1); exp > 0 && exp < max {
+			delay = exp
+		}
+	}
+	if delay < min {
+		delay = min
+	}
+
+	half := delay / 2
+	return delay - half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// RenderPlaceholders replaces {{name}} placeholders in text with the matching variables.
+// Placeholders without a variable are left untouched and returned in order of first appearance.
+func RenderPlaceholders(text string, variables map[string]string) (rendered string, missing []string) {
+	seen := make(map[string]bool)
+	rendered = placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		if !seen[name] {
+			seen[name] = true
+			missing = append(missing, name)
+		}
+		return match
+	})
+	return rendered, missing
+}
+
+// FolderUsage is the number of files and bytes stored below one or more folders
+type FolderUsage struct {
+	Files int   `json:"files"`
+	Bytes int64 `json:"bytes"`
+}
+
+type prunableFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// collectFiles walks the folders and returns their files oldest first. Missing folders are skipped,
+// and so are dotfiles and dot folders, like the .gitignore files that keep the folders in the repository.
+func collectFiles(folders ...string) ([]prunableFile, error) {
+	var files []prunableFile
+	for _, folder := range folders {
+		err := filepath.WalkDir(folder, func(path string, entry os.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if path != folder && strings.HasPrefix(entry.Name(), ".") {
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+			files = append(files, prunableFile{path: path, size: info.Size(), modTime: info.ModTime()})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	return files, nil
+}
+
+// GetFolderUsage sums the files stored below the folders
+func GetFolderUsage(folders ...string) (FolderUsage, error) {
+	files, err := collectFiles(folders...)
+	if err != nil {
+		return FolderUsage{}, err
+	}
+
+	usage := FolderUsage{Files: len(files)}
+	for _, file := range files {
+		usage.Bytes += file.size
+	}
+	return usage, nil
+}
+
+// PruneFolders removes the files modified before the cutoff, then the oldest files until the folders
+// hold at most maxBytes. A zero cutoff or maxBytes disables that rule. Emptied subfolders are removed,
+// the folders themselves are kept. It returns what was removed.
+func PruneFolders(before time.Time, maxBytes int64, folders ...string) (FolderUsage, error) {
+	files, err := collectFiles(folders...)
+	if err != nil {
+		return FolderUsage{}, err
+	}
+
+	var total int64
+	for _, file := range files {
+		total += file.size
+	}
+
+	var removed FolderUsage
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		expired := !before.IsZero() && file.modTime.Before(before)
+		oversized := maxBytes > 0 && total > maxBytes
+		if !expired && !oversized {
+			// Files are sorted oldest first, so the rest are newer and fit the size cap
+			break
+		}
+		if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		total -= file.size
+		removed.Files++
+		removed.Bytes += file.size
+		dirs[filepath.Dir(file.path)] = true
+	}
+
+	roots := make(map[string]bool, len(folders))
+	for _, folder := range folders {
+		roots[filepath.Clean(folder)] = true
+	}
+	for dir := range dirs {
+		// os.Remove only succeeds on empty folders, so stop at the first one still in use
+		for !roots[filepath.Clean(dir)] && os.Remove(dir) == nil {
+			dir = filepath.Dir(dir)
+		}
+	}
+
+	return removed, nil
+}
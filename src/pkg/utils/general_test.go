@@ -206,7 +206,7 @@ func (suite *UtilsTestSuite) TestDownloadImageFromURL() {
 	}))
 	defer server.Close() // Ensure the server is closed when the test ends
 
-	imageData, fileName, err := utils.DownloadImageFromURL(server.URL + "/image.jpg")
+	imageData, fileName, err := utils.DownloadImageFromURL(server.URL+"/image.jpg", config.WhatsappSettingMaxImageSize)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), []byte("image data"), imageData)
 	assert.Equal(suite.T(), "image.jpg", fileName)
@@ -340,7 +340,7 @@ func (suite *UtilsTestSuite) TestDownloadImageFromURLEdgeCases() {
 	}))
 	defer server.Close()
 
-	_, _, err := utils.DownloadImageFromURL(server.URL)
+	_, _, err := utils.DownloadImageFromURL(server.URL, config.WhatsappSettingMaxImageSize)
 	assert.Error(suite.T(), err)
 	assert.Contains(suite.T(), err.Error(), "invalid content type")
 
@@ -350,7 +350,7 @@ func (suite *UtilsTestSuite) TestDownloadImageFromURLEdgeCases() {
 	}))
 	defer errorServer.Close()
 
-	_, _, err = utils.DownloadImageFromURL(errorServer.URL)
+	_, _, err = utils.DownloadImageFromURL(errorServer.URL, config.WhatsappSettingMaxImageSize)
 	assert.Error(suite.T(), err)
 	assert.Contains(suite.T(), err.Error(), "HTTP request failed")
 
@@ -363,7 +363,7 @@ func (suite *UtilsTestSuite) TestDownloadImageFromURLEdgeCases() {
 	}))
 	defer extServer.Close()
 
-	_, _, err = utils.DownloadImageFromURL(extServer.URL + "/image.gif")
+	_, _, err = utils.DownloadImageFromURL(extServer.URL+"/image.gif", config.WhatsappSettingMaxImageSize)
 	assert.Error(suite.T(), err)
 	assert.Contains(suite.T(), err.Error(), "unsupported file type")
 
@@ -382,25 +382,45 @@ func (suite *UtilsTestSuite) TestDownloadImageFromURLEdgeCases() {
 	defer validExtServer.Close()
 
 	// Test .jpg
-	data, filename, err := utils.DownloadImageFromURL(validExtServer.URL + "/test.jpg")
+	data, filename, err := utils.DownloadImageFromURL(validExtServer.URL+"/test.jpg", config.WhatsappSettingMaxImageSize)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "test.jpg", filename)
 	assert.Equal(suite.T(), []byte("valid image data"), data)
 
 	// Test .png
-	data, filename, err = utils.DownloadImageFromURL(validExtServer.URL + "/test.png")
+	data, filename, err = utils.DownloadImageFromURL(validExtServer.URL+"/test.png", config.WhatsappSettingMaxImageSize)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "test.png", filename)
 
 	// Test .webp
-	data, filename, err = utils.DownloadImageFromURL(validExtServer.URL + "/test.webp")
+	data, filename, err = utils.DownloadImageFromURL(validExtServer.URL+"/test.webp", config.WhatsappSettingMaxImageSize)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "test.webp", filename)
 
 	// Test filename extraction with query parameters
-	data, filename, err = utils.DownloadImageFromURL(validExtServer.URL + "/test.jpg?v=1&size=large")
+	data, filename, err = utils.DownloadImageFromURL(validExtServer.URL+"/test.jpg?v=1&size=large", config.WhatsappSettingMaxImageSize)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "test.jpg", filename)
+
+	// Test max size, known from the content length
+	_, _, err = utils.DownloadImageFromURL(validExtServer.URL+"/test.jpg", 8)
+	assert.ErrorIs(suite.T(), err, utils.ErrImageTooLarge)
+
+	// Test max size, without content length
+	chunkedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("valid "))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("image data"))
+	}))
+	defer chunkedServer.Close()
+
+	_, _, err = utils.DownloadImageFromURL(chunkedServer.URL+"/test.jpg", 8)
+	assert.ErrorIs(suite.T(), err, utils.ErrImageTooLarge)
+
+	data, _, err = utils.DownloadImageFromURL(chunkedServer.URL+"/test.jpg", 16)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []byte("valid image data"), data)
 }
 
 func (suite *UtilsTestSuite) TestDownloadAudioFromURL() {
@@ -604,6 +624,171 @@ func (suite *UtilsTestSuite) TestDownloadVideoFromURL() {
 	assert.Contains(suite.T(), err.Error(), "too many redirects")
 }
 
+func (suite *UtilsTestSuite) TestParseQuietHours() {
+	tests := []struct {
+		name    string
+		value   string
+		want    *utils.QuietHours
+		wantErr bool
+	}{
+		{name: "should return nil for empty value", value: "", want: nil},
+		{name: "should parse same day window", value: "12:30-13:45", want: &utils.QuietHours{Start: 750, End: 825}},
+		{name: "should parse window past midnight", value: "22:00-07:00", want: &utils.QuietHours{Start: 1320, End: 420}},
+		{name: "should fail without separator", value: "22:00", wantErr: true},
+		{name: "should fail with invalid time", value: "25:00-07:00", wantErr: true},
+		{name: "should fail with empty window", value: "07:00-07:00", wantErr: true},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got, err := utils.ParseQuietHours(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func (suite *UtilsTestSuite) TestQuietHoursRemaining() {
+	overnight := utils.QuietHours{Start: 22 * 60, End: 7 * 60}
+	daytime := utils.QuietHours{Start: 12 * 60, End: 13 * 60}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		window utils.QuietHours
+		at     time.Time
+		want   time.Duration
+	}{
+		{name: "should be outside overnight window", window: overnight, at: at(12, 0), want: 0},
+		{name: "should be inside overnight window before midnight", window: overnight, at: at(23, 0), want: 8 * time.Hour},
+		{name: "should be inside overnight window after midnight", window: overnight, at: at(6, 30), want: 30 * time.Minute},
+		{name: "should end at window end", window: overnight, at: at(7, 0), want: 0},
+		{name: "should be inside daytime window", window: daytime, at: at(12, 15), want: 45 * time.Minute},
+		{name: "should be outside daytime window", window: daytime, at: at(11, 59), want: 0},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.window.Remaining(tt.at))
+		})
+	}
+}
+
+func (suite *UtilsTestSuite) TestBackoff() {
+	tests := []struct {
+		name    string
+		attempt int
+		want    time.Duration
+	}{
+		{name: "should start at min", attempt: 1, want: 2 * time.Second},
+		{name: "should treat attempt below one as first", attempt: 0, want: 2 * time.Second},
+		{name: "should double every attempt", attempt: 4, want: 16 * time.Second},
+		{name: "should cap at max", attempt: 10, want: time.Minute},
+		{name: "should not overflow on large attempts", attempt: 200, want: time.Minute},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				got := utils.Backoff(tt.attempt, 2*time.Second, time.Minute)
+				assert.GreaterOrEqual(t, got, tt.want/2)
+				assert.LessOrEqual(t, got, tt.want)
+			}
+		})
+	}
+}
+
+func (suite *UtilsTestSuite) TestRenderPlaceholders() {
+	tests := []struct {
+		name        string
+		text        string
+		variables   map[string]string
+		want        string
+		wantMissing []string
+	}{
+		{name: "should keep text without placeholders", text: "Hello", want: "Hello"},
+		{name: "should replace placeholders", text: "Hi {{name}}, your code is {{ code }}", variables: map[string]string{"name": "Budi", "code": "42"}, want: "Hi Budi, your code is 42"},
+		{name: "should replace repeated placeholders", text: "{{name}} {{name}}", variables: map[string]string{"name": "Ani"}, want: "Ani Ani"},
+		{name: "should report missing placeholders once", text: "{{a}} {{b}} {{a}}", variables: map[string]string{"b": "x"}, want: "{{a}} x {{a}}", wantMissing: []string{"a"}},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			got, missing := utils.RenderPlaceholders(tt.text, tt.variables)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantMissing, missing)
+		})
+	}
+}
+
+func (suite *UtilsTestSuite) TestPruneFolders() {
+	now := time.Now()
+	writeFile := func(t *testing.T, path string, size int, age time.Duration) {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		assert.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+		assert.NoError(t, os.Chtimes(path, now.Add(-age), now.Add(-age)))
+	}
+	setup := func(t *testing.T) string {
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "628123", "2025-01-01", "old.jpg"), 100, 48*time.Hour)
+		writeFile(t, filepath.Join(root, "628123", "2025-01-02", "middle.jpg"), 200, 24*time.Hour)
+		writeFile(t, filepath.Join(root, "628123", "2025-01-03", "new.jpg"), 300, time.Hour)
+		return root
+	}
+
+	tests := []struct {
+		name        string
+		before      time.Time
+		maxBytes    int64
+		wantRemoved utils.FolderUsage
+		wantLeft    utils.FolderUsage
+	}{
+		{name: "should keep everything without rules", wantLeft: utils.FolderUsage{Files: 3, Bytes: 600}},
+		{name: "should remove files older than the cutoff", before: now.Add(-12 * time.Hour), wantRemoved: utils.FolderUsage{Files: 2, Bytes: 300}, wantLeft: utils.FolderUsage{Files: 1, Bytes: 300}},
+		{name: "should remove oldest files over the size cap", maxBytes: 500, wantRemoved: utils.FolderUsage{Files: 1, Bytes: 100}, wantLeft: utils.FolderUsage{Files: 2, Bytes: 500}},
+		{name: "should apply both rules", before: now.Add(-36 * time.Hour), maxBytes: 300, wantRemoved: utils.FolderUsage{Files: 2, Bytes: 300}, wantLeft: utils.FolderUsage{Files: 1, Bytes: 300}},
+	}
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			root := setup(t)
+			removed, err := utils.PruneFolders(tt.before, tt.maxBytes, root)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRemoved, removed)
+
+			left, err := utils.GetFolderUsage(root)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantLeft, left)
+
+			if tt.wantRemoved.Files > 0 {
+				_, err = os.Stat(filepath.Join(root, "628123", "2025-01-01"))
+				assert.True(t, os.IsNotExist(err), "emptied folders should be removed")
+			}
+			_, err = os.Stat(root)
+			assert.NoError(t, err, "the pruned folder itself should be kept")
+		})
+	}
+
+	suite.T().Run("should keep dotfiles", func(t *testing.T) {
+		root := setup(t)
+		writeFile(t, filepath.Join(root, ".gitignore"), 10, 48*time.Hour)
+		writeFile(t, filepath.Join(root, ".cache", "old.jpg"), 10, 48*time.Hour)
+
+		removed, err := utils.PruneFolders(now, 0, root)
+		assert.NoError(t, err)
+		assert.Equal(t, utils.FolderUsage{Files: 3, Bytes: 600}, removed)
+		assert.FileExists(t, filepath.Join(root, ".gitignore"))
+		assert.FileExists(t, filepath.Join(root, ".cache", "old.jpg"))
+	})
+
+	suite.T().Run("should skip missing folders", func(t *testing.T) {
+		usage, err := utils.GetFolderUsage(filepath.Join(t.TempDir(), "missing"))
+		assert.NoError(t, err)
+		assert.Equal(t, utils.FolderUsage{}, usage)
+	})
+}
+
 func TestUtilsTestSuite(t *testing.T) {
 	suite.Run(t, new(UtilsTestSuite))
 }
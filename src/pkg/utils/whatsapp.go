@@ -174,7 +174,7 @@ func ExtractMessageTextFromEvent(evt *events.Message) string {
 			messageText = "📊 " + messageText
 		}
 	} else if pollMessageV5 := evt.Message.GetPollCreationMessageV5(); pollMessageV5 != nil {
-		messageText = pollMessageV5.GetMessage().GetConversation()
+		messageText = pollMessageV5.GetName()
 		if messageText == "" {
 			messageText = "📊 Poll"
 		} else {
@@ -538,7 +538,7 @@ func SanitizePhone(phone *string) {
 func IsOnWhatsapp(client *whatsmeow.Client, jid string) bool {
 	// only check if the jid a user with @s.whatsapp.net
 	if strings.Contains(jid, "@s.whatsapp.net") {
-		data, err := client.IsOnWhatsApp([]string{jid})
+		data, err := client.IsOnWhatsApp(context.Background(), []string{jid})
 		if err != nil {
 			logrus.Error("Failed to check if user is on whatsapp: ", err)
 			return false
@@ -63,11 +63,14 @@ func (h *AppHandler) toolLoginWithQR() mcp.Tool {
 		mcp.WithReadOnlyHintAnnotation(false),
 		mcp.WithDestructiveHintAnnotation(true),
 		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("account_id",
+			mcp.Description("Account to log in. Leave empty for the default device."),
+		),
 	)
 }
 
-func (h *AppHandler) handleLoginWithQR(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	resp, err := h.appService.Login(ctx)
+func (h *AppHandler) handleLoginWithQR(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.appService.Login(ctx, request.GetString("account_id", ""))
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +105,9 @@ func (h *AppHandler) toolLoginWithCode() mcp.Tool {
 			mcp.Description("Phone number in international format (e.g. +628123456789)."),
 			mcp.Required(),
 		),
+		mcp.WithString("account_id",
+			mcp.Description("Account to log in. Leave empty for the default device."),
+		),
 	)
 }
 
@@ -112,7 +118,7 @@ func (h *AppHandler) handleLoginWithCode(ctx context.Context, request mcp.CallTo
 	}
 
 	trimmedPhone := strings.TrimSpace(phone)
-	pairCode, err := h.appService.LoginWithCode(ctx, trimmedPhone)
+	pairCode, err := h.appService.LoginWithCode(ctx, request.GetString("account_id", ""), trimmedPhone)
 	if err != nil {
 		return nil, err
 	}
@@ -134,11 +140,14 @@ func (h *AppHandler) toolLogout() mcp.Tool {
 		mcp.WithReadOnlyHintAnnotation(false),
 		mcp.WithDestructiveHintAnnotation(true),
 		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("account_id",
+			mcp.Description("Account to log out. Leave empty for the default device."),
+		),
 	)
 }
 
-func (h *AppHandler) handleLogout(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	if err := h.appService.Logout(ctx); err != nil {
+func (h *AppHandler) handleLogout(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := h.appService.Logout(ctx, request.GetString("account_id", "")); err != nil {
 		return nil, err
 	}
 
@@ -153,11 +162,14 @@ func (h *AppHandler) toolReconnect() mcp.Tool {
 		mcp.WithReadOnlyHintAnnotation(false),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("account_id",
+			mcp.Description("Account to reconnect. Leave empty for the default device."),
+		),
 	)
 }
 
-func (h *AppHandler) handleReconnect(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	if err := h.appService.Reconnect(ctx); err != nil {
+func (h *AppHandler) handleReconnect(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := h.appService.Reconnect(ctx, request.GetString("account_id", "")); err != nil {
 		return nil, err
 	}
 
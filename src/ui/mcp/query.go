@@ -32,6 +32,7 @@ func (h *QueryHandler) AddQueryTools(mcpServer *server.MCPServer) {
 	mcpServer.AddTool(h.toolListContacts(), h.handleListContacts)
 	mcpServer.AddTool(h.toolListChats(), h.handleListChats)
 	mcpServer.AddTool(h.toolGetChatMessages(), h.handleGetChatMessages)
+	mcpServer.AddTool(h.toolSearchMessages(), h.handleSearchMessages)
 	mcpServer.AddTool(h.toolDownloadMedia(), h.handleDownloadMedia)
 }
 
@@ -47,7 +48,7 @@ func (h *QueryHandler) toolListContacts() mcp.Tool {
 }
 
 func (h *QueryHandler) handleListContacts(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	resp, err := h.userService.MyListContacts(ctx)
+	resp, err := h.userService.MyListContacts(ctx, domainUser.AccountRequest{})
 	if err != nil {
 		return nil, err
 	}
@@ -64,6 +65,9 @@ func (h *QueryHandler) toolListChats() mcp.Tool {
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("account_id",
+			mcp.Description("Account whose chats are listed. Leave empty for the default device."),
+		),
 		mcp.WithNumber("limit",
 			mcp.Description("Maximum number of chats to return (default 25, max 100)."),
 			mcp.DefaultNumber(25),
@@ -96,10 +100,11 @@ func (h *QueryHandler) handleListChats(ctx context.Context, request mcp.CallTool
 	}
 
 	req := domainChat.ListChatsRequest{
-		Limit:    request.GetInt("limit", 25),
-		Offset:   request.GetInt("offset", 0),
-		Search:   request.GetString("search", ""),
-		HasMedia: hasMedia,
+		AccountID: request.GetString("account_id", ""),
+		Limit:     request.GetInt("limit", 25),
+		Offset:    request.GetInt("offset", 0),
+		Search:    request.GetString("search", ""),
+		HasMedia:  hasMedia,
 	}
 
 	resp, err := h.chatService.ListChats(ctx, req)
@@ -128,6 +133,9 @@ func (h *QueryHandler) toolGetChatMessages() mcp.Tool {
 			mcp.Description("The chat JID (e.g., 628123456789@s.whatsapp.net or group@g.us)."),
 			mcp.Required(),
 		),
+		mcp.WithString("account_id",
+			mcp.Description("Account that owns the chat. Leave empty for the default device."),
+		),
 		mcp.WithNumber("limit",
 			mcp.Description("Maximum number of messages to return (default 50, max 100)."),
 			mcp.DefaultNumber(50),
@@ -198,6 +206,7 @@ func (h *QueryHandler) handleGetChatMessages(ctx context.Context, request mcp.Ca
 	}
 
 	req := domainChat.GetChatMessagesRequest{
+		AccountID: request.GetString("account_id", ""),
 		ChatJID:   chatJID,
 		Limit:     request.GetInt("limit", 50),
 		Offset:    request.GetInt("offset", 0),
@@ -221,6 +230,106 @@ func (h *QueryHandler) handleGetChatMessages(ctx context.Context, request mcp.Ca
 	return mcp.NewToolResultStructured(resp, fallback), nil
 }
 
+func (h *QueryHandler) toolSearchMessages() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_search_messages",
+		mcp.WithDescription("Search messages across all chats of an account, best matches first, with the matched words highlighted in a snippet."),
+		mcp.WithTitleAnnotation("Search Messages"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("query",
+			mcp.Description("Words to search for. Every word has to match the start of a word in the message."),
+			mcp.Required(),
+		),
+		mcp.WithString("account_id",
+			mcp.Description("Account whose messages are searched. Leave empty for the default device."),
+		),
+		mcp.WithString("chat_jid",
+			mcp.Description("Only search this chat (e.g., 628123456789@s.whatsapp.net or group@g.us)."),
+		),
+		mcp.WithString("sender",
+			mcp.Description("Only return messages sent by this phone number or JID."),
+		),
+		mcp.WithString("media_type",
+			mcp.Description("Only return messages with this media type (image, video, audio, document, sticker)."),
+		),
+		mcp.WithBoolean("is_from_me",
+			mcp.Description("If provided, filter messages sent by you (true) or others (false)."),
+		),
+		mcp.WithString("start_time",
+			mcp.Description("Filter messages sent after this RFC3339 timestamp."),
+		),
+		mcp.WithString("end_time",
+			mcp.Description("Filter messages sent before this RFC3339 timestamp."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of messages to return (default 25, max 100)."),
+			mcp.DefaultNumber(25),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of matches to skip from the start (default 0)."),
+			mcp.DefaultNumber(0),
+		),
+	)
+}
+
+func (h *QueryHandler) handleSearchMessages(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := request.RequireString("query")
+	if err != nil {
+		return nil, err
+	}
+
+	var startTimePtr *string
+	startTime := strings.TrimSpace(request.GetString("start_time", ""))
+	if startTime != "" {
+		startTimePtr = &startTime
+	}
+
+	var endTimePtr *string
+	endTime := strings.TrimSpace(request.GetString("end_time", ""))
+	if endTime != "" {
+		endTimePtr = &endTime
+	}
+
+	var isFromMePtr *bool
+	if args := request.GetArguments(); args != nil {
+		if value, ok := args["is_from_me"]; ok {
+			parsed, err := toBool(value)
+			if err != nil {
+				return nil, err
+			}
+			isFromMePtr = &parsed
+		}
+	}
+
+	req := domainChat.SearchMessagesRequest{
+		AccountID: request.GetString("account_id", ""),
+		Query:     query,
+		ChatJID:   request.GetString("chat_jid", ""),
+		Sender:    request.GetString("sender", ""),
+		MediaType: request.GetString("media_type", ""),
+		IsFromMe:  isFromMePtr,
+		StartTime: startTimePtr,
+		EndTime:   endTimePtr,
+		Limit:     request.GetInt("limit", 25),
+		Offset:    request.GetInt("offset", 0),
+	}
+
+	resp, err := h.chatService.SearchMessages(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf(
+		"Found %d messages matching %q (showing %d)",
+		resp.Pagination.Total,
+		query,
+		len(resp.Data),
+	)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
 func (h *QueryHandler) toolDownloadMedia() mcp.Tool {
 	return mcp.NewTool(
 		"whatsapp_download_message_media",
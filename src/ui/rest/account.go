@@ -1,9 +1,17 @@
 package rest
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
 )
 
 func InitRestAccount(app fiber.Router, accountService account.IAccountUsecase) {
@@ -12,11 +20,17 @@ func InitRestAccount(app fiber.Router, accountService account.IAccountUsecase) {
 	app.Get("/accounts/:accountId", getAccount(accountService))
 	app.Delete("/accounts/:accountId", deleteAccount(accountService))
 	app.Post("/accounts/:accountId/login", loginAccount(accountService))
+	app.Get("/accounts/:accountId/login/stream", loginAccountStream(accountService))
 	app.Post("/accounts/:accountId/login-with-code", loginAccountWithCode(accountService))
 	app.Post("/accounts/:accountId/logout", logoutAccount(accountService))
 	app.Post("/accounts/:accountId/reconnect", reconnectAccount(accountService))
 	app.Post("/accounts/:accountId/webhook", setAccountWebhook(accountService))
 	app.Get("/accounts/:accountId/webhook", getAccountWebhook(accountService))
+	app.Get("/accounts/:accountId/history", getConnectionHistory(accountService))
+	app.Get("/accounts/:accountId/settings", getAccountSettings(accountService))
+	app.Patch("/accounts/:accountId/settings", updateAccountSettings(accountService))
+	app.Get("/accounts/:accountId/backup", backupAccount(accountService))
+	app.Post("/accounts/:accountId/restore", restoreAccount(accountService))
 }
 
 func createAccount(service account.IAccountUsecase) fiber.Handler {
@@ -172,6 +186,64 @@ func loginAccount(service account.IAccountUsecase) fiber.Handler {
 	}
 }
 
+// loginAccountStream pushes the QR codes of the login as Server-Sent Events until the pairing
+// succeeds, times out or fails. Leaving the stream cancels the login.
+func loginAccountStream(service account.IAccountUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		accountID := c.Params("accountId")
+		if accountID == "" {
+			return c.Status(400).JSON(utils.ResponseData{
+				Status:  400,
+				Code:    "BAD_REQUEST",
+				Message: "Account ID is required",
+			})
+		}
+
+		// The stream outlives the handler, so the login gets its own context cancelled when the stream ends
+		ctx, cancel := context.WithCancel(context.Background())
+		events, err := service.LoginAccountStream(ctx, accountID)
+		if err != nil {
+			cancel()
+			return c.Status(500).JSON(utils.ResponseData{
+				Status:  500,
+				Code:    "ERROR",
+				Message: err.Error(),
+			})
+		}
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+		c.Set("X-Accel-Buffering", "no")
+		c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+			defer cancel()
+
+			heartbeat := time.NewTicker(eventHeartbeatInterval)
+			defer heartbeat.Stop()
+
+			for {
+				select {
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					data, err := json.Marshal(event)
+					if err != nil {
+						return
+					}
+					fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Event, data)
+				case <-heartbeat.C:
+					_, _ = w.WriteString(": heartbeat\n\n")
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		}))
+		return nil
+	}
+}
+
 func loginAccountWithCode(service account.IAccountUsecase) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		accountID := c.Params("accountId")
@@ -365,4 +437,182 @@ func getAccountWebhook(service account.IAccountUsecase) fiber.Handler {
 			Results: webhook,
 		})
 	}
+}
+
+func getConnectionHistory(service account.IAccountUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		accountID := c.Params("accountId")
+		if accountID == "" {
+			return c.Status(400).JSON(utils.ResponseData{
+				Status:  400,
+				Code:    "BAD_REQUEST",
+				Message: "Account ID is required",
+			})
+		}
+
+		history, err := service.GetConnectionHistory(c.Context(), account.ConnectionHistoryRequest{
+			AccountID: accountID,
+			Limit:     c.QueryInt("limit", 0),
+			Offset:    c.QueryInt("offset", 0),
+		})
+		if err != nil {
+			return c.Status(500).JSON(utils.ResponseData{
+				Status:  500,
+				Code:    "ERROR",
+				Message: err.Error(),
+			})
+		}
+
+		return c.JSON(utils.ResponseData{
+			Status:  200,
+			Code:    "SUCCESS",
+			Message: "Success get connection history",
+			Results: history,
+		})
+	}
+}
+
+func getAccountSettings(service account.IAccountUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		accountID := c.Params("accountId")
+		if accountID == "" {
+			return c.Status(400).JSON(utils.ResponseData{
+				Status:  400,
+				Code:    "BAD_REQUEST",
+				Message: "Account ID is required",
+			})
+		}
+
+		settings, err := service.GetSettings(c.Context(), accountID)
+		if err != nil {
+			return c.Status(500).JSON(utils.ResponseData{
+				Status:  500,
+				Code:    "ERROR",
+				Message: err.Error(),
+			})
+		}
+
+		return c.JSON(utils.ResponseData{
+			Status:  200,
+			Code:    "SUCCESS",
+			Message: "Success get account settings",
+			Results: settings,
+		})
+	}
+}
+
+func updateAccountSettings(service account.IAccountUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		accountID := c.Params("accountId")
+		if accountID == "" {
+			return c.Status(400).JSON(utils.ResponseData{
+				Status:  400,
+				Code:    "BAD_REQUEST",
+				Message: "Account ID is required",
+			})
+		}
+
+		var req account.UpdateSettingsRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(utils.ResponseData{
+				Status:  400,
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+			})
+		}
+		req.AccountID = accountID
+
+		settings, err := service.UpdateSettings(c.Context(), req)
+		if err != nil {
+			return c.Status(500).JSON(utils.ResponseData{
+				Status:  500,
+				Code:    "ERROR",
+				Message: err.Error(),
+			})
+		}
+
+		return c.JSON(utils.ResponseData{
+			Status:  200,
+			Code:    "SUCCESS",
+			Message: "Account settings updated successfully",
+			Results: settings,
+		})
+	}
+}
+
+// backupAccount streams the encrypted backup as an attachment, the passphrase is read from a header
+// so it doesn't end up in access logs. Errors past the headers can only be logged.
+func backupAccount(service account.IAccountUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		accountID := c.Params("accountId")
+		if accountID == "" {
+			return c.Status(400).JSON(utils.ResponseData{
+				Status:  400,
+				Code:    "BAD_REQUEST",
+				Message: "Account ID is required",
+			})
+		}
+
+		backup, err := service.BackupAccount(c.UserContext(), account.BackupRequest{
+			AccountID:    accountID,
+			Passphrase:   c.Get("X-Backup-Passphrase"),
+			IncludeChats: c.QueryBool("include_chats", false),
+			Release:      c.QueryBool("release", false),
+		})
+		if err != nil {
+			return c.Status(500).JSON(utils.ResponseData{
+				Status:  500,
+				Code:    "ERROR",
+				Message: err.Error(),
+			})
+		}
+
+		c.Attachment(backup.Filename)
+		c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+		c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+			if err := backup.Write(w); err != nil {
+				logrus.WithError(err).WithField("account_id", accountID).Error("Failed to write account backup")
+			}
+			_ = w.Flush()
+		}))
+		return nil
+	}
+}
+
+func restoreAccount(service account.IAccountUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		accountID := c.Params("accountId")
+		if accountID == "" {
+			return c.Status(400).JSON(utils.ResponseData{
+				Status:  400,
+				Code:    "BAD_REQUEST",
+				Message: "Account ID is required",
+			})
+		}
+
+		request := account.RestoreRequest{
+			AccountID:  accountID,
+			Passphrase: c.FormValue("passphrase"),
+			Force:      c.FormValue("force") == "true",
+		}
+		if backup, err := c.FormFile("backup"); err == nil {
+			request.Backup = backup
+		}
+
+		response, err := service.RestoreAccount(c.UserContext(), request)
+		if err != nil {
+			return c.Status(500).JSON(utils.ResponseData{
+				Status:  500,
+				Code:    "ERROR",
+				Message: err.Error(),
+			})
+		}
+
+		return c.JSON(utils.ResponseData{
+			Status:  200,
+			Code:    "SUCCESS",
+			Message: "Account restored successfully",
+			Results: response,
+		})
+	}
 }
\ No newline at end of file
@@ -0,0 +1,62 @@
+package rest
+
+import (
+	domainApiKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKey struct {
+	Service domainApiKey.IAPIKeyUsecase
+}
+
+func InitRestAPIKey(app fiber.Router, service domainApiKey.IAPIKeyUsecase) APIKey {
+	rest := APIKey{Service: service}
+
+	app.Get("/api-keys", rest.ListAPIKeys)
+	app.Post("/api-keys", rest.CreateAPIKey)
+	app.Delete("/api-keys/:keyId", rest.RevokeAPIKey)
+
+	return rest
+}
+
+func (controller *APIKey) ListAPIKeys(c *fiber.Ctx) error {
+	response, err := controller.Service.ListAPIKeys(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get API keys",
+		Results: response,
+	})
+}
+
+func (controller *APIKey) CreateAPIKey(c *fiber.Ctx) error {
+	var request domainApiKey.CreateAPIKeyRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.CreateAPIKey(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success create API key, store the key now as it won't be shown again",
+		Results: response,
+	})
+}
+
+func (controller *APIKey) RevokeAPIKey(c *fiber.Ctx) error {
+	request := domainApiKey.RevokeAPIKeyRequest{KeyID: c.Params("keyId")}
+
+	err := controller.Service.RevokeAPIKey(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success revoke API key",
+	})
+}
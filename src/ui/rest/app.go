@@ -27,7 +27,7 @@ func InitRestApp(app fiber.Router, service domainApp.IAppUsecase) App {
 }
 
 func (handler *App) Login(c *fiber.Ctx) error {
-	response, err := handler.Service.Login(c.UserContext())
+	response, err := handler.Service.Login(c.UserContext(), c.Query("account_id"))
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -42,7 +42,7 @@ func (handler *App) Login(c *fiber.Ctx) error {
 }
 
 func (handler *App) LoginWithCode(c *fiber.Ctx) error {
-	pairCode, err := handler.Service.LoginWithCode(c.UserContext(), c.Query("phone"))
+	pairCode, err := handler.Service.LoginWithCode(c.UserContext(), c.Query("account_id"), c.Query("phone"))
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -56,7 +56,7 @@ func (handler *App) LoginWithCode(c *fiber.Ctx) error {
 }
 
 func (handler *App) Logout(c *fiber.Ctx) error {
-	err := handler.Service.Logout(c.UserContext())
+	err := handler.Service.Logout(c.UserContext(), c.Query("account_id"))
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -68,7 +68,7 @@ func (handler *App) Logout(c *fiber.Ctx) error {
 }
 
 func (handler *App) Reconnect(c *fiber.Ctx) error {
-	err := handler.Service.Reconnect(c.UserContext())
+	err := handler.Service.Reconnect(c.UserContext(), c.Query("account_id"))
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -80,7 +80,7 @@ func (handler *App) Reconnect(c *fiber.Ctx) error {
 }
 
 func (handler *App) Devices(c *fiber.Ctx) error {
-	devices, err := handler.Service.FetchDevices(c.UserContext())
+	devices, err := handler.Service.FetchDevices(c.UserContext(), c.Query("account_id"))
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -93,6 +93,15 @@ func (handler *App) Devices(c *fiber.Ctx) error {
 
 func (handler *App) ConnectionStatus(c *fiber.Ctx) error {
 	isConnected, isLoggedIn, deviceID := whatsapp.GetConnectionStatus()
+	if accountID := c.Query("account_id"); accountID != "" {
+		client, err := whatsapp.GetClientForAccount(c.UserContext(), accountID)
+		utils.PanicIfNeeded(err)
+
+		isConnected, isLoggedIn, deviceID = client.IsConnected(), client.IsLoggedIn(), ""
+		if client.Store != nil && client.Store.ID != nil {
+			deviceID = client.Store.ID.String()
+		}
+	}
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
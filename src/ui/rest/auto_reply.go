@@ -0,0 +1,124 @@
+package rest
+
+import (
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AutoReply struct {
+	Service domainAutoReply.IAutoReplyUsecase
+}
+
+func InitRestAutoReply(app fiber.Router, service domainAutoReply.IAutoReplyUsecase) AutoReply {
+	rest := AutoReply{Service: service}
+
+	app.Get("/accounts/:accountId/auto-replies", rest.ListRules)
+	app.Post("/accounts/:accountId/auto-replies", rest.CreateRule)
+	app.Post("/accounts/:accountId/auto-replies/test", rest.TestRules)
+	app.Get("/accounts/:accountId/auto-replies/:ruleId", rest.GetRule)
+	app.Put("/accounts/:accountId/auto-replies/:ruleId", rest.UpdateRule)
+	app.Delete("/accounts/:accountId/auto-replies/:ruleId", rest.DeleteRule)
+
+	return rest
+}
+
+func (controller *AutoReply) ListRules(c *fiber.Ctx) error {
+	response, err := controller.Service.ListRules(c.UserContext(), c.Params("accountId"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get auto-reply rules",
+		Results: response,
+	})
+}
+
+func (controller *AutoReply) CreateRule(c *fiber.Ctx) error {
+	var request domainAutoReply.RuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.AccountID = c.Params("accountId")
+
+	response, err := controller.Service.CreateRule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success create auto-reply rule",
+		Results: response,
+	})
+}
+
+func (controller *AutoReply) GetRule(c *fiber.Ctx) error {
+	request := domainAutoReply.RuleIdentifierRequest{
+		AccountID: c.Params("accountId"),
+		RuleID:    c.Params("ruleId"),
+	}
+
+	response, err := controller.Service.GetRule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get auto-reply rule",
+		Results: response,
+	})
+}
+
+func (controller *AutoReply) UpdateRule(c *fiber.Ctx) error {
+	var request domainAutoReply.RuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.AccountID = c.Params("accountId")
+	request.RuleID = c.Params("ruleId")
+
+	response, err := controller.Service.UpdateRule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update auto-reply rule",
+		Results: response,
+	})
+}
+
+func (controller *AutoReply) DeleteRule(c *fiber.Ctx) error {
+	request := domainAutoReply.RuleIdentifierRequest{
+		AccountID: c.Params("accountId"),
+		RuleID:    c.Params("ruleId"),
+	}
+
+	err := controller.Service.DeleteRule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success delete auto-reply rule",
+	})
+}
+
+func (controller *AutoReply) TestRules(c *fiber.Ctx) error {
+	var request domainAutoReply.TestRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.AccountID = c.Params("accountId")
+
+	response, err := controller.Service.TestRules(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success test auto-reply rules",
+		Results: response,
+	})
+}
@@ -1,9 +1,13 @@
 package rest
 
 import (
+	"bufio"
+
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
 )
 
 type Chat struct {
@@ -15,8 +19,11 @@ func InitRestChat(app fiber.Router, service domainChat.IChatUsecase) Chat {
 
 	// Chat endpoints
 	app.Get("/chats", rest.ListChats)
+	app.Get("/chats/export", rest.ExportChats)
 	app.Get("/chat/:chat_jid/messages", rest.GetChatMessages)
+	app.Get("/chat/:chat_jid/export", rest.ExportChat)
 	app.Post("/chat/:chat_jid/pin", rest.PinChat)
+	app.Get("/messages/search", rest.SearchMessages)
 
 	return rest
 }
@@ -29,6 +36,7 @@ func (controller *Chat) ListChats(c *fiber.Ctx) error {
 	request.Offset = c.QueryInt("offset", 0)
 	request.Search = c.Query("search", "")
 	request.HasMedia = c.QueryBool("has_media", false)
+	request.AccountID = c.Query("account_id")
 
 	response, err := controller.Service.ListChats(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -52,6 +60,7 @@ func (controller *Chat) GetChatMessages(c *fiber.Ctx) error {
 	request.Offset = c.QueryInt("offset", 0)
 	request.MediaOnly = c.QueryBool("media_only", false)
 	request.Search = c.Query("search", "")
+	request.AccountID = c.Query("account_id")
 
 	// Parse time filters
 	if startTime := c.Query("start_time"); startTime != "" {
@@ -78,6 +87,44 @@ func (controller *Chat) GetChatMessages(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *Chat) SearchMessages(c *fiber.Ctx) error {
+	var request domainChat.SearchMessagesRequest
+
+	// Parse query parameters
+	request.Query = c.Query("q")
+	request.AccountID = c.Query("account_id")
+	request.ChatJID = c.Query("chat_jid")
+	request.Sender = c.Query("sender")
+	request.MediaType = c.Query("media_type")
+	request.MediaOnly = c.QueryBool("media_only", false)
+	request.Limit = c.QueryInt("limit", 25)
+	request.Offset = c.QueryInt("offset", 0)
+
+	// Parse time filters
+	if startTime := c.Query("start_time"); startTime != "" {
+		request.StartTime = &startTime
+	}
+	if endTime := c.Query("end_time"); endTime != "" {
+		request.EndTime = &endTime
+	}
+
+	// Parse is_from_me filter
+	if isFromMeStr := c.Query("is_from_me"); isFromMeStr != "" {
+		isFromMe := c.QueryBool("is_from_me")
+		request.IsFromMe = &isFromMe
+	}
+
+	response, err := controller.Service.SearchMessages(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success search messages",
+		Results: response,
+	})
+}
+
 func (controller *Chat) PinChat(c *fiber.Ctx) error {
 	var request domainChat.PinChatRequest
 
@@ -104,3 +151,51 @@ func (controller *Chat) PinChat(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+// ExportChat downloads one chat as JSON Lines, WhatsApp's text export or a zip with its media
+func (controller *Chat) ExportChat(c *fiber.Ctx) error {
+	request := exportChatsRequest(c)
+	request.ChatJID = c.Params("chat_jid")
+
+	return controller.streamExport(c, request)
+}
+
+// ExportChats downloads every chat of the account
+func (controller *Chat) ExportChats(c *fiber.Ctx) error {
+	return controller.streamExport(c, exportChatsRequest(c))
+}
+
+func exportChatsRequest(c *fiber.Ctx) domainChat.ExportChatsRequest {
+	request := domainChat.ExportChatsRequest{
+		AccountID:    c.Query("account_id"),
+		Format:       c.Query("format"),
+		IncludeMedia: c.QueryBool("include_media", false),
+	}
+
+	// Parse time filters
+	if startTime := c.Query("start_time"); startTime != "" {
+		request.StartTime = &startTime
+	}
+	if endTime := c.Query("end_time"); endTime != "" {
+		request.EndTime = &endTime
+	}
+
+	return request
+}
+
+// streamExport sends the export as an attachment while it is generated, errors past this point
+// can only be logged since the status has been sent already
+func (controller *Chat) streamExport(c *fiber.Ctx, request domainChat.ExportChatsRequest) error {
+	export, err := controller.Service.ExportChats(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	c.Attachment(export.Filename)
+	c.Set(fiber.HeaderContentType, export.ContentType)
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		if err := export.Write(w); err != nil {
+			logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to export chats")
+		}
+		_ = w.Flush()
+	}))
+	return nil
+}
@@ -0,0 +1,103 @@
+package rest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	domainEvent "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/event"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// eventHeartbeatInterval keeps idle streams open through proxies and notices clients that went away
+const eventHeartbeatInterval = 15 * time.Second
+
+type Event struct {
+	Service domainEvent.IEventUsecase
+}
+
+func InitRestEvent(app fiber.Router, service domainEvent.IEventUsecase) Event {
+	rest := Event{Service: service}
+	app.Get("/events", rest.Stream)
+	return rest
+}
+
+// Stream sends the events as Server-Sent Events. A reconnecting client resumes after the
+// Last-Event-ID header (or the cursor query) and first receives the events it missed.
+func (controller *Event) Stream(c *fiber.Ctx) error {
+	request := domainEvent.SubscribeRequest{
+		AccountID: c.Query("account_id"),
+	}
+	for _, event := range strings.Split(c.Query("events"), ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			request.Events = append(request.Events, event)
+		}
+	}
+
+	cursor := c.Get("Last-Event-ID")
+	if cursor == "" {
+		cursor = c.Query("cursor")
+	}
+	if cursor != "" {
+		id, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			panic(pkgError.ValidationError("cursor: must be an event id."))
+		}
+		request.Cursor = id
+	}
+
+	subscription, err := controller.Service.Subscribe(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		defer subscription.Close()
+
+		if subscription.Truncated {
+			fmt.Fprintf(w, "event: stream.truncated\ndata: {\"cursor\":%d}\n\n", request.Cursor)
+		}
+		for _, evt := range subscription.Backlog {
+			writeStreamEvent(w, evt)
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(eventHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case evt, ok := <-subscription.Events:
+				// The subscriber fell behind and was dropped, the client reconnects with its last event id
+				if !ok {
+					return
+				}
+				writeStreamEvent(w, evt)
+			case <-heartbeat.C:
+				_, _ = w.WriteString(": heartbeat\n\n")
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}))
+	return nil
+}
+
+func writeStreamEvent(w *bufio.Writer, evt domainEvent.Event) {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", evt.ID, evt.Event, data)
+}
@@ -5,20 +5,37 @@ import (
 	"mime/multipart"
 	"time"
 
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
-	"go.mau.fi/whatsmeow"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/sirupsen/logrus"
 )
 
 func SetAutoConnectAfterBooting(service domainApp.IAppUsecase) {
 	time.Sleep(2 * time.Second)
-	_ = service.Reconnect(context.Background())
+	_ = service.Reconnect(context.Background(), "")
 }
 
-func SetAutoReconnectChecking(cli *whatsmeow.Client) {
+// SetAutoRestoreAccountsAfterBooting reloads every registered account into the account manager
+func SetAutoRestoreAccountsAfterBooting(service domainAccount.IAccountUsecase) {
+	time.Sleep(2 * time.Second)
+	if err := service.RestoreAccounts(context.Background()); err != nil {
+		logrus.Errorf("Failed to restore accounts: %v", err)
+	}
+}
+
+// SetAutoReconnectChecking reconnects the legacy client when its connection was lost. Managed accounts
+// are watched by their own connection supervisor. The client is looked up on every check, as it is
+// replaced after a logout.
+func SetAutoReconnectChecking() {
 	// Run every 5 minutes to check if the connection is still alive, if not, reconnect
 	go func() {
 		for {
 			time.Sleep(5 * time.Minute)
+			cli := whatsapp.GetClient()
+			if cli == nil || cli.Store == nil || cli.Store.ID == nil {
+				continue
+			}
 			if !cli.IsConnected() {
 				_ = cli.Connect()
 			}
@@ -1,6 +1,8 @@
 package rest
 
 import (
+	"fmt"
+
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
@@ -22,6 +24,7 @@ func InitRestMessage(app fiber.Router, service domainMessage.IMessageUsecase) Me
 	app.Post("/message/:message_id/star", rest.StarMessage)
 	app.Post("/message/:message_id/unstar", rest.UnstarMessage)
 	app.Get("/message/:message_id/download", rest.DownloadMedia)
+	app.Get("/message/:message_id/status", rest.GetMessageStatus)
 	return rest
 }
 
@@ -164,6 +167,7 @@ func (controller *Message) DownloadMedia(c *fiber.Ctx) error {
 
 	request.MessageID = c.Params("message_id")
 	request.Phone = c.Query("phone")
+	request.AccountID = c.Query("account_id")
 	utils.SanitizePhone(&request.Phone)
 
 	response, err := controller.Service.DownloadMedia(c.UserContext(), request)
@@ -176,3 +180,20 @@ func (controller *Message) DownloadMedia(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Message) GetMessageStatus(c *fiber.Ctx) error {
+	var request domainMessage.MessageStatusRequest
+
+	request.MessageID = c.Params("message_id")
+	request.AccountID = c.Query("account_id")
+
+	response, err := controller.Service.GetMessageStatus(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Message %s is %s", response.MessageID, response.Status),
+		Results: response,
+	})
+}
@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainApiKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// HasAPIKey reports whether the request authenticates with an API key instead of basic auth
+func HasAPIKey(c *fiber.Ctx) bool {
+	return requestAPIKey(c) != ""
+}
+
+// APIKey authenticates requests carrying an X-Api-Key header or a Bearer token and checks
+// the scope of the route and every account_id of the request against the key.
+// Requests without a key are left to basic auth.
+func APIKey(service domainApiKey.IAPIKeyUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		plainKey := requestAPIKey(c)
+		if plainKey == "" {
+			return c.Next()
+		}
+
+		key, err := service.Authenticate(c.UserContext(), plainKey)
+		if err != nil {
+			panic(err)
+		}
+
+		path := strings.TrimPrefix(c.Path(), config.AppBasePath)
+		scope := requiredScope(c.Method(), path)
+		if !key.HasScope(scope) {
+			panic(pkgError.ForbiddenError(fmt.Sprintf("API key is missing the %s scope", scope)))
+		}
+
+		if instanceRoutes[path] && !key.AllowsAllAccounts() {
+			panic(pkgError.ForbiddenError(fmt.Sprintf("API key has to allow all accounts to access %s", path)))
+		}
+
+		accountIDs := requestAccountIDs(c, path)
+		if len(accountIDs) == 0 && !key.AllowsAllAccounts() {
+			panic(pkgError.ForbiddenError("account_id is required for this API key"))
+		}
+		for _, accountID := range accountIDs {
+			if !key.AllowsAccount(accountID) {
+				panic(pkgError.ForbiddenError(fmt.Sprintf("API key is not allowed to access account %s", accountID)))
+			}
+		}
+
+		c.SetUserContext(domainApiKey.WithAPIKey(c.UserContext(), key))
+
+		return c.Next()
+	}
+}
+
+// appSessionRoutes change the session of a device although they are GETs
+var appSessionRoutes = map[string]bool{
+	"/app/login":           true,
+	"/app/login-with-code": true,
+	"/app/logout":          true,
+	"/app/reconnect":       true,
+}
+
+// instanceRoutes report on every account of the instance, naming an account in the request doesn't
+// narrow them down, so they need a key that allows all accounts
+var instanceRoutes = map[string]bool{
+	"/metrics":           true,
+	"/app/storage":       true,
+	"/app/storage/prune": true,
+}
+
+func requestAPIKey(c *fiber.Ctx) string {
+	if key := c.Get("X-Api-Key"); key != "" {
+		return key
+	}
+	if auth := c.Get(fiber.HeaderAuthorization); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return ""
+}
+
+// requiredScope maps a route to a scope: key management needs admin, account management
+// reads need read and changes need admin, any other read needs read and the rest needs send.
+// The QR login stream is a read that logs the account in, so it needs admin like the other logins,
+// and a backup holds the session credentials, so it needs admin too. The session routes of /app
+// are GETs that log in, log out or reconnect a device, so they need admin as well. Dead letters of the
+// global webhooks hold events of every account, and the metrics and storage statistics cover every
+// account, so they need admin too.
+func requiredScope(method, path string) string {
+	switch {
+	case path == "/api-keys" || strings.HasPrefix(path, "/api-keys/"):
+		return domainApiKey.ScopeAdmin
+	case path == "/metrics" || path == "/app/storage":
+		return domainApiKey.ScopeAdmin
+	case strings.HasPrefix(path, "/webhook/dead-letters"):
+		return domainApiKey.ScopeAdmin
+	case path == "/accounts" || strings.HasPrefix(path, "/accounts/") || strings.HasPrefix(path, "/app/"):
+		if method == fiber.MethodGet && !strings.HasSuffix(path, "/login/stream") && !strings.HasSuffix(path, "/backup") && !appSessionRoutes[path] {
+			return domainApiKey.ScopeRead
+		}
+		return domainApiKey.ScopeAdmin
+	case method == fiber.MethodGet:
+		return domainApiKey.ScopeRead
+	default:
+		return domainApiKey.ScopeSend
+	}
+}
+
+// requestAccountIDs collects every account a request names: the :accountId path segment, every
+// account_id query parameter and every account_id body field. Keys are matched case-insensitively
+// and bodies are read by the same content type rules as BodyParser, so a handler can't be pointed
+// at another account through a value the key wasn't checked against.
+func requestAccountIDs(c *fiber.Ctx, path string) []string {
+	var accountIDs []string
+	add := func(key, value string) {
+		if value != "" && strings.EqualFold(key, "account_id") {
+			accountIDs = append(accountIDs, value)
+		}
+	}
+
+	if rest, ok := strings.CutPrefix(path, "/accounts/"); ok {
+		segment, _, _ := strings.Cut(rest, "/")
+		if accountID, err := url.PathUnescape(segment); err == nil {
+			add("account_id", accountID)
+		}
+	}
+
+	c.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+		add(string(key), string(value))
+	})
+
+	switch ctype := bodyContentType(c); {
+	case strings.HasSuffix(ctype, "json"):
+		for _, field := range jsonFields(c.Body()) {
+			add(field[0], field[1])
+		}
+	case ctype == fiber.MIMEApplicationForm:
+		c.Request().PostArgs().VisitAll(func(key, value []byte) {
+			add(string(key), string(value))
+		})
+	case ctype == fiber.MIMETextXML || ctype == fiber.MIMEApplicationXML:
+		// Request structs have no xml tags, so XML binds elements named like the field
+		for _, field := range xmlFields(c.Body()) {
+			if field[0] == "AccountID" {
+				add("account_id", field[1])
+			}
+		}
+	case ctype == fiber.MIMEMultipartForm:
+		if form, err := c.MultipartForm(); err == nil {
+			for key, values := range form.Value {
+				for _, value := range values {
+					add(key, value)
+				}
+			}
+		}
+	}
+
+	return accountIDs
+}
+
+// bodyContentType normalizes the content type the way BodyParser does before picking a decoder
+func bodyContentType(c *fiber.Ctx) string {
+	ctype := utils.ParseVendorSpecificContentType(strings.ToLower(string(c.Request().Header.ContentType())))
+	ctype, _, _ = strings.Cut(ctype, ";")
+	return ctype
+}
+
+// jsonFields returns every top level string field of a JSON object, duplicated keys included
+func jsonFields(body []byte) [][2]string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+
+	var fields [][2]string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return fields
+		}
+		key, _ := token.(string)
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return fields
+		}
+		var text string
+		if json.Unmarshal(value, &text) == nil {
+			fields = append(fields, [2]string{key, text})
+		}
+	}
+	return fields
+}
+
+// xmlFields returns the text of every element directly under the root element of an XML document
+func xmlFields(body []byte) [][2]string {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+
+	var fields [][2]string
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return fields
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 2 {
+				var text string
+				if decoder.DecodeElement(&text, &element) == nil {
+					fields = append(fields, [2]string{element.Name.Local, text})
+				}
+				depth--
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainApiKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAPIKeyUsecase struct {
+	domainApiKey.IAPIKeyUsecase
+	keys map[string]*domainApiKey.APIKey
+}
+
+func (f fakeAPIKeyUsecase) Authenticate(_ context.Context, key string) (*domainApiKey.APIKey, error) {
+	if apiKey, ok := f.keys[key]; ok {
+		return apiKey, nil
+	}
+	return nil, pkgError.AuthError("invalid or revoked API key")
+}
+
+func newAPIKeyTestApp() *fiber.App {
+	app := fiber.New()
+	app.Use(Recovery())
+	app.Use(APIKey(fakeAPIKeyUsecase{keys: map[string]*domainApiKey.APIKey{
+		"send-a":  {ID: "1", AccountIDs: []string{"a"}, Scopes: []string{domainApiKey.ScopeSend}},
+		"read-a":  {ID: "2", AccountIDs: []string{"a"}, Scopes: []string{domainApiKey.ScopeRead}},
+		"read-*":  {ID: "3", AccountIDs: []string{domainApiKey.AllAccounts}, Scopes: []string{domainApiKey.ScopeRead}},
+		"admin-*": {ID: "4", AccountIDs: []string{domainApiKey.AllAccounts}, Scopes: []string{domainApiKey.ScopeAdmin}},
+		"admin-a": {ID: "5", AccountIDs: []string{"a"}, Scopes: []string{domainApiKey.ScopeAdmin}},
+	}}))
+	app.All("/*", func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+	return app
+}
+
+func multipartBody(t *testing.T, fields map[string]string) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for key, value := range fields {
+		require.NoError(t, writer.WriteField(key, value))
+	}
+	require.NoError(t, writer.Close())
+	return &body, writer.FormDataContentType()
+}
+
+func TestAPIKeyAccountAccess(t *testing.T) {
+	formBody, formType := multipartBody(t, map[string]string{"account_id": "b", "phone": "628"})
+
+	tests := []struct {
+		name        string
+		key         string
+		method      string
+		target      string
+		contentType string
+		body        string
+		multipart   *bytes.Buffer
+		status      int
+	}{
+		{
+			name:   "should allow the account of the key",
+			key:    "send-a",
+			method: fiber.MethodPost,
+			target: "/send/message?account_id=a",
+			status: http.StatusOK,
+		},
+		{
+			name:   "should reject a request without account",
+			key:    "send-a",
+			method: fiber.MethodPost,
+			target: "/send/message",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "should reject another account in the path",
+			key:    "read-a",
+			method: fiber.MethodGet,
+			target: "/accounts/b/webhook",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "should reject a repeated query parameter",
+			key:    "send-a",
+			method: fiber.MethodPost,
+			target: "/send/message?account_id=a&account_id=b",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "should reject a query parameter in another case",
+			key:    "send-a",
+			method: fiber.MethodPost,
+			target: "/send/message?account_id=a&Account_ID=b",
+			status: http.StatusForbidden,
+		},
+		{
+			name:        "should reject a form value differing from the query",
+			key:         "send-a",
+			method:      fiber.MethodPost,
+			target:      "/send/message?account_id=a",
+			contentType: fiber.MIMEApplicationForm,
+			body:        "account_id=b",
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "should reject a multipart value differing from the query",
+			key:         "send-a",
+			method:      fiber.MethodPost,
+			target:      "/send/message?account_id=a",
+			contentType: formType,
+			multipart:   formBody,
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "should reject a JSON body with an upper case content type",
+			key:         "send-a",
+			method:      fiber.MethodPost,
+			target:      "/send/message?account_id=a",
+			contentType: "Application/JSON",
+			body:        `{"account_id":"b"}`,
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "should reject a text/json body",
+			key:         "send-a",
+			method:      fiber.MethodPost,
+			target:      "/send/message?account_id=a",
+			contentType: "text/json; charset=utf-8",
+			body:        `{"account_id":"b"}`,
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "should reject a vendor JSON body",
+			key:         "send-a",
+			method:      fiber.MethodPost,
+			target:      "/send/message?account_id=a",
+			contentType: "application/vnd.api+json",
+			body:        `{"account_id":"b"}`,
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "should reject a repeated JSON field",
+			key:         "send-a",
+			method:      fiber.MethodPost,
+			target:      "/send/message",
+			contentType: fiber.MIMEApplicationJSON,
+			body:        `{"account_id":"a","phone":"628","account_id":"b"}`,
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "should reject a JSON field in another case",
+			key:         "send-a",
+			method:      fiber.MethodPost,
+			target:      "/send/message",
+			contentType: fiber.MIMEApplicationJSON,
+			body:        `{"account_id":"a","ACCOUNT_ID":"b"}`,
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "should allow a JSON body of the key's account",
+			key:         "send-a",
+			method:      fiber.MethodPost,
+			target:      "/send/message",
+			contentType: fiber.MIMEApplicationJSON,
+			body:        `{"account_id":"a","phone":"628"}`,
+			status:      http.StatusOK,
+		},
+		{
+			name:        "should reject an XML body",
+			key:         "send-a",
+			method:      fiber.MethodPost,
+			target:      "/send/message?account_id=a",
+			contentType: fiber.MIMEApplicationXML,
+			body:        `<request><AccountID>b</AccountID></request>`,
+			status:      http.StatusForbidden,
+		},
+	}
+
+	app := newAPIKeyTestApp()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body *bytes.Buffer
+			if tt.multipart != nil {
+				body = bytes.NewBuffer(tt.multipart.Bytes())
+			} else {
+				body = bytes.NewBufferString(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.target, body)
+			req.Header.Set("X-Api-Key", tt.key)
+			if tt.contentType != "" {
+				req.Header.Set(fiber.HeaderContentType, tt.contentType)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
+
+func TestAPIKeyScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		target string
+		status int
+	}{
+		{name: "should allow reading devices", key: "read-*", target: "/app/devices", status: http.StatusOK},
+		{name: "should allow reading the status", key: "read-a", target: "/app/status?account_id=a", status: http.StatusOK},
+		{name: "should reject logging in", key: "read-*", target: "/app/login", status: http.StatusForbidden},
+		{name: "should reject logging in with a code", key: "read-a", target: "/app/login-with-code?account_id=a", status: http.StatusForbidden},
+		{name: "should reject logging out", key: "read-*", target: "/app/logout", status: http.StatusForbidden},
+		{name: "should reject reconnecting", key: "read-a", target: "/app/reconnect?account_id=a", status: http.StatusForbidden},
+		{name: "should reject the legacy device for an account key", key: "read-a", target: "/app/devices", status: http.StatusForbidden},
+		{name: "should reject global dead letters without admin", key: "read-*", target: "/webhook/dead-letters", status: http.StatusForbidden},
+		{name: "should reject global dead letters for an account key", key: "admin-a", target: "/webhook/dead-letters", status: http.StatusForbidden},
+		{name: "should allow global dead letters for an admin key", key: "admin-*", target: "/webhook/dead-letters", status: http.StatusOK},
+		{name: "should reject metrics without admin", key: "read-*", target: "/metrics", status: http.StatusForbidden},
+		{name: "should reject metrics for an account key", key: "admin-a", target: "/metrics?account_id=a", status: http.StatusForbidden},
+		{name: "should reject metrics for a read account key", key: "read-a", target: "/metrics?account_id=a", status: http.StatusForbidden},
+		{name: "should allow metrics for an admin key", key: "admin-*", target: "/metrics", status: http.StatusOK},
+		{name: "should reject storage statistics without admin", key: "read-*", target: "/app/storage", status: http.StatusForbidden},
+		{name: "should reject storage statistics for an account key", key: "admin-a", target: "/app/storage?account_id=a", status: http.StatusForbidden},
+		{name: "should reject pruning storage for an account key", key: "admin-a", target: "/app/storage/prune?account_id=a", status: http.StatusForbidden},
+		{name: "should allow storage statistics for an admin key", key: "admin-*", target: "/app/storage", status: http.StatusOK},
+		{name: "should allow retention policies for an account key", key: "read-a", target: "/app/storage/retention?account_id=a", status: http.StatusOK},
+	}
+
+	app := newAPIKeyTestApp()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, tt.target, strings.NewReader(""))
+			req.Header.Set("X-Api-Key", tt.key)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package rest
 
 import (
+	"bufio"
+	"encoding/json"
+
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
 )
 
 type Send struct {
@@ -24,6 +29,8 @@ func InitRestSend(app fiber.Router, service domainSend.ISendUsecase) Send {
 	app.Post("/send/poll", rest.SendPoll)
 	app.Post("/send/presence", rest.SendPresence)
 	app.Post("/send/chat-presence", rest.SendChatPresence)
+	app.Post("/send/bulk", rest.SendBulk)
+	app.Get("/send/jobs/:id", rest.GetJob)
 	return rest
 }
 
@@ -33,6 +40,7 @@ func (controller *Send) SendText(c *fiber.Ctx) error {
 	utils.PanicIfNeeded(err)
 
 	utils.SanitizePhone(&request.Phone)
+	parseTemplateVariables(c, &request.TemplateOptions)
 
 	response, err := controller.Service.SendText(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -58,6 +66,7 @@ func (controller *Send) SendImage(c *fiber.Ctx) error {
 	}
 
 	utils.SanitizePhone(&request.Phone)
+	parseTemplateVariables(c, &request.TemplateOptions)
 
 	response, err := controller.Service.SendImage(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -75,11 +84,16 @@ func (controller *Send) SendFile(c *fiber.Ctx) error {
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
 
+	// The file is optional when a template with media is used
 	file, err := c.FormFile("file")
-	utils.PanicIfNeeded(err)
+	if err == nil {
+		request.File = file
+	} else if request.TemplateID == "" {
+		utils.PanicIfNeeded(err)
+	}
 
-	request.File = file
 	utils.SanitizePhone(&request.Phone)
+	parseTemplateVariables(c, &request.TemplateOptions)
 
 	response, err := controller.Service.SendFile(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -266,3 +280,68 @@ func (controller *Send) SendChatPresence(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Send) GetJob(c *fiber.Ctx) error {
+	var request domainSend.JobRequest
+	request.JobID = c.Params("id")
+	request.AccountID = c.Query("account_id")
+
+	response, err := controller.Service.GetJob(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get send job",
+		Results: response,
+	})
+}
+
+// SendBulk streams one JSON line per recipient as soon as that recipient has been handled
+func (controller *Send) SendBulk(c *fiber.Ctx) error {
+	var request domainSend.BulkRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	// Multipart requests carry the recipients as a JSON encoded field
+	if recipients := c.FormValue("recipients"); len(request.Recipients) == 0 && recipients != "" {
+		if err := json.Unmarshal([]byte(recipients), &request.Recipients); err != nil {
+			panic(pkgError.ValidationError("recipients: must be a JSON array of {phone, variables}"))
+		}
+	}
+
+	if file, err := c.FormFile("image"); err == nil {
+		request.Image = file
+	}
+	if file, err := c.FormFile("file"); err == nil {
+		request.File = file
+	}
+
+	for i := range request.Recipients {
+		utils.SanitizePhone(&request.Recipients[i].Phone)
+	}
+
+	results, err := controller.Service.SendBulk(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		encoder := json.NewEncoder(w)
+		// Keep draining after the client goes away so the sender is never blocked
+		for result := range results {
+			if err := encoder.Encode(result); err == nil {
+				_ = w.Flush()
+			}
+		}
+	}))
+	return nil
+}
+
+// parseTemplateVariables reads the template variables of multipart requests, which arrive as a JSON encoded field
+func parseTemplateVariables(c *fiber.Ctx, options *domainSend.TemplateOptions) {
+	if variables := c.FormValue("variables"); options.Variables == nil && variables != "" {
+		if err := json.Unmarshal([]byte(variables), &options.Variables); err != nil {
+			panic(pkgError.ValidationError("variables: must be a JSON object of names to values"))
+		}
+	}
+}
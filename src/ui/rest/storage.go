@@ -0,0 +1,91 @@
+package rest
+
+import (
+	domainStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/storage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Storage struct {
+	Service domainStorage.IStorageUsecase
+}
+
+func InitRestStorage(app fiber.Router, service domainStorage.IStorageUsecase) Storage {
+	rest := Storage{Service: service}
+
+	app.Get("/app/storage", rest.GetStatistics)
+	app.Post("/app/storage/prune", rest.Prune)
+	app.Get("/app/storage/retention", rest.ListRetentionPolicies)
+	app.Put("/app/storage/retention", rest.SetRetentionPolicy)
+	app.Delete("/app/storage/retention", rest.DeleteRetentionPolicy)
+
+	return rest
+}
+
+func (controller *Storage) GetStatistics(c *fiber.Ctx) error {
+	response, err := controller.Service.GetStatistics(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get storage statistics",
+		Results: response,
+	})
+}
+
+func (controller *Storage) Prune(c *fiber.Ctx) error {
+	response, err := controller.Service.Prune(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success prune storage",
+		Results: response,
+	})
+}
+
+func (controller *Storage) ListRetentionPolicies(c *fiber.Ctx) error {
+	response, err := controller.Service.ListRetentionPolicies(c.UserContext(), c.Query("account_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get retention policies",
+		Results: response,
+	})
+}
+
+func (controller *Storage) SetRetentionPolicy(c *fiber.Ctx) error {
+	var request domainStorage.RetentionPolicyRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.SetRetentionPolicy(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success set retention policy",
+		Results: response,
+	})
+}
+
+func (controller *Storage) DeleteRetentionPolicy(c *fiber.Ctx) error {
+	var request domainStorage.RetentionPolicyIdentifierRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	err = controller.Service.DeleteRetentionPolicy(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success delete retention policy",
+		Results: nil,
+	})
+}
@@ -0,0 +1,111 @@
+package rest
+
+import (
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Template struct {
+	Service domainTemplate.ITemplateUsecase
+}
+
+func InitRestTemplate(app fiber.Router, service domainTemplate.ITemplateUsecase) Template {
+	rest := Template{Service: service}
+
+	app.Get("/accounts/:accountId/templates", rest.ListTemplates)
+	app.Post("/accounts/:accountId/templates", rest.CreateTemplate)
+	app.Get("/accounts/:accountId/templates/:templateId", rest.GetTemplate)
+	app.Put("/accounts/:accountId/templates/:templateId", rest.UpdateTemplate)
+	app.Delete("/accounts/:accountId/templates/:templateId", rest.DeleteTemplate)
+
+	return rest
+}
+
+func (controller *Template) ListTemplates(c *fiber.Ctx) error {
+	response, err := controller.Service.ListTemplates(c.UserContext(), c.Params("accountId"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get templates",
+		Results: response,
+	})
+}
+
+func (controller *Template) CreateTemplate(c *fiber.Ctx) error {
+	var request domainTemplate.TemplateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.AccountID = c.Params("accountId")
+	if file, err := c.FormFile("media"); err == nil {
+		request.Media = file
+	}
+
+	response, err := controller.Service.CreateTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success create template",
+		Results: response,
+	})
+}
+
+func (controller *Template) GetTemplate(c *fiber.Ctx) error {
+	request := domainTemplate.TemplateIdentifierRequest{
+		AccountID:  c.Params("accountId"),
+		TemplateID: c.Params("templateId"),
+	}
+
+	response, err := controller.Service.GetTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get template",
+		Results: response,
+	})
+}
+
+func (controller *Template) UpdateTemplate(c *fiber.Ctx) error {
+	var request domainTemplate.UpdateTemplateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.AccountID = c.Params("accountId")
+	request.TemplateID = c.Params("templateId")
+	if file, err := c.FormFile("media"); err == nil {
+		request.Media = file
+	}
+
+	response, err := controller.Service.UpdateTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update template",
+		Results: response,
+	})
+}
+
+func (controller *Template) DeleteTemplate(c *fiber.Ctx) error {
+	request := domainTemplate.TemplateIdentifierRequest{
+		AccountID:  c.Params("accountId"),
+		TemplateID: c.Params("templateId"),
+	}
+
+	err := controller.Service.DeleteTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success delete template",
+	})
+}
@@ -81,7 +81,11 @@ func (controller *User) UserChangeAvatar(c *fiber.Ctx) error {
 }
 
 func (controller *User) UserMyPrivacySetting(c *fiber.Ctx) error {
-	response, err := controller.Service.MyPrivacySetting(c.UserContext())
+	var request domainUser.AccountRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.MyPrivacySetting(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -93,7 +97,11 @@ func (controller *User) UserMyPrivacySetting(c *fiber.Ctx) error {
 }
 
 func (controller *User) UserMyListGroups(c *fiber.Ctx) error {
-	response, err := controller.Service.MyListGroups(c.UserContext())
+	var request domainUser.AccountRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.MyListGroups(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -105,7 +113,11 @@ func (controller *User) UserMyListGroups(c *fiber.Ctx) error {
 }
 
 func (controller *User) UserMyListNewsletter(c *fiber.Ctx) error {
-	response, err := controller.Service.MyListNewsletter(c.UserContext())
+	var request domainUser.AccountRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.MyListNewsletter(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -117,7 +129,11 @@ func (controller *User) UserMyListNewsletter(c *fiber.Ctx) error {
 }
 
 func (controller *User) UserMyListContacts(c *fiber.Ctx) error {
-	response, err := controller.Service.MyListContacts(c.UserContext())
+	var request domainUser.AccountRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.MyListContacts(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -0,0 +1,177 @@
+package rest
+
+import (
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Webhook struct {
+	Service domainWebhook.IWebhookUsecase
+}
+
+func InitRestWebhook(app fiber.Router, service domainWebhook.IWebhookUsecase) Webhook {
+	rest := Webhook{Service: service}
+
+	// Webhook endpoints
+	app.Get("/accounts/:accountId/webhooks", rest.ListEndpoints)
+	app.Post("/accounts/:accountId/webhooks", rest.CreateEndpoint)
+	app.Get("/accounts/:accountId/webhooks/:webhookId", rest.GetEndpoint)
+	app.Put("/accounts/:accountId/webhooks/:webhookId", rest.UpdateEndpoint)
+	app.Delete("/accounts/:accountId/webhooks/:webhookId", rest.DeleteEndpoint)
+
+	// Dead letter endpoints
+	app.Get("/accounts/:accountId/dead-letters", rest.ListDeadLetters)
+	app.Post("/accounts/:accountId/dead-letters/replay", rest.ReplayDeadLetters)
+	app.Delete("/accounts/:accountId/dead-letters", rest.PurgeDeadLetters)
+
+	// Dead letters of the global webhooks, which belong to no account
+	app.Get("/webhook/dead-letters", rest.ListDeadLetters)
+	app.Post("/webhook/dead-letters/replay", rest.ReplayDeadLetters)
+	app.Delete("/webhook/dead-letters", rest.PurgeDeadLetters)
+
+	return rest
+}
+
+func (controller *Webhook) ListEndpoints(c *fiber.Ctx) error {
+	response, err := controller.Service.ListEndpoints(c.UserContext(), c.Params("accountId"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhooks",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) CreateEndpoint(c *fiber.Ctx) error {
+	var request domainWebhook.EndpointRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.AccountID = c.Params("accountId")
+
+	response, err := controller.Service.CreateEndpoint(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success create webhook",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) GetEndpoint(c *fiber.Ctx) error {
+	request := domainWebhook.EndpointIdentifierRequest{
+		AccountID:  c.Params("accountId"),
+		EndpointID: c.Params("webhookId"),
+	}
+
+	response, err := controller.Service.GetEndpoint(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) UpdateEndpoint(c *fiber.Ctx) error {
+	var request domainWebhook.UpdateEndpointRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.AccountID = c.Params("accountId")
+	request.EndpointID = c.Params("webhookId")
+
+	response, err := controller.Service.UpdateEndpoint(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update webhook",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) DeleteEndpoint(c *fiber.Ctx) error {
+	request := domainWebhook.EndpointIdentifierRequest{
+		AccountID:  c.Params("accountId"),
+		EndpointID: c.Params("webhookId"),
+	}
+
+	err := controller.Service.DeleteEndpoint(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success delete webhook",
+	})
+}
+
+func (controller *Webhook) ListDeadLetters(c *fiber.Ctx) error {
+	var request domainWebhook.ListDeadLettersRequest
+
+	request.AccountID = c.Params("accountId")
+	request.Global = request.AccountID == ""
+	request.Limit = c.QueryInt("limit", 25)
+	request.Offset = c.QueryInt("offset", 0)
+
+	response, err := controller.Service.ListDeadLetters(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get dead letters",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) ReplayDeadLetters(c *fiber.Ctx) error {
+	var request domainWebhook.DeadLettersRequest
+	if len(c.Body()) > 0 {
+		err := c.BodyParser(&request)
+		utils.PanicIfNeeded(err)
+	}
+
+	request.AccountID = c.Params("accountId")
+	request.Global = request.AccountID == ""
+
+	response, err := controller.Service.ReplayDeadLetters(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success replay dead letters",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) PurgeDeadLetters(c *fiber.Ctx) error {
+	var request domainWebhook.DeadLettersRequest
+	if len(c.Body()) > 0 {
+		err := c.BodyParser(&request)
+		utils.PanicIfNeeded(err)
+	}
+
+	request.AccountID = c.Params("accountId")
+	request.Global = request.AccountID == ""
+
+	response, err := controller.Service.PurgeDeadLetters(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success purge dead letters",
+		Results: response,
+	})
+}
@@ -110,7 +110,7 @@ func RegisterRoutes(app fiber.Router, service domainApp.IAppUsecase) {
 				}
 
 				if messageData.Code == "FETCH_DEVICES" {
-					devices, _ := service.FetchDevices(context.Background())
+					devices, _ := service.FetchDevices(context.Background(), "")
 					Broadcast <- BroadcastMessage{
 						Code:    "LIST_DEVICES",
 						Message: "Device found",
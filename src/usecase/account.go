@@ -2,18 +2,23 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	infraAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/account"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
 	"github.com/skip2/go-qrcode"
 	"go.mau.fi/whatsmeow"
@@ -24,13 +29,15 @@ type accountService struct {
 	accountRepo      domainAccount.IAccountRepository
 	accountManager   domainAccount.IAccountManager
 	chatStorageRepo  domainChatStorage.IChatStorageRepository
+	webhookRepo      domainWebhook.IWebhookRepository
 }
 
-func NewAccountService(accountRepo domainAccount.IAccountRepository, chatStorageRepo domainChatStorage.IChatStorageRepository) domainAccount.IAccountUsecase {
+func NewAccountService(accountRepo domainAccount.IAccountRepository, chatStorageRepo domainChatStorage.IChatStorageRepository, webhookRepo domainWebhook.IWebhookRepository) domainAccount.IAccountUsecase {
 	return &accountService{
 		accountRepo:     accountRepo,
 		accountManager:  infraAccount.GlobalAccountManager,
 		chatStorageRepo: chatStorageRepo,
+		webhookRepo:     webhookRepo,
 	}
 }
 
@@ -55,53 +62,153 @@ func (s *accountService) CreateAccount(ctx context.Context, accountID string) (d
 		return domainAccount.CreateAccountResponse{}, pkgError.InternalServerError(fmt.Sprintf("Failed to create account: %v", err))
 	}
 
-	// Initialize WhatsApp database for this account
-	dbURI := fmt.Sprintf("file:storages/whatsapp_%s.db?_foreign_keys=1", accountID)
+	// Initialize WhatsApp database and client for this account
+	client, db, err := s.initAccountClient(ctx, accountID)
+	if err != nil {
+		return domainAccount.CreateAccountResponse{}, pkgError.InternalServerError(fmt.Sprintf("Failed to initialize WhatsApp client: %v", err))
+	}
+
+	// Store client in account manager
+	logrus.Infof("Storing client in account manager for account %s", accountID)
+	s.accountManager.SetClient(accountID, client, db)
+
+	// Verify client was stored
+	if storedClient := s.accountManager.GetClient(accountID); storedClient == nil {
+		return domainAccount.CreateAccountResponse{}, pkgError.InternalServerError("Failed to store client in account manager")
+	}
+
+	return domainAccount.CreateAccountResponse{
+		AccountID: accountID,
+		Message:   "Account created successfully",
+	}, nil
+}
+
+// initAccountClient opens the device and keys databases of an account and builds its WhatsApp client
+func (s *accountService) initAccountClient(ctx context.Context, accountID string) (client *whatsmeow.Client, db *sqlstore.Container, err error) {
+	// InitWaDB and InitAccountWaCLI panic on failure, turn that into an error for the caller
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	dbURI := accountDBURI(accountID)
 	logrus.Infof("Initializing database for account %s with URI: %s", accountID, dbURI)
 
-	db := whatsapp.InitWaDB(ctx, dbURI)
+	db = whatsapp.InitWaDB(ctx, dbURI)
 	if db == nil {
-		return domainAccount.CreateAccountResponse{}, pkgError.InternalServerError("Failed to initialize WhatsApp database")
+		return nil, nil, fmt.Errorf("failed to initialize WhatsApp database")
 	}
 
 	var keysDB *sqlstore.Container
 	if config.DBKeysURI != "" {
-		// Format keysDB URI properly - replace foreign_keys param and add account suffix
-		var keysDBURI string
-		if strings.Contains(config.DBKeysURI, "?") {
-			// Replace foreign_keys=on with foreign_keys=1 and add account suffix before the query params
-			baseURI := strings.Split(config.DBKeysURI, "?")[0]
-			queryParams := strings.Split(config.DBKeysURI, "?")[1]
-			// Replace foreign_keys=on with foreign_keys=1
-			queryParams = strings.ReplaceAll(queryParams, "_foreign_keys=on", "_foreign_keys=1")
-			keysDBURI = fmt.Sprintf("%s_%s?%s", baseURI, accountID, queryParams)
-		} else {
-			keysDBURI = fmt.Sprintf("%s_%s?_foreign_keys=1", config.DBKeysURI, accountID)
-		}
+		keysDBURI := accountKeysDBURI(accountID)
 		logrus.Infof("Initializing keys database for account %s with URI: %s", accountID, keysDBURI)
 		keysDB = whatsapp.InitWaDB(ctx, keysDBURI)
 	}
 
-	// Initialize WhatsApp client for this account
 	logrus.Infof("Initializing WhatsApp client for account %s", accountID)
-	client := whatsapp.InitWaCLI(ctx, db, keysDB, s.chatStorageRepo)
+	client = whatsapp.InitAccountWaCLI(ctx, accountID, db, keysDB, s.chatStorageRepo)
 	if client == nil {
-		return domainAccount.CreateAccountResponse{}, pkgError.InternalServerError("Failed to initialize WhatsApp client")
+		return nil, nil, fmt.Errorf("failed to initialize WhatsApp client")
 	}
 
-	// Store client in account manager
-	logrus.Infof("Storing client in account manager for account %s", accountID)
-	s.accountManager.SetClient(accountID, client, db)
+	return client, db, nil
+}
 
-	// Verify client was stored
-	if storedClient := s.accountManager.GetClient(accountID); storedClient == nil {
-		return domainAccount.CreateAccountResponse{}, pkgError.InternalServerError("Failed to store client in account manager")
+// accountDBURI is the URI of the device database of an account
+func accountDBURI(accountID string) string {
+	return fmt.Sprintf("file:storages/whatsapp_%s.db?_foreign_keys=1", accountID)
+}
+
+// accountKeysDBURI derives the keys database URI of an account from the configured keys database URI
+func accountKeysDBURI(accountID string) string {
+	if strings.Contains(config.DBKeysURI, "?") {
+		// Add account suffix before the query params and replace foreign_keys=on with foreign_keys=1
+		baseURI := strings.Split(config.DBKeysURI, "?")[0]
+		queryParams := strings.Split(config.DBKeysURI, "?")[1]
+		queryParams = strings.ReplaceAll(queryParams, "_foreign_keys=on", "_foreign_keys=1")
+		return fmt.Sprintf("%s_%s?%s", baseURI, accountID, queryParams)
 	}
+	return fmt.Sprintf("%s_%s?_foreign_keys=1", config.DBKeysURI, accountID)
+}
 
-	return domainAccount.CreateAccountResponse{
-		AccountID: accountID,
-		Message:   "Account created successfully",
-	}, nil
+// RestoreAccounts rebuilds the clients of all registered accounts and reconnects the paired ones
+func (s *accountService) RestoreAccounts(ctx context.Context) error {
+	accounts, err := s.accountRepo.ListAccounts()
+	if err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("Failed to list accounts: %v", err))
+	}
+
+	total := len(accounts)
+	if total == 0 {
+		logrus.Info("[ACCOUNT_RESTORE] No registered accounts to restore")
+		return nil
+	}
+	logrus.Infof("[ACCOUNT_RESTORE] Restoring %d registered accounts", total)
+
+	concurrency := config.AccountRestoreConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+
+	var (
+		wg          sync.WaitGroup
+		restored    int32
+		reconnected int32
+		failed      int32
+	)
+
+	for i, account := range accounts {
+		position := i + 1
+
+		if s.accountManager.GetClient(account.ID) != nil {
+			logrus.Infof("[ACCOUNT_RESTORE] (%d/%d) Account %s already loaded, skipping", position, total, account.ID)
+			continue
+		}
+
+		client, db, err := s.initAccountClient(ctx, account.ID)
+		if err != nil {
+			atomic.AddInt32(&failed, 1)
+			logrus.Errorf("[ACCOUNT_RESTORE] (%d/%d) Failed to restore account %s: %v", position, total, account.ID, err)
+			continue
+		}
+		s.accountManager.SetClient(account.ID, client, db)
+		atomic.AddInt32(&restored, 1)
+
+		if client.Store == nil || client.Store.ID == nil {
+			logrus.Infof("[ACCOUNT_RESTORE] (%d/%d) Account %s is not paired, waiting for login", position, total, account.ID)
+			continue
+		}
+		if account.Status == domainAccount.StatusReleased {
+			logrus.Infof("[ACCOUNT_RESTORE] (%d/%d) Account %s was released by a backup, waiting for reconnect", position, total, account.ID)
+			continue
+		}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(position int, account *domainAccount.Account, client *whatsmeow.Client) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			if err := client.Connect(); err != nil {
+				atomic.AddInt32(&failed, 1)
+				logrus.Errorf("[ACCOUNT_RESTORE] (%d/%d) Failed to reconnect account %s: %v", position, total, account.ID, err)
+				return
+			}
+
+			// The status is stored by the connection supervisor once the client is logged in,
+			// Connect only opens the websocket
+			atomic.AddInt32(&reconnected, 1)
+			logrus.Infof("[ACCOUNT_RESTORE] (%d/%d) Account %s reconnected", position, total, account.ID)
+		}(position, account, client)
+	}
+
+	wg.Wait()
+	logrus.Infof("[ACCOUNT_RESTORE] Completed: %d restored, %d reconnected, %d failed", restored, reconnected, failed)
+
+	return nil
 }
 
 func (s *accountService) DeleteAccount(ctx context.Context, accountID string) error {
@@ -240,17 +347,7 @@ func (s *accountService) LoginAccount(ctx context.Context, accountID string) (do
 				Code:      evt.Code,
 			}, nil
 		case "success":
-			// Update account status
-			account, _ := s.accountRepo.GetAccount(accountID)
-			if account != nil {
-				account.Status = domainAccount.StatusLoggedIn
-				account.LastConnected = time.Now()
-				if client.Store != nil && client.Store.ID != nil {
-					account.DeviceID = client.Store.ID.String()
-					account.PhoneNumber = client.Store.ID.User
-				}
-				s.accountRepo.UpdateAccount(account)
-			}
+			s.markLoggedIn(accountID, client)
 			return domainAccount.LoginResponse{Code: "success"}, nil
 		}
 	case <-time.After(120 * time.Second):
@@ -260,6 +357,92 @@ func (s *accountService) LoginAccount(ctx context.Context, accountID string) (do
 	return domainAccount.LoginResponse{}, pkgError.InternalServerError("Unknown error during login")
 }
 
+// LoginAccountStream connects the account and streams every QR code of the pairing, followed by the outcome.
+// The stream ends when the pairing is done or ctx is cancelled, which also disconnects the client.
+func (s *accountService) LoginAccountStream(ctx context.Context, accountID string) (<-chan domainAccount.LoginEvent, error) {
+	client := s.accountManager.GetClient(accountID)
+	if client == nil {
+		return nil, pkgError.NotFoundError("Account not found or not initialized")
+	}
+
+	if client.IsLoggedIn() {
+		return nil, pkgError.BadRequestError("Account is already logged in")
+	}
+
+	if client.IsConnected() {
+		client.Disconnect()
+	}
+
+	qrChan, err := client.GetQRChannel(ctx)
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("Failed to get QR channel: %v", err))
+	}
+	if err := client.Connect(); err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("Failed to connect: %v", err))
+	}
+
+	events := make(chan domainAccount.LoginEvent, 1)
+	go func() {
+		defer close(events)
+
+		for evt := range qrChan {
+			var event domainAccount.LoginEvent
+			switch evt.Event {
+			case whatsmeow.QRChannelEventCode:
+				png, err := qrcode.Encode(evt.Code, qrcode.Medium, 512)
+				if err != nil {
+					event = domainAccount.LoginEvent{Event: domainAccount.LoginEventError, Message: fmt.Sprintf("Failed to generate QR code: %v", err)}
+					break
+				}
+				event = domainAccount.LoginEvent{
+					Event:   domainAccount.LoginEventCode,
+					Code:    evt.Code,
+					Image:   "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+					Timeout: int(evt.Timeout.Seconds()),
+				}
+			case whatsmeow.QRChannelSuccess.Event:
+				s.markLoggedIn(accountID, client)
+				event = domainAccount.LoginEvent{Event: domainAccount.LoginEventSuccess}
+			case whatsmeow.QRChannelTimeout.Event:
+				event = domainAccount.LoginEvent{Event: domainAccount.LoginEventTimeout, Message: "QR code was not scanned in time"}
+			default:
+				message := evt.Event
+				if evt.Error != nil {
+					message = evt.Error.Error()
+				}
+				event = domainAccount.LoginEvent{Event: domainAccount.LoginEventError, Message: message}
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+			if event.Event != domainAccount.LoginEventCode {
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// markLoggedIn stores the device of a freshly paired account
+func (s *accountService) markLoggedIn(accountID string, client *whatsmeow.Client) {
+	account, _ := s.accountRepo.GetAccount(accountID)
+	if account == nil {
+		return
+	}
+
+	account.Status = domainAccount.StatusLoggedIn
+	account.LastConnected = time.Now()
+	if client.Store != nil && client.Store.ID != nil {
+		account.DeviceID = client.Store.ID.String()
+		account.PhoneNumber = client.Store.ID.User
+	}
+	s.accountRepo.UpdateAccount(account)
+}
+
 func (s *accountService) LoginAccountWithCode(ctx context.Context, accountID string, phoneNumber string) (string, error) {
 	client := s.accountManager.GetClient(accountID)
 	if client == nil {
@@ -326,18 +509,11 @@ func (s *accountService) ReconnectAccount(ctx context.Context, accountID string)
 		client.Disconnect()
 	}
 
+	// The account status is updated by the connection supervisor once the client is logged in
 	if err := client.Connect(); err != nil {
 		return pkgError.InternalServerError(fmt.Sprintf("Failed to reconnect: %v", err))
 	}
 
-	// Update account status
-	account, _ := s.accountRepo.GetAccount(accountID)
-	if account != nil {
-		account.Status = domainAccount.StatusConnected
-		account.LastConnected = time.Now()
-		s.accountRepo.UpdateAccount(account)
-	}
-
 	return nil
 }
 
@@ -374,4 +550,136 @@ func (s *accountService) GetAccountWebhook(ctx context.Context, accountID string
 	}
 
 	return *webhook, nil
+}
+
+func (s *accountService) GetConnectionHistory(ctx context.Context, request domainAccount.ConnectionHistoryRequest) (domainAccount.ConnectionHistoryResponse, error) {
+	if err := validations.ValidateConnectionHistory(ctx, &request); err != nil {
+		return domainAccount.ConnectionHistoryResponse{}, err
+	}
+
+	account, err := s.accountRepo.GetAccount(request.AccountID)
+	if err != nil {
+		return domainAccount.ConnectionHistoryResponse{}, pkgError.InternalServerError(fmt.Sprintf("Failed to get account: %v", err))
+	}
+	if account == nil {
+		return domainAccount.ConnectionHistoryResponse{}, pkgError.NotFoundError("Account not found")
+	}
+
+	events, err := s.accountRepo.ListConnectionEvents(request.AccountID, request.Limit, request.Offset)
+	if err != nil {
+		return domainAccount.ConnectionHistoryResponse{}, pkgError.InternalServerError(fmt.Sprintf("Failed to get connection history: %v", err))
+	}
+
+	response := domainAccount.ConnectionHistoryResponse{
+		Events: make([]domainAccount.ConnectionEvent, 0, len(events)),
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	}
+	for _, event := range events {
+		response.Events = append(response.Events, *event)
+	}
+	return response, nil
+}
+
+func (s *accountService) GetSettings(ctx context.Context, accountID string) (domainAccount.SettingsResponse, error) {
+	account, err := s.accountRepo.GetAccount(accountID)
+	if err != nil {
+		return domainAccount.SettingsResponse{}, pkgError.InternalServerError(fmt.Sprintf("Failed to get account: %v", err))
+	}
+	if account == nil {
+		return domainAccount.SettingsResponse{}, pkgError.NotFoundError("Account not found")
+	}
+
+	overrides, err := s.accountRepo.GetSettings(accountID)
+	if err != nil {
+		return domainAccount.SettingsResponse{}, pkgError.InternalServerError(fmt.Sprintf("Failed to get settings: %v", err))
+	}
+
+	return settingsResponse(accountID, overrides), nil
+}
+
+func (s *accountService) UpdateSettings(ctx context.Context, request domainAccount.UpdateSettingsRequest) (domainAccount.SettingsResponse, error) {
+	if err := validations.ValidateUpdateSettings(ctx, request); err != nil {
+		return domainAccount.SettingsResponse{}, err
+	}
+
+	account, err := s.accountRepo.GetAccount(request.AccountID)
+	if err != nil {
+		return domainAccount.SettingsResponse{}, pkgError.InternalServerError(fmt.Sprintf("Failed to get account: %v", err))
+	}
+	if account == nil {
+		return domainAccount.SettingsResponse{}, pkgError.NotFoundError("Account not found")
+	}
+
+	overrides, err := s.accountRepo.GetSettings(request.AccountID)
+	if err != nil {
+		return domainAccount.SettingsResponse{}, pkgError.InternalServerError(fmt.Sprintf("Failed to get settings: %v", err))
+	}
+
+	// Only the fields present in the request change, the reset ones go back to the global value
+	if request.AutoMarkRead != nil {
+		overrides.AutoMarkRead = request.AutoMarkRead
+	}
+	if request.AutoReplyMessage != nil {
+		overrides.AutoReplyMessage = request.AutoReplyMessage
+	}
+	if request.DeviceName != nil {
+		overrides.DeviceName = request.DeviceName
+	}
+	if request.MaxImageSize != nil {
+		overrides.MaxImageSize = request.MaxImageSize
+	}
+	if request.MaxFileSize != nil {
+		overrides.MaxFileSize = request.MaxFileSize
+	}
+	if request.MaxVideoSize != nil {
+		overrides.MaxVideoSize = request.MaxVideoSize
+	}
+	if request.DisappearingDuration != nil {
+		overrides.DisappearingDuration = request.DisappearingDuration
+	}
+	if request.WebhookSecret != nil {
+		overrides.WebhookSecret = request.WebhookSecret
+	}
+	for _, name := range request.Reset {
+		switch name {
+		case domainAccount.SettingAutoMarkRead:
+			overrides.AutoMarkRead = nil
+		case domainAccount.SettingAutoReplyMessage:
+			overrides.AutoReplyMessage = nil
+		case domainAccount.SettingDeviceName:
+			overrides.DeviceName = nil
+		case domainAccount.SettingMaxImageSize:
+			overrides.MaxImageSize = nil
+		case domainAccount.SettingMaxFileSize:
+			overrides.MaxFileSize = nil
+		case domainAccount.SettingMaxVideoSize:
+			overrides.MaxVideoSize = nil
+		case domainAccount.SettingDisappearingDuration:
+			overrides.DisappearingDuration = nil
+		case domainAccount.SettingWebhookSecret:
+			overrides.WebhookSecret = nil
+		}
+	}
+
+	if err := s.accountRepo.SaveSettings(request.AccountID, overrides); err != nil {
+		return domainAccount.SettingsResponse{}, pkgError.InternalServerError(fmt.Sprintf("Failed to save settings: %v", err))
+	}
+
+	return settingsResponse(request.AccountID, overrides), nil
+}
+
+// settingsResponse reports the settings of an account without its webhook secret,
+// read scoped keys may view settings and the effective value can be the global secret
+func settingsResponse(accountID string, overrides *domainAccount.Settings) domainAccount.SettingsResponse {
+	response := domainAccount.SettingsResponse{
+		AccountID:               accountID,
+		Overrides:               *overrides,
+		Effective:               whatsapp.EffectiveSettings(overrides),
+		WebhookSecretOverridden: overrides.WebhookSecret != nil,
+	}
+	response.WebhookSecretSet = response.Effective.WebhookSecret != ""
+	response.Overrides.WebhookSecret = nil
+	response.Effective.WebhookSecret = ""
+	return response
 }
\ No newline at end of file
@@ -0,0 +1,533 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Entries of the zip inside an encrypted account backup
+const (
+	backupManifestFile    = "manifest.json"
+	backupDeviceStoreFile = "device.db"
+	backupKeysStoreFile   = "keys.db"
+	backupChatsFile       = "chats.jsonl"
+)
+
+// BackupAccount copies the device and keys stores of the account up front, the returned Write packs
+// them with the account data, and optionally the chat storage, in a zip encrypted with the passphrase
+func (s *accountService) BackupAccount(ctx context.Context, request domainAccount.BackupRequest) (domainAccount.AccountBackup, error) {
+	if err := validations.ValidateBackupAccount(ctx, request); err != nil {
+		return domainAccount.AccountBackup{}, err
+	}
+
+	account, err := s.accountRepo.GetAccount(request.AccountID)
+	if err != nil {
+		return domainAccount.AccountBackup{}, pkgError.InternalServerError(fmt.Sprintf("Failed to get account: %v", err))
+	}
+	if account == nil {
+		return domainAccount.AccountBackup{}, pkgError.NotFoundError("Account not found")
+	}
+
+	var keysURI string
+	if config.DBKeysURI != "" {
+		keysURI = accountKeysDBURI(request.AccountID)
+		if _, err := whatsapp.StoreFilePath(keysURI); err != nil {
+			return domainAccount.AccountBackup{}, pkgError.BadRequestError(err.Error())
+		}
+	}
+
+	manifest := domainAccount.BackupManifest{
+		Version:     domainAccount.BackupVersion,
+		AccountID:   account.ID,
+		CreatedAt:   time.Now().UTC(),
+		DeviceID:    account.DeviceID,
+		PhoneNumber: account.PhoneNumber,
+		KeysStore:   keysURI != "",
+		ChatStorage: request.IncludeChats,
+		Account:     *account,
+	}
+	client := s.accountManager.GetClient(request.AccountID)
+	if client != nil && client.Store != nil && client.Store.ID != nil {
+		manifest.DeviceID = client.Store.ID.String()
+		manifest.PhoneNumber = client.Store.ID.User
+	}
+	if manifest.DeviceID == "" {
+		return domainAccount.AccountBackup{}, pkgError.BadRequestError("Account has no session to back up, login first")
+	}
+
+	webhook, err := s.accountRepo.GetWebhook(request.AccountID)
+	if err != nil {
+		return domainAccount.AccountBackup{}, pkgError.InternalServerError(fmt.Sprintf("Failed to get webhook: %v", err))
+	}
+	manifest.Webhook = *webhook
+
+	settings, err := s.accountRepo.GetSettings(request.AccountID)
+	if err != nil {
+		return domainAccount.AccountBackup{}, pkgError.InternalServerError(fmt.Sprintf("Failed to get settings: %v", err))
+	}
+	manifest.Settings = *settings
+
+	endpoints, err := s.webhookRepo.ListEndpoints(request.AccountID)
+	if err != nil {
+		return domainAccount.AccountBackup{}, pkgError.InternalServerError(fmt.Sprintf("Failed to list webhook endpoints: %v", err))
+	}
+	for _, endpoint := range endpoints {
+		manifest.Endpoints = append(manifest.Endpoints, *endpoint)
+	}
+
+	backup := &accountBackup{passphrase: request.Passphrase, manifest: manifest}
+	if request.IncludeChats {
+		backup.chats = &chatExporter{
+			ctx:     ctx,
+			repo:    s.chatStorageRepo,
+			request: domainChat.ExportChatsRequest{AccountID: request.AccountID},
+		}
+		backup.chats.chats, err = s.chatStorageRepo.GetChats(&domainChatStorage.ChatFilter{AccountID: request.AccountID})
+		if err != nil {
+			return domainAccount.AccountBackup{}, pkgError.InternalServerError(fmt.Sprintf("Failed to get chats: %v", err))
+		}
+	}
+
+	// A released session is disconnected before the copy, so the stores don't change after it was taken.
+	// An account without a loaded client is only marked, so it isn't connected again on the next start.
+	if client != nil {
+		backup.manifest.Connected = client.IsConnected()
+	}
+	if request.Release {
+		if client != nil {
+			whatsapp.ReleaseSession(client)
+		} else {
+			account.Status = domainAccount.StatusReleased
+			if err := s.accountRepo.UpdateAccount(account); err != nil {
+				return domainAccount.AccountBackup{}, pkgError.InternalServerError(fmt.Sprintf("Failed to release account: %v", err))
+			}
+		}
+		backup.manifest.Released = true
+		backup.manifest.Account.Status = domainAccount.StatusReleased
+	}
+
+	backup.dir, err = os.MkdirTemp("", "account-backup-*")
+	if err != nil {
+		return domainAccount.AccountBackup{}, pkgError.InternalServerError(fmt.Sprintf("Failed to prepare backup: %v", err))
+	}
+	if err := whatsapp.SnapshotStore(ctx, accountDBURI(request.AccountID), filepath.Join(backup.dir, backupDeviceStoreFile)); err != nil {
+		os.RemoveAll(backup.dir)
+		return domainAccount.AccountBackup{}, pkgError.InternalServerError(fmt.Sprintf("Failed to copy device store: %v", err))
+	}
+	if keysURI != "" {
+		if err := whatsapp.SnapshotStore(ctx, keysURI, filepath.Join(backup.dir, backupKeysStoreFile)); err != nil {
+			os.RemoveAll(backup.dir)
+			return domainAccount.AccountBackup{}, pkgError.InternalServerError(fmt.Sprintf("Failed to copy keys store: %v", err))
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"account_id":    request.AccountID,
+		"released":      backup.manifest.Released,
+		"include_chats": request.IncludeChats,
+	}).Info("Prepared account backup")
+
+	return domainAccount.AccountBackup{
+		Filename: fmt.Sprintf("%s-%s.wagobackup", sanitizeExportFilename(request.AccountID), manifest.CreatedAt.Format("20060102-150405")),
+		Write:    backup.write,
+	}, nil
+}
+
+// RestoreAccount rehydrates an account from a backup: the device and keys stores, the account row,
+// its webhooks and settings, and the chats when the backup has them. A paired session is reconnected.
+func (s *accountService) RestoreAccount(ctx context.Context, request domainAccount.RestoreRequest) (domainAccount.RestoreResponse, error) {
+	if err := validations.ValidateRestoreAccount(ctx, request); err != nil {
+		return domainAccount.RestoreResponse{}, err
+	}
+
+	archivePath, err := decryptBackup(request)
+	if err != nil {
+		return domainAccount.RestoreResponse{}, err
+	}
+	defer os.Remove(archivePath)
+
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return domainAccount.RestoreResponse{}, pkgError.ValidationError("backup: not an account backup")
+	}
+	defer archive.Close()
+
+	manifest, err := readBackupManifest(archive)
+	if err != nil {
+		return domainAccount.RestoreResponse{}, err
+	}
+
+	// The device store only works together with the keys store layout it was used with
+	if manifest.KeysStore != (config.DBKeysURI != "") {
+		return domainAccount.RestoreResponse{}, pkgError.BadRequestError("The backup and this instance don't agree on a separate keys store, set the same --db-keys-uri usage as the source")
+	}
+	devicePath, _ := whatsapp.StoreFilePath(accountDBURI(request.AccountID))
+	var keysPath string
+	if manifest.KeysStore {
+		if keysPath, err = whatsapp.StoreFilePath(accountKeysDBURI(request.AccountID)); err != nil {
+			return domainAccount.RestoreResponse{}, pkgError.BadRequestError(err.Error())
+		}
+	}
+
+	if err := s.ensureSessionNotLive(request, manifest); err != nil {
+		return domainAccount.RestoreResponse{}, err
+	}
+
+	// The stores are extracted next to their destination first, so a broken backup leaves the account as it was
+	deviceFile, err := extractBackupStore(archive, backupDeviceStoreFile, devicePath)
+	if err != nil {
+		return domainAccount.RestoreResponse{}, pkgError.InternalServerError(fmt.Sprintf("Failed to restore device store: %v", err))
+	}
+	defer os.Remove(deviceFile)
+	var keysFile string
+	if keysPath != "" {
+		if keysFile, err = extractBackupStore(archive, backupKeysStoreFile, keysPath); err != nil {
+			return domainAccount.RestoreResponse{}, pkgError.InternalServerError(fmt.Sprintf("Failed to restore keys store: %v", err))
+		}
+		defer os.Remove(keysFile)
+	}
+
+	// The current client of the account lets go of the stores before they are swapped, and is loaded
+	// again from the stores in place when the swap fails
+	previous := s.accountManager.GetClient(request.AccountID)
+	if previous != nil {
+		db := s.accountManager.GetDB(request.AccountID)
+		s.accountManager.RemoveClient(request.AccountID)
+		if db != nil {
+			db.Close()
+		}
+	}
+	if err := swapBackupStore(deviceFile, devicePath); err != nil {
+		s.reloadAccountClient(ctx, request.AccountID, previous != nil)
+		return domainAccount.RestoreResponse{}, pkgError.InternalServerError(fmt.Sprintf("Failed to restore device store: %v", err))
+	}
+	if keysFile != "" {
+		if err := swapBackupStore(keysFile, keysPath); err != nil {
+			s.reloadAccountClient(ctx, request.AccountID, previous != nil)
+			return domainAccount.RestoreResponse{}, pkgError.InternalServerError(fmt.Sprintf("Failed to restore keys store: %v", err))
+		}
+	}
+
+	response := domainAccount.RestoreResponse{
+		AccountID:   request.AccountID,
+		DeviceID:    manifest.DeviceID,
+		PhoneNumber: manifest.PhoneNumber,
+	}
+	if err := s.restoreAccountData(request.AccountID, manifest); err != nil {
+		s.reloadAccountClient(ctx, request.AccountID, previous != nil)
+		return response, err
+	}
+	response.WebhookEndpoints = len(manifest.Endpoints)
+
+	if manifest.ChatStorage {
+		chats, err := archive.Open(backupChatsFile)
+		if err != nil {
+			return response, pkgError.ValidationError(fmt.Sprintf("backup: %s is missing", backupChatsFile))
+		}
+		imported, err := serviceChat{chatStorageRepo: s.chatStorageRepo}.ImportChats(ctx, domainChat.ImportChatsRequest{
+			AccountID: request.AccountID,
+			Archive:   chats,
+		})
+		chats.Close()
+		if err != nil {
+			return response, err
+		}
+		response.Chats, response.Messages = imported.Chats, imported.Messages
+	}
+
+	client, db, err := s.initAccountClient(ctx, request.AccountID)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("Failed to initialize WhatsApp client: %v", err))
+	}
+	s.accountManager.SetClient(request.AccountID, client, db)
+
+	// The account status is updated by the connection supervisor once the client is logged in
+	if client.Store != nil && client.Store.ID != nil {
+		if err := client.Connect(); err != nil {
+			logrus.Warnf("[ACCOUNT_RESTORE] Restored account %s but failed to connect: %v", request.AccountID, err)
+		}
+		response.IsConnected = client.IsConnected()
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"account_id": request.AccountID,
+		"device_id":  manifest.DeviceID,
+		"connected":  response.IsConnected,
+		"chats":      response.Chats,
+	}).Info("Restored account backup")
+
+	return response, nil
+}
+
+// reloadAccountClient loads the client of an account again after a failed restore let go of it
+func (s *accountService) reloadAccountClient(ctx context.Context, accountID string, loaded bool) {
+	if !loaded {
+		return
+	}
+	client, db, err := s.initAccountClient(ctx, accountID)
+	if err != nil {
+		logrus.Errorf("[ACCOUNT_RESTORE] Failed to reload the client of account %s: %v", accountID, err)
+		return
+	}
+	s.accountManager.SetClient(accountID, client, db)
+}
+
+// ensureSessionNotLive refuses to restore a session that is still in use: the account is connected
+// on this instance, another account here holds the same device, or the session wasn't released on the
+// source when the backup was taken. An unreleased source may reconnect at any time, even when it was
+// disconnected during the backup, so only force restores such a backup.
+func (s *accountService) ensureSessionNotLive(request domainAccount.RestoreRequest, manifest *domainAccount.BackupManifest) error {
+	for accountID, client := range s.accountManager.ListClients() {
+		if accountID == request.AccountID {
+			if client.IsConnected() {
+				return pkgError.BadRequestError(fmt.Sprintf("Account %s is connected on this instance, logout or delete it before restoring", accountID))
+			}
+			continue
+		}
+		if client.Store != nil && client.Store.ID != nil && client.Store.ID.String() == manifest.DeviceID {
+			return pkgError.BadRequestError(fmt.Sprintf("The session of this backup is already used by account %s on this instance", accountID))
+		}
+	}
+
+	if !manifest.Released && !request.Force {
+		return pkgError.BadRequestError("The session was not released when the backup was taken. Take the backup again with release=true, or restore with force=true once the source instance is shut down")
+	}
+	return nil
+}
+
+// restoreAccountData creates or updates the account row and replaces its webhook, endpoints and settings
+func (s *accountService) restoreAccountData(accountID string, manifest *domainAccount.BackupManifest) error {
+	account := manifest.Account
+	account.ID = accountID
+	account.Status = domainAccount.StatusDisconnected
+	account.DeviceID = manifest.DeviceID
+	account.PhoneNumber = manifest.PhoneNumber
+
+	existing, err := s.accountRepo.GetAccount(accountID)
+	if err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("Failed to get account: %v", err))
+	}
+	if existing != nil {
+		err = s.accountRepo.UpdateAccount(&account)
+	} else {
+		if account.CreatedAt.IsZero() {
+			account.CreatedAt = time.Now()
+		}
+		err = s.accountRepo.CreateAccount(&account)
+	}
+	if err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("Failed to restore account: %v", err))
+	}
+
+	if manifest.Webhook.URL != "" {
+		if err := s.accountRepo.SetWebhook(accountID, manifest.Webhook.URL, manifest.Webhook.Secret); err != nil {
+			return pkgError.InternalServerError(fmt.Sprintf("Failed to restore webhook: %v", err))
+		}
+	}
+	if err := s.accountRepo.SaveSettings(accountID, &manifest.Settings); err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("Failed to restore settings: %v", err))
+	}
+
+	current, err := s.webhookRepo.ListEndpoints(accountID)
+	if err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("Failed to list webhook endpoints: %v", err))
+	}
+	for _, endpoint := range current {
+		if err := s.webhookRepo.DeleteEndpoint(accountID, endpoint.ID); err != nil {
+			return pkgError.InternalServerError(fmt.Sprintf("Failed to replace webhook endpoint %s: %v", endpoint.ID, err))
+		}
+	}
+	for _, endpoint := range manifest.Endpoints {
+		// Endpoint IDs are unique across accounts, they are only kept when the account keeps its ID
+		endpoint.AccountID = accountID
+		if manifest.AccountID != accountID {
+			endpoint.ID = uuid.NewString()
+		}
+		if err := s.webhookRepo.CreateEndpoint(&endpoint); err != nil {
+			return pkgError.InternalServerError(fmt.Sprintf("Failed to restore webhook endpoint %s: %v", endpoint.ID, err))
+		}
+	}
+
+	return nil
+}
+
+// accountBackup writes a prepared backup and removes the copied stores afterwards
+type accountBackup struct {
+	passphrase string
+	manifest   domainAccount.BackupManifest
+	dir        string
+	chats      *chatExporter
+}
+
+func (b *accountBackup) write(w io.Writer) error {
+	defer os.RemoveAll(b.dir)
+
+	encrypted, err := utils.NewEncryptWriter(w, b.passphrase)
+	if err != nil {
+		return err
+	}
+	archive := zip.NewWriter(encrypted)
+
+	entry, err := archive.Create(backupManifestFile)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(entry).Encode(b.manifest); err != nil {
+		return err
+	}
+
+	stores := []string{backupDeviceStoreFile}
+	if b.manifest.KeysStore {
+		stores = append(stores, backupKeysStoreFile)
+	}
+	for _, name := range stores {
+		if err := addBackupFile(archive, name, filepath.Join(b.dir, name)); err != nil {
+			return err
+		}
+	}
+
+	if b.chats != nil {
+		entry, err := archive.Create(backupChatsFile)
+		if err != nil {
+			return err
+		}
+		if err := b.chats.writeJSONL(entry, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return encrypted.Close()
+}
+
+func addBackupFile(archive *zip.Writer, name string, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, file)
+	return err
+}
+
+// decryptBackup decrypts the uploaded backup into a temporary zip, the caller removes it
+func decryptBackup(request domainAccount.RestoreRequest) (string, error) {
+	upload, err := request.Backup.Open()
+	if err != nil {
+		return "", pkgError.InternalServerError(fmt.Sprintf("Failed to read backup: %v", err))
+	}
+	defer upload.Close()
+
+	decrypted, err := utils.NewDecryptReader(upload, request.Passphrase)
+	if err != nil {
+		if errors.Is(err, utils.ErrDecryption) {
+			return "", pkgError.ValidationError("backup: not an encrypted account backup")
+		}
+		return "", pkgError.InternalServerError(fmt.Sprintf("Failed to read backup: %v", err))
+	}
+
+	archive, err := os.CreateTemp("", "account-restore-*.zip")
+	if err != nil {
+		return "", pkgError.InternalServerError(fmt.Sprintf("Failed to prepare restore: %v", err))
+	}
+	defer archive.Close()
+
+	if _, err := io.Copy(archive, decrypted); err != nil {
+		os.Remove(archive.Name())
+		if errors.Is(err, utils.ErrDecryption) {
+			return "", pkgError.ValidationError("backup: wrong passphrase or corrupted backup")
+		}
+		return "", pkgError.InternalServerError(fmt.Sprintf("Failed to decrypt backup: %v", err))
+	}
+	return archive.Name(), nil
+}
+
+func readBackupManifest(archive *zip.ReadCloser) (*domainAccount.BackupManifest, error) {
+	entry, err := archive.Open(backupManifestFile)
+	if err != nil {
+		return nil, pkgError.ValidationError(fmt.Sprintf("backup: %s is missing", backupManifestFile))
+	}
+	defer entry.Close()
+
+	var manifest domainAccount.BackupManifest
+	if err := json.NewDecoder(entry).Decode(&manifest); err != nil {
+		return nil, pkgError.ValidationError(fmt.Sprintf("backup: invalid %s: %v", backupManifestFile, err))
+	}
+	if manifest.Version > domainAccount.BackupVersion {
+		return nil, pkgError.ValidationError(fmt.Sprintf("backup version %d is newer than the supported version %d", manifest.Version, domainAccount.BackupVersion))
+	}
+	if manifest.DeviceID == "" {
+		return nil, pkgError.ValidationError("backup: the manifest has no device")
+	}
+	return &manifest, nil
+}
+
+// extractBackupStore writes a store of the backup to a temporary file next to its destination, the
+// caller swaps it in with swapBackupStore and removes it when it wasn't
+func extractBackupStore(archive *zip.ReadCloser, name string, dest string) (string, error) {
+	entry, err := archive.Open(name)
+	if err != nil {
+		return "", fmt.Errorf("%s is missing from the backup", name)
+	}
+	defer entry.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", err
+	}
+	file, err := os.CreateTemp(filepath.Dir(dest), filepath.Base(dest)+".restore-*")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(file, entry); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// swapBackupStore moves an extracted store into place, leftover journal files of the previous store
+// would otherwise be replayed into the restored one
+func swapBackupStore(path string, dest string) error {
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		if err := os.Remove(dest + suffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(path, dest)
+}
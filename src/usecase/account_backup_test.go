@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	infraAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/account"
+	infraChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatstorage"
+	infraWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const backupTestPassphrase = "correct horse battery staple"
+
+// newBackupTestService runs in a temporary working directory, as the device stores of accounts live in storages/
+func newBackupTestService(t *testing.T) *accountService {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.Mkdir("storages", 0o755))
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join("storages", "storage.db")+"?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	chatStorageRepo := infraChatStorage.NewStorageRepository(db)
+	require.NoError(t, chatStorageRepo.InitializeSchema())
+
+	return &accountService{
+		accountRepo:     infraAccount.NewSQLiteRepository(db),
+		accountManager:  infraAccount.NewAccountManager(),
+		chatStorageRepo: chatStorageRepo,
+		webhookRepo:     infraWebhook.NewSQLiteRepository(db),
+	}
+}
+
+// seedBackupAccount creates an account with a webhook, settings, an endpoint, a chat and an empty device store
+func seedBackupAccount(t *testing.T, s *accountService, accountID string) {
+	require.NoError(t, s.accountRepo.CreateAccount(&domainAccount.Account{
+		ID:          accountID,
+		Status:      domainAccount.StatusDisconnected,
+		DeviceID:    "6281234567890:12@s.whatsapp.net",
+		PhoneNumber: "6281234567890",
+		CreatedAt:   time.Now(),
+	}))
+	require.NoError(t, s.accountRepo.SetWebhook(accountID, "https://example.com/hook", "hook-secret"))
+	deviceName := "Sales"
+	require.NoError(t, s.accountRepo.SaveSettings(accountID, &domainAccount.Settings{DeviceName: &deviceName}))
+	require.NoError(t, s.webhookRepo.CreateEndpoint(&domainWebhook.Endpoint{
+		ID: "endpoint-1", AccountID: accountID, URL: "https://example.com/events", Events: []string{"message"},
+	}))
+
+	chatJID := "6289876543210@s.whatsapp.net"
+	sentAt := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, s.chatStorageRepo.StoreChat(&domainChatStorage.Chat{
+		AccountID: accountID, JID: chatJID, Name: "Customer", LastMessageTime: sentAt,
+	}))
+	require.NoError(t, s.chatStorageRepo.StoreMessage(&domainChatStorage.Message{
+		AccountID: accountID, ID: "MSG1", ChatJID: chatJID, Sender: chatJID, Content: "hello", Timestamp: sentAt,
+	}))
+
+	whatsapp.InitWaDB(context.Background(), accountDBURI(accountID)).Close()
+}
+
+func takeBackup(t *testing.T, s *accountService, request domainAccount.BackupRequest) *multipart.FileHeader {
+	backup, err := s.BackupAccount(context.Background(), request)
+	require.NoError(t, err)
+	return uploadBackup(t, backup.Filename, backup.Write)
+}
+
+// uploadBackup turns what write produces into an uploaded backup file
+func uploadBackup(t *testing.T, filename string, write func(io.Writer) error) *multipart.FileHeader {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("backup", filename)
+	require.NoError(t, err)
+	require.NoError(t, write(part))
+	require.NoError(t, form.Close())
+
+	parsed, err := multipart.NewReader(&body, form.Boundary()).ReadForm(32 << 20)
+	require.NoError(t, err)
+	t.Cleanup(func() { parsed.RemoveAll() })
+	return parsed.File["backup"][0]
+}
+
+func TestAccountBackupRoundTrip(t *testing.T) {
+	s := newBackupTestService(t)
+	seedBackupAccount(t, s, "business")
+
+	backup := takeBackup(t, s, domainAccount.BackupRequest{
+		AccountID: "business", Passphrase: backupTestPassphrase, IncludeChats: true, Release: true,
+	})
+
+	// The account isn't loaded here, releasing marks it so it isn't connected again on start
+	source, err := s.accountRepo.GetAccount("business")
+	require.NoError(t, err)
+	assert.Equal(t, domainAccount.StatusReleased, source.Status)
+
+	_, err = s.RestoreAccount(context.Background(), domainAccount.RestoreRequest{
+		AccountID: "copy", Backup: backup, Passphrase: "wrong passphrase",
+	})
+	assert.IsType(t, pkgError.ValidationError(""), err)
+
+	response, err := s.RestoreAccount(context.Background(), domainAccount.RestoreRequest{
+		AccountID: "copy", Backup: backup, Passphrase: backupTestPassphrase,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "6281234567890:12@s.whatsapp.net", response.DeviceID)
+	assert.Equal(t, 1, response.WebhookEndpoints)
+	assert.Equal(t, 1, response.Chats)
+	assert.Equal(t, 1, response.Messages)
+
+	restored, err := s.accountRepo.GetAccount("copy")
+	require.NoError(t, err)
+	require.NotNil(t, restored)
+	assert.Equal(t, "6281234567890", restored.PhoneNumber)
+	assert.Equal(t, domainAccount.StatusDisconnected, restored.Status)
+
+	webhook, err := s.accountRepo.GetWebhook("copy")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", webhook.URL)
+	assert.Equal(t, "hook-secret", webhook.Secret)
+
+	settings, err := s.accountRepo.GetSettings("copy")
+	require.NoError(t, err)
+	require.NotNil(t, settings.DeviceName)
+	assert.Equal(t, "Sales", *settings.DeviceName)
+
+	// Endpoint IDs are unique across accounts, a restore under another ID gets new ones
+	endpoints, err := s.webhookRepo.ListEndpoints("copy")
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "https://example.com/events", endpoints[0].URL)
+	assert.NotEqual(t, "endpoint-1", endpoints[0].ID)
+
+	messages, err := s.chatStorageRepo.GetMessages(&domainChatStorage.MessageFilter{
+		AccountID: "copy", ChatJID: "6289876543210@s.whatsapp.net", Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "hello", messages[0].Content)
+
+	assert.FileExists(t, filepath.Join("storages", "whatsapp_copy.db"))
+	assert.NotNil(t, s.accountManager.GetClient("copy"))
+}
+
+func TestAccountRestoreRequiresRelease(t *testing.T) {
+	s := newBackupTestService(t)
+	seedBackupAccount(t, s, "business")
+
+	// The session was disconnected during the backup, the source can still connect it again
+	backup := takeBackup(t, s, domainAccount.BackupRequest{AccountID: "business", Passphrase: backupTestPassphrase})
+
+	_, err := s.RestoreAccount(context.Background(), domainAccount.RestoreRequest{
+		AccountID: "copy", Backup: backup, Passphrase: backupTestPassphrase,
+	})
+	assert.IsType(t, pkgError.BadRequestError(""), err)
+
+	copied, err := s.accountRepo.GetAccount("copy")
+	require.NoError(t, err)
+	assert.Nil(t, copied)
+
+	_, err = s.RestoreAccount(context.Background(), domainAccount.RestoreRequest{
+		AccountID: "copy", Backup: backup, Passphrase: backupTestPassphrase, Force: true,
+	})
+	require.NoError(t, err)
+}
+
+func TestAccountRestoreKeepsClientOnBrokenBackup(t *testing.T) {
+	s := newBackupTestService(t)
+	previousKeysURI := config.DBKeysURI
+	config.DBKeysURI = "file:storages/whatsapp_keys.db?_foreign_keys=on"
+	t.Cleanup(func() { config.DBKeysURI = previousKeysURI })
+
+	seedBackupAccount(t, s, "business")
+	client, db, err := s.initAccountClient(context.Background(), "business")
+	require.NoError(t, err)
+	s.accountManager.SetClient("business", client, db)
+	deviceStore, err := os.ReadFile(filepath.Join("storages", "whatsapp_business.db"))
+	require.NoError(t, err)
+
+	// The manifest announces a keys store the backup doesn't have
+	backup := uploadBackup(t, "broken.wagobackup", func(w io.Writer) error {
+		encrypted, err := utils.NewEncryptWriter(w, backupTestPassphrase)
+		if err != nil {
+			return err
+		}
+		archive := zip.NewWriter(encrypted)
+		entry, err := archive.Create(backupManifestFile)
+		if err != nil {
+			return err
+		}
+		if err := json.NewEncoder(entry).Encode(domainAccount.BackupManifest{
+			Version: domainAccount.BackupVersion, AccountID: "business", DeviceID: "6281234567890:12@s.whatsapp.net",
+			KeysStore: true, Released: true,
+		}); err != nil {
+			return err
+		}
+		if err := addBackupFile(archive, backupDeviceStoreFile, filepath.Join("storages", "whatsapp_business.db")); err != nil {
+			return err
+		}
+		if err := archive.Close(); err != nil {
+			return err
+		}
+		return encrypted.Close()
+	})
+
+	_, err = s.RestoreAccount(context.Background(), domainAccount.RestoreRequest{
+		AccountID: "business", Backup: backup, Passphrase: backupTestPassphrase,
+	})
+	assert.IsType(t, pkgError.InternalServerError(""), err)
+
+	// Nothing was swapped, the account keeps its client and stores
+	assert.Same(t, client, s.accountManager.GetClient("business"))
+	current, err := os.ReadFile(filepath.Join("storages", "whatsapp_business.db"))
+	require.NoError(t, err)
+	assert.Equal(t, deviceStore, current)
+	leftovers, err := filepath.Glob(filepath.Join("storages", "*.restore-*"))
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainApiKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	apiKeyPrefix = "wago_"
+	// apiKeyTouchInterval limits how often last_used_at is written for a busy key
+	apiKeyTouchInterval = time.Minute
+)
+
+type serviceAPIKey struct {
+	apiKeyRepo  domainApiKey.IAPIKeyRepository
+	accountRepo domainAccount.IAccountRepository
+}
+
+func NewAPIKeyService(apiKeyRepo domainApiKey.IAPIKeyRepository, accountRepo domainAccount.IAccountRepository) domainApiKey.IAPIKeyUsecase {
+	return &serviceAPIKey{
+		apiKeyRepo:  apiKeyRepo,
+		accountRepo: accountRepo,
+	}
+}
+
+func (service serviceAPIKey) CreateAPIKey(ctx context.Context, request domainApiKey.CreateAPIKeyRequest) (response domainApiKey.CreateAPIKeyResponse, err error) {
+	if err = validations.ValidateCreateAPIKey(ctx, &request); err != nil {
+		return response, err
+	}
+
+	accountIDs := uniqueStrings(request.AccountIDs)
+	for _, accountID := range accountIDs {
+		if accountID == domainApiKey.AllAccounts {
+			continue
+		}
+		account, err := service.accountRepo.GetAccount(accountID)
+		if err != nil {
+			return response, pkgError.InternalServerError(fmt.Sprintf("failed to get account: %v", err))
+		}
+		if account == nil {
+			return response, pkgError.NotFoundError(fmt.Sprintf("account %s not found", accountID))
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to generate API key: %v", err))
+	}
+	plainKey := apiKeyPrefix + hex.EncodeToString(secret)
+
+	key := &domainApiKey.APIKey{
+		ID:         uuid.NewString(),
+		Name:       request.Name,
+		Prefix:     plainKey[:len(apiKeyPrefix)+8],
+		KeyHash:    hashAPIKey(plainKey),
+		AccountIDs: accountIDs,
+		Scopes:     uniqueStrings(request.Scopes),
+	}
+	if err = service.apiKeyRepo.CreateAPIKey(key); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to create API key: %v", err))
+	}
+
+	logrus.Infof("[API_KEY] Created key %s (%s) for accounts %v", key.ID, key.Name, key.AccountIDs)
+
+	response.APIKey = *key
+	response.Key = plainKey
+	return response, nil
+}
+
+func (service serviceAPIKey) ListAPIKeys(_ context.Context) (response []domainApiKey.APIKey, err error) {
+	keys, err := service.apiKeyRepo.ListAPIKeys()
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to list API keys: %v", err))
+	}
+
+	response = make([]domainApiKey.APIKey, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, *key)
+	}
+	return response, nil
+}
+
+func (service serviceAPIKey) RevokeAPIKey(_ context.Context, request domainApiKey.RevokeAPIKeyRequest) (err error) {
+	key, err := service.apiKeyRepo.GetAPIKey(request.KeyID)
+	if err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to get API key: %v", err))
+	}
+	if key == nil {
+		return pkgError.NotFoundError(fmt.Sprintf("API key %s not found", request.KeyID))
+	}
+
+	if err = service.apiKeyRepo.RevokeAPIKey(key.ID, time.Now()); err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to revoke API key: %v", err))
+	}
+
+	logrus.Infof("[API_KEY] Revoked key %s (%s)", key.ID, key.Name)
+	return nil
+}
+
+func (service serviceAPIKey) Authenticate(_ context.Context, plainKey string) (*domainApiKey.APIKey, error) {
+	key, err := service.apiKeyRepo.GetAPIKeyByHash(hashAPIKey(plainKey))
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to get API key: %v", err))
+	}
+	if key == nil || key.RevokedAt != nil {
+		return nil, pkgError.AuthError("invalid or revoked API key")
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := service.apiKeyRepo.TouchAPIKey(key.ID, now); err != nil {
+			logrus.Warnf("[API_KEY] Failed to record usage of key %s: %v", key.ID, err)
+		}
+	}
+
+	return key, nil
+}
+
+// hashAPIKey returns the hex SHA-256 of the key. Keys are random, so a fast hash is enough.
+func hashAPIKey(plainKey string) string {
+	sum := sha256.Sum256([]byte(plainKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
@@ -21,17 +22,26 @@ import (
 	"go.mau.fi/whatsmeow"
 )
 
+// serviceApp manages the session of the legacy device. Requests naming an account are handed
+// to the account usecase, so they only ever touch the client of that account.
 type serviceApp struct {
 	chatStorageRepo domainChatStorage.IChatStorageRepository
+	accountUsecase  domainAccount.IAccountUsecase
 }
 
-func NewAppService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainApp.IAppUsecase {
+func NewAppService(chatStorageRepo domainChatStorage.IChatStorageRepository, accountUsecase domainAccount.IAccountUsecase) domainApp.IAppUsecase {
 	return &serviceApp{
 		chatStorageRepo: chatStorageRepo,
+		accountUsecase:  accountUsecase,
 	}
 }
 
-func (service *serviceApp) Login(_ context.Context) (response domainApp.LoginResponse, err error) {
+func (service *serviceApp) Login(ctx context.Context, accountID string) (response domainApp.LoginResponse, err error) {
+	if accountID != "" {
+		login, err := service.accountUsecase.LoginAccount(ctx, accountID)
+		return domainApp.LoginResponse(login), err
+	}
+
 	client := whatsapp.GetClient()
 	if client == nil {
 		return response, pkgError.ErrWaCLI
@@ -124,12 +134,16 @@ func (service *serviceApp) Login(_ context.Context) (response domainApp.LoginRes
 	return response, nil
 }
 
-func (service *serviceApp) LoginWithCode(ctx context.Context, phoneNumber string) (loginCode string, err error) {
+func (service *serviceApp) LoginWithCode(ctx context.Context, accountID string, phoneNumber string) (loginCode string, err error) {
 	if err = validations.ValidateLoginWithCode(ctx, phoneNumber); err != nil {
 		logrus.Errorf("Error when validate login with code: %s", err.Error())
 		return loginCode, err
 	}
 
+	if accountID != "" {
+		return service.accountUsecase.LoginAccountWithCode(ctx, accountID, phoneNumber)
+	}
+
 	client := whatsapp.GetClient()
 	// detect is already logged in
 	if client.Store.ID != nil {
@@ -138,7 +152,7 @@ func (service *serviceApp) LoginWithCode(ctx context.Context, phoneNumber string
 	}
 
 	// reconnect first
-	_ = service.Reconnect(ctx)
+	_ = service.Reconnect(ctx, "")
 
 	logrus.Infof("[DEBUG] Starting phone pairing for number: %s", phoneNumber)
 	loginCode, err = client.PairPhone(ctx, phoneNumber, true, whatsmeow.PairClientChrome, "Chrome (Linux)")
@@ -158,7 +172,11 @@ func (service *serviceApp) LoginWithCode(ctx context.Context, phoneNumber string
 	return loginCode, nil
 }
 
-func (service *serviceApp) Logout(ctx context.Context) (err error) {
+func (service *serviceApp) Logout(ctx context.Context, accountID string) (err error) {
+	if accountID != "" {
+		return service.accountUsecase.LogoutAccount(ctx, accountID)
+	}
+
 	// [DEBUG] Log database state before logout
 	logrus.Info("[DEBUG] Starting logout process...")
 	devices, dbErr := whatsapp.GetDB().GetAllDevices(ctx)
@@ -203,7 +221,11 @@ func (service *serviceApp) Logout(ctx context.Context) (err error) {
 	return nil
 }
 
-func (service *serviceApp) Reconnect(_ context.Context) (err error) {
+func (service *serviceApp) Reconnect(ctx context.Context, accountID string) (err error) {
+	if accountID != "" {
+		return service.accountUsecase.ReconnectAccount(ctx, accountID)
+	}
+
 	logrus.Info("[DEBUG] Starting reconnect process...")
 
 	client := whatsapp.GetClient()
@@ -226,7 +248,15 @@ func (service *serviceApp) Reconnect(_ context.Context) (err error) {
 	return err
 }
 
-func (service *serviceApp) FirstDevice(ctx context.Context) (response domainApp.DevicesResponse, err error) {
+func (service *serviceApp) FirstDevice(ctx context.Context, accountID string) (response domainApp.DevicesResponse, err error) {
+	if accountID != "" {
+		devices, err := service.accountDevices(ctx, accountID)
+		if err != nil || len(devices) == 0 {
+			return response, err
+		}
+		return devices[0], nil
+	}
+
 	if whatsapp.GetClient() == nil {
 		return response, pkgError.ErrWaCLI
 	}
@@ -246,7 +276,11 @@ func (service *serviceApp) FirstDevice(ctx context.Context) (response domainApp.
 	return response, nil
 }
 
-func (service *serviceApp) FetchDevices(ctx context.Context) (response []domainApp.DevicesResponse, err error) {
+func (service *serviceApp) FetchDevices(ctx context.Context, accountID string) (response []domainApp.DevicesResponse, err error) {
+	if accountID != "" {
+		return service.accountDevices(ctx, accountID)
+	}
+
 	if whatsapp.GetClient() == nil {
 		return response, pkgError.ErrWaCLI
 	}
@@ -270,3 +304,20 @@ func (service *serviceApp) FetchDevices(ctx context.Context) (response []domainA
 
 	return response, nil
 }
+
+// accountDevices lists the device an account is paired with, if any
+func (service *serviceApp) accountDevices(ctx context.Context, accountID string) (response []domainApp.DevicesResponse, err error) {
+	client, err := whatsapp.GetClientForAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if client.Store == nil || client.Store.ID == nil {
+		return response, nil
+	}
+
+	d := domainApp.DevicesResponse{Device: client.Store.ID.String(), Name: client.Store.PushName}
+	if d.Name == "" {
+		d.Name = client.Store.BusinessName
+	}
+	return append(response, d), nil
+}
@@ -0,0 +1,399 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type serviceAutoReply struct {
+	autoReplyRepo domainAutoReply.IAutoReplyRepository
+	accountRepo   domainAccount.IAccountRepository
+	templateRepo  domainTemplate.ITemplateRepository
+	sendUsecase   domainSend.ISendUsecase
+}
+
+func NewAutoReplyService(autoReplyRepo domainAutoReply.IAutoReplyRepository, accountRepo domainAccount.IAccountRepository, templateRepo domainTemplate.ITemplateRepository, sendUsecase domainSend.ISendUsecase) domainAutoReply.IAutoReplyUsecase {
+	return &serviceAutoReply{
+		autoReplyRepo: autoReplyRepo,
+		accountRepo:   accountRepo,
+		templateRepo:  templateRepo,
+		sendUsecase:   sendUsecase,
+	}
+}
+
+func (service serviceAutoReply) CreateRule(ctx context.Context, request domainAutoReply.RuleRequest) (response domainAutoReply.Rule, err error) {
+	normalizeRuleRequest(&request)
+	if err = validations.ValidateAutoReplyRule(ctx, &request); err != nil {
+		return response, err
+	}
+	if err = service.ensureAccount(request.AccountID); err != nil {
+		return response, err
+	}
+
+	rule := &domainAutoReply.Rule{ID: uuid.NewString(), AccountID: request.AccountID, Enabled: true}
+	applyRuleRequest(rule, request)
+
+	if err = service.autoReplyRepo.CreateRule(rule); err != nil {
+		return response, ruleStorageError("create", request.Name, err)
+	}
+	return *rule, nil
+}
+
+func (service serviceAutoReply) ListRules(_ context.Context, accountID string) (response []domainAutoReply.Rule, err error) {
+	if err = service.ensureAccount(accountID); err != nil {
+		return response, err
+	}
+
+	rules, err := service.autoReplyRepo.ListRules(accountID)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to list auto-reply rules: %v", err))
+	}
+
+	response = make([]domainAutoReply.Rule, 0, len(rules))
+	for _, rule := range rules {
+		response = append(response, *rule)
+	}
+	return response, nil
+}
+
+func (service serviceAutoReply) GetRule(_ context.Context, request domainAutoReply.RuleIdentifierRequest) (response domainAutoReply.Rule, err error) {
+	rule, err := service.findRule(request.AccountID, request.RuleID)
+	if err != nil {
+		return response, err
+	}
+	return *rule, nil
+}
+
+func (service serviceAutoReply) UpdateRule(ctx context.Context, request domainAutoReply.RuleRequest) (response domainAutoReply.Rule, err error) {
+	normalizeRuleRequest(&request)
+	if err = validations.ValidateAutoReplyRule(ctx, &request); err != nil {
+		return response, err
+	}
+
+	rule, err := service.findRule(request.AccountID, request.RuleID)
+	if err != nil {
+		return response, err
+	}
+	applyRuleRequest(rule, request)
+
+	if err = service.autoReplyRepo.UpdateRule(rule); err != nil {
+		return response, ruleStorageError("update", request.Name, err)
+	}
+	return *rule, nil
+}
+
+func (service serviceAutoReply) DeleteRule(_ context.Context, request domainAutoReply.RuleIdentifierRequest) (err error) {
+	if _, err = service.findRule(request.AccountID, request.RuleID); err != nil {
+		return err
+	}
+
+	if err = service.autoReplyRepo.DeleteRule(request.AccountID, request.RuleID); err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to delete auto-reply rule: %v", err))
+	}
+	return nil
+}
+
+// TestRules evaluates the rules like an incoming message would, without replying or counting a hit
+func (service serviceAutoReply) TestRules(ctx context.Context, request domainAutoReply.TestRequest) (response domainAutoReply.TestResponse, err error) {
+	if err = validations.ValidateTestAutoReply(ctx, request); err != nil {
+		return response, err
+	}
+	if err = service.ensureAccount(request.AccountID); err != nil {
+		return response, err
+	}
+
+	message := domainAutoReply.IncomingMessage{
+		AccountID: request.AccountID,
+		PushName:  request.Name,
+		Text:      request.Text,
+		IsGroup:   request.IsGroup,
+		Timestamp: time.Now(),
+	}
+	if request.At != "" {
+		message.Timestamp, _ = time.Parse(time.RFC3339, request.At)
+	}
+	if request.Phone != "" {
+		sender, err := utils.ParseJID(request.Phone)
+		if err != nil {
+			return response, pkgError.ValidationError(fmt.Sprintf("phone: %v", err))
+		}
+		message.SenderJID = sender.String()
+	}
+
+	rule, coolingDown, err := service.matchRule(message)
+	if err != nil || rule == nil {
+		return response, err
+	}
+
+	response.Matched = true
+	response.Rule = rule
+	response.CoolingDown = coolingDown
+	response.ReplyType = rule.ReplyType
+	response.Reply = renderRuleReply(rule, message)
+	response.MediaURL = rule.MediaURL
+	if rule.ReplyType == domainAutoReply.ReplyTemplate {
+		template, err := service.templateRepo.GetTemplate(rule.AccountID, rule.TemplateID)
+		if err != nil {
+			return response, pkgError.InternalServerError(fmt.Sprintf("failed to get template: %v", err))
+		}
+		if template != nil {
+			response.Reply, _ = utils.RenderPlaceholders(template.Content, ruleVariables(message))
+		}
+	}
+	return response, nil
+}
+
+// Reply answers an incoming message with the first matching rule. It reports true when a rule
+// matched, also when the contact is still within the cooldown of that rule.
+func (service serviceAutoReply) Reply(ctx context.Context, message domainAutoReply.IncomingMessage) (handled bool, err error) {
+	rule, coolingDown, err := service.matchRule(message)
+	if err != nil || rule == nil {
+		return false, err
+	}
+	if coolingDown {
+		logrus.Debugf("[AUTO_REPLY] Rule %s matched message from %s within its cooldown", rule.Name, message.SenderJID)
+		return true, nil
+	}
+
+	if err = service.sendRuleReply(ctx, rule, message); err != nil {
+		return true, fmt.Errorf("rule %s: %w", rule.Name, err)
+	}
+	if err = service.autoReplyRepo.RecordHit(rule.ID, message.SenderJID, time.Now()); err != nil {
+		logrus.Errorf("[AUTO_REPLY] Failed to record hit of rule %s: %v", rule.Name, err)
+	}
+
+	logrus.Infof("[AUTO_REPLY] Rule %s replied to %s", rule.Name, message.ChatJID)
+	return true, nil
+}
+
+// matchRule returns the first enabled rule matching the message, and whether the sender is within its cooldown
+func (service serviceAutoReply) matchRule(message domainAutoReply.IncomingMessage) (*domainAutoReply.Rule, bool, error) {
+	rules, err := service.autoReplyRepo.ListRules(message.AccountID)
+	if err != nil {
+		return nil, false, pkgError.InternalServerError(fmt.Sprintf("failed to list auto-reply rules: %v", err))
+	}
+
+	for _, rule := range rules {
+		if !ruleMatches(rule, message) {
+			continue
+		}
+		if rule.CooldownMinutes <= 0 || message.SenderJID == "" {
+			return rule, false, nil
+		}
+
+		lastReply, err := service.autoReplyRepo.GetLastReply(rule.ID, message.SenderJID)
+		if err != nil {
+			return nil, false, pkgError.InternalServerError(fmt.Sprintf("failed to get last auto-reply: %v", err))
+		}
+		cooldown := time.Duration(rule.CooldownMinutes) * time.Minute
+		return rule, !lastReply.IsZero() && message.Timestamp.Sub(lastReply) < cooldown, nil
+	}
+	return nil, false, nil
+}
+
+// sendRuleReply sends the reply of the rule through the send usecase, so it is stored like any other sent message
+func (service serviceAutoReply) sendRuleReply(ctx context.Context, rule *domainAutoReply.Rule, message domainAutoReply.IncomingMessage) (err error) {
+	// The send usecase panics when the client is not logged in
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%v", recovered)
+		}
+	}()
+
+	base := domainSend.BaseRequest{AccountID: rule.AccountID, Phone: message.ChatJID}
+	text := renderRuleReply(rule, message)
+
+	switch rule.ReplyType {
+	case domainAutoReply.ReplyTemplate:
+		template, err := service.templateRepo.GetTemplate(rule.AccountID, rule.TemplateID)
+		if err != nil {
+			return err
+		}
+		if template == nil {
+			return fmt.Errorf("template %s not found", rule.TemplateID)
+		}
+
+		options := domainSend.TemplateOptions{TemplateID: template.ID, Variables: ruleVariables(message)}
+		switch template.MediaType {
+		case domainTemplate.MediaTypeImage:
+			_, err = service.sendUsecase.SendImage(ctx, domainSend.ImageRequest{BaseRequest: base, TemplateOptions: options})
+		case domainTemplate.MediaTypeFile:
+			_, err = service.sendUsecase.SendFile(ctx, domainSend.FileRequest{BaseRequest: base, TemplateOptions: options})
+		default:
+			_, err = service.sendUsecase.SendText(ctx, domainSend.MessageRequest{BaseRequest: base, TemplateOptions: options})
+		}
+		return err
+	case domainAutoReply.ReplyMedia:
+		mediaURL := rule.MediaURL
+		if rule.MediaType == domainAutoReply.MediaTypeVideo {
+			_, err = service.sendUsecase.SendVideo(ctx, domainSend.VideoRequest{BaseRequest: base, Caption: text, VideoURL: &mediaURL})
+		} else {
+			_, err = service.sendUsecase.SendImage(ctx, domainSend.ImageRequest{BaseRequest: base, Caption: text, ImageURL: &mediaURL})
+		}
+		return err
+	default:
+		_, err = service.sendUsecase.SendText(ctx, domainSend.MessageRequest{BaseRequest: base, Message: text})
+		return err
+	}
+}
+
+func (service serviceAutoReply) findRule(accountID, ruleID string) (*domainAutoReply.Rule, error) {
+	if err := service.ensureAccount(accountID); err != nil {
+		return nil, err
+	}
+
+	rule, err := service.autoReplyRepo.GetRule(accountID, ruleID)
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to get auto-reply rule: %v", err))
+	}
+	if rule == nil {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("auto-reply rule %s not found", ruleID))
+	}
+	return rule, nil
+}
+
+func (service serviceAutoReply) ensureAccount(accountID string) error {
+	account, err := service.accountRepo.GetAccount(accountID)
+	if err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to get account: %v", err))
+	}
+	if account == nil {
+		return pkgError.NotFoundError(fmt.Sprintf("account %s not found", accountID))
+	}
+	return nil
+}
+
+// normalizeRuleRequest trims the request and lowercases the enumerations before validation
+func normalizeRuleRequest(request *domainAutoReply.RuleRequest) {
+	request.Name = strings.TrimSpace(request.Name)
+	request.MatchType = strings.ToLower(strings.TrimSpace(request.MatchType))
+	request.ReplyType = strings.ToLower(strings.TrimSpace(request.ReplyType))
+	request.MediaType = strings.ToLower(strings.TrimSpace(request.MediaType))
+	request.ActiveHours = strings.TrimSpace(request.ActiveHours)
+	request.Timezone = strings.TrimSpace(request.Timezone)
+	for i, day := range request.ActiveDays {
+		request.ActiveDays[i] = strings.ToLower(strings.TrimSpace(day))
+	}
+}
+
+func applyRuleRequest(rule *domainAutoReply.Rule, request domainAutoReply.RuleRequest) {
+	rule.Name = request.Name
+	if request.Enabled != nil {
+		rule.Enabled = *request.Enabled
+	}
+	rule.Priority = request.Priority
+	rule.MatchType = request.MatchType
+	rule.Pattern = request.Pattern
+	rule.CaseSensitive = request.CaseSensitive
+	rule.IncludeGroups = request.IncludeGroups
+	rule.ActiveHours = request.ActiveHours
+	rule.ActiveDays = request.ActiveDays
+	if rule.ActiveDays == nil {
+		rule.ActiveDays = []string{}
+	}
+	rule.Timezone = request.Timezone
+	rule.CooldownMinutes = request.CooldownMinutes
+	rule.ReplyType = request.ReplyType
+	rule.ReplyText = request.ReplyText
+	rule.TemplateID, rule.MediaURL, rule.MediaType = "", "", ""
+	switch request.ReplyType {
+	case domainAutoReply.ReplyTemplate:
+		rule.TemplateID = request.TemplateID
+	case domainAutoReply.ReplyMedia:
+		rule.MediaURL, rule.MediaType = request.MediaURL, request.MediaType
+	}
+}
+
+func ruleStorageError(action, name string, err error) error {
+	// SQLite and PostgreSQL report the unique name constraint differently
+	if strings.Contains(err.Error(), "UNIQUE constraint failed") || strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
+		return pkgError.ValidationError(fmt.Sprintf("name: auto-reply rule %s already exists.", name))
+	}
+	return pkgError.InternalServerError(fmt.Sprintf("failed to %s auto-reply rule: %v", action, err))
+}
+
+// ruleMatches reports whether an enabled rule applies to the message at the time it was received
+func ruleMatches(rule *domainAutoReply.Rule, message domainAutoReply.IncomingMessage) bool {
+	if !rule.Enabled || (message.IsGroup && !rule.IncludeGroups) || !ruleActiveAt(rule, message.Timestamp) {
+		return false
+	}
+
+	flags := "(?i)"
+	if rule.CaseSensitive {
+		flags = ""
+	}
+
+	switch rule.MatchType {
+	case domainAutoReply.MatchAny:
+		return true
+	case domainAutoReply.MatchRegex:
+		pattern, err := regexp.Compile(flags + rule.Pattern)
+		return err == nil && pattern.MatchString(message.Text)
+	case domainAutoReply.MatchKeyword:
+		for _, keyword := range strings.Split(rule.Pattern, ",") {
+			keyword = strings.TrimSpace(keyword)
+			if keyword == "" {
+				continue
+			}
+			// Keywords match whole words, so "price" does not match "priceless"
+			pattern := regexp.MustCompile(flags + `(?:^|[^\p{L}\p{N}_])` + regexp.QuoteMeta(keyword) + `(?:$|[^\p{L}\p{N}_])`)
+			if pattern.MatchString(message.Text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ruleActiveAt checks the active days and hours of the rule in its time zone
+func ruleActiveAt(rule *domainAutoReply.Rule, at time.Time) bool {
+	location, err := time.LoadLocation(rule.Timezone)
+	if err != nil {
+		return false
+	}
+	local := at.In(location)
+
+	if len(rule.ActiveDays) > 0 {
+		today := strings.ToLower(local.Weekday().String()[:3])
+		active := false
+		for _, day := range rule.ActiveDays {
+			active = active || day == today
+		}
+		if !active {
+			return false
+		}
+	}
+
+	window, err := utils.ParseQuietHours(rule.ActiveHours)
+	if err != nil {
+		return false
+	}
+	return window == nil || window.Remaining(local) > 0
+}
+
+func ruleVariables(message domainAutoReply.IncomingMessage) map[string]string {
+	phone := ""
+	if sender, err := types.ParseJID(message.SenderJID); err == nil {
+		phone = sender.User
+	}
+	return map[string]string{"name": message.PushName, "phone": phone}
+}
+
+func renderRuleReply(rule *domainAutoReply.Rule, message domainAutoReply.IncomingMessage) string {
+	reply, _ := utils.RenderPlaceholders(rule.ReplyText, ruleVariables(message))
+	return reply
+}
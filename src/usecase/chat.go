@@ -8,6 +8,7 @@ import (
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
@@ -31,6 +32,7 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 
 	// Create filter from request
 	filter := &domainChatStorage.ChatFilter{
+		AccountID:  request.AccountID,
 		Limit:      request.Limit,
 		Offset:     request.Offset,
 		SearchName: request.Search,
@@ -45,7 +47,7 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 	}
 
 	// Get total count for pagination
-	totalCount, err := service.chatStorageRepo.GetTotalChatCount()
+	totalCount, err := service.chatStorageRepo.GetAccountChatCount(request.AccountID)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get total chat count")
 		// Continue with partial data
@@ -91,7 +93,7 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	}
 
 	// Get chat info first
-	chat, err := service.chatStorageRepo.GetChat(request.ChatJID)
+	chat, err := service.chatStorageRepo.GetChat(request.AccountID, request.ChatJID)
 	if err != nil {
 		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get chat info")
 		return response, err
@@ -102,6 +104,7 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 
 	// Create message filter from request
 	filter := &domainChatStorage.MessageFilter{
+		AccountID: request.AccountID,
 		ChatJID:   request.ChatJID,
 		Limit:     request.Limit,
 		Offset:    request.Offset,
@@ -130,7 +133,7 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	var messages []*domainChatStorage.Message
 	if request.Search != "" {
 		// Use search functionality if search query is provided
-		messages, err = service.chatStorageRepo.SearchMessages(request.ChatJID, request.Search, request.Limit)
+		messages, err = service.chatStorageRepo.SearchMessages(request.AccountID, request.ChatJID, request.Search, request.Limit)
 		if err != nil {
 			logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to search messages")
 			return response, err
@@ -145,29 +148,64 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	}
 
 	// Get total message count for pagination
-	totalCount, err := service.chatStorageRepo.GetChatMessageCount(request.ChatJID)
+	totalCount, err := service.chatStorageRepo.GetChatMessageCount(request.AccountID, request.ChatJID)
 	if err != nil {
 		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get message count")
 		// Continue with partial data
 		totalCount = 0
 	}
 
+	// Load reactions, edit history and receipts for the returned page in one query each
+	messageIDs := make([]string, 0, len(messages))
+	for _, message := range messages {
+		messageIDs = append(messageIDs, message.ID)
+	}
+
+	reactions, err := service.chatStorageRepo.GetReactions(request.AccountID, request.ChatJID, messageIDs)
+	if err != nil {
+		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get message reactions")
+		return response, err
+	}
+
+	edits, err := service.chatStorageRepo.GetMessageEdits(request.AccountID, request.ChatJID, messageIDs)
+	if err != nil {
+		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get message edits")
+		return response, err
+	}
+
+	receipts, err := service.chatStorageRepo.GetReceipts(request.AccountID, messageIDs)
+	if err != nil {
+		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get message receipts")
+		return response, err
+	}
+
+	receiptsByMessage := make(map[string][]*domainChatStorage.MessageReceipt)
+	for _, receipt := range receipts {
+		receiptsByMessage[receipt.MessageID] = append(receiptsByMessage[receipt.MessageID], receipt)
+	}
+
+	reactionsByMessage := aggregateReactions(reactions)
+	editsByMessage := make(map[string][]domainChat.MessageEditInfo)
+	for _, edit := range edits {
+		editsByMessage[edit.MessageID] = append(editsByMessage[edit.MessageID], domainChat.MessageEditInfo{
+			PreviousContent: edit.PreviousContent,
+			Content:         edit.Content,
+			EditedAt:        edit.EditedAt.Format(time.RFC3339),
+		})
+	}
+
 	// Convert entities to domain objects
 	messageInfos := make([]domainChat.MessageInfo, 0, len(messages))
 	for _, message := range messages {
-		messageInfo := domainChat.MessageInfo{
-			ID:         message.ID,
-			ChatJID:    message.ChatJID,
-			SenderJID:  message.Sender,
-			Content:    message.Content,
-			Timestamp:  message.Timestamp.Format(time.RFC3339),
-			IsFromMe:   message.IsFromMe,
-			MediaType:  message.MediaType,
-			Filename:   message.Filename,
-			URL:        message.URL,
-			FileLength: message.FileLength,
-			CreatedAt:  message.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:  message.UpdatedAt.Format(time.RFC3339),
+		messageInfo := newMessageInfo(message)
+		messageInfo.EditHistory = editsByMessage[message.ID]
+		if reactions, ok := reactionsByMessage[message.ID]; ok {
+			messageInfo.Reactions = reactions
+		}
+		if message.IsFromMe {
+			summary := summarizeReceipts(receiptsByMessage[message.ID])
+			messageInfo.DeliveredAt = formatReceiptTime(summary.deliveredAt)
+			messageInfo.ReadAt = formatReceiptTime(summary.readAt)
 		}
 		messageInfos = append(messageInfos, messageInfo)
 	}
@@ -203,13 +241,75 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	return response, nil
 }
 
+func (service serviceChat) SearchMessages(ctx context.Context, request domainChat.SearchMessagesRequest) (response domainChat.SearchMessagesResponse, err error) {
+	if err = validations.ValidateSearchMessages(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.MessageSearchFilter{
+		AccountID: request.AccountID,
+		Query:     request.Query,
+		ChatJID:   request.ChatJID,
+		MediaType: request.MediaType,
+		MediaOnly: request.MediaOnly,
+		IsFromMe:  request.IsFromMe,
+		Limit:     request.Limit,
+		Offset:    request.Offset,
+	}
+
+	if request.Sender != "" {
+		sender, err := utils.ParseJID(request.Sender)
+		if err != nil {
+			return response, pkgError.ValidationError(fmt.Sprintf("sender: %v", err))
+		}
+		filter.Sender = sender.ToNonAD().String()
+	}
+
+	// Time filters are checked by the validation
+	if request.StartTime != nil && *request.StartTime != "" {
+		startTime, _ := time.Parse(time.RFC3339, *request.StartTime)
+		filter.StartTime = &startTime
+	}
+	if request.EndTime != nil && *request.EndTime != "" {
+		endTime, _ := time.Parse(time.RFC3339, *request.EndTime)
+		filter.EndTime = &endTime
+	}
+
+	results, total, err := service.chatStorageRepo.SearchAllMessages(filter)
+	if err != nil {
+		logrus.WithError(err).WithField("query", request.Query).Error("Failed to search messages")
+		return response, err
+	}
+
+	response.Data = make([]domainChat.MessageSearchResult, 0, len(results))
+	for _, result := range results {
+		response.Data = append(response.Data, domainChat.MessageSearchResult{
+			MessageInfo: newMessageInfo(result.Message),
+			Snippet:     result.Snippet,
+			Score:       result.Score,
+		})
+	}
+	response.Pagination = domainChat.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  int(total),
+	}
+
+	return response, nil
+}
+
 func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinChatRequest) (response domainChat.PinChatResponse, err error) {
 	if err = validations.ValidatePinChat(ctx, &request); err != nil {
 		return response, err
 	}
 
 	// Validate JID and ensure connection
-	targetJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.ChatJID)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return response, err
+	}
+
+	targetJID, err := utils.ValidateJidWithLogin(client, request.ChatJID)
 	if err != nil {
 		return response, err
 	}
@@ -218,7 +318,7 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 	patchInfo := appstate.BuildPin(targetJID, request.Pinned)
 
 	// Send app state update
-	if err = whatsapp.GetClient().SendAppState(ctx, patchInfo); err != nil {
+	if err = client.SendAppState(ctx, patchInfo); err != nil {
 		logrus.WithError(err).WithFields(logrus.Fields{
 			"chat_jid": request.ChatJID,
 			"pinned":   request.Pinned,
@@ -244,3 +344,52 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 
 	return response, nil
 }
+
+// aggregateReactions groups reactions by message and emoji, keeping the order in which each emoji was first used
+func aggregateReactions(reactions []*domainChatStorage.MessageReaction) map[string][]domainChat.ReactionInfo {
+	result := make(map[string][]domainChat.ReactionInfo)
+	for _, reaction := range reactions {
+		infos := result[reaction.MessageID]
+
+		found := false
+		for i := range infos {
+			if infos[i].Emoji == reaction.Emoji {
+				infos[i].Count++
+				infos[i].Senders = append(infos[i].Senders, reaction.Sender)
+				found = true
+				break
+			}
+		}
+		if !found {
+			infos = append(infos, domainChat.ReactionInfo{
+				Emoji:   reaction.Emoji,
+				Count:   1,
+				Senders: []string{reaction.Sender},
+			})
+		}
+
+		result[reaction.MessageID] = infos
+	}
+	return result
+}
+
+// newMessageInfo converts a stored message, reactions, edit history and receipts are filled in by the caller
+func newMessageInfo(message *domainChatStorage.Message) domainChat.MessageInfo {
+	return domainChat.MessageInfo{
+		ID:         message.ID,
+		ChatJID:    message.ChatJID,
+		SenderJID:  message.Sender,
+		Content:    message.Content,
+		Timestamp:  message.Timestamp.Format(time.RFC3339),
+		IsFromMe:   message.IsFromMe,
+		MediaType:  message.MediaType,
+		Filename:   message.Filename,
+		URL:        message.URL,
+		FileLength: message.FileLength,
+		IsRevoked:  message.IsRevoked,
+		EditCount:  message.EditCount,
+		Reactions:  []domainChat.ReactionInfo{},
+		CreatedAt:  message.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  message.UpdatedAt.Format(time.RFC3339),
+	}
+}
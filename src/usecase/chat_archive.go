@@ -0,0 +1,668 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+)
+
+const (
+	// exportPageSize is the number of messages loaded from storage at a time while exporting
+	exportPageSize = 500
+	// importBatchSize is the number of archived messages stored per transaction while importing
+	importBatchSize = 500
+	// maxArchiveLineSize bounds a single archive record, a message with a long edit history stays well below it
+	maxArchiveLineSize = 16 * 1024 * 1024
+)
+
+// ExportChats validates the export and loads the chats up front, messages are streamed by the returned Write
+func (service serviceChat) ExportChats(ctx context.Context, request domainChat.ExportChatsRequest) (export domainChat.ChatExport, err error) {
+	if err = validations.ValidateExportChats(ctx, &request); err != nil {
+		return export, err
+	}
+
+	exporter := &chatExporter{
+		ctx:     ctx,
+		repo:    service.chatStorageRepo,
+		request: request,
+	}
+
+	// Time filters are checked by the validation
+	if request.StartTime != nil && *request.StartTime != "" {
+		startTime, _ := time.Parse(time.RFC3339, *request.StartTime)
+		exporter.startTime = &startTime
+	}
+	if request.EndTime != nil && *request.EndTime != "" {
+		endTime, _ := time.Parse(time.RFC3339, *request.EndTime)
+		exporter.endTime = &endTime
+	}
+
+	if request.ChatJID != "" {
+		chat, err := service.chatStorageRepo.GetChat(request.AccountID, request.ChatJID)
+		if err != nil {
+			return export, err
+		}
+		if chat == nil {
+			return export, pkgError.NotFoundError(fmt.Sprintf("chat with JID %s not found", request.ChatJID))
+		}
+		exporter.chats = []*domainChatStorage.Chat{chat}
+	} else {
+		exporter.chats, err = service.chatStorageRepo.GetChats(&domainChatStorage.ChatFilter{AccountID: request.AccountID})
+		if err != nil {
+			return export, err
+		}
+	}
+
+	// Media is downloaded again from WhatsApp, so the account has to be online
+	if request.IncludeMedia {
+		exporter.client, err = whatsapp.GetClientForAccount(ctx, request.AccountID)
+		if err != nil {
+			return export, err
+		}
+		utils.MustLogin(exporter.client)
+	}
+
+	export.Filename, export.ContentType = exporter.output()
+	export.Write = exporter.write
+	return export, nil
+}
+
+// ImportChats loads a JSON Lines archive into the chat storage. Messages that are already stored
+// are skipped, so importing the same archive twice does not duplicate edits or reactions.
+func (service serviceChat) ImportChats(ctx context.Context, request domainChat.ImportChatsRequest) (response domainChat.ImportChatsResponse, err error) {
+	scanner := bufio.NewScanner(request.Archive)
+	scanner.Buffer(make([]byte, 64*1024), maxArchiveLineSize)
+
+	importer := &chatImporter{repo: service.chatStorageRepo, chats: make(map[string]bool)}
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var record domainChat.ArchiveRecord
+		if err = json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return response, pkgError.ValidationError(fmt.Sprintf("line %d: %v", line, err))
+		}
+
+		if importer.header == nil {
+			if record.Type != domainChat.ArchiveRecordHeader || record.Archive == nil {
+				return response, pkgError.ValidationError(fmt.Sprintf("line %d: the archive must start with an %q record", line, domainChat.ArchiveRecordHeader))
+			}
+			if record.Archive.Version > domainChat.ArchiveVersion {
+				return response, pkgError.ValidationError(fmt.Sprintf("archive version %d is newer than the supported version %d", record.Archive.Version, domainChat.ArchiveVersion))
+			}
+			importer.header = record.Archive
+			importer.accountID = record.Archive.AccountID
+			if request.AccountID != "" {
+				importer.accountID = request.AccountID
+			}
+			continue
+		}
+
+		switch {
+		case record.Type == domainChat.ArchiveRecordChat && record.Chat != nil:
+			err = importer.storeChat(record.Chat)
+		case record.Type == domainChat.ArchiveRecordMessage && record.Message != nil:
+			err = importer.addMessage(record.Message)
+		default:
+			err = pkgError.ValidationError(fmt.Sprintf("unknown record type %q", record.Type))
+		}
+		if err != nil {
+			return importer.response(), fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return importer.response(), pkgError.ValidationError(fmt.Sprintf("line %d: %v", line+1, err))
+	}
+	if importer.header == nil {
+		return response, pkgError.ValidationError("the archive is empty")
+	}
+	if err = importer.flush(); err != nil {
+		return importer.response(), err
+	}
+
+	response = importer.response()
+	logrus.WithFields(logrus.Fields{
+		"account_id":       response.AccountID,
+		"chats":            response.Chats,
+		"messages":         response.Messages,
+		"skipped_messages": response.SkippedMessages,
+	}).Info("Imported chat archive successfully")
+
+	return response, nil
+}
+
+// chatExporter writes the chats of an export, a client is only set when media is bundled
+type chatExporter struct {
+	ctx       context.Context
+	repo      domainChatStorage.IChatStorageRepository
+	client    *whatsmeow.Client
+	request   domainChat.ExportChatsRequest
+	chats     []*domainChatStorage.Chat
+	startTime *time.Time
+	endTime   *time.Time
+}
+
+// zipped reports whether the export holds several files: bundled media, or one text file per chat
+func (e *chatExporter) zipped() bool {
+	return e.request.IncludeMedia || (e.request.Format == domainChat.ExportFormatTXT && e.request.ChatJID == "")
+}
+
+// baseName names the export after the chat like WhatsApp does, or after the account
+func (e *chatExporter) baseName() string {
+	if e.request.ChatJID != "" {
+		return "WhatsApp Chat with " + sanitizeExportFilename(chatDisplayName(e.chats[0]))
+	}
+	if e.request.AccountID == "" {
+		return "WhatsApp Chats"
+	}
+	return "WhatsApp Chats " + sanitizeExportFilename(e.request.AccountID)
+}
+
+func (e *chatExporter) output() (filename string, contentType string) {
+	switch {
+	case e.zipped():
+		return e.baseName() + ".zip", "application/zip"
+	case e.request.Format == domainChat.ExportFormatTXT:
+		return e.baseName() + ".txt", "text/plain; charset=utf-8"
+	default:
+		return e.baseName() + ".jsonl", "application/x-ndjson"
+	}
+}
+
+func (e *chatExporter) write(w io.Writer) error {
+	if !e.zipped() {
+		if e.request.Format == domainChat.ExportFormatTXT {
+			return e.writeTXT(w, e.chats[0], nil, "")
+		}
+		return e.writeJSONL(w, nil)
+	}
+
+	archive := zip.NewWriter(w)
+	if e.request.Format == domainChat.ExportFormatTXT {
+		for _, chat := range e.chats {
+			folder := e.chatFolder(chat)
+			name := path.Join(folder, "WhatsApp Chat with "+sanitizeExportFilename(chatDisplayName(chat))+".txt")
+			err := spoolZipEntry(archive, name, func(body io.Writer) error {
+				return e.writeTXT(body, chat, archive, folder)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		err := spoolZipEntry(archive, e.baseName()+".jsonl", func(body io.Writer) error {
+			return e.writeJSONL(body, archive)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// chatFolder keeps the files of each chat apart in account-wide zips, a single chat is kept at the root
+func (e *chatExporter) chatFolder(chat *domainChatStorage.Chat) string {
+	if e.request.ChatJID != "" {
+		return ""
+	}
+	if phone := utils.ExtractPhoneNumber(chat.JID); phone != "" {
+		return phone
+	}
+	return sanitizeExportFilename(chat.JID)
+}
+
+// writeJSONL writes the archive header followed by every chat and its messages, media goes into the zip when given
+func (e *chatExporter) writeJSONL(w io.Writer, archive *zip.Writer) error {
+	encoder := json.NewEncoder(w)
+	err := encoder.Encode(domainChat.ArchiveRecord{
+		Type: domainChat.ArchiveRecordHeader,
+		Archive: &domainChat.ArchiveHeader{
+			Version:    domainChat.ArchiveVersion,
+			AccountID:  e.request.AccountID,
+			ExportedAt: time.Now().UTC(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, chat := range e.chats {
+		err := encoder.Encode(domainChat.ArchiveRecord{
+			Type: domainChat.ArchiveRecordChat,
+			Chat: &domainChat.ArchiveChat{
+				JID:                 chat.JID,
+				Name:                chat.Name,
+				LastMessageTime:     chat.LastMessageTime,
+				EphemeralExpiration: chat.EphemeralExpiration,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		folder := e.chatFolder(chat)
+		err = e.forEachMessage(chat, func(message *domainChat.ArchiveMessage, stored *domainChatStorage.Message) error {
+			if archive != nil {
+				if err := e.bundleMedia(archive, folder, stored, message); err != nil {
+					return err
+				}
+			}
+			return encoder.Encode(domainChat.ArchiveRecord{Type: domainChat.ArchiveRecordMessage, Message: message})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeTXT writes a chat the way WhatsApp's "Export chat" does, media goes into the zip when given
+func (e *chatExporter) writeTXT(w io.Writer, chat *domainChatStorage.Chat, archive *zip.Writer, folder string) error {
+	buffered := bufio.NewWriter(w)
+	isGroup := utils.IsGroupJID(chat.JID)
+
+	err := e.forEachMessage(chat, func(message *domainChat.ArchiveMessage, stored *domainChatStorage.Message) error {
+		if archive != nil {
+			if err := e.bundleMedia(archive, folder, stored, message); err != nil {
+				return err
+			}
+		}
+
+		sender := "You"
+		if !message.IsFromMe {
+			sender = utils.ExtractPhoneNumber(message.SenderJID)
+			if !isGroup && chat.Name != "" {
+				sender = chat.Name
+			}
+		}
+
+		var text string
+		switch {
+		case message.IsRevoked:
+			text = "This message was deleted"
+		case message.MediaPath != "":
+			text = path.Base(message.MediaPath) + " (file attached)"
+			if message.Content != "" {
+				text += "\n" + message.Content
+			}
+		case message.MediaType != "":
+			text = "<Media omitted>"
+			if message.Content != "" {
+				text += "\n" + message.Content
+			}
+		default:
+			text = message.Content
+		}
+		if len(message.EditHistory) > 0 && !message.IsRevoked {
+			text += " <This message was edited>"
+		}
+
+		_, err := fmt.Fprintf(buffered, "%s - %s: %s\n", message.Timestamp.Format("02/01/2006, 15:04"), sender, text)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return buffered.Flush()
+}
+
+// forEachMessage loads the messages of a chat oldest first, together with their edits, reactions and receipts
+func (e *chatExporter) forEachMessage(chat *domainChatStorage.Chat, fn func(message *domainChat.ArchiveMessage, stored *domainChatStorage.Message) error) error {
+	filter := &domainChatStorage.MessageFilter{
+		AccountID:   e.request.AccountID,
+		ChatJID:     chat.JID,
+		Limit:       exportPageSize,
+		StartTime:   e.startTime,
+		EndTime:     e.endTime,
+		OldestFirst: true,
+	}
+
+	for {
+		messages, err := e.repo.GetMessages(filter)
+		if err != nil {
+			return fmt.Errorf("failed to get messages of %s: %w", chat.JID, err)
+		}
+
+		messageIDs := make([]string, 0, len(messages))
+		for _, message := range messages {
+			messageIDs = append(messageIDs, message.ID)
+		}
+
+		edits, err := e.repo.GetMessageEdits(e.request.AccountID, chat.JID, messageIDs)
+		if err != nil {
+			return fmt.Errorf("failed to get message edits of %s: %w", chat.JID, err)
+		}
+		reactions, err := e.repo.GetReactions(e.request.AccountID, chat.JID, messageIDs)
+		if err != nil {
+			return fmt.Errorf("failed to get message reactions of %s: %w", chat.JID, err)
+		}
+		receipts, err := e.repo.GetReceipts(e.request.AccountID, messageIDs)
+		if err != nil {
+			return fmt.Errorf("failed to get message receipts of %s: %w", chat.JID, err)
+		}
+
+		editsByMessage := make(map[string][]domainChat.ArchiveEdit)
+		for _, edit := range edits {
+			editsByMessage[edit.MessageID] = append(editsByMessage[edit.MessageID], domainChat.ArchiveEdit{
+				PreviousContent: edit.PreviousContent,
+				Content:         edit.Content,
+				EditedAt:        edit.EditedAt,
+			})
+		}
+		reactionsByMessage := make(map[string][]domainChat.ArchiveReaction)
+		for _, reaction := range reactions {
+			reactionsByMessage[reaction.MessageID] = append(reactionsByMessage[reaction.MessageID], domainChat.ArchiveReaction{
+				SenderJID: reaction.Sender,
+				Emoji:     reaction.Emoji,
+				Timestamp: reaction.Timestamp,
+			})
+		}
+		receiptsByMessage := make(map[string][]domainChat.ArchiveReceipt)
+		for _, receipt := range receipts {
+			receiptsByMessage[receipt.MessageID] = append(receiptsByMessage[receipt.MessageID], domainChat.ArchiveReceipt{
+				Recipient:   receipt.Recipient,
+				Participant: receipt.Participant,
+				DeliveredAt: receipt.DeliveredAt,
+				ReadAt:      receipt.ReadAt,
+			})
+		}
+
+		for _, message := range messages {
+			archived := newArchiveMessage(message)
+			archived.EditHistory = editsByMessage[message.ID]
+			archived.Reactions = reactionsByMessage[message.ID]
+			archived.Receipts = receiptsByMessage[message.ID]
+			if err := fn(archived, message); err != nil {
+				return err
+			}
+		}
+
+		if len(messages) < exportPageSize {
+			return nil
+		}
+		filter.Offset += len(messages)
+	}
+}
+
+// bundleMedia downloads the media of a message into the zip. A media that can no longer be
+// downloaded is noted on the message instead of failing the whole export.
+func (e *chatExporter) bundleMedia(archive *zip.Writer, folder string, stored *domainChatStorage.Message, message *domainChat.ArchiveMessage) error {
+	if stored.MediaType == "" || stored.IsRevoked {
+		return nil
+	}
+	if stored.URL == "" {
+		message.MediaError = "the media URL was not stored"
+		return nil
+	}
+
+	extracted, err := downloadStoredMedia(e.ctx, e.client, stored)
+	if err != nil {
+		logrus.WithError(err).WithField("message_id", stored.ID).Warn("Failed to download media for chat export")
+		message.MediaError = err.Error()
+		return nil
+	}
+
+	file, err := os.Open(extracted.MediaPath)
+	if err != nil {
+		return fmt.Errorf("failed to open downloaded media: %w", err)
+	}
+	defer file.Close()
+
+	// Media is compressed already, storing it keeps the export fast
+	name := path.Join(folder, filepath.Base(extracted.MediaPath))
+	entry, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: stored.Timestamp})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(entry, file); err != nil {
+		return err
+	}
+
+	message.MediaPath = name
+	return nil
+}
+
+// spoolZipEntry writes an entry through a temporary file, a zip only takes one entry at a time
+// and bundled media is added while the entry is generated
+func spoolZipEntry(archive *zip.Writer, name string, fn func(body io.Writer) error) error {
+	spool, err := os.CreateTemp("", "chat-export-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	buffered := bufio.NewWriter(spool)
+	if err := fn(buffered); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, spool)
+	return err
+}
+
+// chatImporter stores archived messages in batches, a chat has to be imported before its messages
+type chatImporter struct {
+	repo      domainChatStorage.IChatStorageRepository
+	header    *domainChat.ArchiveHeader
+	accountID string
+	chats     map[string]bool
+	pending   []*domainChat.ArchiveMessage
+	imported  domainChat.ImportChatsResponse
+}
+
+func (i *chatImporter) response() domainChat.ImportChatsResponse {
+	response := i.imported
+	response.AccountID = i.accountID
+	return response
+}
+
+func (i *chatImporter) storeChat(chat *domainChat.ArchiveChat) error {
+	if chat.JID == "" {
+		return pkgError.ValidationError("chat: jid is required")
+	}
+	if err := i.flush(); err != nil {
+		return err
+	}
+
+	err := i.repo.StoreChat(&domainChatStorage.Chat{
+		AccountID:           i.accountID,
+		JID:                 chat.JID,
+		Name:                chat.Name,
+		LastMessageTime:     chat.LastMessageTime,
+		EphemeralExpiration: chat.EphemeralExpiration,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store chat %s: %w", chat.JID, err)
+	}
+
+	i.chats[chat.JID] = true
+	i.imported.Chats++
+	return nil
+}
+
+func (i *chatImporter) addMessage(message *domainChat.ArchiveMessage) error {
+	if message.ID == "" {
+		return pkgError.ValidationError("message: id is required")
+	}
+	if !i.chats[message.ChatJID] {
+		return pkgError.ValidationError(fmt.Sprintf("message %s comes before its chat %s", message.ID, message.ChatJID))
+	}
+
+	existing, err := i.repo.GetMessageByID(i.accountID, message.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check message %s: %w", message.ID, err)
+	}
+	if existing != nil {
+		i.imported.SkippedMessages++
+		return nil
+	}
+
+	i.pending = append(i.pending, message)
+	if len(i.pending) >= importBatchSize {
+		return i.flush()
+	}
+	return nil
+}
+
+// flush stores the pending messages with their original content, then replays the edits so the
+// history and edit count come out as they were exported
+func (i *chatImporter) flush() error {
+	if len(i.pending) == 0 {
+		return nil
+	}
+
+	messages := make([]*domainChatStorage.Message, 0, len(i.pending))
+	for _, archived := range i.pending {
+		message := &domainChatStorage.Message{
+			AccountID:     i.accountID,
+			ID:            archived.ID,
+			ChatJID:       archived.ChatJID,
+			Sender:        archived.SenderJID,
+			Content:       archived.Content,
+			Timestamp:     archived.Timestamp,
+			IsFromMe:      archived.IsFromMe,
+			MediaType:     archived.MediaType,
+			Filename:      archived.Filename,
+			URL:           archived.URL,
+			MediaKey:      archived.MediaKey,
+			FileSHA256:    archived.FileSHA256,
+			FileEncSHA256: archived.FileEncSHA256,
+			FileLength:    archived.FileLength,
+		}
+		if len(archived.EditHistory) > 0 {
+			message.Content = archived.EditHistory[0].PreviousContent
+		}
+		messages = append(messages, message)
+	}
+	if err := i.repo.StoreMessagesBatch(messages); err != nil {
+		return fmt.Errorf("failed to store messages: %w", err)
+	}
+
+	for index, archived := range i.pending {
+		// Messages without content or media are not stored
+		if messages[index].Content == "" && messages[index].MediaType == "" {
+			i.imported.SkippedMessages++
+			continue
+		}
+
+		for _, edit := range archived.EditHistory {
+			err := i.repo.StoreMessageEdit(&domainChatStorage.MessageEdit{
+				AccountID: i.accountID,
+				ChatJID:   archived.ChatJID,
+				MessageID: archived.ID,
+				Content:   edit.Content,
+				EditedAt:  edit.EditedAt,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to store edit of message %s: %w", archived.ID, err)
+			}
+		}
+		if archived.IsRevoked {
+			if err := i.repo.MarkMessageRevoked(i.accountID, archived.ID, archived.ChatJID); err != nil {
+				return fmt.Errorf("failed to revoke message %s: %w", archived.ID, err)
+			}
+		}
+		for _, reaction := range archived.Reactions {
+			err := i.repo.StoreReaction(&domainChatStorage.MessageReaction{
+				AccountID: i.accountID,
+				ChatJID:   archived.ChatJID,
+				MessageID: archived.ID,
+				Sender:    reaction.SenderJID,
+				Emoji:     reaction.Emoji,
+				Timestamp: reaction.Timestamp,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to store reaction to message %s: %w", archived.ID, err)
+			}
+		}
+		for _, receipt := range archived.Receipts {
+			err := i.repo.StoreReceipt(&domainChatStorage.MessageReceipt{
+				AccountID:   i.accountID,
+				ChatJID:     archived.ChatJID,
+				MessageID:   archived.ID,
+				Recipient:   receipt.Recipient,
+				Participant: receipt.Participant,
+				DeliveredAt: receipt.DeliveredAt,
+				ReadAt:      receipt.ReadAt,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to store receipt of message %s: %w", archived.ID, err)
+			}
+		}
+		i.imported.Messages++
+	}
+
+	i.pending = i.pending[:0]
+	return nil
+}
+
+func newArchiveMessage(message *domainChatStorage.Message) *domainChat.ArchiveMessage {
+	return &domainChat.ArchiveMessage{
+		ID:            message.ID,
+		ChatJID:       message.ChatJID,
+		SenderJID:     message.Sender,
+		Content:       message.Content,
+		Timestamp:     message.Timestamp,
+		IsFromMe:      message.IsFromMe,
+		MediaType:     message.MediaType,
+		Filename:      message.Filename,
+		URL:           message.URL,
+		MediaKey:      message.MediaKey,
+		FileSHA256:    message.FileSHA256,
+		FileEncSHA256: message.FileEncSHA256,
+		FileLength:    message.FileLength,
+		IsRevoked:     message.IsRevoked,
+	}
+}
+
+// chatDisplayName falls back to the phone number of chats without a name
+func chatDisplayName(chat *domainChatStorage.Chat) string {
+	if chat.Name != "" {
+		return chat.Name
+	}
+	return utils.ExtractPhoneNumber(chat.JID)
+}
+
+// sanitizeExportFilename drops the characters that are not allowed in file names on common systems
+func sanitizeExportFilename(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
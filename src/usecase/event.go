@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainEvent "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/event"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+type serviceEvent struct {
+	accountRepo domainAccount.IAccountRepository
+}
+
+func NewEventService(accountRepo domainAccount.IAccountRepository) domainEvent.IEventUsecase {
+	return &serviceEvent{
+		accountRepo: accountRepo,
+	}
+}
+
+func (service *serviceEvent) Subscribe(ctx context.Context, request domainEvent.SubscribeRequest) (response *domainEvent.Subscription, err error) {
+	if err = validations.ValidateSubscribeEvents(ctx, request); err != nil {
+		return nil, err
+	}
+	if config.EventStreamBuffer <= 0 {
+		return nil, pkgError.BadRequestError("event stream is disabled, set --event-stream-buffer above 0 to enable it")
+	}
+
+	if request.AccountID != "" {
+		account, err := service.accountRepo.GetAccount(request.AccountID)
+		if err != nil {
+			return nil, pkgError.InternalServerError(fmt.Sprintf("failed to get account: %v", err))
+		}
+		if account == nil {
+			return nil, pkgError.NotFoundError(fmt.Sprintf("account %s not found", request.AccountID))
+		}
+	}
+
+	return whatsapp.SubscribeEvents(request.AccountID, request.Events, request.Cursor), nil
+}
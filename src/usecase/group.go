@@ -26,9 +26,13 @@ func (service serviceGroup) JoinGroupWithLink(ctx context.Context, request domai
 	if err = validations.ValidateJoinGroupWithLink(ctx, request); err != nil {
 		return groupID, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return groupID, err
+	}
+	utils.MustLogin(client)
 
-	jid, err := whatsapp.GetClient().JoinGroupWithLink(request.Link)
+	jid, err := client.JoinGroupWithLink(ctx, request.Link)
 	if err != nil {
 		return
 	}
@@ -40,21 +44,30 @@ func (service serviceGroup) LeaveGroup(ctx context.Context, request domainGroup.
 		return err
 	}
 
-	JID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return err
+	}
+
+	JID, err := utils.ValidateJidWithLogin(client, request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().LeaveGroup(JID)
+	return client.LeaveGroup(ctx, JID)
 }
 
 func (service serviceGroup) CreateGroup(ctx context.Context, request domainGroup.CreateGroupRequest) (groupID string, err error) {
 	if err = validations.ValidateCreateGroup(ctx, request); err != nil {
 		return groupID, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return groupID, err
+	}
+	utils.MustLogin(client)
 
-	participantsJID, err := service.participantToJID(request.Participants)
+	participantsJID, err := service.participantToJID(client, request.Participants)
 	if err != nil {
 		return
 	}
@@ -66,7 +79,7 @@ func (service serviceGroup) CreateGroup(ctx context.Context, request domainGroup
 		GroupLinkedParent: types.GroupLinkedParent{},
 	}
 
-	groupInfo, err := whatsapp.GetClient().CreateGroup(ctx, groupConfig)
+	groupInfo, err := client.CreateGroup(ctx, groupConfig)
 	if err != nil {
 		return
 	}
@@ -78,9 +91,13 @@ func (service serviceGroup) GetGroupInfoFromLink(ctx context.Context, request do
 	if err = validations.ValidateGetGroupInfoFromLink(ctx, request); err != nil {
 		return response, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return response, err
+	}
+	utils.MustLogin(client)
 
-	groupInfo, err := whatsapp.GetClient().GetGroupInfoFromLink(request.Link)
+	groupInfo, err := client.GetGroupInfoFromLink(ctx, request.Link)
 	if err != nil {
 		return response, err
 	}
@@ -104,19 +121,23 @@ func (service serviceGroup) ManageParticipant(ctx context.Context, request domai
 	if err = validations.ValidateParticipant(ctx, request); err != nil {
 		return result, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return result, err
+	}
+	utils.MustLogin(client)
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
 	if err != nil {
 		return result, err
 	}
 
-	participantsJID, err := service.participantToJID(request.Participants)
+	participantsJID, err := service.participantToJID(client, request.Participants)
 	if err != nil {
 		return result, err
 	}
 
-	participants, err := whatsapp.GetClient().UpdateGroupParticipants(groupJID, participantsJID, request.Action)
+	participants, err := client.UpdateGroupParticipants(ctx, groupJID, participantsJID, request.Action)
 	if err != nil {
 		return result, err
 	}
@@ -145,12 +166,17 @@ func (service serviceGroup) GetGroupParticipants(ctx context.Context, request do
 		return response, err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
 	if err != nil {
 		return response, err
 	}
 
-	groupInfo, err := whatsapp.GetClient().GetGroupInfo(groupJID)
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	if err != nil {
+		return response, err
+	}
+
+	groupInfo, err := client.GetGroupInfo(ctx, groupJID)
 	if err != nil {
 		return response, err
 	}
@@ -181,12 +207,17 @@ func (service serviceGroup) GetGroupRequestParticipants(ctx context.Context, req
 		return result, err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return result, err
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
 	if err != nil {
 		return result, err
 	}
 
-	participants, err := whatsapp.GetClient().GetGroupRequestParticipants(groupJID)
+	participants, err := client.GetGroupRequestParticipants(ctx, groupJID)
 	if err != nil {
 		return result, err
 	}
@@ -206,17 +237,22 @@ func (service serviceGroup) ManageGroupRequestParticipants(ctx context.Context,
 		return result, err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return result, err
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
 	if err != nil {
 		return result, err
 	}
 
-	participantsJID, err := service.participantToJID(request.Participants)
+	participantsJID, err := service.participantToJID(client, request.Participants)
 	if err != nil {
 		return result, err
 	}
 
-	participants, err := whatsapp.GetClient().UpdateGroupRequestParticipants(groupJID, participantsJID, request.Action)
+	participants, err := client.UpdateGroupRequestParticipants(ctx, groupJID, participantsJID, request.Action)
 	if err != nil {
 		return result, err
 	}
@@ -240,12 +276,12 @@ func (service serviceGroup) ManageGroupRequestParticipants(ctx context.Context,
 	return result, nil
 }
 
-func (service serviceGroup) participantToJID(participants []string) ([]types.JID, error) {
+func (service serviceGroup) participantToJID(client *whatsmeow.Client, participants []string) ([]types.JID, error) {
 	var participantsJID []types.JID
 	for _, participant := range participants {
 		formattedParticipant := participant + config.WhatsappTypeUser
 
-		if !utils.IsOnWhatsapp(whatsapp.GetClient(), formattedParticipant) {
+		if !utils.IsOnWhatsapp(client, formattedParticipant) {
 			return nil, pkgError.ErrUserNotRegistered
 		}
 
@@ -261,7 +297,12 @@ func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGro
 		return pictureID, err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return pictureID, err
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
 	if err != nil {
 		return pictureID, err
 	}
@@ -284,7 +325,7 @@ func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGro
 		photoBytes = processedImageBuffer.Bytes()
 	}
 
-	pictureID, err = whatsapp.GetClient().SetGroupPhoto(groupJID, photoBytes)
+	pictureID, err = client.SetGroupPhoto(ctx, groupJID, photoBytes)
 	if err != nil {
 		logrus.Printf("Failed to set group photo: %v", err)
 		return pictureID, err
@@ -298,12 +339,17 @@ func (service serviceGroup) SetGroupName(ctx context.Context, request domainGrou
 		return err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return err
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().SetGroupName(groupJID, request.Name)
+	return client.SetGroupName(ctx, groupJID, request.Name)
 }
 
 func (service serviceGroup) SetGroupLocked(ctx context.Context, request domainGroup.SetGroupLockedRequest) (err error) {
@@ -311,12 +357,17 @@ func (service serviceGroup) SetGroupLocked(ctx context.Context, request domainGr
 		return err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().SetGroupLocked(groupJID, request.Locked)
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	if err != nil {
+		return err
+	}
+
+	return client.SetGroupLocked(ctx, groupJID, request.Locked)
 }
 
 func (service serviceGroup) SetGroupAnnounce(ctx context.Context, request domainGroup.SetGroupAnnounceRequest) (err error) {
@@ -324,12 +375,17 @@ func (service serviceGroup) SetGroupAnnounce(ctx context.Context, request domain
 		return err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().SetGroupAnnounce(groupJID, request.Announce)
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	if err != nil {
+		return err
+	}
+
+	return client.SetGroupAnnounce(ctx, groupJID, request.Announce)
 }
 
 func (service serviceGroup) SetGroupTopic(ctx context.Context, request domainGroup.SetGroupTopicRequest) (err error) {
@@ -337,13 +393,18 @@ func (service serviceGroup) SetGroupTopic(ctx context.Context, request domainGro
 		return err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return err
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
 	if err != nil {
 		return err
 	}
 
 	// SetGroupTopic with auto-generated IDs (previousID and newID will be handled automatically)
-	return whatsapp.GetClient().SetGroupTopic(groupJID, "", "", request.Topic)
+	return client.SetGroupTopic(ctx, groupJID, "", "", request.Topic)
 }
 
 // GroupInfo retrieves detailed information about a WhatsApp group
@@ -353,17 +414,23 @@ func (service serviceGroup) GroupInfo(ctx context.Context, request domainGroup.G
 		return response, err
 	}
 
+	// Resolve the client for the requested account
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return response, err
+	}
+
 	// Ensure we are logged in
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(client)
 
 	// Validate and parse the provided group JID / ID
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
 	if err != nil {
 		return response, err
 	}
 
 	// Fetch group information from WhatsApp
-	groupInfo, err := whatsapp.GetClient().GetGroupInfo(groupJID)
+	groupInfo, err := client.GetGroupInfo(ctx, groupJID)
 	if err != nil {
 		return response, err
 	}
@@ -380,14 +447,18 @@ func (service serviceGroup) GetGroupInviteLink(ctx context.Context, request doma
 	if err = validations.ValidateGetGroupInviteLink(ctx, request); err != nil {
 		return response, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return response, err
+	}
+	utils.MustLogin(client)
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
 	if err != nil {
 		return response, err
 	}
 
-	inviteLink, err := whatsapp.GetClient().GetGroupInviteLink(groupJID, request.Reset)
+	inviteLink, err := client.GetGroupInviteLink(ctx, groupJID, request.Reset)
 	if err != nil {
 		return response, err
 	}
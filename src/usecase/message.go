@@ -11,6 +11,7 @@ import (
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
@@ -37,13 +38,18 @@ func (service serviceMessage) MarkAsRead(ctx context.Context, request domainMess
 	if err = validations.ValidateMarkAsRead(ctx, request); err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return response, err
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
 	if err != nil {
 		return response, err
 	}
 
 	ids := []types.MessageID{request.MessageID}
-	if err = whatsapp.GetClient().MarkRead(ctx, ids, time.Now(), dataWaRecipient, *whatsapp.GetClient().Store.ID); err != nil {
+	if err = client.MarkRead(ctx, ids, time.Now(), dataWaRecipient, *client.Store.ID); err != nil {
 		return response, err
 	}
 
@@ -51,7 +57,7 @@ func (service serviceMessage) MarkAsRead(ctx context.Context, request domainMess
 		"phone":      request.Phone,
 		"message_id": request.MessageID,
 		"chat":       dataWaRecipient.String(),
-		"sender":     whatsapp.GetClient().Store.ID.String(),
+		"sender":     client.Store.ID.String(),
 	})
 
 	response.MessageID = request.MessageID
@@ -63,7 +69,12 @@ func (service serviceMessage) ReactMessage(ctx context.Context, request domainMe
 	if err = validations.ValidateReactMessage(ctx, request); err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return response, err
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
 	if err != nil {
 		return response, err
 	}
@@ -79,11 +90,23 @@ func (service serviceMessage) ReactMessage(ctx context.Context, request domainMe
 			SenderTimestampMS: proto.Int64(time.Now().UnixMilli()),
 		},
 	}
-	ts, err := whatsapp.GetClient().SendMessage(ctx, dataWaRecipient, msg)
+	ts, err := client.SendMessage(ctx, dataWaRecipient, msg)
 	if err != nil {
 		return response, err
 	}
 
+	// Our own reactions are not echoed back as events, so they are stored here
+	if err := service.chatStorageRepo.StoreReaction(&domainChatStorage.MessageReaction{
+		AccountID: request.AccountID,
+		ChatJID:   dataWaRecipient.String(),
+		MessageID: request.MessageID,
+		Sender:    client.Store.ID.ToNonAD().String(),
+		Emoji:     request.Emoji,
+		Timestamp: ts.Timestamp,
+	}); err != nil {
+		logrus.Warnf("Failed to store sent reaction: %v", err)
+	}
+
 	response.MessageID = ts.ID
 	response.Status = fmt.Sprintf("Reaction sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp)
 	return response, nil
@@ -93,16 +116,25 @@ func (service serviceMessage) RevokeMessage(ctx context.Context, request domainM
 	if err = validations.ValidateRevokeMessage(ctx, request); err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return response, err
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
 	if err != nil {
 		return response, err
 	}
 
-	ts, err := whatsapp.GetClient().SendMessage(context.Background(), dataWaRecipient, whatsapp.GetClient().BuildRevoke(dataWaRecipient, types.EmptyJID, request.MessageID))
+	ts, err := client.SendMessage(context.Background(), dataWaRecipient, client.BuildRevoke(dataWaRecipient, types.EmptyJID, request.MessageID))
 	if err != nil {
 		return response, err
 	}
 
+	if err := service.chatStorageRepo.MarkMessageRevoked(request.AccountID, request.MessageID, dataWaRecipient.String()); err != nil {
+		logrus.Warnf("Failed to store message revocation: %v", err)
+	}
+
 	response.MessageID = ts.ID
 	response.Status = fmt.Sprintf("Revoke success %s (server timestamp: %s)", request.Phone, ts.Timestamp)
 	return response, nil
@@ -112,7 +144,12 @@ func (service serviceMessage) DeleteMessage(ctx context.Context, request domainM
 	if err = validations.ValidateDeleteMessage(ctx, request); err != nil {
 		return err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return err
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
 	if err != nil {
 		return err
 	}
@@ -126,7 +163,7 @@ func (service serviceMessage) DeleteMessage(ctx context.Context, request domainM
 		Timestamp: time.Now(),
 		Type:      appstate.WAPatchRegularHigh,
 		Mutations: []appstate.MutationInfo{{
-			Index: []string{appstate.IndexDeleteMessageForMe, dataWaRecipient.String(), request.MessageID, isFromMe, whatsapp.GetClient().Store.ID.String()},
+			Index: []string{appstate.IndexDeleteMessageForMe, dataWaRecipient.String(), request.MessageID, isFromMe, client.Store.ID.String()},
 			Value: &waSyncAction.SyncActionValue{
 				DeleteMessageForMeAction: &waSyncAction.DeleteMessageForMeAction{
 					DeleteMedia:      proto.Bool(true),
@@ -136,7 +173,7 @@ func (service serviceMessage) DeleteMessage(ctx context.Context, request domainM
 		}},
 	}
 
-	if err = whatsapp.GetClient().SendAppState(ctx, patchInfo); err != nil {
+	if err = client.SendAppState(ctx, patchInfo); err != nil {
 		return err
 	}
 	return nil
@@ -147,17 +184,32 @@ func (service serviceMessage) UpdateMessage(ctx context.Context, request domainM
 		return response, err
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return response, err
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
 	if err != nil {
 		return response, err
 	}
 
 	msg := &waE2E.Message{Conversation: proto.String(request.Message)}
-	ts, err := whatsapp.GetClient().SendMessage(context.Background(), dataWaRecipient, whatsapp.GetClient().BuildEdit(dataWaRecipient, request.MessageID, msg))
+	ts, err := client.SendMessage(context.Background(), dataWaRecipient, client.BuildEdit(dataWaRecipient, request.MessageID, msg))
 	if err != nil {
 		return response, err
 	}
 
+	if err := service.chatStorageRepo.StoreMessageEdit(&domainChatStorage.MessageEdit{
+		AccountID: request.AccountID,
+		ChatJID:   dataWaRecipient.String(),
+		MessageID: request.MessageID,
+		Content:   request.Message,
+		EditedAt:  ts.Timestamp,
+	}); err != nil {
+		logrus.Warnf("Failed to store message edit: %v", err)
+	}
+
 	response.MessageID = ts.ID
 	response.Status = fmt.Sprintf("Update message success %s (server timestamp: %s)", request.Phone, ts.Timestamp)
 	return response, nil
@@ -169,7 +221,12 @@ func (service serviceMessage) StarMessage(ctx context.Context, request domainMes
 		return err
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return err
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
 	if err != nil {
 		return err
 	}
@@ -179,9 +236,9 @@ func (service serviceMessage) StarMessage(ctx context.Context, request domainMes
 		isFromMe = false
 	}
 
-	patchInfo := appstate.BuildStar(dataWaRecipient.ToNonAD(), *whatsapp.GetClient().Store.ID, request.MessageID, isFromMe, request.IsStarred)
+	patchInfo := appstate.BuildStar(dataWaRecipient.ToNonAD(), *client.Store.ID, request.MessageID, isFromMe, request.IsStarred)
 
-	if err = whatsapp.GetClient().SendAppState(ctx, patchInfo); err != nil {
+	if err = client.SendAppState(ctx, patchInfo); err != nil {
 		return err
 	}
 	return nil
@@ -193,13 +250,18 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 		return response, err
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
+	if err != nil {
+		return response, err
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(client, request.Phone)
 	if err != nil {
 		return response, err
 	}
 
 	// Query the message from chat storage
-	message, err := service.chatStorageRepo.GetMessageByID(request.MessageID)
+	message, err := service.chatStorageRepo.GetMessageByID(request.AccountID, request.MessageID)
 	if err != nil {
 		return response, fmt.Errorf("message not found: %v", err)
 	}
@@ -218,17 +280,53 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 		return response, fmt.Errorf("message %s does not belong to chat %s", request.MessageID, dataWaRecipient.String())
 	}
 
+	extractedMedia, err := downloadStoredMedia(ctx, client, message)
+	if err != nil {
+		return response, err
+	}
+
+	// Get file size
+	fileInfo, err := os.Stat(extractedMedia.MediaPath)
+	if err != nil {
+		logrus.Warnf("Could not get file size for %s: %v", extractedMedia.MediaPath, err)
+	}
+
+	// Build response
+	response.MessageID = request.MessageID
+	response.Status = fmt.Sprintf("Media downloaded successfully to %s", extractedMedia.MediaPath)
+	response.MediaType = message.MediaType
+	response.Filename = filepath.Base(extractedMedia.MediaPath)
+	response.FilePath = extractedMedia.MediaPath
+	if fileInfo != nil {
+		response.FileSize = fileInfo.Size()
+	}
+
+	logrus.Info(map[string]any{
+		"message_id": request.MessageID,
+		"phone":      request.Phone,
+		"chat":       dataWaRecipient.String(),
+		"media_type": response.MediaType,
+		"file_path":  response.FilePath,
+		"file_size":  response.FileSize,
+	})
+
+	return response, nil
+}
+
+// downloadStoredMedia downloads the media of a stored message using its media key and URL,
+// saving it under the chat folder of config.PathMedia
+func downloadStoredMedia(ctx context.Context, client *whatsmeow.Client, message *domainChatStorage.Message) (extractedMedia utils.ExtractedMedia, err error) {
 	// Create directory structure for organized storage
 	chatDir := filepath.Join(config.PathMedia, utils.ExtractPhoneNumber(message.ChatJID))
 	dateDir := filepath.Join(chatDir, message.Timestamp.Format("2006-01-02"))
 
 	err = os.MkdirAll(dateDir, 0755)
 	if err != nil {
-		return response, fmt.Errorf("failed to create directory: %v", err)
+		return extractedMedia, fmt.Errorf("failed to create directory: %v", err)
 	}
 
 	// Create a downloadable message interface based on media type
-	var downloadableMsg interface{}
+	var downloadableMsg whatsmeow.DownloadableMessage
 
 	switch message.MediaType {
 	case "image":
@@ -273,39 +371,101 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 			FileLength:    proto.Uint64(message.FileLength),
 		}
 	default:
-		return response, fmt.Errorf("unsupported media type: %s", message.MediaType)
+		return extractedMedia, fmt.Errorf("unsupported media type: %s", message.MediaType)
 	}
 
 	// Download the media using existing utils.ExtractMedia function
-	extractedMedia, err := utils.ExtractMedia(ctx, whatsapp.GetClient(), dateDir, downloadableMsg.(whatsmeow.DownloadableMessage))
+	extractedMedia, err = utils.ExtractMedia(ctx, client, dateDir, downloadableMsg)
 	if err != nil {
-		return response, fmt.Errorf("failed to download media: %v", err)
+		return extractedMedia, fmt.Errorf("failed to download media: %v", err)
 	}
 
-	// Get file size
-	fileInfo, err := os.Stat(extractedMedia.MediaPath)
+	return extractedMedia, nil
+}
+
+// GetMessageStatus implements message.IMessageService.
+func (service serviceMessage) GetMessageStatus(ctx context.Context, request domainMessage.MessageStatusRequest) (response domainMessage.MessageStatusResponse, err error) {
+	if err = validations.ValidateGetMessageStatus(ctx, request); err != nil {
+		return response, err
+	}
+
+	message, err := service.chatStorageRepo.GetMessageByID(request.AccountID, request.MessageID)
 	if err != nil {
-		logrus.Warnf("Could not get file size for %s: %v", extractedMedia.MediaPath, err)
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to get message: %v", err))
+	}
+	if message == nil {
+		return response, pkgError.NotFoundError(fmt.Sprintf("message with ID %s not found", request.MessageID))
 	}
 
-	// Build response
-	response.MessageID = request.MessageID
-	response.Status = fmt.Sprintf("Media downloaded successfully to %s", extractedMedia.MediaPath)
-	response.MediaType = message.MediaType
-	response.Filename = filepath.Base(extractedMedia.MediaPath)
-	response.FilePath = extractedMedia.MediaPath
-	if fileInfo != nil {
-		response.FileSize = fileInfo.Size()
+	receipts, err := service.chatStorageRepo.GetReceipts(request.AccountID, []string{request.MessageID})
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to get receipts: %v", err))
 	}
 
-	logrus.Info(map[string]any{
-		"message_id": request.MessageID,
-		"phone":      request.Phone,
-		"chat":       dataWaRecipient.String(),
-		"media_type": response.MediaType,
-		"file_path":  response.FilePath,
-		"file_size":  response.FileSize,
-	})
+	summary := summarizeReceipts(receipts)
+	response = domainMessage.MessageStatusResponse{
+		MessageID:      message.ID,
+		ChatJID:        message.ChatJID,
+		Status:         summary.status(),
+		DeliveredAt:    formatReceiptTime(summary.deliveredAt),
+		ReadAt:         formatReceiptTime(summary.readAt),
+		DeliveredCount: len(summary.delivered),
+		ReadCount:      len(summary.read),
+		Receipts:       make([]domainMessage.ReceiptInfo, 0, len(receipts)),
+	}
+	for _, receipt := range receipts {
+		response.Receipts = append(response.Receipts, domainMessage.ReceiptInfo{
+			Recipient:   receipt.Recipient,
+			Participant: receipt.Participant,
+			DeliveredAt: formatReceiptTime(receipt.DeliveredAt),
+			ReadAt:      formatReceiptTime(receipt.ReadAt),
+		})
+	}
 
 	return response, nil
 }
+
+// receiptSummary holds the first delivery and read of a message and the participants that reached each state
+type receiptSummary struct {
+	deliveredAt *time.Time
+	readAt      *time.Time
+	delivered   map[string]bool
+	read        map[string]bool
+}
+
+func summarizeReceipts(receipts []*domainChatStorage.MessageReceipt) receiptSummary {
+	summary := receiptSummary{delivered: make(map[string]bool), read: make(map[string]bool)}
+	for _, receipt := range receipts {
+		if receipt.DeliveredAt != nil {
+			summary.delivered[receipt.Participant] = true
+			if summary.deliveredAt == nil || receipt.DeliveredAt.Before(*summary.deliveredAt) {
+				summary.deliveredAt = receipt.DeliveredAt
+			}
+		}
+		if receipt.ReadAt != nil {
+			summary.read[receipt.Participant] = true
+			if summary.readAt == nil || receipt.ReadAt.Before(*summary.readAt) {
+				summary.readAt = receipt.ReadAt
+			}
+		}
+	}
+	return summary
+}
+
+func (s receiptSummary) status() string {
+	switch {
+	case s.readAt != nil:
+		return domainMessage.MessageStatusRead
+	case s.deliveredAt != nil:
+		return domainMessage.MessageStatusDelivered
+	default:
+		return domainMessage.MessageStatusSent
+	}
+}
+
+func formatReceiptTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
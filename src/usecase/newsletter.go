@@ -20,10 +20,15 @@ func (service serviceNewsletter) Unfollow(ctx context.Context, request domainNew
 		return err
 	}
 
-	JID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.NewsletterID)
+	client, err := whatsapp.GetClientForAccount(ctx, request.AccountID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().UnfollowNewsletter(JID)
+	JID, err := utils.ValidateJidWithLogin(client, request.NewsletterID)
+	if err != nil {
+		return err
+	}
+
+	return client.UnfollowNewsletter(ctx, JID)
 }
@@ -16,8 +16,11 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
 	infraAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/account"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/metrics"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
@@ -34,10 +37,16 @@ import (
 type serviceSend struct {
 	appService      app.IAppUsecase
 	chatStorageRepo domainChatStorage.IChatStorageRepository
+	sendJobRepo     domainSend.ISendJobRepository
+	templateRepo    domainTemplate.ITemplateRepository
 }
 
-// getClient gets the WhatsApp client for the specified account
-func (service serviceSend) getClient(accountID string) (*whatsmeow.Client, error) {
+// getClient gets the WhatsApp client for the specified account, if the API key of the request allows it
+func (service serviceSend) getClient(ctx context.Context, accountID string) (*whatsmeow.Client, error) {
+	if err := whatsapp.AuthorizeAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+
 	client := infraAccount.GlobalAccountManager.GetClient(accountID)
 	if client == nil {
 		return nil, pkgError.NotFoundError("Account not found or not connected")
@@ -45,21 +54,40 @@ func (service serviceSend) getClient(accountID string) (*whatsmeow.Client, error
 	return client, nil
 }
 
-func NewSendService(appService app.IAppUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository) domainSend.ISendUsecase {
+// accountDefaults applies the settings of the sending account to a request: uploads are validated against
+// its media size limits, and its default disappearing duration is used when the request sets none
+func (service serviceSend) accountDefaults(ctx context.Context, accountID string, duration **int) context.Context {
+	settings := whatsapp.GetAccountSettings(accountID)
+	if *duration == nil && settings.DisappearingDuration > 0 {
+		defaultDuration := settings.DisappearingDuration
+		*duration = &defaultDuration
+	}
+	return validations.WithMediaLimits(ctx, validations.MediaLimits{
+		Image: settings.MaxImageSize,
+		File:  settings.MaxFileSize,
+		Video: settings.MaxVideoSize,
+	})
+}
+
+func NewSendService(appService app.IAppUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository, sendJobRepo domainSend.ISendJobRepository, templateRepo domainTemplate.ITemplateRepository) domainSend.ISendUsecase {
 	return &serviceSend{
 		appService:      appService,
 		chatStorageRepo: chatStorageRepo,
+		sendJobRepo:     sendJobRepo,
+		templateRepo:    templateRepo,
 	}
 }
 
 // wrapSendMessage wraps the message sending process with message ID saving
-func (service serviceSend) wrapSendMessage(ctx context.Context, accountID string, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
+func (service serviceSend) wrapSendMessage(ctx context.Context, accountID string, recipient types.JID, msg *waE2E.Message, content string, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
 	client := infraAccount.GlobalAccountManager.GetClient(accountID)
 	if client == nil {
 		return whatsmeow.SendResponse{}, pkgError.NotFoundError("Account not found or not connected")
 	}
 
-	ts, err := client.SendMessage(ctx, recipient, msg)
+	start := time.Now()
+	ts, err := client.SendMessage(ctx, recipient, msg, extra...)
+	metrics.ObserveMessageSent(accountID, msg, time.Since(start), err)
 	if err != nil {
 		return whatsmeow.SendResponse{}, err
 	}
@@ -76,7 +104,7 @@ func (service serviceSend) wrapSendMessage(ctx context.Context, accountID string
 		storeCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 
-		if err := service.chatStorageRepo.StoreSentMessageWithContext(storeCtx, ts.ID, senderJID, recipient.String(), content, ts.Timestamp); err != nil {
+		if err := service.chatStorageRepo.StoreSentMessageWithContext(storeCtx, accountID, ts.ID, senderJID, recipient.String(), content, ts.Timestamp); err != nil {
 			if errors.Is(err, context.DeadlineExceeded) {
 				logrus.Warn("Timeout storing sent message")
 			} else {
@@ -89,11 +117,16 @@ func (service serviceSend) wrapSendMessage(ctx context.Context, accountID string
 }
 
 func (service serviceSend) SendText(ctx context.Context, request domainSend.MessageRequest) (response domainSend.GenericResponse, err error) {
+	ctx = service.accountDefaults(ctx, request.AccountID, &request.BaseRequest.Duration)
+	if _, err = service.applyTemplate(request.AccountID, request.TemplateOptions, "message", &request.Message); err != nil {
+		return response, err
+	}
+
 	err = validations.ValidateSendMessage(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	client, err := service.getClient(request.AccountID)
+	client, err := service.getClient(ctx, request.AccountID)
 	if err != nil {
 		return response, err
 	}
@@ -120,7 +153,7 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 	if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
 		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	} else {
-		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(request.BaseRequest.Phone))
+		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(request.AccountID, request.BaseRequest.Phone))
 	}
 
 	parsedMentions := service.getMentionFromText(ctx, client, request.Message)
@@ -130,7 +163,7 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 
 	// Reply message
 	if request.ReplyMessageID != nil && *request.ReplyMessageID != "" {
-		message, err := service.chatStorageRepo.GetMessageByID(*request.ReplyMessageID)
+		message, err := service.chatStorageRepo.GetMessageByID(request.AccountID, *request.ReplyMessageID)
 		if err != nil {
 			logrus.Warnf("Error retrieving reply message ID %s: %v, continuing without reply context", *request.ReplyMessageID, err)
 		} else if message != nil { // Only set reply context if we found the message
@@ -159,7 +192,7 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 			if request.BaseRequest.Duration != nil && *request.BaseRequest.Duration > 0 {
 				ctxInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 			} else {
-				ctxInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(participantJID))
+				ctxInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(request.AccountID, participantJID))
 			}
 
 			// Preserve mentions
@@ -176,6 +209,10 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 		}
 	}
 
+	if request.BaseRequest.IsQueued() {
+		return service.enqueueMessage(request.BaseRequest, dataWaRecipient, msg, request.Message)
+	}
+
 	ts, err := service.wrapSendMessage(ctx, request.AccountID, dataWaRecipient, msg, request.Message)
 	if err != nil {
 		return response, err
@@ -187,11 +224,23 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 }
 
 func (service serviceSend) SendImage(ctx context.Context, request domainSend.ImageRequest) (response domainSend.GenericResponse, err error) {
+	ctx = service.accountDefaults(ctx, request.AccountID, &request.BaseRequest.Duration)
+	template, err := service.applyTemplate(request.AccountID, request.TemplateOptions, "caption", &request.Caption)
+	if err != nil {
+		return response, err
+	}
+
 	err = validations.ValidateSendImage(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	client, err := service.getClient(request.AccountID)
+
+	// An uploaded image or image URL takes precedence over the image of the template
+	useTemplateImage := request.Image == nil && (request.ImageURL == nil || *request.ImageURL == "")
+	if useTemplateImage && (template == nil || template.MediaType != domainTemplate.MediaTypeImage) {
+		return response, pkgError.ValidationError("template_id: template has no image")
+	}
+	client, err := service.getClient(ctx, request.AccountID)
 	if err != nil {
 		return response, err
 	}
@@ -210,7 +259,11 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 
 	if request.ImageURL != nil && *request.ImageURL != "" {
 		// Download image from URL
-		imageData, fileName, err := utils.DownloadImageFromURL(*request.ImageURL)
+		maxSize := validations.MediaLimitsFromContext(ctx).Image
+		imageData, fileName, err := utils.DownloadImageFromURL(*request.ImageURL, maxSize)
+		if errors.Is(err, utils.ErrImageTooLarge) {
+			return response, pkgError.ValidationError(fmt.Sprintf("image from URL exceeds the max image size of %d bytes", maxSize))
+		}
 		if err != nil {
 			return response, pkgError.InternalServerError(fmt.Sprintf("failed to download image from URL %v", err))
 		}
@@ -254,6 +307,13 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 			return response, err
 		}
 		imageName = request.Image.Filename
+	} else if useTemplateImage {
+		oriImagePath = fmt.Sprintf("%s/%s", config.PathSendItems, template.MediaName)
+		imageName = template.MediaName
+		err = os.WriteFile(oriImagePath, template.Media, 0644)
+		if err != nil {
+			return response, pkgError.InternalServerError(fmt.Sprintf("failed to save template image %v", err))
+		}
 	}
 	deletedItems = append(deletedItems, oriImagePath)
 
@@ -336,13 +396,19 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 	if request.Caption != "" {
 		caption = "🖼️ " + request.Caption
 	}
-	ts, err := service.wrapSendMessage(ctx, request.AccountID, dataWaRecipient, msg, caption)
+	// The image is already uploaded, so the local copies can go regardless of how the message is sent
 	go func() {
 		errDelete := utils.RemoveFile(0, deletedItems...)
 		if errDelete != nil {
 			fmt.Println("error when deleting picture: ", errDelete)
 		}
 	}()
+
+	if request.BaseRequest.IsQueued() {
+		return service.enqueueMessage(request.BaseRequest, dataWaRecipient, msg, caption)
+	}
+
+	ts, err := service.wrapSendMessage(ctx, request.AccountID, dataWaRecipient, msg, caption)
 	if err != nil {
 		return response, err
 	}
@@ -353,11 +419,20 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 }
 
 func (service serviceSend) SendFile(ctx context.Context, request domainSend.FileRequest) (response domainSend.GenericResponse, err error) {
+	ctx = service.accountDefaults(ctx, request.AccountID, &request.BaseRequest.Duration)
+	template, err := service.applyTemplate(request.AccountID, request.TemplateOptions, "caption", &request.Caption)
+	if err != nil {
+		return response, err
+	}
+
 	err = validations.ValidateSendFile(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	client, err := service.getClient(request.AccountID)
+	if request.File == nil && (template == nil || !template.HasMedia()) {
+		return response, pkgError.ValidationError("template_id: template has no media")
+	}
+	client, err := service.getClient(ctx, request.AccountID)
 	if err != nil {
 		return response, err
 	}
@@ -366,7 +441,18 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 		return response, err
 	}
 
-	fileBytes := helpers.MultipartFormFileHeaderToBytes(request.File)
+	// An uploaded file takes precedence over the media of the template
+	var (
+		fileBytes []byte
+		fileName  string
+	)
+	if request.File != nil {
+		fileBytes = helpers.MultipartFormFileHeaderToBytes(request.File)
+		fileName = request.File.Filename
+	} else {
+		fileBytes = template.Media
+		fileName = template.MediaName
+	}
 	fileMimeType := http.DetectContentType(fileBytes)
 
 	// Send to WA server
@@ -379,11 +465,11 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 	msg := &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{
 		URL:           proto.String(uploadedFile.URL),
 		Mimetype:      proto.String(fileMimeType),
-		Title:         proto.String(request.File.Filename),
+		Title:         proto.String(fileName),
 		FileSHA256:    uploadedFile.FileSHA256,
 		FileLength:    proto.Uint64(uploadedFile.FileLength),
 		MediaKey:      uploadedFile.MediaKey,
-		FileName:      proto.String(request.File.Filename),
+		FileName:      proto.String(fileName),
 		FileEncSHA256: uploadedFile.FileEncSHA256,
 		DirectPath:    proto.String(uploadedFile.DirectPath),
 		Caption:       proto.String(request.Caption),
@@ -407,6 +493,11 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 	if request.Caption != "" {
 		caption = "📄 " + request.Caption
 	}
+
+	if request.BaseRequest.IsQueued() {
+		return service.enqueueMessage(request.BaseRequest, dataWaRecipient, msg, caption)
+	}
+
 	ts, err := service.wrapSendMessage(ctx, request.AccountID, dataWaRecipient, msg, caption)
 	if err != nil {
 		return response, err
@@ -418,11 +509,12 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 }
 
 func (service serviceSend) SendVideo(ctx context.Context, request domainSend.VideoRequest) (response domainSend.GenericResponse, err error) {
+	ctx = service.accountDefaults(ctx, request.AccountID, &request.BaseRequest.Duration)
 	err = validations.ValidateSendVideo(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	client, err := service.getClient(request.AccountID)
+	client, err := service.getClient(ctx, request.AccountID)
 	if err != nil {
 		return response, err
 	}
@@ -586,6 +678,11 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 	if request.Caption != "" {
 		caption = "🎥 " + request.Caption
 	}
+
+	if request.BaseRequest.IsQueued() {
+		return service.enqueueMessage(request.BaseRequest, dataWaRecipient, msg, caption)
+	}
+
 	ts, err := service.wrapSendMessage(ctx, request.AccountID, dataWaRecipient, msg, caption)
 	if err != nil {
 		return response, err
@@ -597,11 +694,12 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 }
 
 func (service serviceSend) SendContact(ctx context.Context, request domainSend.ContactRequest) (response domainSend.GenericResponse, err error) {
+	ctx = service.accountDefaults(ctx, request.AccountID, &request.BaseRequest.Duration)
 	err = validations.ValidateSendContact(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	client, err := service.getClient(request.AccountID)
+	client, err := service.getClient(ctx, request.AccountID)
 	if err != nil {
 		return response, err
 	}
@@ -633,6 +731,10 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 
 	content := "👤 " + request.ContactName
 
+	if request.BaseRequest.IsQueued() {
+		return service.enqueueMessage(request.BaseRequest, dataWaRecipient, msg, content)
+	}
+
 	ts, err := service.wrapSendMessage(ctx, request.AccountID, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
@@ -644,11 +746,12 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 }
 
 func (service serviceSend) SendLink(ctx context.Context, request domainSend.LinkRequest) (response domainSend.GenericResponse, err error) {
+	ctx = service.accountDefaults(ctx, request.AccountID, &request.BaseRequest.Duration)
 	err = validations.ValidateSendLink(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	client, err := service.getClient(request.AccountID)
+	client, err := service.getClient(ctx, request.AccountID)
 	if err != nil {
 		return response, err
 	}
@@ -712,6 +815,11 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 	if request.Caption != "" {
 		content = "🔗 " + request.Caption
 	}
+
+	if request.BaseRequest.IsQueued() {
+		return service.enqueueMessage(request.BaseRequest, dataWaRecipient, msg, content)
+	}
+
 	ts, err := service.wrapSendMessage(ctx, request.AccountID, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
@@ -723,11 +831,12 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 }
 
 func (service serviceSend) SendLocation(ctx context.Context, request domainSend.LocationRequest) (response domainSend.GenericResponse, err error) {
+	ctx = service.accountDefaults(ctx, request.AccountID, &request.BaseRequest.Duration)
 	err = validations.ValidateSendLocation(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	client, err := service.getClient(request.AccountID)
+	client, err := service.getClient(ctx, request.AccountID)
 	if err != nil {
 		return response, err
 	}
@@ -760,6 +869,10 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 
 	content := "📍 " + request.Latitude + ", " + request.Longitude
 
+	if request.BaseRequest.IsQueued() {
+		return service.enqueueMessage(request.BaseRequest, dataWaRecipient, msg, content)
+	}
+
 	// Send WhatsApp Message Proto
 	ts, err := service.wrapSendMessage(ctx, request.AccountID, dataWaRecipient, msg, content)
 	if err != nil {
@@ -772,13 +885,14 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 }
 
 func (service serviceSend) SendAudio(ctx context.Context, request domainSend.AudioRequest) (response domainSend.GenericResponse, err error) {
+	ctx = service.accountDefaults(ctx, request.AccountID, &request.BaseRequest.Duration)
 	// Validate request
 	err = validations.ValidateSendAudio(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
-	client, err := service.getClient(request.AccountID)
+	client, err := service.getClient(ctx, request.AccountID)
 	if err != nil {
 		return response, err
 	}
@@ -839,6 +953,10 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 
 	content := "🎵 Audio"
 
+	if request.BaseRequest.IsQueued() {
+		return service.enqueueMessage(request.BaseRequest, dataWaRecipient, msg, content)
+	}
+
 	ts, err := service.wrapSendMessage(ctx, request.AccountID, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
@@ -850,11 +968,12 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 }
 
 func (service serviceSend) SendPoll(ctx context.Context, request domainSend.PollRequest) (response domainSend.GenericResponse, err error) {
+	ctx = service.accountDefaults(ctx, request.AccountID, &request.BaseRequest.Duration)
 	err = validations.ValidateSendPoll(ctx, request)
 	if err != nil {
 		return response, err
 	}
-	client, err := service.getClient(request.AccountID)
+	client, err := service.getClient(ctx, request.AccountID)
 	if err != nil {
 		return response, err
 	}
@@ -874,6 +993,10 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 		msg.PollCreationMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
 
+	if request.BaseRequest.IsQueued() {
+		return service.enqueueMessage(request.BaseRequest, dataWaRecipient, msg, content)
+	}
+
 	ts, err := service.wrapSendMessage(ctx, request.AccountID, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
@@ -890,11 +1013,11 @@ func (service serviceSend) SendPresence(ctx context.Context, request domainSend.
 		return response, err
 	}
 
-	client, err := service.getClient(request.AccountID)
+	client, err := service.getClient(ctx, request.AccountID)
 	if err != nil {
 		return response, err
 	}
-	err = client.SendPresence(types.Presence(request.Type))
+	err = client.SendPresence(ctx, types.Presence(request.Type))
 	if err != nil {
 		return response, err
 	}
@@ -910,7 +1033,7 @@ func (service serviceSend) SendChatPresence(ctx context.Context, request domainS
 		return response, err
 	}
 
-	client, err := service.getClient(request.AccountID)
+	client, err := service.getClient(ctx, request.AccountID)
 	if err != nil {
 		return response, err
 	}
@@ -936,7 +1059,7 @@ func (service serviceSend) SendChatPresence(ctx context.Context, request domainS
 		return response, fmt.Errorf("invalid action: %s. Must be 'start' or 'stop'", request.Action)
 	}
 
-	err = client.SendChatPresence(userJid, presenceType, "")
+	err = client.SendChatPresence(ctx, userJid, presenceType, "")
 	if err != nil {
 		return response, err
 	}
@@ -958,13 +1081,14 @@ func (service serviceSend) getMentionFromText(_ context.Context, client *whatsme
 }
 
 func (service serviceSend) SendSticker(ctx context.Context, request domainSend.StickerRequest) (response domainSend.GenericResponse, err error) {
+	ctx = service.accountDefaults(ctx, request.AccountID, &request.BaseRequest.Duration)
 	// Validate request
 	err = validations.ValidateSendSticker(ctx, request)
 	if err != nil {
 		return response, err
 	}
 
-	client, err := service.getClient(request.AccountID)
+	client, err := service.getClient(ctx, request.AccountID)
 	if err != nil {
 		return response, err
 	}
@@ -997,7 +1121,11 @@ func (service serviceSend) SendSticker(ctx context.Context, request domainSend.S
 	// Handle sticker from URL or file
 	if request.StickerURL != nil && *request.StickerURL != "" {
 		// Download sticker from URL
-		imageData, _, err := utils.DownloadImageFromURL(*request.StickerURL)
+		maxSize := validations.MediaLimitsFromContext(ctx).Image
+		imageData, _, err := utils.DownloadImageFromURL(*request.StickerURL, maxSize)
+		if errors.Is(err, utils.ErrImageTooLarge) {
+			return response, pkgError.ValidationError(fmt.Sprintf("sticker from URL exceeds the max image size of %d bytes", maxSize))
+		}
 		if err != nil {
 			return response, pkgError.InternalServerError(fmt.Sprintf("failed to download sticker from URL: %v", err))
 		}
@@ -1133,6 +1261,10 @@ func (service serviceSend) SendSticker(ctx context.Context, request domainSend.S
 
 	content := "🎨 Sticker"
 
+	if request.BaseRequest.IsQueued() {
+		return service.enqueueMessage(request.BaseRequest, dataWaRecipient, msg, content)
+	}
+
 	// Send the sticker message
 	ts, err := service.wrapSendMessage(ctx, request.AccountID, dataWaRecipient, msg, content)
 	if err != nil {
@@ -1145,7 +1277,7 @@ func (service serviceSend) SendSticker(ctx context.Context, request domainSend.S
 }
 
 func (service serviceSend) uploadMedia(ctx context.Context, accountID string, mediaType whatsmeow.MediaType, media []byte, recipient types.JID) (uploaded whatsmeow.UploadResponse, err error) {
-	client, err := service.getClient(accountID)
+	client, err := service.getClient(ctx, accountID)
 	if err != nil {
 		return whatsmeow.UploadResponse{}, err
 	}
@@ -1158,13 +1290,13 @@ func (service serviceSend) uploadMedia(ctx context.Context, accountID string, me
 	return uploaded, err
 }
 
-func (service serviceSend) getDefaultEphemeralExpiration(jid string) (expiration uint32) {
+func (service serviceSend) getDefaultEphemeralExpiration(accountID, jid string) (expiration uint32) {
 	expiration = 0
 	if jid == "" {
 		return expiration
 	}
 
-	chat, err := service.chatStorageRepo.GetChat(jid)
+	chat, err := service.chatStorageRepo.GetChat(accountID, jid)
 	if err != nil {
 		return expiration
 	}
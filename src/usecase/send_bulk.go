@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/disintegration/imaging"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// bulkTarget is a recipient that has been checked and had its text rendered before sending starts
+type bulkTarget struct {
+	phone string
+	jid   types.JID
+	text  string
+	err   string
+}
+
+func (service serviceSend) SendBulk(ctx context.Context, request domainSend.BulkRequest) (<-chan domainSend.BulkResult, error) {
+	ctx = service.accountDefaults(ctx, request.AccountID, &request.Duration)
+	if err := validations.ValidateSendBulk(ctx, request); err != nil {
+		return nil, err
+	}
+	client, err := service.getClient(ctx, request.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	utils.MustLogin(client)
+
+	// Check every recipient before anything is sent, so bad numbers are reported without waiting for the delay
+	targets := make([]bulkTarget, len(request.Recipients))
+	for i, recipient := range request.Recipients {
+		targets[i] = service.checkBulkRecipient(client, request.Message, recipient)
+	}
+
+	// Media is uploaded once and the same upload is referenced by every recipient's message
+	base, err := service.buildBulkMessage(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	delay := config.SendBulkDelay
+	if request.DelayMs != nil {
+		delay = time.Duration(*request.DelayMs) * time.Millisecond
+	}
+
+	results := make(chan domainSend.BulkResult)
+	go func() {
+		defer close(results)
+
+		sent := false
+		for _, target := range targets {
+			if target.err != "" {
+				results <- domainSend.BulkResult{Phone: target.phone, Error: target.err}
+				continue
+			}
+
+			if sent && delay > 0 {
+				time.Sleep(delay)
+			}
+			sent = true
+
+			results <- service.sendBulkMessage(request, base, target)
+		}
+	}()
+
+	return results, nil
+}
+
+func (service serviceSend) checkBulkRecipient(client *whatsmeow.Client, message string, recipient domainSend.BulkRecipient) bulkTarget {
+	target := bulkTarget{phone: recipient.Phone}
+
+	jid, err := utils.ParseJID(recipient.Phone)
+	if err != nil {
+		target.err = err.Error()
+		return target
+	}
+	if jid.Server == types.NewsletterServer {
+		target.err = "newsletters are not supported in bulk sends"
+		return target
+	}
+	if config.WhatsappAccountValidation && !utils.IsOnWhatsapp(client, recipient.Phone) {
+		target.err = fmt.Sprintf("Phone %s is not on whatsapp", recipient.Phone)
+		return target
+	}
+
+	text, missing := utils.RenderPlaceholders(message, recipient.Variables)
+	if len(missing) > 0 {
+		target.err = fmt.Sprintf("missing variables: %s", strings.Join(missing, ", "))
+		return target
+	}
+
+	target.jid = jid
+	target.text = text
+	return target
+}
+
+// buildBulkMessage builds the message shared by all recipients, without text, caption or expiration
+func (service serviceSend) buildBulkMessage(ctx context.Context, request domainSend.BulkRequest) (*waE2E.Message, error) {
+	// Newsletters are rejected per recipient, so a regular upload serves everyone
+	uploadTarget := types.EmptyJID
+
+	switch {
+	case request.Image != nil || (request.ImageURL != nil && *request.ImageURL != ""):
+		var imageData []byte
+		if request.Image != nil {
+			imageData = helpers.MultipartFormFileHeaderToBytes(request.Image)
+		} else {
+			maxSize := validations.MediaLimitsFromContext(ctx).Image
+			data, _, err := utils.DownloadImageFromURL(*request.ImageURL, maxSize)
+			if errors.Is(err, utils.ErrImageTooLarge) {
+				return nil, pkgError.ValidationError(fmt.Sprintf("image from URL exceeds the max image size of %d bytes", maxSize))
+			}
+			if err != nil {
+				return nil, pkgError.InternalServerError(fmt.Sprintf("failed to download image from URL %v", err))
+			}
+			imageData = data
+		}
+
+		srcImage, err := imaging.Decode(bytes.NewReader(imageData))
+		if err != nil {
+			return nil, pkgError.InternalServerError(fmt.Sprintf("failed to decode image %v", err))
+		}
+
+		// WhatsApp doesn't display WebP images, so they are sent as PNG like in SendImage
+		if http.DetectContentType(imageData) == "image/webp" {
+			var pngBuffer bytes.Buffer
+			if err := imaging.Encode(&pngBuffer, srcImage, imaging.PNG); err != nil {
+				return nil, pkgError.InternalServerError(fmt.Sprintf("failed to convert WebP to PNG %v", err))
+			}
+			imageData = pngBuffer.Bytes()
+		}
+
+		var thumbnail bytes.Buffer
+		if err := imaging.Encode(&thumbnail, imaging.Resize(srcImage, 100, 0, imaging.Lanczos), imaging.JPEG); err != nil {
+			return nil, pkgError.InternalServerError(fmt.Sprintf("failed to generate thumbnail %v", err))
+		}
+
+		uploaded, err := service.uploadMedia(ctx, request.AccountID, whatsmeow.MediaImage, imageData, uploadTarget)
+		if err != nil {
+			return nil, err
+		}
+
+		return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+			JPEGThumbnail: thumbnail.Bytes(),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			Mimetype:      proto.String(http.DetectContentType(imageData)),
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uint64(len(imageData))),
+		}}, nil
+
+	case request.File != nil:
+		fileBytes := helpers.MultipartFormFileHeaderToBytes(request.File)
+		uploaded, err := service.uploadMedia(ctx, request.AccountID, whatsmeow.MediaDocument, fileBytes, uploadTarget)
+		if err != nil {
+			return nil, err
+		}
+
+		return &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{
+			URL:           proto.String(uploaded.URL),
+			Mimetype:      proto.String(http.DetectContentType(fileBytes)),
+			Title:         proto.String(request.File.Filename),
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+			MediaKey:      uploaded.MediaKey,
+			FileName:      proto.String(request.File.Filename),
+			FileEncSHA256: uploaded.FileEncSHA256,
+			DirectPath:    proto.String(uploaded.DirectPath),
+		}}, nil
+
+	default:
+		return &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{}}, nil
+	}
+}
+
+// sendBulkMessage fills in the recipient's text and expiration on a copy of the shared message and sends it
+func (service serviceSend) sendBulkMessage(request domainSend.BulkRequest, base *waE2E.Message, target bulkTarget) domainSend.BulkResult {
+	result := domainSend.BulkResult{Phone: target.phone}
+
+	expiration := service.getDefaultEphemeralExpiration(request.AccountID, target.jid.String())
+	if request.Duration != nil && *request.Duration > 0 {
+		expiration = uint32(*request.Duration)
+	}
+	contextInfo := &waE2E.ContextInfo{Expiration: proto.Uint32(expiration)}
+
+	msg := proto.Clone(base).(*waE2E.Message)
+	var content string
+	switch {
+	case msg.ImageMessage != nil:
+		msg.ImageMessage.Caption = proto.String(target.text)
+		msg.ImageMessage.ContextInfo = contextInfo
+		content = "🖼️ Image"
+		if target.text != "" {
+			content = "🖼️ " + target.text
+		}
+	case msg.DocumentMessage != nil:
+		msg.DocumentMessage.Caption = proto.String(target.text)
+		msg.DocumentMessage.ContextInfo = contextInfo
+		content = "📄 Document"
+		if target.text != "" {
+			content = "📄 " + target.text
+		}
+	default:
+		msg.ExtendedTextMessage.Text = proto.String(target.text)
+		msg.ExtendedTextMessage.ContextInfo = contextInfo
+		content = target.text
+	}
+
+	ts, err := service.wrapSendMessage(context.Background(), request.AccountID, target.jid, msg, content)
+	if err != nil {
+		logrus.Warnf("[SEND_BULK] Failed to send to %s: %v", target.phone, err)
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Success = true
+	result.MessageID = ts.ID
+	return result
+}
@@ -0,0 +1,326 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	infraAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/account"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	sendQueuePollInterval   = 5 * time.Second
+	sendQueueRetryBaseDelay = 30 * time.Second
+	sendQueueRetryMaxDelay  = 10 * time.Minute
+	// A job claimed longer ago than this was interrupted, e.g. by a crash during the send
+	sendQueueClaimLease = 10 * time.Minute
+)
+
+// enqueueMessage stores an already built message in the send queue instead of sending it right away
+func (service serviceSend) enqueueMessage(request domainSend.BaseRequest, recipient types.JID, msg *waE2E.Message, content string) (response domainSend.GenericResponse, err error) {
+	if service.sendJobRepo == nil {
+		return response, pkgError.InternalServerError("send queue is not available")
+	}
+
+	sendAt := time.Now()
+	if request.SendAt != nil && *request.SendAt != "" {
+		sendAt, err = time.Parse(time.RFC3339, *request.SendAt)
+		if err != nil {
+			return response, pkgError.ValidationError("send_at: must be a valid RFC3339 timestamp")
+		}
+	}
+
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to encode message: %v", err))
+	}
+
+	job := &domainSend.Job{
+		ID:        uuid.NewString(),
+		AccountID: request.AccountID,
+		Recipient: recipient.String(),
+		Content:   content,
+		Message:   payload,
+		SendAt:    sendAt,
+	}
+	if err = service.sendJobRepo.CreateJob(job); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to queue message: %v", err))
+	}
+
+	response.JobID = job.ID
+	response.Status = fmt.Sprintf("Message to %s queued for %s", request.Phone, job.SendAt.Format(time.RFC3339))
+	return response, nil
+}
+
+func (service serviceSend) GetJob(ctx context.Context, request domainSend.JobRequest) (response domainSend.JobResponse, err error) {
+	if err = validations.ValidateGetJob(ctx, request); err != nil {
+		return response, err
+	}
+	if service.sendJobRepo == nil {
+		return response, pkgError.InternalServerError("send queue is not available")
+	}
+
+	job, err := service.sendJobRepo.GetJob(request.JobID)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to get send job: %v", err))
+	}
+	if job == nil || (request.AccountID != "" && job.AccountID != request.AccountID) {
+		return response, pkgError.NotFoundError(fmt.Sprintf("send job %s not found", request.JobID))
+	}
+
+	return domainSend.JobResponse{
+		JobID:     job.ID,
+		AccountID: job.AccountID,
+		Recipient: job.Recipient,
+		Status:    job.Status,
+		MessageID: job.MessageID,
+		Error:     job.Error,
+		Attempts:  job.Attempts,
+		SendAt:    job.SendAt,
+		SentAt:    job.SentAt,
+		CreatedAt: job.CreatedAt,
+	}, nil
+}
+
+// SendQueueWorker delivers queued messages with one goroutine per account,
+// spacing them by the configured rate and jitter and holding them back during quiet hours.
+// The rate is measured from the last attempt stored in the queue, so it holds across restarts.
+type SendQueueWorker struct {
+	service     serviceSend
+	jobRepo     domainSend.ISendJobRepository
+	interval    time.Duration
+	jitter      time.Duration
+	quietHours  *utils.QuietHours
+	maxAttempts int
+
+	mu     sync.Mutex
+	active map[string]bool
+}
+
+func NewSendQueueWorker(sendJobRepo domainSend.ISendJobRepository, chatStorageRepo domainChatStorage.IChatStorageRepository) (*SendQueueWorker, error) {
+	quietHours, err := utils.ParseQuietHours(config.SendQueueQuietHours)
+	if err != nil {
+		return nil, err
+	}
+
+	ratePerMinute := config.SendQueueRatePerMinute
+	if ratePerMinute < 1 {
+		ratePerMinute = 1
+	}
+
+	maxAttempts := config.SendQueueMaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &SendQueueWorker{
+		service:     serviceSend{chatStorageRepo: chatStorageRepo, sendJobRepo: sendJobRepo},
+		jobRepo:     sendJobRepo,
+		interval:    time.Minute / time.Duration(ratePerMinute),
+		jitter:      config.SendQueueJitter,
+		quietHours:  quietHours,
+		maxAttempts: maxAttempts,
+		active:      make(map[string]bool),
+	}, nil
+}
+
+// Run polls the queue for accounts with due messages until ctx is cancelled
+func (w *SendQueueWorker) Run(ctx context.Context) {
+	logrus.Infof("[SEND_QUEUE] Started with an interval of %s between messages per account", w.interval)
+
+	ticker := time.NewTicker(sendQueuePollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.sweep()
+
+		accountIDs, err := w.jobRepo.ListDueAccounts(time.Now())
+		if err != nil {
+			logrus.Errorf("[SEND_QUEUE] Failed to list due accounts: %v", err)
+		}
+		for _, accountID := range accountIDs {
+			w.startAccount(ctx, accountID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweep expires jobs whose account never got to send them and fails jobs interrupted mid-send.
+// Interrupted jobs aren't sent again, as the message may already have been delivered.
+func (w *SendQueueWorker) sweep() {
+	now := time.Now()
+
+	if config.SendQueueMaxAge > 0 {
+		expired, err := w.jobRepo.ExpireJobs(now.Add(-config.SendQueueMaxAge))
+		if err != nil {
+			logrus.Errorf("[SEND_QUEUE] Failed to expire jobs: %v", err)
+		} else if expired > 0 {
+			logrus.Warnf("[SEND_QUEUE] Expired %d job(s) queued for more than %s", expired, config.SendQueueMaxAge)
+		}
+	}
+
+	stale, err := w.jobRepo.FailStaleJobs(now.Add(-sendQueueClaimLease), "send was interrupted, the message may or may not have been delivered")
+	if err != nil {
+		logrus.Errorf("[SEND_QUEUE] Failed to fail interrupted jobs: %v", err)
+	} else if stale > 0 {
+		logrus.Warnf("[SEND_QUEUE] Failed %d job(s) interrupted during the send", stale)
+	}
+}
+
+func (w *SendQueueWorker) startAccount(ctx context.Context, accountID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.active[accountID] {
+		return
+	}
+	w.active[accountID] = true
+	go w.runAccount(ctx, accountID)
+}
+
+// runAccount sends the due messages of one account and returns once there is nothing left to send
+func (w *SendQueueWorker) runAccount(ctx context.Context, accountID string) {
+	defer func() {
+		w.mu.Lock()
+		delete(w.active, accountID)
+		w.mu.Unlock()
+	}()
+
+	for {
+		if !w.waitForSlot(ctx, accountID) {
+			return
+		}
+
+		client := infraAccount.GlobalAccountManager.GetClient(accountID)
+		if client == nil || !client.IsConnected() {
+			logrus.Debugf("[SEND_QUEUE] Account %s is not connected, keeping its jobs queued", accountID)
+			return
+		}
+
+		job, err := w.jobRepo.ClaimDueJob(accountID, time.Now())
+		if err != nil {
+			logrus.Errorf("[SEND_QUEUE] Failed to claim next job for account %s: %v", accountID, err)
+			return
+		}
+		if job == nil {
+			return
+		}
+
+		w.deliver(ctx, client, job)
+	}
+}
+
+// waitForSlot blocks until the account may send again, returning false when ctx is cancelled
+func (w *SendQueueWorker) waitForSlot(ctx context.Context, accountID string) bool {
+	for {
+		wait := time.Duration(0)
+		if w.quietHours != nil {
+			wait = w.quietHours.Remaining(time.Now())
+		}
+
+		if wait == 0 {
+			lastSent, err := w.jobRepo.LastAttemptAt(accountID)
+			if err != nil {
+				logrus.Errorf("[SEND_QUEUE] Failed to get last send of account %s: %v", accountID, err)
+				return false
+			}
+
+			delay := w.interval
+			if w.jitter > 0 {
+				delay += time.Duration(rand.Int63n(int64(w.jitter)))
+			}
+			wait = time.Until(lastSent.Add(delay))
+			if wait <= 0 {
+				return true
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(wait):
+		}
+	}
+}
+
+// deliver sends a single claimed job, queueing it again after a transient error
+func (w *SendQueueWorker) deliver(ctx context.Context, client *whatsmeow.Client, job *domainSend.Job) {
+	recipient, err := types.ParseJID(job.Recipient)
+	if err != nil {
+		w.fail(job, fmt.Sprintf("invalid recipient: %v", err))
+		return
+	}
+
+	msg := &waE2E.Message{}
+	if err := proto.Unmarshal(job.Message, msg); err != nil {
+		w.fail(job, fmt.Sprintf("invalid message: %v", err))
+		return
+	}
+
+	if job.MessageID == "" {
+		job.MessageID = client.GenerateMessageID()
+	}
+
+	ts, err := w.service.wrapSendMessage(ctx, job.AccountID, recipient, msg, job.Content, whatsmeow.SendRequestExtra{ID: job.MessageID})
+	if err != nil {
+		w.retry(job, err.Error())
+		return
+	}
+
+	if err := w.jobRepo.MarkJobSent(job.ID, ts.ID, ts.Timestamp); err != nil {
+		logrus.Errorf("[SEND_QUEUE] Failed to mark job %s as sent: %v", job.ID, err)
+	}
+	logrus.Infof("[SEND_QUEUE] Job %s sent to %s as %s", job.ID, job.Recipient, ts.ID)
+}
+
+// retry queues a job again with a growing delay, or fails it once it used all its attempts
+func (w *SendQueueWorker) retry(job *domainSend.Job, reason string) {
+	if job.Attempts >= w.maxAttempts {
+		w.fail(job, reason)
+		return
+	}
+
+	sendAt := time.Now().Add(sendQueueRetryDelay(job.Attempts))
+	logrus.Warnf("[SEND_QUEUE] Attempt %d of job %s to %s failed, retrying at %s: %s", job.Attempts, job.ID, job.Recipient, sendAt.Format(time.RFC3339), reason)
+	if err := w.jobRepo.RetryJob(job.ID, job.MessageID, sendAt, reason); err != nil {
+		logrus.Errorf("[SEND_QUEUE] Failed to reschedule job %s: %v", job.ID, err)
+	}
+}
+
+func (w *SendQueueWorker) fail(job *domainSend.Job, reason string) {
+	logrus.Warnf("[SEND_QUEUE] Job %s to %s failed: %s", job.ID, job.Recipient, reason)
+	if err := w.jobRepo.MarkJobFailed(job.ID, reason); err != nil {
+		logrus.Errorf("[SEND_QUEUE] Failed to mark job %s as failed: %v", job.ID, err)
+	}
+}
+
+// sendQueueRetryDelay doubles the base delay for every failed attempt, up to the max delay
+func sendQueueRetryDelay(attempts int) time.Duration {
+	delay := sendQueueRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= sendQueueRetryMaxDelay {
+			return sendQueueRetryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"fmt"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+// applyTemplate loads the requested template and renders its content into target, which holds the
+// message text or caption. It returns nil when the request doesn't use a template.
+func (service serviceSend) applyTemplate(accountID string, options domainSend.TemplateOptions, field string, target *string) (*domainTemplate.Template, error) {
+	if options.TemplateID == "" {
+		return nil, nil
+	}
+	if *target != "" {
+		return nil, pkgError.ValidationError(fmt.Sprintf("%s: cannot be combined with template_id.", field))
+	}
+	if service.templateRepo == nil {
+		return nil, pkgError.InternalServerError("templates are not available")
+	}
+
+	template, err := service.templateRepo.GetTemplate(accountID, options.TemplateID)
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to get template: %v", err))
+	}
+	if template == nil {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("template %s not found", options.TemplateID))
+	}
+
+	if err := validations.ValidateTemplateVariables(template.Content, options.Variables); err != nil {
+		return nil, err
+	}
+
+	*target, _ = utils.RenderPlaceholders(template.Content, options.Variables)
+	return template, nil
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/storage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceStorage struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+	accountRepo     domainAccount.IAccountRepository
+
+	// mu serializes prune runs, so the janitor and the prune endpoint never overlap
+	mu        sync.Mutex
+	lastPrune *domainStorage.PruneReport
+}
+
+func NewStorageService(chatStorageRepo domainChatStorage.IChatStorageRepository, accountRepo domainAccount.IAccountRepository) domainStorage.IStorageUsecase {
+	return &serviceStorage{
+		chatStorageRepo: chatStorageRepo,
+		accountRepo:     accountRepo,
+	}
+}
+
+// mediaFolders are the folders whose files are pruned by age and by the media size cap. Their files
+// aren't linked to stored messages, so they only follow the global rules: retention policies and
+// starred messages don't keep a file.
+func mediaFolders() []string {
+	return []string{config.PathMedia, config.PathSendItems}
+}
+
+func (service *serviceStorage) GetStatistics(_ context.Context) (response domainStorage.Statistics, err error) {
+	response.Chats, response.Messages, err = service.chatStorageRepo.GetStorageStatistics()
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to get storage statistics: %v", err))
+	}
+
+	response.DatabaseSize, err = service.chatStorageRepo.GetDatabaseSize()
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to get database size: %v", err))
+	}
+
+	usage, err := utils.GetFolderUsage(mediaFolders()...)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to get media folder size: %v", err))
+	}
+	response.Media = domainStorage.FolderUsage{Files: usage.Files, Bytes: usage.Bytes}
+
+	response.Retention = domainStorage.RetentionConfig{
+		MaxMessageDays: config.RetentionMaxMessageDays,
+		MaxMediaSize:   config.RetentionMaxMediaSize,
+		KeepStarred:    config.RetentionKeepStarred,
+		Interval:       config.RetentionInterval.String(),
+	}
+
+	service.mu.Lock()
+	if service.lastPrune != nil {
+		lastPrune := *service.lastPrune
+		response.LastPrune = &lastPrune
+	}
+	service.mu.Unlock()
+
+	return response, nil
+}
+
+// Prune applies the retention rules: chat policies first, then account policies without the chats
+// that have a policy of their own, then the global rule for everything without a policy. Media files
+// are only pruned by the global age and size cap, see mediaFolders, and the database is vacuumed when
+// anything was removed.
+func (service *serviceStorage) Prune(_ context.Context) (response domainStorage.PruneReport, err error) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	response.StartedAt = time.Now()
+	defer func() {
+		response.FinishedAt = time.Now()
+		if err != nil {
+			response.Error = err.Error()
+		}
+		report := response
+		service.lastPrune = &report
+	}()
+
+	policies, err := service.chatStorageRepo.GetRetentionPolicies()
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to get retention policies: %v", err))
+	}
+
+	var filters []*domainChatStorage.MessagePruneFilter
+	for _, policy := range policies {
+		if policy.MaxMessageDays <= 0 {
+			continue
+		}
+		filter := &domainChatStorage.MessagePruneFilter{
+			Before:      retentionCutoff(response.StartedAt, policy.MaxMessageDays),
+			KeepStarred: policy.KeepStarred,
+			AccountID:   policy.AccountID,
+			ChatJID:     policy.ChatJID,
+		}
+		if policy.ChatJID == "" {
+			for _, chatPolicy := range policies {
+				if chatPolicy.AccountID == policy.AccountID && chatPolicy.ChatJID != "" {
+					filter.Except = append(filter.Except, chatPolicy)
+				}
+			}
+		}
+		filters = append(filters, filter)
+	}
+	if config.RetentionMaxMessageDays > 0 {
+		filters = append(filters, &domainChatStorage.MessagePruneFilter{
+			Before:      retentionCutoff(response.StartedAt, config.RetentionMaxMessageDays),
+			KeepStarred: config.RetentionKeepStarred,
+			AllAccounts: true,
+			Except:      policies,
+		})
+	}
+
+	var errs []error
+	for _, filter := range filters {
+		removed, err := service.chatStorageRepo.PruneMessages(filter)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to prune messages of %s: %w", pruneScope(filter), err))
+			continue
+		}
+		response.MessagesRemoved += removed
+	}
+
+	var filesBefore time.Time
+	if config.RetentionMaxMessageDays > 0 {
+		filesBefore = retentionCutoff(response.StartedAt, config.RetentionMaxMessageDays)
+	}
+	if !filesBefore.IsZero() || config.RetentionMaxMediaSize > 0 {
+		removed, err := utils.PruneFolders(filesBefore, config.RetentionMaxMediaSize, mediaFolders()...)
+		response.FilesRemoved, response.BytesFreed = removed.Files, removed.Bytes
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to prune media files: %w", err))
+		}
+	}
+
+	if response.MessagesRemoved > 0 {
+		if err := service.chatStorageRepo.Vacuum(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to vacuum chat storage: %w", err))
+		} else {
+			response.Vacuumed = true
+		}
+	}
+
+	if len(errs) > 0 {
+		return response, pkgError.InternalServerError(errors.Join(errs...).Error())
+	}
+	return response, nil
+}
+
+func (service *serviceStorage) ListRetentionPolicies(_ context.Context, accountID string) (response []domainStorage.RetentionPolicy, err error) {
+	policies, err := service.chatStorageRepo.GetRetentionPolicies()
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to get retention policies: %v", err))
+	}
+
+	response = []domainStorage.RetentionPolicy{}
+	for _, policy := range policies {
+		if accountID != "" && policy.AccountID != accountID {
+			continue
+		}
+		response = append(response, toRetentionPolicy(policy))
+	}
+	return response, nil
+}
+
+func (service *serviceStorage) SetRetentionPolicy(ctx context.Context, request domainStorage.RetentionPolicyRequest) (response domainStorage.RetentionPolicy, err error) {
+	if err = validations.ValidateSetRetentionPolicy(ctx, request); err != nil {
+		return response, err
+	}
+
+	account, err := service.accountRepo.GetAccount(request.AccountID)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to get account: %v", err))
+	}
+	if account == nil {
+		return response, pkgError.NotFoundError(fmt.Sprintf("account %s not found", request.AccountID))
+	}
+
+	policy := &domainChatStorage.RetentionPolicy{
+		AccountID:      request.AccountID,
+		ChatJID:        strings.TrimSpace(request.ChatJID),
+		MaxMessageDays: request.MaxMessageDays,
+		KeepStarred:    true,
+	}
+	if request.KeepStarred != nil {
+		policy.KeepStarred = *request.KeepStarred
+	}
+
+	if err = service.chatStorageRepo.StoreRetentionPolicy(policy); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to store retention policy: %v", err))
+	}
+	return toRetentionPolicy(policy), nil
+}
+
+func (service *serviceStorage) DeleteRetentionPolicy(ctx context.Context, request domainStorage.RetentionPolicyIdentifierRequest) (err error) {
+	if err = validations.ValidateRetentionPolicyIdentifier(ctx, request); err != nil {
+		return err
+	}
+
+	if err = service.chatStorageRepo.DeleteRetentionPolicy(request.AccountID, strings.TrimSpace(request.ChatJID)); err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to delete retention policy: %v", err))
+	}
+	return nil
+}
+
+func toRetentionPolicy(policy *domainChatStorage.RetentionPolicy) domainStorage.RetentionPolicy {
+	return domainStorage.RetentionPolicy{
+		AccountID:      policy.AccountID,
+		ChatJID:        policy.ChatJID,
+		MaxMessageDays: policy.MaxMessageDays,
+		KeepStarred:    policy.KeepStarred,
+		UpdatedAt:      policy.UpdatedAt,
+	}
+}
+
+func retentionCutoff(now time.Time, days int) time.Time {
+	return now.AddDate(0, 0, -days)
+}
+
+func pruneScope(filter *domainChatStorage.MessagePruneFilter) string {
+	switch {
+	case filter.AllAccounts:
+		return "all accounts"
+	case filter.ChatJID != "":
+		return fmt.Sprintf("chat %s of account %s", filter.ChatJID, filter.AccountID)
+	default:
+		return "account " + filter.AccountID
+	}
+}
+
+// StorageJanitor applies the retention rules on a fixed interval
+type StorageJanitor struct {
+	storageUsecase domainStorage.IStorageUsecase
+	interval       time.Duration
+}
+
+func NewStorageJanitor(storageUsecase domainStorage.IStorageUsecase) *StorageJanitor {
+	return &StorageJanitor{
+		storageUsecase: storageUsecase,
+		interval:       config.RetentionInterval,
+	}
+}
+
+// Run prunes the storage right away and then on every interval until ctx is cancelled
+func (j *StorageJanitor) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		logrus.Info("[STORAGE_JANITOR] Disabled because the retention interval is not positive")
+		return
+	}
+	logrus.Infof("[STORAGE_JANITOR] Started with an interval of %s", j.interval)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := j.storageUsecase.Prune(ctx)
+		if err != nil {
+			logrus.Errorf("[STORAGE_JANITOR] Prune failed: %v", err)
+		}
+		if report.MessagesRemoved > 0 || report.FilesRemoved > 0 {
+			logrus.Infof("[STORAGE_JANITOR] Removed %d messages and %d files (%d bytes)",
+				report.MessagesRemoved, report.FilesRemoved, report.BytesFreed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
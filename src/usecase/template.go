@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+)
+
+type serviceTemplate struct {
+	templateRepo domainTemplate.ITemplateRepository
+	accountRepo  domainAccount.IAccountRepository
+}
+
+func NewTemplateService(templateRepo domainTemplate.ITemplateRepository, accountRepo domainAccount.IAccountRepository) domainTemplate.ITemplateUsecase {
+	return &serviceTemplate{
+		templateRepo: templateRepo,
+		accountRepo:  accountRepo,
+	}
+}
+
+func (service serviceTemplate) CreateTemplate(ctx context.Context, request domainTemplate.TemplateRequest) (response domainTemplate.Template, err error) {
+	if err = validations.ValidateCreateTemplate(ctx, &request); err != nil {
+		return response, err
+	}
+	if err = service.ensureAccount(request.AccountID); err != nil {
+		return response, err
+	}
+
+	template := &domainTemplate.Template{
+		ID:        uuid.NewString(),
+		AccountID: request.AccountID,
+		Name:      request.Name,
+		Content:   request.Content,
+	}
+	if request.Media != nil {
+		setTemplateMedia(template, request.Media.Filename, helpers.MultipartFormFileHeaderToBytes(request.Media))
+	}
+
+	if err = service.templateRepo.CreateTemplate(template); err != nil {
+		return response, templateStorageError("create", request.Name, err)
+	}
+
+	return withTemplateVariables(template), nil
+}
+
+func (service serviceTemplate) ListTemplates(_ context.Context, accountID string) (response []domainTemplate.Template, err error) {
+	if err = service.ensureAccount(accountID); err != nil {
+		return response, err
+	}
+
+	templates, err := service.templateRepo.ListTemplates(accountID)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to list templates: %v", err))
+	}
+
+	response = make([]domainTemplate.Template, 0, len(templates))
+	for _, template := range templates {
+		response = append(response, withTemplateVariables(template))
+	}
+	return response, nil
+}
+
+func (service serviceTemplate) GetTemplate(_ context.Context, request domainTemplate.TemplateIdentifierRequest) (response domainTemplate.Template, err error) {
+	template, err := service.findTemplate(request.AccountID, request.TemplateID)
+	if err != nil {
+		return response, err
+	}
+	return withTemplateVariables(template), nil
+}
+
+func (service serviceTemplate) UpdateTemplate(ctx context.Context, request domainTemplate.UpdateTemplateRequest) (response domainTemplate.Template, err error) {
+	if err = validations.ValidateUpdateTemplate(ctx, &request); err != nil {
+		return response, err
+	}
+
+	template, err := service.findTemplate(request.AccountID, request.TemplateID)
+	if err != nil {
+		return response, err
+	}
+
+	template.Name = request.Name
+	template.Content = request.Content
+	if request.Media != nil {
+		setTemplateMedia(template, request.Media.Filename, helpers.MultipartFormFileHeaderToBytes(request.Media))
+	} else if request.RemoveMedia {
+		setTemplateMedia(template, "", nil)
+	}
+	if template.Content == "" && !template.HasMedia() {
+		return response, pkgError.ValidationError("content: cannot be blank.")
+	}
+
+	if err = service.templateRepo.UpdateTemplate(template); err != nil {
+		return response, templateStorageError("update", request.Name, err)
+	}
+
+	return withTemplateVariables(template), nil
+}
+
+func (service serviceTemplate) DeleteTemplate(_ context.Context, request domainTemplate.TemplateIdentifierRequest) (err error) {
+	if _, err = service.findTemplate(request.AccountID, request.TemplateID); err != nil {
+		return err
+	}
+
+	if err = service.templateRepo.DeleteTemplate(request.AccountID, request.TemplateID); err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to delete template: %v", err))
+	}
+	return nil
+}
+
+func (service serviceTemplate) findTemplate(accountID, templateID string) (*domainTemplate.Template, error) {
+	if err := service.ensureAccount(accountID); err != nil {
+		return nil, err
+	}
+
+	template, err := service.templateRepo.GetTemplate(accountID, templateID)
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to get template: %v", err))
+	}
+	if template == nil {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("template %s not found", templateID))
+	}
+	return template, nil
+}
+
+func (service serviceTemplate) ensureAccount(accountID string) error {
+	account, err := service.accountRepo.GetAccount(accountID)
+	if err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to get account: %v", err))
+	}
+	if account == nil {
+		return pkgError.NotFoundError(fmt.Sprintf("account %s not found", accountID))
+	}
+	return nil
+}
+
+// setTemplateMedia stores the media on the template, images are sent as images and anything else as a document
+func setTemplateMedia(template *domainTemplate.Template, name string, media []byte) {
+	if len(media) == 0 {
+		template.MediaType, template.MediaName, template.MediaMime, template.Media = "", "", "", nil
+		return
+	}
+
+	mime := http.DetectContentType(media)
+	template.MediaType = domainTemplate.MediaTypeFile
+	if mime == "image/jpeg" || mime == "image/png" {
+		template.MediaType = domainTemplate.MediaTypeImage
+	}
+	template.MediaName = name
+	template.MediaMime = mime
+	template.Media = media
+}
+
+// withTemplateVariables lists the placeholders of the template content
+func withTemplateVariables(template *domainTemplate.Template) domainTemplate.Template {
+	_, variables := utils.RenderPlaceholders(template.Content, nil)
+	if variables == nil {
+		variables = []string{}
+	}
+	template.Variables = variables
+	return *template
+}
+
+func templateStorageError(action, name string, err error) error {
+	// SQLite and PostgreSQL report the unique name constraint differently
+	if strings.Contains(err.Error(), "UNIQUE constraint failed") || strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
+		return pkgError.ValidationError(fmt.Sprintf("name: template %s already exists.", name))
+	}
+	return pkgError.InternalServerError(fmt.Sprintf("failed to %s template: %v", action, err))
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceWebhook struct {
+	webhookRepo domainWebhook.IWebhookRepository
+	accountRepo domainAccount.IAccountRepository
+}
+
+func NewWebhookService(webhookRepo domainWebhook.IWebhookRepository, accountRepo domainAccount.IAccountRepository) domainWebhook.IWebhookUsecase {
+	return &serviceWebhook{
+		webhookRepo: webhookRepo,
+		accountRepo: accountRepo,
+	}
+}
+
+func (service serviceWebhook) CreateEndpoint(ctx context.Context, request domainWebhook.EndpointRequest) (response domainWebhook.Endpoint, err error) {
+	if err = validations.ValidateCreateEndpoint(ctx, &request); err != nil {
+		return response, err
+	}
+	if err = service.ensureAccount(request.AccountID); err != nil {
+		return response, err
+	}
+
+	endpoint := &domainWebhook.Endpoint{
+		ID:        uuid.NewString(),
+		AccountID: request.AccountID,
+		URL:       request.URL,
+		Secret:    request.Secret,
+		Events:    uniqueStrings(request.Events),
+	}
+	if err = service.webhookRepo.CreateEndpoint(endpoint); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to create webhook: %v", err))
+	}
+
+	return *endpoint, nil
+}
+
+func (service serviceWebhook) ListEndpoints(_ context.Context, accountID string) (response []domainWebhook.Endpoint, err error) {
+	if err = service.ensureAccount(accountID); err != nil {
+		return response, err
+	}
+
+	endpoints, err := service.webhookRepo.ListEndpoints(accountID)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to list webhooks: %v", err))
+	}
+
+	response = make([]domainWebhook.Endpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		response = append(response, *endpoint)
+	}
+	return response, nil
+}
+
+func (service serviceWebhook) GetEndpoint(_ context.Context, request domainWebhook.EndpointIdentifierRequest) (response domainWebhook.Endpoint, err error) {
+	endpoint, err := service.findEndpoint(request.AccountID, request.EndpointID)
+	if err != nil {
+		return response, err
+	}
+	return *endpoint, nil
+}
+
+func (service serviceWebhook) UpdateEndpoint(ctx context.Context, request domainWebhook.UpdateEndpointRequest) (response domainWebhook.Endpoint, err error) {
+	if err = validations.ValidateUpdateEndpoint(ctx, &request); err != nil {
+		return response, err
+	}
+
+	endpoint, err := service.findEndpoint(request.AccountID, request.EndpointID)
+	if err != nil {
+		return response, err
+	}
+
+	endpoint.URL = request.URL
+	endpoint.Secret = request.Secret
+	endpoint.Events = uniqueStrings(request.Events)
+	if err = service.webhookRepo.UpdateEndpoint(endpoint); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to update webhook: %v", err))
+	}
+
+	return *endpoint, nil
+}
+
+func (service serviceWebhook) DeleteEndpoint(_ context.Context, request domainWebhook.EndpointIdentifierRequest) (err error) {
+	if _, err = service.findEndpoint(request.AccountID, request.EndpointID); err != nil {
+		return err
+	}
+
+	if err = service.webhookRepo.DeleteEndpoint(request.AccountID, request.EndpointID); err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to delete webhook: %v", err))
+	}
+	return nil
+}
+
+func (service serviceWebhook) ListDeadLetters(ctx context.Context, request domainWebhook.ListDeadLettersRequest) (response domainWebhook.ListDeadLettersResponse, err error) {
+	if err = validations.ValidateListDeadLetters(ctx, &request); err != nil {
+		return response, err
+	}
+	if err = service.ensureDeadLetterScope(request.AccountID, request.Global); err != nil {
+		return response, err
+	}
+
+	deliveries, err := service.webhookRepo.ListDeadDeliveries(request.AccountID, request.Limit, request.Offset)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to list dead webhook deliveries")
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to list dead letters: %v", err))
+	}
+
+	total, err := service.webhookRepo.CountDeadDeliveries(request.AccountID)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to count dead webhook deliveries")
+		// Continue with partial data
+		total = 0
+	}
+
+	response.Data = make([]domainWebhook.Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response.Data = append(response.Data, *delivery)
+	}
+	response.Pagination = domainWebhook.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+
+	return response, nil
+}
+
+func (service serviceWebhook) ReplayDeadLetters(ctx context.Context, request domainWebhook.DeadLettersRequest) (response domainWebhook.DeadLettersResponse, err error) {
+	if err = validations.ValidateDeadLetters(ctx, &request); err != nil {
+		return response, err
+	}
+	if err = service.ensureDeadLetterScope(request.AccountID, request.Global); err != nil {
+		return response, err
+	}
+
+	affected, err := service.webhookRepo.ReplayDeadDeliveries(request.AccountID, request.DeliveryIDs)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to replay dead letters: %v", err))
+	}
+
+	logrus.Infof("[WEBHOOK_OUTBOX] Replaying %d dead letter(s) for %s", affected, deadLetterScope(request.AccountID))
+
+	response.AccountID = request.AccountID
+	response.Affected = affected
+	return response, nil
+}
+
+func (service serviceWebhook) PurgeDeadLetters(ctx context.Context, request domainWebhook.DeadLettersRequest) (response domainWebhook.DeadLettersResponse, err error) {
+	if err = validations.ValidateDeadLetters(ctx, &request); err != nil {
+		return response, err
+	}
+	if err = service.ensureDeadLetterScope(request.AccountID, request.Global); err != nil {
+		return response, err
+	}
+
+	affected, err := service.webhookRepo.PurgeDeadDeliveries(request.AccountID, request.DeliveryIDs)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to purge dead letters: %v", err))
+	}
+
+	logrus.Infof("[WEBHOOK_OUTBOX] Purged %d dead letter(s) for %s", affected, deadLetterScope(request.AccountID))
+
+	response.AccountID = request.AccountID
+	response.Affected = affected
+	return response, nil
+}
+
+func (service serviceWebhook) findEndpoint(accountID, endpointID string) (*domainWebhook.Endpoint, error) {
+	if err := service.ensureAccount(accountID); err != nil {
+		return nil, err
+	}
+
+	endpoint, err := service.webhookRepo.GetEndpoint(accountID, endpointID)
+	if err != nil {
+		return nil, pkgError.InternalServerError(fmt.Sprintf("failed to get webhook: %v", err))
+	}
+	if endpoint == nil {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("webhook %s not found", endpointID))
+	}
+	return endpoint, nil
+}
+
+// uniqueStrings drops repeated values while keeping the requested order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// ensureDeadLetterScope checks the account of a dead letter request. Deliveries of the global
+// webhooks are stored without account, so the global scope needs no account check.
+func (service serviceWebhook) ensureDeadLetterScope(accountID string, global bool) error {
+	if global {
+		return nil
+	}
+	return service.ensureAccount(accountID)
+}
+
+func deadLetterScope(accountID string) string {
+	if accountID == "" {
+		return "the global webhooks"
+	}
+	return "account " + accountID
+}
+
+func (service serviceWebhook) ensureAccount(accountID string) error {
+	account, err := service.accountRepo.GetAccount(accountID)
+	if err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to get account: %v", err))
+	}
+	if account == nil {
+		return pkgError.NotFoundError(fmt.Sprintf("account %s not found", accountID))
+	}
+	return nil
+}
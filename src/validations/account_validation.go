@@ -0,0 +1,99 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateUpdateSettings(ctx context.Context, request domainAccount.UpdateSettingsRequest) error {
+	names := make([]any, len(domainAccount.SettingNames))
+	for i, name := range domainAccount.SettingNames {
+		names[i] = name
+	}
+
+	// Min skips zero values, Required makes a zero size override fail the same way
+	minSize := []validation.Rule{validation.Required.Error("must be no less than 1"), validation.Min(int64(1))}
+
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.AccountID, validation.Required),
+		validation.Field(&request.DeviceName, validation.When(request.DeviceName != nil, validation.Required, validation.Length(1, 50))),
+		validation.Field(&request.MaxImageSize, validation.When(request.MaxImageSize != nil, minSize...)),
+		validation.Field(&request.MaxFileSize, validation.When(request.MaxFileSize != nil, minSize...)),
+		validation.Field(&request.MaxVideoSize, validation.When(request.MaxVideoSize != nil, minSize...)),
+		validation.Field(&request.DisappearingDuration, validation.Min(0), validation.Max(int(maxDuration))),
+		validation.Field(&request.WebhookSecret, validation.When(request.WebhookSecret != nil, validation.Required)),
+		validation.Field(&request.Reset,
+			validation.Each(validation.In(names...).Error("must be one of "+strings.Join(domainAccount.SettingNames, ", "))),
+		),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	set := map[string]bool{
+		domainAccount.SettingAutoMarkRead:         request.AutoMarkRead != nil,
+		domainAccount.SettingAutoReplyMessage:     request.AutoReplyMessage != nil,
+		domainAccount.SettingDeviceName:           request.DeviceName != nil,
+		domainAccount.SettingMaxImageSize:         request.MaxImageSize != nil,
+		domainAccount.SettingMaxFileSize:          request.MaxFileSize != nil,
+		domainAccount.SettingMaxVideoSize:         request.MaxVideoSize != nil,
+		domainAccount.SettingDisappearingDuration: request.DisappearingDuration != nil,
+		domainAccount.SettingWebhookSecret:        request.WebhookSecret != nil,
+	}
+	for _, name := range request.Reset {
+		if set[name] {
+			return pkgError.ValidationError(fmt.Sprintf("reset: %s can't be set and reset at the same time.", name))
+		}
+	}
+
+	return nil
+}
+
+func ValidateBackupAccount(ctx context.Context, request domainAccount.BackupRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.AccountID, validation.Required),
+		validation.Field(&request.Passphrase, validation.Required.Error("is required in the X-Backup-Passphrase header"), validation.Length(8, 0)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateRestoreAccount(ctx context.Context, request domainAccount.RestoreRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.AccountID, validation.Required),
+		validation.Field(&request.Backup, validation.Required),
+		validation.Field(&request.Passphrase, validation.Required),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateConnectionHistory(ctx context.Context, request *domainAccount.ConnectionHistoryRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.AccountID, validation.Required),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(500)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package validations
+
+import (
+	"context"
+	"mime/multipart"
+	"testing"
+
+	domainAccount "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/account"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConnectionHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainAccount.ConnectionHistoryRequest
+		limit   int
+		err     any
+	}{
+		{
+			name:    "should success with default limit",
+			request: domainAccount.ConnectionHistoryRequest{AccountID: "default"},
+			limit:   50,
+			err:     nil,
+		},
+		{
+			name:    "should success with paging",
+			request: domainAccount.ConnectionHistoryRequest{AccountID: "default", Limit: 10, Offset: 20},
+			limit:   10,
+			err:     nil,
+		},
+		{
+			name:    "should error without account",
+			request: domainAccount.ConnectionHistoryRequest{Limit: 10},
+			limit:   10,
+			err:     pkgError.ValidationError("account_id: cannot be blank."),
+		},
+		{
+			name:    "should error with too large limit",
+			request: domainAccount.ConnectionHistoryRequest{AccountID: "default", Limit: 501},
+			limit:   501,
+			err:     pkgError.ValidationError("limit: must be no greater than 500."),
+		},
+		{
+			name:    "should error with negative offset",
+			request: domainAccount.ConnectionHistoryRequest{AccountID: "default", Offset: -1},
+			limit:   50,
+			err:     pkgError.ValidationError("offset: must be no less than 0."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateConnectionHistory(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.limit, tt.request.Limit)
+		})
+	}
+}
+
+func TestValidateUpdateSettings(t *testing.T) {
+	enabled := true
+	empty := ""
+	deviceName := "Sales Desk"
+	zeroSize := int64(0)
+	duration := 86400
+	negative := -1
+
+	tests := []struct {
+		name    string
+		request domainAccount.UpdateSettingsRequest
+		err     any
+	}{
+		{
+			name: "should success with overrides",
+			request: domainAccount.UpdateSettingsRequest{
+				AccountID: "default",
+				Settings:  domainAccount.Settings{AutoMarkRead: &enabled, DeviceName: &deviceName, DisappearingDuration: &duration},
+			},
+			err: nil,
+		},
+		{
+			name: "should success resetting a setting",
+			request: domainAccount.UpdateSettingsRequest{
+				AccountID: "default",
+				Reset:     []string{domainAccount.SettingWebhookSecret},
+			},
+			err: nil,
+		},
+		{
+			name:    "should error without account",
+			request: domainAccount.UpdateSettingsRequest{Settings: domainAccount.Settings{AutoMarkRead: &enabled}},
+			err:     pkgError.ValidationError("account_id: cannot be blank."),
+		},
+		{
+			name: "should error with empty device name",
+			request: domainAccount.UpdateSettingsRequest{
+				AccountID: "default",
+				Settings:  domainAccount.Settings{DeviceName: &empty},
+			},
+			err: pkgError.ValidationError("device_name: cannot be blank."),
+		},
+		{
+			name: "should error with zero media size",
+			request: domainAccount.UpdateSettingsRequest{
+				AccountID: "default",
+				Settings:  domainAccount.Settings{MaxImageSize: &zeroSize},
+			},
+			err: pkgError.ValidationError("max_image_size: must be no less than 1."),
+		},
+		{
+			name: "should error with negative disappearing duration",
+			request: domainAccount.UpdateSettingsRequest{
+				AccountID: "default",
+				Settings:  domainAccount.Settings{DisappearingDuration: &negative},
+			},
+			err: pkgError.ValidationError("disappearing_duration: must be no less than 0."),
+		},
+		{
+			name: "should error resetting an unknown setting",
+			request: domainAccount.UpdateSettingsRequest{
+				AccountID: "default",
+				Reset:     []string{"theme"},
+			},
+			err: pkgError.ValidationError("reset: (0: must be one of auto_mark_read, auto_reply_message, device_name, max_image_size, max_file_size, max_video_size, disappearing_duration, webhook_secret.)."),
+		},
+		{
+			name: "should error setting and resetting the same setting",
+			request: domainAccount.UpdateSettingsRequest{
+				AccountID: "default",
+				Settings:  domainAccount.Settings{AutoMarkRead: &enabled},
+				Reset:     []string{domainAccount.SettingAutoMarkRead},
+			},
+			err: pkgError.ValidationError("reset: auto_mark_read can't be set and reset at the same time."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpdateSettings(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateBackupAccount(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainAccount.BackupRequest
+		err     any
+	}{
+		{
+			name:    "should success with passphrase",
+			request: domainAccount.BackupRequest{AccountID: "default", Passphrase: "correct horse", IncludeChats: true},
+			err:     nil,
+		},
+		{
+			name:    "should error without passphrase",
+			request: domainAccount.BackupRequest{AccountID: "default"},
+			err:     pkgError.ValidationError("passphrase: is required in the X-Backup-Passphrase header."),
+		},
+		{
+			name:    "should error with short passphrase",
+			request: domainAccount.BackupRequest{AccountID: "default", Passphrase: "short"},
+			err:     pkgError.ValidationError("passphrase: the length must be no less than 8."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBackupAccount(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateRestoreAccount(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainAccount.RestoreRequest
+		err     any
+	}{
+		{
+			name:    "should success with backup and passphrase",
+			request: domainAccount.RestoreRequest{AccountID: "default", Backup: &multipart.FileHeader{Filename: "default.wagobackup"}, Passphrase: "correct horse"},
+			err:     nil,
+		},
+		{
+			name:    "should error without backup",
+			request: domainAccount.RestoreRequest{AccountID: "default", Passphrase: "correct horse"},
+			err:     pkgError.ValidationError("backup: cannot be blank."),
+		},
+		{
+			name:    "should error without passphrase",
+			request: domainAccount.RestoreRequest{AccountID: "default", Backup: &multipart.FileHeader{Filename: "default.wagobackup"}},
+			err:     pkgError.ValidationError("passphrase: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRestoreAccount(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package validations
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	domainApiKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// apiKeyAccountPattern matches an account ID or the * wildcard; commas are reserved for storage
+var apiKeyAccountPattern = regexp.MustCompile(`^(\*|[^,\s*]+)$`)
+
+func ValidateCreateAPIKey(ctx context.Context, request *domainApiKey.CreateAPIKeyRequest) error {
+	scopes := make([]any, len(domainApiKey.Scopes))
+	for i, scope := range domainApiKey.Scopes {
+		scopes[i] = scope
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&request.AccountIDs, validation.Required, validation.Each(validation.Required, validation.Length(1, 50), validation.Match(apiKeyAccountPattern).Error("must be an account ID or *"))),
+		validation.Field(&request.Scopes, validation.Required, validation.Each(validation.Required, validation.In(scopes...).Error("must be one of "+strings.Join(domainApiKey.Scopes, ", ")))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainApiKey "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/apikey"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainApiKey.CreateAPIKeyRequest
+		err     any
+	}{
+		{
+			name:    "should success with account scoped key",
+			request: domainApiKey.CreateAPIKeyRequest{Name: "support", AccountIDs: []string{"support-1", "support-2"}, Scopes: []string{"send", "read"}},
+			err:     nil,
+		},
+		{
+			name:    "should success with wildcard admin key",
+			request: domainApiKey.CreateAPIKeyRequest{Name: "ops", AccountIDs: []string{"*"}, Scopes: []string{"admin"}},
+			err:     nil,
+		},
+		{
+			name:    "should error without accounts",
+			request: domainApiKey.CreateAPIKeyRequest{Name: "support", Scopes: []string{"send"}},
+			err:     pkgError.ValidationError("account_ids: cannot be blank."),
+		},
+		{
+			name:    "should error with comma in account id",
+			request: domainApiKey.CreateAPIKeyRequest{Name: "support", AccountIDs: []string{"a,b"}, Scopes: []string{"send"}},
+			err:     pkgError.ValidationError("account_ids: (0: must be an account ID or *.)."),
+		},
+		{
+			name:    "should error with unknown scope",
+			request: domainApiKey.CreateAPIKeyRequest{Name: "support", AccountIDs: []string{"support-1"}, Scopes: []string{"write"}},
+			err:     pkgError.ValidationError("scopes: (0: must be one of send, read, admin.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateAPIKey(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package validations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+func ValidateAutoReplyRule(ctx context.Context, request *domainAutoReply.RuleRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.AccountID, validation.Required),
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&request.MatchType, validation.Required,
+			validation.In(domainAutoReply.MatchKeyword, domainAutoReply.MatchRegex, domainAutoReply.MatchAny)),
+		validation.Field(&request.Pattern,
+			validation.When(request.MatchType != domainAutoReply.MatchAny, validation.Required),
+			validation.When(request.MatchType == domainAutoReply.MatchRegex, validation.By(isRegex))),
+		validation.Field(&request.ActiveHours, validation.By(isTimeWindow)),
+		validation.Field(&request.ActiveDays, validation.Each(validation.In("mon", "tue", "wed", "thu", "fri", "sat", "sun"))),
+		validation.Field(&request.Timezone, validation.By(isTimezone)),
+		validation.Field(&request.CooldownMinutes, validation.Min(0)),
+		validation.Field(&request.ReplyType, validation.Required,
+			validation.In(domainAutoReply.ReplyText, domainAutoReply.ReplyTemplate, domainAutoReply.ReplyMedia)),
+		validation.Field(&request.ReplyText, validation.When(request.ReplyType == domainAutoReply.ReplyText, validation.Required)),
+		validation.Field(&request.TemplateID, validation.When(request.ReplyType == domainAutoReply.ReplyTemplate, validation.Required)),
+		validation.Field(&request.MediaURL, validation.When(request.ReplyType == domainAutoReply.ReplyMedia, validation.Required, is.URL)),
+		validation.Field(&request.MediaType, validation.When(request.ReplyType == domainAutoReply.ReplyMedia, validation.Required,
+			validation.In(domainAutoReply.MediaTypeImage, domainAutoReply.MediaTypeVideo))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateTestAutoReply(ctx context.Context, request domainAutoReply.TestRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.AccountID, validation.Required),
+		validation.Field(&request.Text, validation.Required),
+		validation.Field(&request.At, validation.Date(time.RFC3339)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func isRegex(value any) error {
+	pattern, _ := value.(string)
+	if _, err := regexp.Compile(pattern); err != nil {
+		return errors.New("must be a valid regular expression")
+	}
+	return nil
+}
+
+// isTimeWindow checks an optional HH:MM-HH:MM window
+func isTimeWindow(value any) error {
+	window, _ := value.(string)
+	if _, err := utils.ParseQuietHours(window); err != nil {
+		return errors.New("must use the HH:MM-HH:MM format")
+	}
+	return nil
+}
+
+func isTimezone(value any) error {
+	name, _ := value.(string)
+	if _, err := time.LoadLocation(name); err != nil {
+		return errors.New("must be a valid IANA time zone")
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAutoReplyRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainAutoReply.RuleRequest
+		err     any
+	}{
+		{
+			name: "should success with keyword text reply",
+			request: domainAutoReply.RuleRequest{AccountID: "default", Name: "pricing", MatchType: "keyword", Pattern: "price,pricing",
+				ReplyType: "text", ReplyText: "See our price list"},
+			err: nil,
+		},
+		{
+			name: "should success with out-of-hours template reply",
+			request: domainAutoReply.RuleRequest{AccountID: "default", Name: "closed", MatchType: "any", ActiveHours: "17:00-08:00",
+				ActiveDays: []string{"sat", "sun"}, Timezone: "Asia/Jakarta", CooldownMinutes: 60, ReplyType: "template", TemplateID: "tpl-1"},
+			err: nil,
+		},
+		{
+			name: "should success with media reply",
+			request: domainAutoReply.RuleRequest{AccountID: "default", Name: "menu", MatchType: "regex", Pattern: `(?i)^menu$`,
+				ReplyType: "media", MediaURL: "https://example.com/menu.jpg", MediaType: "image"},
+			err: nil,
+		},
+		{
+			name:    "should error without pattern",
+			request: domainAutoReply.RuleRequest{AccountID: "default", Name: "pricing", MatchType: "keyword", ReplyType: "text", ReplyText: "Hi"},
+			err:     pkgError.ValidationError("pattern: cannot be blank."),
+		},
+		{
+			name:    "should error with invalid regex",
+			request: domainAutoReply.RuleRequest{AccountID: "default", Name: "broken", MatchType: "regex", Pattern: "(", ReplyType: "text", ReplyText: "Hi"},
+			err:     pkgError.ValidationError("pattern: must be a valid regular expression."),
+		},
+		{
+			name: "should error with invalid window",
+			request: domainAutoReply.RuleRequest{AccountID: "default", Name: "closed", MatchType: "any", ActiveHours: "17:00",
+				ReplyType: "text", ReplyText: "Closed"},
+			err: pkgError.ValidationError("active_hours: must use the HH:MM-HH:MM format."),
+		},
+		{
+			name: "should error with invalid day",
+			request: domainAutoReply.RuleRequest{AccountID: "default", Name: "closed", MatchType: "any", ActiveDays: []string{"sunday"},
+				ReplyType: "text", ReplyText: "Closed"},
+			err: pkgError.ValidationError("active_days: (0: must be a valid value.)."),
+		},
+		{
+			name: "should error with invalid timezone",
+			request: domainAutoReply.RuleRequest{AccountID: "default", Name: "closed", MatchType: "any", Timezone: "Mars/Base",
+				ReplyType: "text", ReplyText: "Closed"},
+			err: pkgError.ValidationError("timezone: must be a valid IANA time zone."),
+		},
+		{
+			name:    "should error with media reply without url",
+			request: domainAutoReply.RuleRequest{AccountID: "default", Name: "menu", MatchType: "any", ReplyType: "media", MediaType: "image"},
+			err:     pkgError.ValidationError("media_url: cannot be blank."),
+		},
+		{
+			name:    "should error with template reply without template",
+			request: domainAutoReply.RuleRequest{AccountID: "default", Name: "menu", MatchType: "any", ReplyType: "template"},
+			err:     pkgError.ValidationError("template_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAutoReplyRule(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateTestAutoReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainAutoReply.TestRequest
+		err     any
+	}{
+		{
+			name:    "should success",
+			request: domainAutoReply.TestRequest{AccountID: "default", Text: "price?", At: "2025-01-04T20:00:00+07:00"},
+			err:     nil,
+		},
+		{
+			name:    "should error without text",
+			request: domainAutoReply.TestRequest{AccountID: "default"},
+			err:     pkgError.ValidationError("text: cannot be blank."),
+		},
+		{
+			name:    "should error with invalid time",
+			request: domainAutoReply.TestRequest{AccountID: "default", Text: "price?", At: "tomorrow"},
+			err:     pkgError.ValidationError("at: must be a valid date."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTestAutoReply(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
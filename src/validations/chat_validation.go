@@ -2,6 +2,8 @@ package validations
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
@@ -45,6 +47,40 @@ func ValidateGetChatMessages(ctx context.Context, request *domainChat.GetChatMes
 	return nil
 }
 
+func ValidateSearchMessages(ctx context.Context, request *domainChat.SearchMessagesRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Query, validation.Required),
+		validation.Field(&request.MediaType, validation.In("image", "video", "audio", "document", "sticker")),
+		validation.Field(&request.StartTime, validation.By(isRFC3339)),
+		validation.Field(&request.EndTime, validation.By(isRFC3339)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// isRFC3339 checks that an optional time filter is an RFC3339 timestamp
+func isRFC3339(value any) error {
+	timestamp, _ := value.(*string)
+	if timestamp == nil || *timestamp == "" {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, *timestamp); err != nil {
+		return errors.New("must be a valid RFC3339 timestamp")
+	}
+	return nil
+}
+
 func ValidatePinChat(ctx context.Context, request *domainChat.PinChatRequest) error {
 	err := validation.ValidateStructWithContext(ctx, request,
 		validation.Field(&request.ChatJID, validation.Required),
@@ -56,3 +92,22 @@ func ValidatePinChat(ctx context.Context, request *domainChat.PinChatRequest) er
 
 	return nil
 }
+
+func ValidateExportChats(ctx context.Context, request *domainChat.ExportChatsRequest) error {
+	// Set default format if not provided
+	if request.Format == "" {
+		request.Format = domainChat.ExportFormatJSONL
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Format, validation.In(domainChat.ExportFormatJSONL, domainChat.ExportFormatTXT)),
+		validation.Field(&request.StartTime, validation.By(isRFC3339)),
+		validation.Field(&request.EndTime, validation.By(isRFC3339)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
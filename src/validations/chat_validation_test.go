@@ -141,6 +141,78 @@ func TestValidateGetChatMessages(t *testing.T) {
 	}
 }
 
+func TestValidateSearchMessages(t *testing.T) {
+	validTime := "2025-01-02T15:04:05Z"
+	invalidTime := "yesterday"
+
+	type args struct {
+		request domainChat.SearchMessagesRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with query only (limit auto set to default)",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query: "invoice",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with all filters",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query:     "invoice",
+				ChatJID:   "6289685028129@s.whatsapp.net",
+				Sender:    "6289685028129",
+				MediaType: "document",
+				StartTime: &validTime,
+				EndTime:   &validTime,
+				Limit:     100,
+				Offset:    25,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty query",
+			args: args{request: domainChat.SearchMessagesRequest{}},
+			err:  pkgError.ValidationError("q: cannot be blank."),
+		},
+		{
+			name: "should error with unknown media type",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query:     "invoice",
+				MediaType: "gif",
+			}},
+			err: pkgError.ValidationError("media_type: must be a valid value."),
+		},
+		{
+			name: "should error with invalid start time",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query:     "invoice",
+				StartTime: &invalidTime,
+			}},
+			err: pkgError.ValidationError("start_time: must be a valid RFC3339 timestamp."),
+		},
+		{
+			name: "should error with limit too high",
+			args: args{request: domainChat.SearchMessagesRequest{
+				Query: "invoice",
+				Limit: 101,
+			}},
+			err: pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSearchMessages(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
 func TestValidatePinChat(t *testing.T) {
 	type args struct {
 		request domainChat.PinChatRequest
@@ -183,3 +255,55 @@ func TestValidatePinChat(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateExportChats(t *testing.T) {
+	validTime := "2025-01-02T15:04:05Z"
+	invalidTime := "last month"
+
+	type args struct {
+		request domainChat.ExportChatsRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with empty request (format auto set to jsonl)",
+			args: args{request: domainChat.ExportChatsRequest{}},
+			err:  nil,
+		},
+		{
+			name: "should success with text format, media and time range",
+			args: args{request: domainChat.ExportChatsRequest{
+				ChatJID:      "6289685028129@s.whatsapp.net",
+				Format:       domainChat.ExportFormatTXT,
+				IncludeMedia: true,
+				StartTime:    &validTime,
+				EndTime:      &validTime,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with unknown format",
+			args: args{request: domainChat.ExportChatsRequest{
+				Format: "csv",
+			}},
+			err: pkgError.ValidationError("format: must be a valid value."),
+		},
+		{
+			name: "should error with invalid end time",
+			args: args{request: domainChat.ExportChatsRequest{
+				EndTime: &invalidTime,
+			}},
+			err: pkgError.ValidationError("end_time: must be a valid RFC3339 timestamp."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExportChats(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package validations
+
+import (
+	"context"
+	"strings"
+
+	domainEvent "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/event"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateSubscribeEvents(ctx context.Context, request domainEvent.SubscribeRequest) error {
+	events := make([]any, len(domainEvent.Events))
+	for i, event := range domainEvent.Events {
+		events[i] = event
+	}
+
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Events, validation.Each(validation.Required, validation.In(events...).Error("must be one of "+strings.Join(domainEvent.Events, ", ")))),
+		validation.Field(&request.Cursor, validation.Min(int64(0))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainEvent "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/event"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSubscribeEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainEvent.SubscribeRequest
+		err     any
+	}{
+		{
+			name:    "should success with every event of every account",
+			request: domainEvent.SubscribeRequest{},
+			err:     nil,
+		},
+		{
+			name:    "should success with stream only events and a cursor",
+			request: domainEvent.SubscribeRequest{AccountID: "default", Events: []string{"message", "presence", "connection"}, Cursor: 42},
+			err:     nil,
+		},
+		{
+			name:    "should error with unknown event",
+			request: domainEvent.SubscribeRequest{Events: []string{"message", "typing"}},
+			err:     pkgError.ValidationError("events: (1: must be one of message, message.ack, message.delete, message.revoke, message.edit, group.participants, account.connected, account.disconnected, account.logged_out, account.stream_replaced, account.paired, presence, connection.)."),
+		},
+		{
+			name:    "should error with negative cursor",
+			request: domainEvent.SubscribeRequest{Cursor: -1},
+			err:     pkgError.ValidationError("cursor: must be no less than 0."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSubscribeEvents(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
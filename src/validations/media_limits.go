@@ -0,0 +1,37 @@
+package validations
+
+import (
+	"context"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+// MediaLimits are the upload sizes, in bytes, a send request is validated against
+type MediaLimits struct {
+	Image int64
+	File  int64
+	Video int64
+}
+
+type mediaLimitsKey struct{}
+
+// WithMediaLimits returns a context whose send requests are validated against the given limits
+// instead of the global ones, e.g. the media size settings of the sending account
+func WithMediaLimits(ctx context.Context, limits MediaLimits) context.Context {
+	return context.WithValue(ctx, mediaLimitsKey{}, limits)
+}
+
+// MediaLimitsFromContext returns the limits set on the context, the global limits fill in the missing ones
+func MediaLimitsFromContext(ctx context.Context) MediaLimits {
+	limits, _ := ctx.Value(mediaLimitsKey{}).(MediaLimits)
+	if limits.Image <= 0 {
+		limits.Image = config.WhatsappSettingMaxImageSize
+	}
+	if limits.File <= 0 {
+		limits.File = config.WhatsappSettingMaxFileSize
+	}
+	if limits.Video <= 0 {
+		limits.Video = config.WhatsappSettingMaxVideoSize
+	}
+	return limits
+}
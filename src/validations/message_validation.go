@@ -102,3 +102,15 @@ func ValidateDownloadMedia(ctx context.Context, request domainMessage.DownloadMe
 
 	return nil
 }
+
+func ValidateGetMessageStatus(ctx context.Context, request domainMessage.MessageStatusRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.MessageID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
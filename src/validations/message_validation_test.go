@@ -291,3 +291,48 @@ func TestValidateStarMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateGetMessageStatus(t *testing.T) {
+	type args struct {
+		request domainMessage.MessageStatusRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with message id",
+			args: args{request: domainMessage.MessageStatusRequest{
+				MessageID: "3EB0789ABC123456",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with message id and account id",
+			args: args{request: domainMessage.MessageStatusRequest{
+				AccountID: "sales",
+				MessageID: "3EB0789ABC123456",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty message id",
+			args: args{request: domainMessage.MessageStatusRequest{
+				AccountID: "sales",
+			}},
+			err: pkgError.ValidationError("message_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGetMessageStatus(context.Background(), tt.args.request)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
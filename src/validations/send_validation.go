@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/dustin/go-humanize"
@@ -29,6 +29,17 @@ func validateDuration(dur *int) error {
 	return nil
 }
 
+// validateSendAt validates that the optional schedule time is an RFC3339 timestamp.
+func validateSendAt(sendAt *string) error {
+	if sendAt == nil || *sendAt == "" {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, *sendAt); err != nil {
+		return pkgError.ValidationError("send_at: must be a valid RFC3339 timestamp (e.g. 2025-01-02T15:04:05Z)")
+	}
+	return nil
+}
+
 // validateAccountID validates that the account ID is provided and has valid format
 func validateAccountID(accountID string) error {
 	if accountID == "" {
@@ -84,6 +95,10 @@ func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest)
 	if err := validateDuration(request.Duration); err != nil {
 		return err
 	}
+
+	if err := validateSendAt(request.SendAt); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -101,7 +116,7 @@ func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) err
 		return err
 	}
 
-	if request.Image == nil && (request.ImageURL == nil || *request.ImageURL == "") {
+	if request.Image == nil && (request.ImageURL == nil || *request.ImageURL == "") && request.TemplateID == "" {
 		return pkgError.ValidationError("either Image or ImageURL must be provided")
 	}
 
@@ -115,6 +130,11 @@ func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) err
 		if !availableMimes[request.Image.Header.Get("Content-Type")] {
 			return pkgError.ValidationError("your image is not allowed. please use jpg/jpeg/png")
 		}
+
+		if maxSize := MediaLimitsFromContext(ctx).Image; request.Image.Size > maxSize {
+			maxSizeString := humanize.Bytes(uint64(maxSize))
+			return pkgError.ValidationError(fmt.Sprintf("max image upload is %s, please upload in cloud and send via text if your image is higher than %s", maxSizeString, maxSizeString))
+		}
 	}
 
 	if request.ImageURL != nil {
@@ -133,6 +153,10 @@ func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) err
 		return err
 	}
 
+	if err := validateSendAt(request.SendAt); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -188,13 +212,17 @@ func ValidateSendSticker(ctx context.Context, request domainSend.StickerRequest)
 		return err
 	}
 
+	if err := validateSendAt(request.SendAt); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func ValidateSendFile(ctx context.Context, request domainSend.FileRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
-		validation.Field(&request.File, validation.Required),
+		validation.Field(&request.File, validation.When(request.TemplateID == "", validation.Required)),
 	)
 
 	if err != nil {
@@ -206,8 +234,8 @@ func ValidateSendFile(ctx context.Context, request domainSend.FileRequest) error
 		return err
 	}
 
-	if request.File.Size > config.WhatsappSettingMaxFileSize { // 10MB
-		maxSizeString := humanize.Bytes(uint64(config.WhatsappSettingMaxFileSize))
+	if maxSize := MediaLimitsFromContext(ctx).File; request.File != nil && request.File.Size > maxSize {
+		maxSizeString := humanize.Bytes(uint64(maxSize))
 		return pkgError.ValidationError(fmt.Sprintf("max file upload is %s, please upload in cloud and send via text if your file is higher than %s", maxSizeString, maxSizeString))
 	}
 
@@ -215,6 +243,10 @@ func ValidateSendFile(ctx context.Context, request domainSend.FileRequest) error
 		return err
 	}
 
+	if err := validateSendAt(request.SendAt); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -251,8 +283,8 @@ func ValidateSendVideo(ctx context.Context, request domainSend.VideoRequest) err
 			return pkgError.ValidationError("your video type is not allowed. please use mp4/mkv/avi/x-msvideo")
 		}
 
-		if request.Video.Size > config.WhatsappSettingMaxVideoSize { // 30MB
-			maxSizeString := humanize.Bytes(uint64(config.WhatsappSettingMaxVideoSize))
+		if maxSize := MediaLimitsFromContext(ctx).Video; request.Video.Size > maxSize {
+			maxSizeString := humanize.Bytes(uint64(maxSize))
 			return pkgError.ValidationError(fmt.Sprintf("max video upload is %s, please upload in cloud and send via text if your file is higher than %s", maxSizeString, maxSizeString))
 		}
 	}
@@ -272,6 +304,10 @@ func ValidateSendVideo(ctx context.Context, request domainSend.VideoRequest) err
 		return err
 	}
 
+	if err := validateSendAt(request.SendAt); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -300,6 +336,10 @@ func ValidateSendContact(ctx context.Context, request domainSend.ContactRequest)
 		return err
 	}
 
+	if err := validateSendAt(request.SendAt); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -323,6 +363,10 @@ func ValidateSendLink(ctx context.Context, request domainSend.LinkRequest) error
 		return err
 	}
 
+	if err := validateSendAt(request.SendAt); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -346,6 +390,10 @@ func ValidateSendLocation(ctx context.Context, request domainSend.LocationReques
 		return err
 	}
 
+	if err := validateSendAt(request.SendAt); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -421,6 +469,10 @@ func ValidateSendAudio(ctx context.Context, request domainSend.AudioRequest) err
 		return err
 	}
 
+	if err := validateSendAt(request.SendAt); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -454,6 +506,10 @@ func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error
 		return err
 	}
 
+	if err := validateSendAt(request.SendAt); err != nil {
+		return err
+	}
+
 	// validate options should be unique each other
 	uniqueOptions := make(map[string]bool)
 	for _, option := range request.Options {
@@ -466,6 +522,69 @@ func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error
 	return nil
 }
 
+func ValidateSendBulk(ctx context.Context, request domainSend.BulkRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Recipients, validation.Required),
+		validation.Field(&request.DelayMs, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if err := validateAccountID(request.AccountID); err != nil {
+		return err
+	}
+
+	for i, recipient := range request.Recipients {
+		if err := validatePhoneNumber(recipient.Phone); err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("recipients[%d]: %s", i, err.Error()))
+		}
+	}
+
+	hasImage := request.Image != nil || (request.ImageURL != nil && *request.ImageURL != "")
+	if hasImage && request.File != nil {
+		return pkgError.ValidationError("either an image or a file can be attached, not both")
+	}
+	if !hasImage && request.File == nil && request.Message == "" {
+		return pkgError.ValidationError("message: cannot be blank.")
+	}
+
+	if request.Image != nil {
+		availableMimes := map[string]bool{
+			"image/jpeg": true,
+			"image/jpg":  true,
+			"image/png":  true,
+		}
+
+		if !availableMimes[request.Image.Header.Get("Content-Type")] {
+			return pkgError.ValidationError("your image is not allowed. please use jpg/jpeg/png")
+		}
+
+		if maxSize := MediaLimitsFromContext(ctx).Image; request.Image.Size > maxSize {
+			maxSizeString := humanize.Bytes(uint64(maxSize))
+			return pkgError.ValidationError(fmt.Sprintf("max image upload is %s, please upload in cloud and send via text if your image is higher than %s", maxSizeString, maxSizeString))
+		}
+	}
+
+	if request.ImageURL != nil && *request.ImageURL != "" {
+		if err := validation.Validate(*request.ImageURL, is.URL); err != nil {
+			return pkgError.ValidationError("ImageURL must be a valid URL")
+		}
+	}
+
+	if maxSize := MediaLimitsFromContext(ctx).File; request.File != nil && request.File.Size > maxSize {
+		maxSizeString := humanize.Bytes(uint64(maxSize))
+		return pkgError.ValidationError(fmt.Sprintf("max file upload is %s, please upload in cloud and send via text if your file is higher than %s", maxSizeString, maxSizeString))
+	}
+
+	if err := validateDuration(request.Duration); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func ValidateSendPresence(ctx context.Context, request domainSend.PresenceRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Type, validation.In("available", "unavailable")),
@@ -495,3 +614,15 @@ func ValidateSendChatPresence(ctx context.Context, request domainSend.ChatPresen
 
 	return nil
 }
+
+func ValidateGetJob(ctx context.Context, request domainSend.JobRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.JobID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -24,7 +24,8 @@ func TestValidateSendMessage(t *testing.T) {
 			name: "should success with phone and message",
 			args: args{request: domainSend.MessageRequest{
 				BaseRequest: domainSend.BaseRequest{
-					Phone: "1728937129312@s.whatsapp.net",
+					AccountID: "default",
+					Phone:     "1728937129312@s.whatsapp.net",
 				},
 				Message: "Hello this is testing",
 			}},
@@ -34,7 +35,8 @@ func TestValidateSendMessage(t *testing.T) {
 			name: "should error with empty phone",
 			args: args{request: domainSend.MessageRequest{
 				BaseRequest: domainSend.BaseRequest{
-					Phone: "",
+					AccountID: "default",
+					Phone:     "",
 				},
 				Message: "Hello this is testing",
 			}},
@@ -44,7 +46,8 @@ func TestValidateSendMessage(t *testing.T) {
 			name: "should error with empty message",
 			args: args{request: domainSend.MessageRequest{
 				BaseRequest: domainSend.BaseRequest{
-					Phone: "1728937129312@s.whatsapp.net",
+					AccountID: "default",
+					Phone:     "1728937129312@s.whatsapp.net",
 				},
 				Message: "",
 			}},
@@ -921,6 +924,42 @@ func TestValidateDuration(t *testing.T) {
 	}
 }
 
+func TestValidateSendAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		sendAt *string
+		err    any
+	}{
+		{
+			name:   "should success with nil send_at",
+			sendAt: nil,
+			err:    nil,
+		},
+		{
+			name:   "should success with empty send_at",
+			sendAt: func() *string { s := ""; return &s }(),
+			err:    nil,
+		},
+		{
+			name:   "should success with RFC3339 send_at",
+			sendAt: func() *string { s := "2025-01-02T15:04:05+07:00"; return &s }(),
+			err:    nil,
+		},
+		{
+			name:   "should error with invalid send_at",
+			sendAt: func() *string { s := "2025-01-02 15:04"; return &s }(),
+			err:    pkgError.ValidationError("send_at: must be a valid RFC3339 timestamp (e.g. 2025-01-02T15:04:05Z)"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSendAt(tt.sendAt)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
 func TestValidateSendMessage_WithDuration(t *testing.T) {
 	type args struct {
 		request domainSend.MessageRequest
@@ -934,8 +973,9 @@ func TestValidateSendMessage_WithDuration(t *testing.T) {
 			name: "should success with valid duration",
 			args: args{request: domainSend.MessageRequest{
 				BaseRequest: domainSend.BaseRequest{
-					Phone:    "1728937129312@s.whatsapp.net",
-					Duration: func() *int { d := 3600; return &d }(),
+					AccountID: "default",
+					Phone:     "1728937129312@s.whatsapp.net",
+					Duration:  func() *int { d := 3600; return &d }(),
 				},
 				Message: "Hello this is testing",
 			}},
@@ -945,8 +985,9 @@ func TestValidateSendMessage_WithDuration(t *testing.T) {
 			name: "should error with invalid duration",
 			args: args{request: domainSend.MessageRequest{
 				BaseRequest: domainSend.BaseRequest{
-					Phone:    "1728937129312@s.whatsapp.net",
-					Duration: func() *int { d := -1; return &d }(),
+					AccountID: "default",
+					Phone:     "1728937129312@s.whatsapp.net",
+					Duration:  func() *int { d := -1; return &d }(),
 				},
 				Message: "Hello this is testing",
 			}},
@@ -1106,3 +1147,79 @@ func TestValidateSendAudio_WithDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSendBulk(t *testing.T) {
+	type args struct {
+		request domainSend.BulkRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with text message",
+			args: args{request: domainSend.BulkRequest{
+				AccountID: "default",
+				Recipients: []domainSend.BulkRecipient{
+					{Phone: "6281234567890@s.whatsapp.net", Variables: map[string]string{"name": "Budi"}},
+					{Phone: "6281234567891@s.whatsapp.net"},
+				},
+				Message: "Hello {{name}}",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty recipients",
+			args: args{request: domainSend.BulkRequest{
+				AccountID: "default",
+				Message:   "Hello",
+			}},
+			err: pkgError.ValidationError("recipients: cannot be blank."),
+		},
+		{
+			name: "should error with negative delay",
+			args: args{request: domainSend.BulkRequest{
+				AccountID:  "default",
+				Recipients: []domainSend.BulkRecipient{{Phone: "6281234567890@s.whatsapp.net"}},
+				Message:    "Hello",
+				DelayMs:    func() *int { d := -1; return &d }(),
+			}},
+			err: pkgError.ValidationError("delay_ms: must be no less than 0."),
+		},
+		{
+			name: "should error with local phone format",
+			args: args{request: domainSend.BulkRequest{
+				AccountID:  "default",
+				Recipients: []domainSend.BulkRecipient{{Phone: "6281234567890@s.whatsapp.net"}, {Phone: "081234567890"}},
+				Message:    "Hello",
+			}},
+			err: pkgError.ValidationError("recipients[1]: phone number must be in international format (should not start with 0). For Indonesian numbers, use 62xxx format instead of 08xxx"),
+		},
+		{
+			name: "should error without message or media",
+			args: args{request: domainSend.BulkRequest{
+				AccountID:  "default",
+				Recipients: []domainSend.BulkRecipient{{Phone: "6281234567890@s.whatsapp.net"}},
+			}},
+			err: pkgError.ValidationError("message: cannot be blank."),
+		},
+		{
+			name: "should error with both image and file",
+			args: args{request: domainSend.BulkRequest{
+				AccountID:  "default",
+				Recipients: []domainSend.BulkRecipient{{Phone: "6281234567890@s.whatsapp.net"}},
+				ImageURL:   func() *string { s := "https://example.com/image.png"; return &s }(),
+				File:       &multipart.FileHeader{Filename: "sample.pdf"},
+			}},
+			err: pkgError.ValidationError("either an image or a file can be attached, not both"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendBulk(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
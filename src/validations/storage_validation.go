@@ -0,0 +1,34 @@
+package validations
+
+import (
+	"context"
+
+	domainStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/storage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateSetRetentionPolicy(ctx context.Context, request domainStorage.RetentionPolicyRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.AccountID, validation.Required),
+		validation.Field(&request.MaxMessageDays, validation.Min(0), validation.Max(36500)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateRetentionPolicyIdentifier(ctx context.Context, request domainStorage.RetentionPolicyIdentifierRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.AccountID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/storage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSetRetentionPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainStorage.RetentionPolicyRequest
+		err     any
+	}{
+		{
+			name:    "should success with account policy",
+			request: domainStorage.RetentionPolicyRequest{AccountID: "default", MaxMessageDays: 30},
+			err:     nil,
+		},
+		{
+			name:    "should success keeping a chat forever",
+			request: domainStorage.RetentionPolicyRequest{AccountID: "default", ChatJID: "6289685028129@s.whatsapp.net"},
+			err:     nil,
+		},
+		{
+			name:    "should error without account",
+			request: domainStorage.RetentionPolicyRequest{MaxMessageDays: 30},
+			err:     pkgError.ValidationError("account_id: cannot be blank."),
+		},
+		{
+			name:    "should error with negative days",
+			request: domainStorage.RetentionPolicyRequest{AccountID: "default", MaxMessageDays: -1},
+			err:     pkgError.ValidationError("max_message_days: must be no less than 0."),
+		},
+		{
+			name:    "should error with too many days",
+			request: domainStorage.RetentionPolicyRequest{AccountID: "default", MaxMessageDays: 36501},
+			err:     pkgError.ValidationError("max_message_days: must be no greater than 36500."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSetRetentionPolicy(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/dustin/go-humanize"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+func ValidateCreateTemplate(ctx context.Context, request *domainTemplate.TemplateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.AccountID, validation.Required),
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100), is.PrintableASCII),
+		validation.Field(&request.Content, validation.When(request.Media == nil, validation.Required)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return validateTemplateMedia(request)
+}
+
+func ValidateUpdateTemplate(ctx context.Context, request *domainTemplate.UpdateTemplateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.AccountID, validation.Required),
+		validation.Field(&request.TemplateID, validation.Required),
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100), is.PrintableASCII),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return validateTemplateMedia(&domainTemplate.TemplateRequest{Media: request.Media})
+}
+
+func validateTemplateMedia(request *domainTemplate.TemplateRequest) error {
+	if request.Media != nil && request.Media.Size > config.WhatsappSettingMaxFileSize {
+		maxSizeString := humanize.Bytes(uint64(config.WhatsappSettingMaxFileSize))
+		return pkgError.ValidationError(fmt.Sprintf("media: max file upload is %s", maxSizeString))
+	}
+	return nil
+}
+
+// ValidateTemplateVariables checks that every placeholder of the template content has a variable
+func ValidateTemplateVariables(content string, variables map[string]string) error {
+	if _, missing := utils.RenderPlaceholders(content, variables); len(missing) > 0 {
+		return pkgError.ValidationError(fmt.Sprintf("variables: missing value for %s.", strings.Join(missing, ", ")))
+	}
+	return nil
+}
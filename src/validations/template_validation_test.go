@@ -0,0 +1,91 @@
+package validations
+
+import (
+	"context"
+	"mime/multipart"
+	"testing"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainTemplate.TemplateRequest
+		err     any
+	}{
+		{
+			name:    "should success with content",
+			request: domainTemplate.TemplateRequest{AccountID: "default", Name: "greeting", Content: "Hello {{name}}"},
+			err:     nil,
+		},
+		{
+			name:    "should success with media only",
+			request: domainTemplate.TemplateRequest{AccountID: "default", Name: "brochure", Media: &multipart.FileHeader{Filename: "brochure.pdf", Size: 1024}},
+			err:     nil,
+		},
+		{
+			name:    "should error without name",
+			request: domainTemplate.TemplateRequest{AccountID: "default", Content: "Hello"},
+			err:     pkgError.ValidationError("name: cannot be blank."),
+		},
+		{
+			name:    "should error without content and media",
+			request: domainTemplate.TemplateRequest{AccountID: "default", Name: "empty"},
+			err:     pkgError.ValidationError("content: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateTemplate(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateTemplateVariables(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		variables map[string]string
+		err       any
+	}{
+		{
+			name:      "should success with all variables",
+			content:   "Hi {{name}}, order {{order_id}} is ready",
+			variables: map[string]string{"name": "Budi", "order_id": "A-1"},
+			err:       nil,
+		},
+		{
+			name:    "should success without placeholders",
+			content: "Thank you",
+			err:     nil,
+		},
+		{
+			name:      "should error with missing variables",
+			content:   "Hi {{name}}, order {{order_id}} is ready",
+			variables: map[string]string{"name": "Budi"},
+			err:       pkgError.ValidationError("variables: missing value for order_id."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplateVariables(tt.content, tt.variables)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendFile_WithTemplate(t *testing.T) {
+	request := domainSend.FileRequest{
+		BaseRequest:     domainSend.BaseRequest{Phone: "1728937129312@s.whatsapp.net"},
+		TemplateOptions: domainSend.TemplateOptions{TemplateID: "brochure"},
+	}
+
+	assert.Nil(t, ValidateSendFile(context.Background(), request))
+}
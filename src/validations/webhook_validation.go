@@ -0,0 +1,84 @@
+package validations
+
+import (
+	"context"
+	"strings"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+func webhookEventRules() []validation.Rule {
+	events := make([]any, len(domainWebhook.Events))
+	for i, event := range domainWebhook.Events {
+		events[i] = event
+	}
+
+	return []validation.Rule{
+		validation.Required,
+		validation.Each(validation.Required, validation.In(events...).Error("must be one of "+strings.Join(domainWebhook.Events, ", "))),
+	}
+}
+
+func ValidateCreateEndpoint(ctx context.Context, request *domainWebhook.EndpointRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.AccountID, validation.Required),
+		validation.Field(&request.URL, validation.Required, is.URL),
+		validation.Field(&request.Events, webhookEventRules()...),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateUpdateEndpoint(ctx context.Context, request *domainWebhook.UpdateEndpointRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.AccountID, validation.Required),
+		validation.Field(&request.EndpointID, validation.Required),
+		validation.Field(&request.URL, validation.Required, is.URL),
+		validation.Field(&request.Events, webhookEventRules()...),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateListDeadLetters(ctx context.Context, request *domainWebhook.ListDeadLettersRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.AccountID, validation.When(!request.Global, validation.Required).Else(validation.Empty)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateDeadLetters(ctx context.Context, request *domainWebhook.DeadLettersRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.AccountID, validation.When(!request.Global, validation.Required).Else(validation.Empty)),
+		validation.Field(&request.DeliveryIDs, validation.Each(validation.Required, validation.Min(int64(1)))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateListDeadLetters(t *testing.T) {
+	type args struct {
+		request domainWebhook.ListDeadLettersRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with valid request",
+			args: args{request: domainWebhook.ListDeadLettersRequest{
+				AccountID: "sales",
+				Limit:     25,
+				Offset:    0,
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with zero limit (auto set to default)",
+			args: args{request: domainWebhook.ListDeadLettersRequest{
+				AccountID: "sales",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty account id",
+			args: args{request: domainWebhook.ListDeadLettersRequest{
+				Limit: 25,
+			}},
+			err: pkgError.ValidationError("account_id: cannot be blank."),
+		},
+		{
+			name: "should success with global scope",
+			args: args{request: domainWebhook.ListDeadLettersRequest{
+				Global: true,
+				Limit:  25,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with account id in global scope",
+			args: args{request: domainWebhook.ListDeadLettersRequest{
+				AccountID: "sales",
+				Global:    true,
+			}},
+			err: pkgError.ValidationError("account_id: must be blank."),
+		},
+		{
+			name: "should error with limit too high",
+			args: args{request: domainWebhook.ListDeadLettersRequest{
+				AccountID: "sales",
+				Limit:     101,
+			}},
+			err: pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+		{
+			name: "should error with negative offset",
+			args: args{request: domainWebhook.ListDeadLettersRequest{
+				AccountID: "sales",
+				Limit:     25,
+				Offset:    -1,
+			}},
+			err: pkgError.ValidationError("offset: must be no less than 0."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListDeadLetters(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateDeadLetters(t *testing.T) {
+	type args struct {
+		request domainWebhook.DeadLettersRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success without delivery ids",
+			args: args{request: domainWebhook.DeadLettersRequest{
+				AccountID: "sales",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with delivery ids",
+			args: args{request: domainWebhook.DeadLettersRequest{
+				AccountID:   "sales",
+				DeliveryIDs: []int64{1, 2, 3},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty account id",
+			args: args{request: domainWebhook.DeadLettersRequest{
+				DeliveryIDs: []int64{1},
+			}},
+			err: pkgError.ValidationError("account_id: cannot be blank."),
+		},
+		{
+			name: "should success with global scope",
+			args: args{request: domainWebhook.DeadLettersRequest{
+				Global:      true,
+				DeliveryIDs: []int64{1},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty delivery id",
+			args: args{request: domainWebhook.DeadLettersRequest{
+				AccountID:   "sales",
+				DeliveryIDs: []int64{1, 0},
+			}},
+			err: pkgError.ValidationError("delivery_ids: (1: cannot be blank.)."),
+		},
+		{
+			name: "should error with negative delivery id",
+			args: args{request: domainWebhook.DeadLettersRequest{
+				AccountID:   "sales",
+				DeliveryIDs: []int64{-5},
+			}},
+			err: pkgError.ValidationError("delivery_ids: (0: must be no less than 1.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDeadLetters(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateCreateEndpoint(t *testing.T) {
+	type args struct {
+		request domainWebhook.EndpointRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with valid request",
+			args: args{request: domainWebhook.EndpointRequest{
+				AccountID: "sales",
+				URL:       "https://crm.example.com/webhook",
+				Events:    []string{domainWebhook.EventMessage, domainWebhook.EventReceipt},
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with account lifecycle events",
+			args: args{request: domainWebhook.EndpointRequest{
+				AccountID: "sales",
+				URL:       "https://ops.example.com/webhook",
+				Events:    []string{domainWebhook.EventAccountLoggedOut, domainWebhook.EventAccountStreamReplaced},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with invalid url",
+			args: args{request: domainWebhook.EndpointRequest{
+				AccountID: "sales",
+				URL:       "not a url",
+				Events:    []string{domainWebhook.EventMessage},
+			}},
+			err: pkgError.ValidationError("url: must be a valid URL."),
+		},
+		{
+			name: "should error without events",
+			args: args{request: domainWebhook.EndpointRequest{
+				AccountID: "sales",
+				URL:       "https://crm.example.com/webhook",
+			}},
+			err: pkgError.ValidationError("events: cannot be blank."),
+		},
+		{
+			name: "should error with unknown event",
+			args: args{request: domainWebhook.EndpointRequest{
+				AccountID: "sales",
+				URL:       "https://crm.example.com/webhook",
+				Events:    []string{domainWebhook.EventMessage, "presence"},
+			}},
+			err: pkgError.ValidationError("events: (1: must be one of message, message.ack, message.delete, message.revoke, message.edit, group.participants, account.connected, account.disconnected, account.logged_out, account.stream_replaced, account.paired.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateEndpoint(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateUpdateEndpoint(t *testing.T) {
+	type args struct {
+		request domainWebhook.UpdateEndpointRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with valid request",
+			args: args{request: domainWebhook.UpdateEndpointRequest{
+				AccountID:  "sales",
+				EndpointID: "3f1c2a4e-2b5d-4c1a-9f0e-7d6b5a4c3b2a",
+				URL:        "https://analytics.example.com/webhook",
+				Events:     []string{domainWebhook.EventGroupParticipants},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error without endpoint id",
+			args: args{request: domainWebhook.UpdateEndpointRequest{
+				AccountID: "sales",
+				URL:       "https://analytics.example.com/webhook",
+				Events:    []string{domainWebhook.EventGroupParticipants},
+			}},
+			err: pkgError.ValidationError("endpoint_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpdateEndpoint(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -27,7 +27,7 @@ go mod verify
 
 echo ""
 echo "6. Testing build (this will show actual error)..."
-go build -v -o whatsapp-test 2>&1
+go build -v -tags sqlite_fts5 -o whatsapp-test 2>&1
 
 echo ""
 echo "=== Build SUCCESS! ==="